@@ -0,0 +1,175 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:storageversions
+// +kubebuilder:resource:scope=Cluster,shortName=ngconfig;ngconfigs
+
+// NodeGroupAutoscalingConfig overrides the autoscaling options of the node
+// groups it selects, in the same way for every cloud provider. It takes
+// precedence over options set by the cloud provider (e.g. with node group
+// tags) and over the defaults set by Cluster Autoscaler flags.
+//
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Priority",type="integer",JSONPath=".spec.priority"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type NodeGroupAutoscalingConfig struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object metadata. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#metadata
+	//
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec selects node groups and the options overridden for them.
+	//
+	// +kubebuilder:validation:Required
+	Spec NodeGroupAutoscalingConfigSpec `json:"spec"`
+	// Status of the NodeGroupAutoscalingConfig. CA constantly reconciles this field.
+	//
+	// +optional
+	Status NodeGroupAutoscalingConfigStatus `json:"status,omitempty"`
+}
+
+// NodeGroupAutoscalingConfigList is a object for list of NodeGroupAutoscalingConfig.
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type NodeGroupAutoscalingConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata.
+	//
+	// +optional
+	metav1.ListMeta `json:"metadata"`
+	// Items, list of NodeGroupAutoscalingConfig returned from API.
+	//
+	// +optional
+	Items []NodeGroupAutoscalingConfig `json:"items"`
+}
+
+// NodeGroupAutoscalingConfigSpec selects node groups and the options
+// overridden for them. A node group is selected if its id is listed in
+// NodeGroups or if its template node matches NodeSelector.
+type NodeGroupAutoscalingConfigSpec struct {
+	// NodeGroups lists ids of the selected node groups, as reported by the
+	// cloud provider.
+	//
+	// +optional
+	NodeGroups []string `json:"nodeGroups,omitempty"`
+	// NodeSelector selects node groups by labels of their template nodes.
+	// An empty selector selects all node groups, a nil selector none.
+	//
+	// +optional
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+	// Priority decides which object overrides an option when several objects
+	// selecting the same node group set it. The highest priority wins, ties
+	// are resolved by object names in alphabetical order.
+	//
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+	// Options overridden for the selected node groups. Options which are not
+	// set are left unchanged.
+	//
+	// +kubebuilder:validation:Required
+	Options NodeGroupAutoscalingOptions `json:"options"`
+}
+
+// NodeGroupAutoscalingOptions are the per node group autoscaling options.
+type NodeGroupAutoscalingOptions struct {
+	// ScaleDownUtilizationThreshold is the utilization level below which a node
+	// can be considered for scale down.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1
+	ScaleDownUtilizationThreshold *float64 `json:"scaleDownUtilizationThreshold,omitempty"`
+	// ScaleDownGpuUtilizationThreshold is the GPU utilization level below which
+	// a GPU node can be considered for scale down.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1
+	ScaleDownGpuUtilizationThreshold *float64 `json:"scaleDownGpuUtilizationThreshold,omitempty"`
+	// ScaleDownUnneededTime is how long a node should be unneeded before it is
+	// eligible for scale down.
+	//
+	// +optional
+	ScaleDownUnneededTime *metav1.Duration `json:"scaleDownUnneededTime,omitempty"`
+	// ScaleDownUnreadyTime is how long an unready node should be unneeded
+	// before it is eligible for scale down.
+	//
+	// +optional
+	ScaleDownUnreadyTime *metav1.Duration `json:"scaleDownUnreadyTime,omitempty"`
+	// MaxNodeProvisionTime is the maximum time CA waits for a node to be
+	// provisioned.
+	//
+	// +optional
+	MaxNodeProvisionTime *metav1.Duration `json:"maxNodeProvisionTime,omitempty"`
+	// IgnoreDaemonSetsUtilization tells whether DaemonSet pods are ignored when
+	// computing the utilization of a node for scale down.
+	//
+	// +optional
+	IgnoreDaemonSetsUtilization *bool `json:"ignoreDaemonSetsUtilization,omitempty"`
+	// MinSizeSchedules raise the min size of the node groups during recurring
+	// time windows, in the format of the --min-size-schedules flag, e.g.
+	// "weekdays 08:00-18:00 Europe/Warsaw min=10".
+	//
+	// +optional
+	MinSizeSchedules []string `json:"minSizeSchedules,omitempty"`
+	// ZeroOrMaxNodeScaling tells whether the node groups can only be scaled
+	// up to their max size or down to zero, all nodes at once.
+	//
+	// +optional
+	ZeroOrMaxNodeScaling *bool `json:"zeroOrMaxNodeScaling,omitempty"`
+}
+
+// NodeGroupAutoscalingConfigStatus represents the status of a
+// NodeGroupAutoscalingConfig.
+type NodeGroupAutoscalingConfigStatus struct {
+	// ObservedGeneration is the generation of the spec reflected by the status.
+	//
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// NodeGroups lists ids of the node groups the object currently applies to.
+	//
+	// +optional
+	NodeGroups []string `json:"nodeGroups,omitempty"`
+	// Conditions represent the observations of the object's state.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +patchStrategy=merge
+	// +patchMergeKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+const (
+	// Accepted indicates that the spec is valid and the options are applied
+	// to the selected node groups. If the condition is false, the object is
+	// ignored.
+	Accepted string = "Accepted"
+)
+
+const (
+	// AcceptedReason is the reason of a true Accepted condition.
+	AcceptedReason = "Accepted"
+	// InvalidSpecReason is the reason of a false Accepted condition caused
+	// by an invalid spec.
+	InvalidSpecReason = "InvalidSpec"
+)
@@ -357,6 +357,9 @@ func (ng *nodegroup) GetOptions(defaults config.NodeGroupAutoscalingOptions) (*c
 	if opt, ok := getDurationOption(options, ng.Id(), config.DefaultMaxNodeProvisionTimeKey); ok {
 		defaults.MaxNodeProvisionTime = opt
 	}
+	if opt, ok := getMinSizeSchedulesOption(options, ng.Id(), config.DefaultMinSizeSchedulesKey); ok {
+		defaults.MinSizeSchedules = opt
+	}
 
 	return &defaults, nil
 }
@@ -468,3 +471,18 @@ func getDurationOption(options map[string]string, templateName, name string) (ti
 
 	return option, true
 }
+
+func getMinSizeSchedulesOption(options map[string]string, templateName, name string) ([]config.MinSizeSchedule, bool) {
+	raw, ok := options[name]
+	if !ok {
+		return nil, false
+	}
+
+	option, err := config.ParseMinSizeSchedules(raw)
+	if err != nil {
+		klog.Warningf("failed to convert autoscaling_options option %q (value %q) for scalable resource %q to min size schedules: %v", name, raw, templateName, err)
+		return nil, false
+	}
+
+	return option, true
+}
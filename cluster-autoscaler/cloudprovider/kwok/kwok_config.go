@@ -147,6 +147,8 @@ func LoadConfigFile(kubeClient kubeclient.Interface) (*KwokProviderConfig, error
 
 	if kwokConfig.Kwok == nil {
 		kwokConfig.Kwok = &KwokConfig{}
+	} else if err := kwokConfig.Kwok.validate(); err != nil {
+		return nil, err
 	}
 
 	return &kwokConfig, nil
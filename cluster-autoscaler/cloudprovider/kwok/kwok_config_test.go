@@ -20,10 +20,12 @@ import (
 	"testing"
 
 	"os"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
 	core "k8s.io/client-go/testing"
@@ -34,6 +36,8 @@ var testConfigs = map[string]string{
 	"without-kwok":             withoutKwok,
 	"with-static-kwok-release": withStaticKwokRelease,
 	"skip-kwok-install":        skipKwokInstall,
+	"with-provisioning":        withProvisioning,
+	"invalid-provisioning":     invalidProvisioning,
 }
 
 // with node templates from configmap
@@ -229,6 +233,39 @@ kwok:
   skipInstall: true
 `
 
+const withProvisioning = `
+apiVersion: v1alpha1
+readNodesFrom: configmap
+nodegroups:
+  fromNodeLabelKey: "node.kubernetes.io/instance-type"
+configmap:
+  name: kwok-provider-templates
+kwok:
+  seed: 42
+  provisioning:
+    latency:
+      distribution: uniform
+      min: 1m
+      max: 2m
+    readinessDelay: 30s
+    stockoutRate: 0.1
+  nodegroupProvisioning:
+    m5.xlarge:
+      quota: 3
+`
+
+const invalidProvisioning = `
+apiVersion: v1alpha1
+readNodesFrom: configmap
+nodegroups:
+  fromNodeLabelKey: "node.kubernetes.io/instance-type"
+configmap:
+  name: kwok-provider-templates
+kwok:
+  provisioning:
+    failureRate: 2
+`
+
 func TestLoadConfigFile(t *testing.T) {
 	defer func() {
 		os.Unsetenv("KWOK_PROVIDER_CONFIGMAP")
@@ -282,4 +319,27 @@ func TestLoadConfigFile(t *testing.T) {
 	assert.NotNil(t, kwokConfig)
 	assert.NotNil(t, kwokConfig.status)
 	assert.NotEmpty(t, kwokConfig.status.gpuLabel)
+
+	os.Setenv("KWOK_PROVIDER_CONFIGMAP", "with-provisioning")
+	kwokConfig, err = LoadConfigFile(fakeClient)
+	assert.Nil(t, err)
+	assert.Equal(t, &KwokConfig{
+		Seed: 42,
+		Provisioning: &ProvisioningConfig{
+			Latency: &LatencyConfig{
+				Distribution: uniformDistribution,
+				Min:          metav1.Duration{Duration: time.Minute},
+				Max:          metav1.Duration{Duration: 2 * time.Minute},
+			},
+			ReadinessDelay: metav1.Duration{Duration: 30 * time.Second},
+			StockoutRate:   0.1,
+		},
+		NodegroupProvisioning: map[string]*ProvisioningConfig{
+			"m5.xlarge": {Quota: 3},
+		},
+	}, kwokConfig.Kwok)
+
+	os.Setenv("KWOK_PROVIDER_CONFIGMAP", "invalid-provisioning")
+	_, err = LoadConfigFile(fakeClient)
+	assert.ErrorContains(t, err, "kwok.provisioning: failureRate and stockoutRate")
 }
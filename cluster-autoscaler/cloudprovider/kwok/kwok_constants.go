@@ -28,6 +28,9 @@ const (
 	NGMaxSizeAnnotation = "cluster-autoscaler.kwok.nodegroup/max-count"
 	// NGDesiredSizeAnnotation is annotation on template nodes which specify desired size of the nodegroup
 	NGDesiredSizeAnnotation = "cluster-autoscaler.kwok.nodegroup/desired-count"
+	// NodeReadyAtAnnotation is the annotation on new nodes which stay NotReady until the given time (RFC 3339)
+	// when provisioning is simulated with a readiness delay
+	NodeReadyAtAnnotation = "cluster-autoscaler.kwok.nodegroup/ready-at"
 
 	// KwokManagedAnnotation is the default annotation
 	// that kwok manages to decide if it should manage
@@ -141,6 +141,7 @@ func LoadNodeTemplatesFromConfigMap(configMapName string,
 func createNodegroups(nodes []*apiv1.Node, kubeClient kubernetes.Interface, kc *KwokProviderConfig, initCustomLister listerFn,
 	allNodeLister v1lister.NodeLister) []*NodeGroup {
 	ngs := map[string]*NodeGroup{}
+	sim := newSimulation(kc.Kwok.Seed)
 
 	// note: not using _, node := range nodes here because it leads to unexpected behavior
 	// more info: https://stackoverflow.com/a/38693163/6874596
@@ -178,6 +179,8 @@ func createNodegroups(nodes []*apiv1.Node, kubeClient kubernetes.Interface, kc *
 
 		ng.kubeClient = kubeClient
 		ng.lister = initCustomLister(allNodeLister, filterFn)
+		ng.provisioning = kc.Kwok.provisioningFor(ng.name)
+		ng.simulation = sim
 
 		ngs[ngName] = ng
 	}
@@ -19,6 +19,7 @@ package kwok
 import (
 	"context"
 	"fmt"
+	"time"
 
 	apiv1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -68,18 +69,13 @@ func (nodeGroup *NodeGroup) IncreaseSize(delta int) error {
 
 	klog.V(5).Infof("increasing size of nodegroup '%s' to %v (old size: %v, delta: %v)", nodeGroup.name, newSize, size, delta)
 
-	schedNode, err := nodeGroup.TemplateNodeInfo()
-	if err != nil {
-		return fmt.Errorf("couldn't create a template node for nodegroup %s", nodeGroup.name)
+	if nodeGroup.provisioning != nil {
+		return nodeGroup.addInstances(delta)
 	}
 
 	for i := 0; i < delta; i++ {
-		node := schedNode.Node()
-		node.Name = fmt.Sprintf("%s-%s", nodeGroup.name, rand.String(5))
-		node.Spec.ProviderID = getProviderID(node.Name)
-		_, err := nodeGroup.kubeClient.CoreV1().Nodes().Create(context.Background(), node, v1.CreateOptions{})
-		if err != nil {
-			return fmt.Errorf("couldn't create new node '%s': %v", node.Name, err)
+		if err := nodeGroup.createNode(fmt.Sprintf("%s-%s", nodeGroup.name, rand.String(5)), time.Time{}); err != nil {
+			return err
 		}
 		nodeGroup.targetSize += 1
 	}
@@ -104,8 +100,15 @@ func (nodeGroup *NodeGroup) DeleteNodes(nodes []*apiv1.Node) error {
 	}
 
 	for _, node := range nodes {
+		// instances which failed to be created or are still being created have no node in the cluster
+		if nodeGroup.removeInstance(node.Spec.ProviderID) {
+			nodeGroup.targetSize -= 1
+			continue
+		}
+
 		// TODO(vadasambar): check if there's a better way than returning an error here
-		if node.GetAnnotations()[KwokManagedAnnotation] != "fake" {
+		_, notReadyYet := node.GetAnnotations()[NodeReadyAtAnnotation]
+		if node.GetAnnotations()[KwokManagedAnnotation] != "fake" && !notReadyYet {
 			return fmt.Errorf(notManagedByKwokErr, node.GetName())
 		}
 
@@ -138,6 +141,7 @@ func (nodeGroup *NodeGroup) DecreaseTargetSize(delta int) error {
 			attemptToDeleteExistingNodesErr, size, delta, len(nodes))
 	}
 
+	nodeGroup.cancelInstances(-delta)
 	nodeGroup.targetSize = newSize
 
 	return nil
@@ -182,7 +186,7 @@ func (nodeGroup *NodeGroup) Nodes() ([]cloudprovider.Instance, error) {
 			ErrorInfo: nil,
 		}})
 	}
-	return instances, nil
+	return append(instances, nodeGroup.instanceList()...), nil
 }
 
 // TemplateNodeInfo returns a node template for this node group.
@@ -145,7 +145,22 @@ func (kwok *KwokCloudProvider) Refresh() error {
 	}
 
 	for _, ng := range kwok.nodeGroups {
-		ng.targetSize = targetSizeInCluster[ng.Id()]
+		if ng.provisioning == nil {
+			ng.targetSize = targetSizeInCluster[ng.Id()]
+			continue
+		}
+
+		// nodes created now are not listed yet
+		created, err := ng.createDueNodes()
+		if err != nil {
+			klog.ErrorS(err, "failed to create nodes", "nodegroup", ng.Id())
+			return err
+		}
+		if err := ng.markReadyNodes(); err != nil {
+			klog.ErrorS(err, "failed to mark nodes as ready", "nodegroup", ng.Id())
+			return err
+		}
+		ng.targetSize = targetSizeInCluster[ng.Id()] + created + len(ng.instances)
 	}
 
 	return nil
@@ -0,0 +1,345 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kwok
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	constantDistribution = "constant"
+	uniformDistribution  = "uniform"
+	normalDistribution   = "normal"
+
+	// error codes reported for instances which failed to be created
+	stockoutErrorCode      = "STOCKOUT"
+	quotaExceededErrorCode = "QUOTA_EXCEEDED"
+	failureErrorCode       = "SIMULATED_FAILURE"
+)
+
+// simulation holds the state shared by nodegroups simulating node provisioning
+type simulation struct {
+	mutex  sync.Mutex
+	random *rand.Rand
+	now    func() time.Time
+}
+
+func newSimulation(seed int64) *simulation {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &simulation{
+		random: rand.New(rand.NewSource(seed)),
+		now:    time.Now,
+	}
+}
+
+// instance is an instance being created or which failed to be created
+type instance struct {
+	name string
+	// createTime is the time at which the node is created
+	createTime time.Time
+	// errorInfo is set if the instance failed to be created
+	errorInfo *cloudprovider.InstanceErrorInfo
+}
+
+// newInstance draws the outcome of creating a new instance
+func (s *simulation) newInstance(name string, pc *ProvisioningConfig, overQuota bool) *instance {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	inst := &instance{name: name, createTime: s.now()}
+	if overQuota {
+		inst.errorInfo = &cloudprovider.InstanceErrorInfo{
+			ErrorClass:   cloudprovider.OutOfResourcesErrorClass,
+			ErrorCode:    quotaExceededErrorCode,
+			ErrorMessage: fmt.Sprintf("quota of %d instances exceeded", pc.Quota),
+		}
+		return inst
+	}
+	draw := s.random.Float64()
+	switch {
+	case draw < pc.FailureRate:
+		inst.errorInfo = &cloudprovider.InstanceErrorInfo{
+			ErrorClass:   cloudprovider.OtherErrorClass,
+			ErrorCode:    failureErrorCode,
+			ErrorMessage: "simulated instance creation failure",
+		}
+	case draw < pc.FailureRate+pc.StockoutRate:
+		inst.errorInfo = &cloudprovider.InstanceErrorInfo{
+			ErrorClass:   cloudprovider.OutOfResourcesErrorClass,
+			ErrorCode:    stockoutErrorCode,
+			ErrorMessage: "simulated stockout",
+		}
+	default:
+		if pc.Latency != nil {
+			inst.createTime = inst.createTime.Add(pc.Latency.sample(s.random))
+		}
+	}
+	return inst
+}
+
+// sample draws a latency from the distribution
+func (lc *LatencyConfig) sample(random *rand.Rand) time.Duration {
+	var latency time.Duration
+	switch lc.Distribution {
+	case uniformDistribution:
+		latency = lc.Min.Duration + time.Duration(random.Int63n(int64(lc.Max.Duration-lc.Min.Duration)+1))
+	case normalDistribution:
+		latency = lc.Mean.Duration + time.Duration(random.NormFloat64()*float64(lc.StdDev.Duration))
+		if latency < lc.Min.Duration {
+			latency = lc.Min.Duration
+		}
+		if lc.Max.Duration > 0 && latency > lc.Max.Duration {
+			latency = lc.Max.Duration
+		}
+	default:
+		latency = lc.Mean.Duration
+	}
+	if latency < 0 {
+		return 0
+	}
+	return latency
+}
+
+// provisioningFor returns the provisioning config of the nodegroup, nil if
+// provisioning is not simulated
+func (kc *KwokConfig) provisioningFor(ngName string) *ProvisioningConfig {
+	if pc, found := kc.NodegroupProvisioning[ngName]; found {
+		return pc
+	}
+	return kc.Provisioning
+}
+
+func (kc *KwokConfig) validate() error {
+	if kc.Provisioning != nil {
+		if err := kc.Provisioning.validate(); err != nil {
+			return fmt.Errorf("kwok.provisioning: %v", err)
+		}
+	}
+	for ngName, pc := range kc.NodegroupProvisioning {
+		if pc == nil {
+			return fmt.Errorf("kwok.nodegroupProvisioning.%s is empty", ngName)
+		}
+		if err := pc.validate(); err != nil {
+			return fmt.Errorf("kwok.nodegroupProvisioning.%s: %v", ngName, err)
+		}
+	}
+	return nil
+}
+
+func (pc *ProvisioningConfig) validate() error {
+	if pc.FailureRate < 0 || pc.StockoutRate < 0 || pc.FailureRate+pc.StockoutRate > 1 {
+		return fmt.Errorf("failureRate and stockoutRate must be non-negative and add up to at most 1 (got %v and %v)", pc.FailureRate, pc.StockoutRate)
+	}
+	if pc.Quota < 0 {
+		return fmt.Errorf("quota must be non-negative (got %d)", pc.Quota)
+	}
+	if pc.ReadinessDelay.Duration < 0 {
+		return fmt.Errorf("readinessDelay must be non-negative (got %v)", pc.ReadinessDelay.Duration)
+	}
+	if pc.Latency != nil {
+		return pc.Latency.validate()
+	}
+	return nil
+}
+
+func (lc *LatencyConfig) validate() error {
+	if lc.Mean.Duration < 0 || lc.StdDev.Duration < 0 || lc.Min.Duration < 0 || lc.Max.Duration < 0 {
+		return fmt.Errorf("latency durations must be non-negative")
+	}
+	switch lc.Distribution {
+	case "", constantDistribution, normalDistribution:
+		if lc.Max.Duration > 0 && lc.Min.Duration > lc.Max.Duration {
+			return fmt.Errorf("latency.min can't be greater than latency.max")
+		}
+	case uniformDistribution:
+		if lc.Min.Duration > lc.Max.Duration {
+			return fmt.Errorf("latency.min can't be greater than latency.max")
+		}
+	default:
+		return fmt.Errorf("latency.distribution is invalid (expected: '%s', '%s' or '%s'): %s",
+			constantDistribution, uniformDistribution, normalDistribution, lc.Distribution)
+	}
+	return nil
+}
+
+// addInstances starts creating delta new instances, failing the ones above
+// the quota
+func (nodeGroup *NodeGroup) addInstances(delta int) error {
+	nodeNames, err := nodeGroup.getNodeNamesForNodeGroup()
+	if err != nil {
+		return err
+	}
+	existing := len(nodeNames)
+	for _, inst := range nodeGroup.instances {
+		if inst.errorInfo == nil {
+			existing++
+		}
+	}
+	for i := 0; i < delta; i++ {
+		name := fmt.Sprintf("%s-%s", nodeGroup.name, utilrand.String(5))
+		overQuota := nodeGroup.provisioning.Quota > 0 && existing >= nodeGroup.provisioning.Quota
+		inst := nodeGroup.simulation.newInstance(name, nodeGroup.provisioning, overQuota)
+		if inst.errorInfo == nil {
+			existing++
+		} else {
+			klog.V(2).Infof("simulated failure of instance '%s' in nodegroup '%s': %s", name, nodeGroup.name, inst.errorInfo.ErrorMessage)
+		}
+		nodeGroup.instances = append(nodeGroup.instances, inst)
+		nodeGroup.targetSize += 1
+	}
+	_, err = nodeGroup.createDueNodes()
+	return err
+}
+
+// createDueNodes creates nodes for the instances whose provisioning latency
+// passed and returns the number of created nodes
+func (nodeGroup *NodeGroup) createDueNodes() (int, error) {
+	now := nodeGroup.simulation.now()
+	created := 0
+	remaining := make([]*instance, 0, len(nodeGroup.instances))
+	for i, inst := range nodeGroup.instances {
+		if inst.errorInfo != nil || inst.createTime.After(now) {
+			remaining = append(remaining, inst)
+			continue
+		}
+		var readyAt time.Time
+		if nodeGroup.provisioning.ReadinessDelay.Duration > 0 {
+			readyAt = now.Add(nodeGroup.provisioning.ReadinessDelay.Duration)
+		}
+		if err := nodeGroup.createNode(inst.name, readyAt); err != nil {
+			nodeGroup.instances = append(remaining, nodeGroup.instances[i:]...)
+			return created, err
+		}
+		created++
+	}
+	nodeGroup.instances = remaining
+	return created, nil
+}
+
+// createNode creates a node from the template, which stays NotReady until
+// readyAt if it's set
+func (nodeGroup *NodeGroup) createNode(name string, readyAt time.Time) error {
+	node := nodeGroup.nodeTemplate.DeepCopy()
+	node.Name = name
+	node.Spec.ProviderID = getProviderID(node.Name)
+	if !readyAt.IsZero() {
+		// kwok marks the nodes it manages as ready, so the node is handed
+		// over to kwok only once it should become ready.
+		if node.Annotations == nil {
+			node.Annotations = map[string]string{}
+		}
+		delete(node.Annotations, KwokManagedAnnotation)
+		node.Annotations[NodeReadyAtAnnotation] = readyAt.Format(time.RFC3339)
+		setNodeReadyCondition(node, apiv1.ConditionFalse, readyAt)
+	}
+	_, err := nodeGroup.kubeClient.CoreV1().Nodes().Create(context.Background(), node, v1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("couldn't create new node '%s': %v", node.Name, err)
+	}
+	return nil
+}
+
+// markReadyNodes marks the nodes whose readiness delay passed as ready
+func (nodeGroup *NodeGroup) markReadyNodes() error {
+	nodes, err := nodeGroup.lister.List()
+	if err != nil {
+		return err
+	}
+	now := nodeGroup.simulation.now()
+	for _, node := range nodes {
+		readyAt, found := node.GetAnnotations()[NodeReadyAtAnnotation]
+		if !found {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339, readyAt); err == nil && t.After(now) {
+			continue
+		}
+		updated := node.DeepCopy()
+		delete(updated.Annotations, NodeReadyAtAnnotation)
+		updated.Annotations[KwokManagedAnnotation] = "fake"
+		updated, err = nodeGroup.kubeClient.CoreV1().Nodes().Update(context.Background(), updated, v1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("couldn't update node '%s': %v", node.GetName(), err)
+		}
+		setNodeReadyCondition(updated, apiv1.ConditionTrue, now)
+		if _, err := nodeGroup.kubeClient.CoreV1().Nodes().UpdateStatus(context.Background(), updated, v1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("couldn't mark node '%s' as ready: %v", node.GetName(), err)
+		}
+		klog.V(5).Infof("node '%s' in nodegroup '%s' is ready", node.GetName(), nodeGroup.name)
+	}
+	return nil
+}
+
+// removeInstance removes the instance with the given provider id, returning
+// false if there is none
+func (nodeGroup *NodeGroup) removeInstance(providerID string) bool {
+	for i, inst := range nodeGroup.instances {
+		if getProviderID(inst.name) == providerID {
+			nodeGroup.instances = append(nodeGroup.instances[:i], nodeGroup.instances[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// cancelInstances stops creating up to count instances, newest first
+func (nodeGroup *NodeGroup) cancelInstances(count int) {
+	if count > len(nodeGroup.instances) {
+		count = len(nodeGroup.instances)
+	}
+	nodeGroup.instances = nodeGroup.instances[:len(nodeGroup.instances)-count]
+}
+
+// instanceList returns the instances being created or which failed to be created
+func (nodeGroup *NodeGroup) instanceList() []cloudprovider.Instance {
+	instances := make([]cloudprovider.Instance, 0, len(nodeGroup.instances))
+	for _, inst := range nodeGroup.instances {
+		instances = append(instances, cloudprovider.Instance{Id: getProviderID(inst.name), Status: &cloudprovider.InstanceStatus{
+			State:     cloudprovider.InstanceCreating,
+			ErrorInfo: inst.errorInfo,
+		}})
+	}
+	return instances
+}
+
+func setNodeReadyCondition(node *apiv1.Node, status apiv1.ConditionStatus, now time.Time) {
+	condition := apiv1.NodeCondition{
+		Type:               apiv1.NodeReady,
+		Status:             status,
+		LastHeartbeatTime:  v1.NewTime(now),
+		LastTransitionTime: v1.NewTime(now),
+	}
+	for i := range node.Status.Conditions {
+		if node.Status.Conditions[i].Type == apiv1.NodeReady {
+			node.Status.Conditions[i] = condition
+			return
+		}
+	}
+	node.Status.Conditions = append(node.Status.Conditions, condition)
+}
@@ -0,0 +1,281 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kwok
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestProvisioningNodeGroup(pc *ProvisioningConfig) (*NodeGroup, *fake.Clientset, *time.Time) {
+	fakeClient := fake.NewSimpleClientset()
+	now := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	sim := newSimulation(42)
+	sim.now = func() time.Time { return now }
+	ng := &NodeGroup{
+		name:       "ng",
+		kubeClient: fakeClient,
+		lister:     kube_util.NewTestNodeLister(nil),
+		nodeTemplate: &apiv1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "template-node-ng",
+				Annotations: map[string]string{
+					KwokManagedAnnotation: "fake",
+				},
+			},
+		},
+		minSize:      0,
+		targetSize:   0,
+		maxSize:      5,
+		provisioning: pc,
+		simulation:   sim,
+	}
+	return ng, fakeClient, &now
+}
+
+func listNodes(t *testing.T, fakeClient *fake.Clientset) []*apiv1.Node {
+	nodeList, err := fakeClient.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	nodes := []*apiv1.Node{}
+	for i := range nodeList.Items {
+		nodes = append(nodes, &nodeList.Items[i])
+	}
+	return nodes
+}
+
+func TestProvisioningLatencyAndReadiness(t *testing.T) {
+	ng, fakeClient, now := newTestProvisioningNodeGroup(&ProvisioningConfig{
+		Latency:        &LatencyConfig{Mean: metav1.Duration{Duration: time.Minute}},
+		ReadinessDelay: metav1.Duration{Duration: 30 * time.Second},
+	})
+
+	// nodes are not created until the latency passes
+	assert.NoError(t, ng.IncreaseSize(2))
+	assert.Equal(t, 2, ng.targetSize)
+	assert.Len(t, listNodes(t, fakeClient), 0)
+	instances, err := ng.Nodes()
+	assert.NoError(t, err)
+	assert.Len(t, instances, 2)
+	for _, instance := range instances {
+		assert.Equal(t, &cloudprovider.InstanceStatus{State: cloudprovider.InstanceCreating}, instance.Status)
+	}
+
+	*now = now.Add(time.Minute)
+	created, err := ng.createDueNodes()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, created)
+	assert.Len(t, ng.instances, 0)
+
+	// created nodes stay NotReady and are not managed by kwok until the readiness delay passes
+	nodes := listNodes(t, fakeClient)
+	assert.Len(t, nodes, 2)
+	for _, node := range nodes {
+		assert.Equal(t, now.Add(30*time.Second).Format(time.RFC3339), node.Annotations[NodeReadyAtAnnotation])
+		assert.NotContains(t, node.Annotations, KwokManagedAnnotation)
+		assert.Equal(t, apiv1.ConditionFalse, node.Status.Conditions[0].Status)
+	}
+	assert.NotContains(t, ng.nodeTemplate.Annotations, NodeReadyAtAnnotation)
+	ng.lister = kube_util.NewTestNodeLister(nodes)
+
+	*now = now.Add(10 * time.Second)
+	assert.NoError(t, ng.markReadyNodes())
+	for _, node := range listNodes(t, fakeClient) {
+		assert.Contains(t, node.Annotations, NodeReadyAtAnnotation)
+	}
+
+	*now = now.Add(20 * time.Second)
+	assert.NoError(t, ng.markReadyNodes())
+	for _, node := range listNodes(t, fakeClient) {
+		assert.NotContains(t, node.Annotations, NodeReadyAtAnnotation)
+		assert.Equal(t, "fake", node.Annotations[KwokManagedAnnotation])
+		assert.Equal(t, apiv1.ConditionTrue, node.Status.Conditions[0].Status)
+	}
+}
+
+func TestProvisioningFailures(t *testing.T) {
+	testCases := []struct {
+		name            string
+		config          *ProvisioningConfig
+		delta           int
+		expectedNodes   int
+		expectedErrors  []cloudprovider.InstanceErrorClass
+		expectedErrCode string
+	}{
+		{
+			name:            "failure",
+			config:          &ProvisioningConfig{FailureRate: 1},
+			delta:           2,
+			expectedErrors:  []cloudprovider.InstanceErrorClass{cloudprovider.OtherErrorClass, cloudprovider.OtherErrorClass},
+			expectedErrCode: failureErrorCode,
+		},
+		{
+			name:            "stockout",
+			config:          &ProvisioningConfig{StockoutRate: 1},
+			delta:           1,
+			expectedErrors:  []cloudprovider.InstanceErrorClass{cloudprovider.OutOfResourcesErrorClass},
+			expectedErrCode: stockoutErrorCode,
+		},
+		{
+			name:            "quota",
+			config:          &ProvisioningConfig{Quota: 2},
+			delta:           3,
+			expectedNodes:   2,
+			expectedErrors:  []cloudprovider.InstanceErrorClass{cloudprovider.OutOfResourcesErrorClass},
+			expectedErrCode: quotaExceededErrorCode,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ng, fakeClient, _ := newTestProvisioningNodeGroup(tc.config)
+			assert.NoError(t, ng.IncreaseSize(tc.delta))
+			assert.Equal(t, tc.delta, ng.targetSize)
+			assert.Len(t, listNodes(t, fakeClient), tc.expectedNodes)
+			assert.Len(t, ng.instances, len(tc.expectedErrors))
+
+			instances, err := ng.Nodes()
+			assert.NoError(t, err)
+			var errorClasses []cloudprovider.InstanceErrorClass
+			for _, instance := range instances {
+				assert.Equal(t, cloudprovider.InstanceCreating, instance.Status.State)
+				errorClasses = append(errorClasses, instance.Status.ErrorInfo.ErrorClass)
+				assert.Equal(t, tc.expectedErrCode, instance.Status.ErrorInfo.ErrorCode)
+			}
+			assert.Equal(t, tc.expectedErrors, errorClasses)
+
+			// failed instances are deleted by their provider id
+			node := &apiv1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: instances[0].Id},
+				Spec:       apiv1.NodeSpec{ProviderID: instances[0].Id},
+			}
+			assert.NoError(t, ng.DeleteNodes([]*apiv1.Node{node}))
+			assert.Equal(t, tc.delta-1, ng.targetSize)
+			assert.Len(t, ng.instances, len(tc.expectedErrors)-1)
+		})
+	}
+}
+
+func TestDecreaseTargetSizeCancelsInstances(t *testing.T) {
+	ng, fakeClient, _ := newTestProvisioningNodeGroup(&ProvisioningConfig{
+		Latency: &LatencyConfig{Mean: metav1.Duration{Duration: time.Minute}},
+	})
+	assert.NoError(t, ng.IncreaseSize(3))
+	assert.NoError(t, ng.DecreaseTargetSize(-2))
+	assert.Equal(t, 1, ng.targetSize)
+	assert.Len(t, ng.instances, 1)
+	assert.Len(t, listNodes(t, fakeClient), 0)
+}
+
+func TestLatencySample(t *testing.T) {
+	random := rand.New(rand.NewSource(42))
+	constant := &LatencyConfig{Mean: metav1.Duration{Duration: time.Minute}}
+	assert.Equal(t, time.Minute, constant.sample(random))
+
+	uniform := &LatencyConfig{
+		Distribution: uniformDistribution,
+		Min:          metav1.Duration{Duration: time.Minute},
+		Max:          metav1.Duration{Duration: 2 * time.Minute},
+	}
+	normal := &LatencyConfig{
+		Distribution: normalDistribution,
+		Mean:         metav1.Duration{Duration: time.Minute},
+		StdDev:       metav1.Duration{Duration: time.Hour},
+		Max:          metav1.Duration{Duration: 3 * time.Minute},
+	}
+	for i := 0; i < 100; i++ {
+		latency := uniform.sample(random)
+		assert.GreaterOrEqual(t, latency, time.Minute)
+		assert.LessOrEqual(t, latency, 2*time.Minute)
+
+		latency = normal.sample(random)
+		assert.GreaterOrEqual(t, latency, time.Duration(0))
+		assert.LessOrEqual(t, latency, 3*time.Minute)
+	}
+}
+
+func TestKwokConfigValidate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		config      *KwokConfig
+		expectedErr string
+	}{
+		{
+			name:   "empty",
+			config: &KwokConfig{},
+		},
+		{
+			name: "valid",
+			config: &KwokConfig{
+				Provisioning: &ProvisioningConfig{FailureRate: 0.1, StockoutRate: 0.2, Quota: 10},
+				NodegroupProvisioning: map[string]*ProvisioningConfig{
+					"ng": {Latency: &LatencyConfig{Distribution: uniformDistribution, Max: metav1.Duration{Duration: time.Minute}}},
+				},
+			},
+		},
+		{
+			name:        "rates above 1",
+			config:      &KwokConfig{Provisioning: &ProvisioningConfig{FailureRate: 0.6, StockoutRate: 0.6}},
+			expectedErr: "kwok.provisioning: failureRate and stockoutRate",
+		},
+		{
+			name:        "negative quota",
+			config:      &KwokConfig{NodegroupProvisioning: map[string]*ProvisioningConfig{"ng": {Quota: -1}}},
+			expectedErr: "kwok.nodegroupProvisioning.ng: quota must be non-negative",
+		},
+		{
+			name:        "invalid distribution",
+			config:      &KwokConfig{Provisioning: &ProvisioningConfig{Latency: &LatencyConfig{Distribution: "poisson"}}},
+			expectedErr: "latency.distribution is invalid",
+		},
+		{
+			name: "min above max",
+			config: &KwokConfig{Provisioning: &ProvisioningConfig{Latency: &LatencyConfig{
+				Distribution: uniformDistribution,
+				Min:          metav1.Duration{Duration: time.Hour},
+				Max:          metav1.Duration{Duration: time.Minute},
+			}}},
+			expectedErr: "latency.min can't be greater than latency.max",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.config.validate()
+			if tc.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
+func TestProvisioningFor(t *testing.T) {
+	defaults := &ProvisioningConfig{Quota: 1}
+	override := &ProvisioningConfig{Quota: 2}
+	kc := &KwokConfig{Provisioning: defaults, NodegroupProvisioning: map[string]*ProvisioningConfig{"ng2": override}}
+	assert.Equal(t, defaults, kc.provisioningFor("ng1"))
+	assert.Equal(t, override, kc.provisioningFor("ng2"))
+	assert.Nil(t, (&KwokConfig{}).provisioningFor("ng1"))
+}
@@ -18,6 +18,7 @@ package kwok
 
 import (
 	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	listersv1 "k8s.io/client-go/listers/core/v1"
 
@@ -59,6 +60,11 @@ type NodeGroup struct {
 	minSize      int
 	targetSize   int
 	maxSize      int
+	// provisioning is nil if nodes are created instantly
+	provisioning *ProvisioningConfig
+	simulation   *simulation
+	// instances are the instances being created or which failed to be created
+	instances []*instance
 }
 
 // NodegroupsConfig defines options for creating nodegroups
@@ -86,8 +92,44 @@ type GPUConfig struct {
 }
 
 // KwokConfig is the struct to define kwok specific config
-// (needs to be implemented; currently empty)
 type KwokConfig struct {
+	// Provisioning simulates realistic provisioning of new nodes in all nodegroups.
+	// Nodes are created instantly and never fail if it's not set.
+	Provisioning *ProvisioningConfig `json:"provisioning" yaml:"provisioning"`
+	// NodegroupProvisioning overrides Provisioning for nodegroups by name.
+	NodegroupProvisioning map[string]*ProvisioningConfig `json:"nodegroupProvisioning" yaml:"nodegroupProvisioning"`
+	// Seed seeds the random simulation of provisioning, so that runs can be reproduced.
+	// A random seed is used if it's 0.
+	Seed int64 `json:"seed" yaml:"seed"`
+}
+
+// ProvisioningConfig defines how new nodes of a nodegroup are provisioned
+type ProvisioningConfig struct {
+	// Latency is the time between increasing the nodegroup size and creating the node.
+	// Until then, the instance is reported in the creating state.
+	Latency *LatencyConfig `json:"latency" yaml:"latency"`
+	// ReadinessDelay is the time a new node stays NotReady after it's created.
+	ReadinessDelay metav1.Duration `json:"readinessDelay" yaml:"readinessDelay"`
+	// FailureRate is the probability that a new instance fails with an OtherErrorClass error.
+	FailureRate float64 `json:"failureRate" yaml:"failureRate"`
+	// StockoutRate is the probability that a new instance fails with an OutOfResourcesErrorClass error.
+	StockoutRate float64 `json:"stockoutRate" yaml:"stockoutRate"`
+	// Quota is the maximum number of instances in the nodegroup, including the ones being created.
+	// Instances above it fail with an OutOfResourcesErrorClass error. No quota if 0.
+	Quota int `json:"quota" yaml:"quota"`
+}
+
+// LatencyConfig defines the distribution of node provisioning latency
+type LatencyConfig struct {
+	// Distribution is one of: constant (default), uniform, normal.
+	Distribution string `json:"distribution" yaml:"distribution"`
+	// Mean is the latency of the constant distribution and the mean of the normal one.
+	Mean metav1.Duration `json:"mean" yaml:"mean"`
+	// StdDev is the standard deviation of the normal distribution.
+	StdDev metav1.Duration `json:"stdDev" yaml:"stdDev"`
+	// Min and Max are the bounds of the uniform distribution. If set, they also bound the normal one.
+	Min metav1.Duration `json:"min" yaml:"min"`
+	Max metav1.Duration `json:"max" yaml:"max"`
 }
 
 // KwokProviderConfig is the struct to hold kwok provider config
@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pricing
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	v1lister "k8s.io/client-go/listers/core/v1"
+)
+
+// PriceTableConfigMapKey is the ConfigMap key holding the price table.
+const PriceTableConfigMapKey = "prices"
+
+// ConfigMapModel is a PricingModel reading its PriceTable from a ConfigMap,
+// so prices can be updated without restarting Cluster Autoscaler.
+type ConfigMapModel struct {
+	lister        v1lister.ConfigMapNamespaceLister
+	configMapName string
+
+	mutex           sync.Mutex
+	table           *PriceTable
+	resourceVersion string
+}
+
+// NewConfigMapModel returns a pricing model backed by the given ConfigMap.
+func NewConfigMapModel(lister v1lister.ConfigMapNamespaceLister, configMapName string) *ConfigMapModel {
+	return &ConfigMapModel{
+		lister:        lister,
+		configMapName: configMapName,
+	}
+}
+
+// NodePrice returns the price of running the node between startTime and endTime.
+func (m *ConfigMapModel) NodePrice(node *apiv1.Node, startTime time.Time, endTime time.Time) (float64, error) {
+	table, err := m.priceTable()
+	if err != nil {
+		return 0, err
+	}
+	return table.NodePrice(node, startTime, endTime)
+}
+
+// PodPrice returns a theoretical minimum price of running the pod between
+// startTime and endTime.
+func (m *ConfigMapModel) PodPrice(pod *apiv1.Pod, startTime time.Time, endTime time.Time) (float64, error) {
+	table, err := m.priceTable()
+	if err != nil {
+		return 0, err
+	}
+	return table.PodPrice(pod, startTime, endTime)
+}
+
+// priceTable returns the table from the current version of the ConfigMap,
+// parsing it only if it changed since the last call.
+func (m *ConfigMapModel) priceTable() (*PriceTable, error) {
+	cm, err := m.lister.Get(m.configMapName)
+	if err != nil {
+		return nil, fmt.Errorf("price table ConfigMap %s not found: %v", m.configMapName, err)
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.table != nil && m.resourceVersion == cm.ResourceVersion {
+		return m.table, nil
+	}
+	data, found := cm.Data[PriceTableConfigMapKey]
+	if !found {
+		return nil, fmt.Errorf("price table ConfigMap %s has no %q key", m.configMapName, PriceTableConfigMapKey)
+	}
+	table, err := ParsePriceTable([]byte(data))
+	if err != nil {
+		return nil, fmt.Errorf("price table ConfigMap %s: %v", m.configMapName, err)
+	}
+	m.table = table
+	m.resourceVersion = cm.ResourceVersion
+	return table, nil
+}
+
+var _ cloudprovider.PricingModel = &ConfigMapModel{}
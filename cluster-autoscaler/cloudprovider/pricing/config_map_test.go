@@ -0,0 +1,113 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/units"
+)
+
+func TestConfigMapModel(t *testing.T) {
+	start := time.Now()
+	end := start.Add(time.Hour)
+	node := BuildTestNode("n", 1000, units.GiB)
+	pod := BuildTestPod("p", 1000, units.GiB)
+
+	for name, tc := range map[string]struct {
+		configMaps []*apiv1.ConfigMap
+		nodePrice  float64
+		podPrice   float64
+		wantErr    bool
+	}{
+		"valid table": {
+			configMaps: []*apiv1.ConfigMap{{
+				ObjectMeta: metav1.ObjectMeta{Name: "prices", Namespace: "kube-system", ResourceVersion: "1"},
+				Data:       map[string]string{PriceTableConfigMapKey: "cpu: 1\nmemory: 0.5"},
+			}},
+			nodePrice: 1.5,
+			podPrice:  1.5,
+		},
+		"missing config map": {
+			wantErr: true,
+		},
+		"missing key": {
+			configMaps: []*apiv1.ConfigMap{{
+				ObjectMeta: metav1.ObjectMeta{Name: "prices", Namespace: "kube-system"},
+				Data:       map[string]string{"priorities": "cpu: 1\nmemory: 0.5"},
+			}},
+			wantErr: true,
+		},
+		"invalid table": {
+			configMaps: []*apiv1.ConfigMap{{
+				ObjectMeta: metav1.ObjectMeta{Name: "prices", Namespace: "kube-system"},
+				Data:       map[string]string{PriceTableConfigMapKey: "cpu: 1"},
+			}},
+			wantErr: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			lister, err := kubernetes.NewTestConfigMapLister(tc.configMaps)
+			assert.NoError(t, err)
+			model := NewConfigMapModel(lister.ConfigMaps("kube-system"), "prices")
+			nodePrice, err := model.NodePrice(node, start, end)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.InDelta(t, tc.nodePrice, nodePrice, 1e-9)
+			podPrice, err := model.PodPrice(pod, start, end)
+			assert.NoError(t, err)
+			assert.InDelta(t, tc.podPrice, podPrice, 1e-9)
+		})
+	}
+}
+
+func TestConfigMapModelReloadsOnChange(t *testing.T) {
+	start := time.Now()
+	end := start.Add(time.Hour)
+	node := BuildTestNode("n", 1000, units.GiB)
+	cm := &apiv1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "prices", Namespace: "kube-system", ResourceVersion: "1"},
+		Data:       map[string]string{PriceTableConfigMapKey: "cpu: 1\nmemory: 1"},
+	}
+	lister, err := kubernetes.NewTestConfigMapLister([]*apiv1.ConfigMap{cm})
+	assert.NoError(t, err)
+	model := NewConfigMapModel(lister.ConfigMaps("kube-system"), "prices")
+
+	price, err := model.NodePrice(node, start, end)
+	assert.NoError(t, err)
+	assert.InDelta(t, 2.0, price, 1e-9)
+
+	// The table is only parsed again once the ConfigMap version changes.
+	cm.Data[PriceTableConfigMapKey] = "cpu: 2\nmemory: 2"
+	price, err = model.NodePrice(node, start, end)
+	assert.NoError(t, err)
+	assert.InDelta(t, 2.0, price, 1e-9)
+
+	cm.ResourceVersion = "2"
+	price, err = model.NodePrice(node, start, end)
+	assert.NoError(t, err)
+	assert.InDelta(t, 4.0, price, 1e-9)
+}
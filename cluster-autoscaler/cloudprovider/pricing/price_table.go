@@ -0,0 +1,190 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pricing
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/gpu"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/units"
+	"sigs.k8s.io/yaml"
+)
+
+// PriceTable is a user supplied list of hourly prices. It implements
+// cloudprovider.PricingModel, so it can be used by cloud providers which
+// don't know the prices of their nodes.
+//
+// A node price is taken from the first of:
+//   - NodeGroupPrices, using the value of the NodeGroupLabel node label,
+//   - InstanceTypePrices, using the instance type node label,
+//   - CpuPrice, MemoryPrice and GpuPrice multiplied by the node capacity.
+//
+// Pod prices are always computed from their requests, using CpuPrice,
+// MemoryPrice and GpuPrice. If a node has all of the SpotLabels, its price is
+// multiplied by SpotPriceRatio.
+type PriceTable struct {
+	// InstanceTypePrices maps instance types to hourly node prices.
+	InstanceTypePrices map[string]float64 `json:"instanceTypes,omitempty"`
+	// NodeGroupLabel is the node label identifying node groups in NodeGroupPrices.
+	NodeGroupLabel string `json:"nodeGroupLabel,omitempty"`
+	// NodeGroupPrices maps values of NodeGroupLabel to hourly node prices.
+	NodeGroupPrices map[string]float64 `json:"nodeGroups,omitempty"`
+	// CpuPrice is the price of a single core for an hour.
+	CpuPrice float64 `json:"cpu"`
+	// MemoryPrice is the price of a GiB of memory for an hour.
+	MemoryPrice float64 `json:"memory"`
+	// GpuPrice is the price of a single GPU for an hour.
+	GpuPrice float64 `json:"gpu,omitempty"`
+	// GpuResourceName is the extended resource of GPUs, nvidia.com/gpu if not set.
+	GpuResourceName apiv1.ResourceName `json:"gpuResourceName,omitempty"`
+	// SpotLabels are the node labels (and their values) identifying spot or
+	// preemptible nodes.
+	SpotLabels map[string]string `json:"spotLabels,omitempty"`
+	// SpotPriceRatio is the fraction of the regular price paid for spot nodes.
+	SpotPriceRatio float64 `json:"spotPriceRatio,omitempty"`
+}
+
+// ParsePriceTable parses a price table in YAML or JSON format.
+func ParsePriceTable(data []byte) (*PriceTable, error) {
+	table := &PriceTable{}
+	if err := yaml.UnmarshalStrict(data, table); err != nil {
+		return nil, fmt.Errorf("failed to parse price table: %v", err)
+	}
+	if err := table.validate(); err != nil {
+		return nil, fmt.Errorf("invalid price table: %v", err)
+	}
+	return table, nil
+}
+
+// LoadPriceTable reads a price table from a file.
+func LoadPriceTable(path string) (*PriceTable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read price table %s: %v", path, err)
+	}
+	return ParsePriceTable(data)
+}
+
+func (t *PriceTable) validate() error {
+	if t.CpuPrice <= 0 || t.MemoryPrice <= 0 {
+		return fmt.Errorf("cpu and memory prices must be positive, got %v and %v", t.CpuPrice, t.MemoryPrice)
+	}
+	if t.GpuPrice < 0 {
+		return fmt.Errorf("gpu price can't be negative, got %v", t.GpuPrice)
+	}
+	for instanceType, price := range t.InstanceTypePrices {
+		if price < 0 {
+			return fmt.Errorf("price of instance type %s can't be negative, got %v", instanceType, price)
+		}
+	}
+	if len(t.NodeGroupPrices) > 0 && t.NodeGroupLabel == "" {
+		return fmt.Errorf("nodeGroupLabel is required when node group prices are set")
+	}
+	for nodeGroup, price := range t.NodeGroupPrices {
+		if price < 0 {
+			return fmt.Errorf("price of node group %s can't be negative, got %v", nodeGroup, price)
+		}
+	}
+	if t.SpotPriceRatio < 0 {
+		return fmt.Errorf("spot price ratio can't be negative, got %v", t.SpotPriceRatio)
+	}
+	if len(t.SpotLabels) > 0 && t.SpotPriceRatio == 0 {
+		return fmt.Errorf("spotPriceRatio is required when spot labels are set")
+	}
+	return nil
+}
+
+// NodePrice returns the price of running the node between startTime and endTime.
+func (t *PriceTable) NodePrice(node *apiv1.Node, startTime time.Time, endTime time.Time) (float64, error) {
+	hourlyPrice, found := t.listedNodePrice(node)
+	if !found {
+		hourlyPrice = t.resourcesPrice(node.Status.Capacity)
+	}
+	if t.isSpot(node) {
+		hourlyPrice *= t.SpotPriceRatio
+	}
+	return hourlyPrice * getHours(startTime, endTime), nil
+}
+
+// PodPrice returns a theoretical minimum price of running the pod between
+// startTime and endTime, based on its requests.
+func (t *PriceTable) PodPrice(pod *apiv1.Pod, startTime time.Time, endTime time.Time) (float64, error) {
+	hourlyPrice := 0.0
+	for _, container := range pod.Spec.Containers {
+		hourlyPrice += t.resourcesPrice(container.Resources.Requests)
+	}
+	return hourlyPrice * getHours(startTime, endTime), nil
+}
+
+func (t *PriceTable) listedNodePrice(node *apiv1.Node) (float64, bool) {
+	if t.NodeGroupLabel != "" {
+		if price, found := t.NodeGroupPrices[node.Labels[t.NodeGroupLabel]]; found {
+			return price, true
+		}
+	}
+	for _, label := range []string{apiv1.LabelInstanceTypeStable, apiv1.LabelInstanceType} {
+		if instanceType, found := node.Labels[label]; found {
+			if price, found := t.InstanceTypePrices[instanceType]; found {
+				return price, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func (t *PriceTable) resourcesPrice(resources apiv1.ResourceList) float64 {
+	price := 0.0
+	if cpu, found := resources[apiv1.ResourceCPU]; found {
+		price += float64(cpu.MilliValue()) / 1000.0 * t.CpuPrice
+	}
+	if memory, found := resources[apiv1.ResourceMemory]; found {
+		price += float64(memory.Value()) / float64(units.GiB) * t.MemoryPrice
+	}
+	if gpus, found := resources[t.gpuResourceName()]; found {
+		price += float64(gpus.Value()) * t.GpuPrice
+	}
+	return price
+}
+
+func (t *PriceTable) gpuResourceName() apiv1.ResourceName {
+	if t.GpuResourceName != "" {
+		return t.GpuResourceName
+	}
+	return gpu.ResourceNvidiaGPU
+}
+
+func (t *PriceTable) isSpot(node *apiv1.Node) bool {
+	if len(t.SpotLabels) == 0 {
+		return false
+	}
+	for label, value := range t.SpotLabels {
+		if node.Labels[label] != value {
+			return false
+		}
+	}
+	return true
+}
+
+func getHours(startTime time.Time, endTime time.Time) float64 {
+	return endTime.Sub(startTime).Hours()
+}
+
+var _ cloudprovider.PricingModel = &PriceTable{}
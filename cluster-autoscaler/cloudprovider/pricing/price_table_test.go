@@ -0,0 +1,159 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pricing
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/gpu"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/units"
+)
+
+const testTable = `
+instanceTypes:
+  cx21: 0.5
+nodeGroupLabel: pool
+nodeGroups:
+  expensive: 3.0
+cpu: 0.25
+memory: 0.125
+gpu: 2.0
+spotLabels:
+  lifecycle: spot
+spotPriceRatio: 0.25
+`
+
+func TestParsePriceTable(t *testing.T) {
+	table, err := ParsePriceTable([]byte(testTable))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]float64{"cx21": 0.5}, table.InstanceTypePrices)
+	assert.Equal(t, "pool", table.NodeGroupLabel)
+	assert.Equal(t, 0.25, table.CpuPrice)
+
+	for name, tc := range map[string]string{
+		"not yaml":              "cpu: [",
+		"unknown field":         "cpu: 1\nmemory: 1\nram: 1",
+		"missing cpu price":     "memory: 1",
+		"negative gpu price":    "cpu: 1\nmemory: 1\ngpu: -1",
+		"negative instance":     "cpu: 1\nmemory: 1\ninstanceTypes:\n  a: -1",
+		"node groups, no label": "cpu: 1\nmemory: 1\nnodeGroups:\n  a: 1",
+		"spot labels, no ratio": "cpu: 1\nmemory: 1\nspotLabels:\n  a: b",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParsePriceTable([]byte(tc))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadPriceTable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prices.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(testTable), 0644))
+	table, err := LoadPriceTable(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.125, table.MemoryPrice)
+
+	_, err = LoadPriceTable(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestPriceTableNodePrice(t *testing.T) {
+	table, err := ParsePriceTable([]byte(testTable))
+	assert.NoError(t, err)
+	start := time.Now()
+	end := start.Add(2 * time.Hour)
+
+	withLabels := func(node *apiv1.Node, labels map[string]string) *apiv1.Node {
+		node.Labels = labels
+		return node
+	}
+	gpuNode := BuildTestNode("gpu", 2000, 2*units.GiB)
+	gpuNode.Status.Capacity[gpu.ResourceNvidiaGPU] = *resource.NewQuantity(2, resource.DecimalSI)
+
+	for name, tc := range map[string]struct {
+		node  *apiv1.Node
+		price float64
+	}{
+		"from resources": {
+			node:  BuildTestNode("n", 2000, 4*units.GiB),
+			price: 2 * (2*0.25 + 4*0.125),
+		},
+		"gpu surcharge": {
+			node:  gpuNode,
+			price: 2 * (2*0.25 + 2*0.125 + 2*2.0),
+		},
+		"instance type": {
+			node:  withLabels(BuildTestNode("n", 2000, 4*units.GiB), map[string]string{apiv1.LabelInstanceTypeStable: "cx21"}),
+			price: 2 * 0.5,
+		},
+		"legacy instance type label": {
+			node:  withLabels(BuildTestNode("n", 2000, 4*units.GiB), map[string]string{apiv1.LabelInstanceType: "cx21"}),
+			price: 2 * 0.5,
+		},
+		"unknown instance type": {
+			node:  withLabels(BuildTestNode("n", 2000, 4*units.GiB), map[string]string{apiv1.LabelInstanceTypeStable: "cx31"}),
+			price: 2 * (2*0.25 + 4*0.125),
+		},
+		"node group takes precedence": {
+			node:  withLabels(BuildTestNode("n", 2000, 4*units.GiB), map[string]string{apiv1.LabelInstanceTypeStable: "cx21", "pool": "expensive"}),
+			price: 2 * 3.0,
+		},
+		"spot": {
+			node:  withLabels(BuildTestNode("n", 2000, 4*units.GiB), map[string]string{apiv1.LabelInstanceTypeStable: "cx21", "lifecycle": "spot"}),
+			price: 2 * 0.5 * 0.25,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			price, err := table.NodePrice(tc.node, start, end)
+			assert.NoError(t, err)
+			assert.InDelta(t, tc.price, price, 1e-9)
+		})
+	}
+}
+
+func TestPriceTablePodPrice(t *testing.T) {
+	table, err := ParsePriceTable([]byte(testTable))
+	assert.NoError(t, err)
+	start := time.Now()
+	end := start.Add(time.Hour)
+
+	pod := BuildTestPod("p", 500, 2*units.GiB)
+	price, err := table.PodPrice(pod, start, end)
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.5*0.25+2*0.125, price, 1e-9)
+
+	RequestGpuForPod(pod, 1)
+	price, err = table.PodPrice(pod, start, end)
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.5*0.25+2*0.125+2.0, price, 1e-9)
+
+	// A pod requesting the whole node costs as much as the node.
+	node := BuildTestNode("n", 500, 2*units.GiB)
+	nodePrice, err := table.NodePrice(node, start, end)
+	assert.NoError(t, err)
+	pod = BuildTestPod("p", 500, 2*units.GiB)
+	podPrice, err := table.PodPrice(pod, start, end)
+	assert.NoError(t, err)
+	assert.InDelta(t, nodePrice, podPrice, 1e-9)
+}
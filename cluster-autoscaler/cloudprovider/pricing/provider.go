@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pricing
+
+import (
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/errors"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
+	kube_client "k8s.io/client-go/kubernetes"
+)
+
+// fallbackCloudProvider is a cloud provider using a fallback pricing model
+// when the wrapped provider doesn't provide one.
+type fallbackCloudProvider struct {
+	cloudprovider.CloudProvider
+	fallback cloudprovider.PricingModel
+}
+
+// WithFallbackPricing wraps the cloud provider so that Pricing() returns the
+// fallback model whenever the provider's own pricing model is not available.
+// The provider is returned as is if the fallback model is nil.
+func WithFallbackPricing(provider cloudprovider.CloudProvider, fallback cloudprovider.PricingModel) cloudprovider.CloudProvider {
+	if fallback == nil {
+		return provider
+	}
+	return &fallbackCloudProvider{
+		CloudProvider: provider,
+		fallback:      fallback,
+	}
+}
+
+// Pricing returns the pricing model of the wrapped provider, or the fallback
+// model if the provider doesn't implement pricing.
+func (p *fallbackCloudProvider) Pricing() (cloudprovider.PricingModel, errors.AutoscalerError) {
+	model, err := p.CloudProvider.Pricing()
+	if err != nil || model == nil {
+		return p.fallback, nil
+	}
+	return model, nil
+}
+
+// NewFallbackModel returns the pricing model configured by the options: a
+// price table read from PricingConfigFile, or one read from the
+// PricingConfigMapName ConfigMap in ConfigNamespace. It returns nil if
+// neither is set.
+func NewFallbackModel(opts config.AutoscalingOptions, kubeClient kube_client.Interface) (cloudprovider.PricingModel, error) {
+	if opts.PricingConfigFile != "" {
+		table, err := LoadPriceTable(opts.PricingConfigFile)
+		if err != nil {
+			return nil, err
+		}
+		return table, nil
+	}
+	if opts.PricingConfigMapName != "" {
+		// The lister runs for the whole lifetime of the process, same as the
+		// one used by the priority expander.
+		stopChannel := make(chan struct{})
+		lister := kubernetes.NewConfigMapListerForNamespace(kubeClient, stopChannel, opts.ConfigNamespace)
+		return NewConfigMapModel(lister.ConfigMaps(opts.ConfigNamespace), opts.PricingConfigMapName), nil
+	}
+	return nil, nil
+}
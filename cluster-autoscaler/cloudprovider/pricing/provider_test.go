@@ -0,0 +1,65 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pricing
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+)
+
+func TestWithFallbackPricing(t *testing.T) {
+	table := &PriceTable{CpuPrice: 1, MemoryPrice: 1}
+
+	provider := testprovider.NewTestCloudProvider(nil, nil)
+	_, err := provider.Pricing()
+	assert.Error(t, err)
+	assert.Equal(t, provider, WithFallbackPricing(provider, nil))
+
+	wrapped := WithFallbackPricing(provider, table)
+	model, err := wrapped.Pricing()
+	assert.NoError(t, err)
+	assert.Equal(t, table, model)
+	assert.Equal(t, provider.Name(), wrapped.Name())
+
+	providerModel := &PriceTable{CpuPrice: 2, MemoryPrice: 2}
+	pricedProvider := testprovider.NewTestAutoprovisioningCloudProvider(nil, nil, nil, nil, nil, nil)
+	pricedProvider.SetPricingModel(providerModel)
+	model, err = WithFallbackPricing(pricedProvider, table).Pricing()
+	assert.NoError(t, err)
+	assert.Equal(t, providerModel, model)
+}
+
+func TestNewFallbackModel(t *testing.T) {
+	model, err := NewFallbackModel(config.AutoscalingOptions{}, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, model)
+
+	path := filepath.Join(t.TempDir(), "prices.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(testTable), 0644))
+	model, err = NewFallbackModel(config.AutoscalingOptions{PricingConfigFile: path}, nil)
+	assert.NoError(t, err)
+	assert.IsType(t, &PriceTable{}, model)
+
+	model, err = NewFallbackModel(config.AutoscalingOptions{PricingConfigFile: filepath.Join(t.TempDir(), "missing.yaml")}, nil)
+	assert.Error(t, err)
+	assert.Nil(t, model)
+}
@@ -113,6 +113,9 @@ type NodeGroupHealthCondition struct {
 	MinSize int `json:"minSize" yaml:"minSize"`
 	// MaxSize is the CA max size of a node group.
 	MaxSize int `json:"maxSize" yaml:"maxSize"`
+	// ScheduledMinSize is the min size of a node group raised by an active min size schedule.
+	// It's not set if no schedule raises the min size of the node group.
+	ScheduledMinSize int `json:"scheduledMinSize,omitempty" yaml:"scheduledMinSize,omitempty"`
 	// LastProbeTime is the last time we probed the condition.
 	LastProbeTime metav1.Time `json:"lastProbeTime,omitempty" yaml:"lastProbeTime,omitempty"`
 	// LastTransitionTime is the time since when the condition was in the given state.
@@ -195,4 +198,68 @@ type ClusterAutoscalerStatus struct {
 	ClusterWide ClusterWideStatus `json:"clusterWide,omitempty" yaml:"clusterWide,omitempty"`
 	// NodeGroups contains status information of individual node groups on which CA works.
 	NodeGroups []NodeGroupStatus `json:"nodeGroups,omitempty" yaml:"nodeGroups,omitempty"`
+	// DryRunActions contains the most recent actions which CA would have executed if it wasn't running in dry-run mode.
+	DryRunActions []DryRunAction `json:"dryRunActions,omitempty" yaml:"dryRunActions,omitempty"`
+	// MaintenanceWindows contains the maintenance windows which are currently active.
+	MaintenanceWindows []MaintenanceWindowStatus `json:"maintenanceWindows,omitempty" yaml:"maintenanceWindows,omitempty"`
+	// DisruptionBudgets contains the state of the scale-down disruption budgets which currently apply.
+	DisruptionBudgets []DisruptionBudgetStatus `json:"disruptionBudgets,omitempty" yaml:"disruptionBudgets,omitempty"`
+}
+
+// DisruptionBudgetStatus is the state of a scale-down disruption budget in one of its domains.
+type DisruptionBudgetStatus struct {
+	// Name of the budget.
+	Name string `json:"name" yaml:"name"`
+	// NodeGroup is the node group the status applies to, for budgets applying to each node group separately.
+	NodeGroup string `json:"nodeGroup,omitempty" yaml:"nodeGroup,omitempty"`
+	// Nodes is the number of nodes the budget applies to.
+	Nodes int `json:"nodes" yaml:"nodes"`
+	// Allowed is the maximum number of these nodes which can be deleted at once.
+	Allowed int `json:"allowed" yaml:"allowed"`
+	// Disrupting is the number of these nodes being deleted.
+	Disrupting int `json:"disrupting" yaml:"disrupting"`
+}
+
+// MaintenanceWindowStatus is an active maintenance window suspending actuation.
+type MaintenanceWindowStatus struct {
+	// Name of the window.
+	Name string `json:"name" yaml:"name"`
+	// Freeze is the kind of actuation suspended, "ScaleDown" or "All".
+	Freeze string `json:"freeze" yaml:"freeze"`
+	// NodeGroups are the patterns of node group ids the window applies to, empty if it applies to the whole cluster.
+	NodeGroups []string `json:"nodeGroups,omitempty" yaml:"nodeGroups,omitempty"`
+	// Since is the time when Cluster Autoscaler noticed the window started.
+	Since metav1.Time `json:"since,omitempty" yaml:"since,omitempty"`
+}
+
+// DryRunActionType is the type of an action recorded instead of executed in dry-run mode.
+type DryRunActionType string
+
+const (
+	// DryRunCreateNodeGroup means a new node group would have been created.
+	DryRunCreateNodeGroup DryRunActionType = "CreateNodeGroup"
+	// DryRunIncreaseSize means the size of a node group would have been increased.
+	DryRunIncreaseSize DryRunActionType = "IncreaseSize"
+	// DryRunDecreaseTargetSize means the target size of a node group would have been decreased.
+	DryRunDecreaseTargetSize DryRunActionType = "DecreaseTargetSize"
+	// DryRunDeleteNodes means nodes would have been tainted, drained and deleted from a node group.
+	DryRunDeleteNodes DryRunActionType = "DeleteNodes"
+)
+
+// DryRunAction is an action recorded instead of executed in dry-run mode.
+type DryRunAction struct {
+	// Type of the action.
+	Type DryRunActionType `json:"type" yaml:"type"`
+	// Time when the action was recorded.
+	Time metav1.Time `json:"time,omitempty" yaml:"time,omitempty"`
+	// NodeGroup is the id of the node group the action applies to.
+	NodeGroup string `json:"nodeGroup,omitempty" yaml:"nodeGroup,omitempty"`
+	// Delta is the change of the node group size, for IncreaseSize and DecreaseTargetSize.
+	Delta int `json:"delta,omitempty" yaml:"delta,omitempty"`
+	// Nodes are the names of the nodes which would have been deleted.
+	Nodes []string `json:"nodes,omitempty" yaml:"nodes,omitempty"`
+	// EvictedPods are the pods (namespace/name) which would have been evicted while draining Nodes.
+	EvictedPods []string `json:"evictedPods,omitempty" yaml:"evictedPods,omitempty"`
+	// Reason why the action would have been executed, e.g. "ScaleDown" or "Unregistered".
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
 }
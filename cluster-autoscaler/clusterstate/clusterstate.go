@@ -45,8 +45,12 @@ import (
 const (
 	// MaxNodeStartupTime is the maximum time from the moment the node is registered to the time the node is ready.
 	MaxNodeStartupTime = 15 * time.Minute
+	// maxFailedScaleUpHistory is the number of failed scale-ups remembered for each node group.
+	maxFailedScaleUpHistory = 10
 	// maxErrorMessageSize is the maximum size of error messages displayed in config map as the max size of configmap is 1MB.
 	maxErrorMessageSize = 500
+	// NodeInterruptedErrorCode is the error code of node group backoffs caused by node interruptions.
+	NodeInterruptedErrorCode = "NODE_INTERRUPTED"
 	// messageTrancated is displayed at the end of a trancated message.
 	messageTrancated = "<truncated>"
 )
@@ -86,6 +90,9 @@ type ClusterStateRegistryConfig struct {
 	// Minimum number of nodes that must be unready for MaxTotalUnreadyPercentage to apply.
 	// This is to ensure that in very small clusters (e.g. 2 nodes) a single node's failure doesn't disable autoscaling.
 	OkTotalUnreadyCount int
+	// InterruptionTaints are the taints marking nodes about to be interrupted. Nodes removed while
+	// having one of them are registered as interruptions of their node group.
+	InterruptionTaints []string
 }
 
 // IncorrectNodeGroupSize contains information about how much the current size of the node group
@@ -116,6 +123,13 @@ type ScaleUpFailure struct {
 	Time      time.Time
 }
 
+// FailedScaleUp is an entry of the failed scale-up history of a node group.
+type FailedScaleUp struct {
+	Reason       metrics.FailedScaleUpReason `json:"reason"`
+	ErrorMessage string                      `json:"errorMessage,omitempty"`
+	Time         time.Time                   `json:"time"`
+}
+
 // ClusterStateRegistry is a structure to keep track the current state of the cluster.
 type ClusterStateRegistry struct {
 	sync.Mutex
@@ -131,6 +145,7 @@ type ClusterStateRegistry struct {
 	incorrectNodeGroupSizes            map[string]IncorrectNodeGroupSize
 	unregisteredNodes                  map[string]UnregisteredNode
 	deletedNodes                       map[string]struct{}
+	interruptedNodes                   map[string]struct{}
 	candidatesForScaleDown             map[string][]string
 	backoff                            backoff.Backoff
 	lastStatus                         *api.ClusterAutoscalerStatus
@@ -145,6 +160,9 @@ type ClusterStateRegistry struct {
 	// scaleUpFailures contains information about scale-up failures for each node group. It should be
 	// cleared periodically to avoid unnecessary accumulation.
 	scaleUpFailures map[string][]ScaleUpFailure
+	// failedScaleUpHistory contains the last failed scale-ups of each node group. Unlike
+	// scaleUpFailures it isn't cleared and is persisted across restarts.
+	failedScaleUpHistory map[string][]FailedScaleUp
 }
 
 // NodeGroupScalingSafety contains information about the safety of the node group to scale up/down.
@@ -167,6 +185,7 @@ func NewClusterStateRegistry(cloudProvider cloudprovider.CloudProvider, config C
 		incorrectNodeGroupSizes:         make(map[string]IncorrectNodeGroupSize),
 		unregisteredNodes:               make(map[string]UnregisteredNode),
 		deletedNodes:                    make(map[string]struct{}),
+		interruptedNodes:                make(map[string]struct{}),
 		candidatesForScaleDown:          make(map[string][]string),
 		backoff:                         backoff,
 		lastStatus:                      utils.EmptyClusterAutoscalerStatus(),
@@ -174,6 +193,7 @@ func NewClusterStateRegistry(cloudProvider cloudprovider.CloudProvider, config C
 		cloudProviderNodeInstancesCache: utils.NewCloudProviderNodeInstancesCache(cloudProvider),
 		interrupt:                       make(chan struct{}),
 		scaleUpFailures:                 make(map[string][]ScaleUpFailure),
+		failedScaleUpHistory:            make(map[string][]FailedScaleUp),
 		nodeGroupConfigProcessor:        nodeGroupConfigProcessor,
 	}
 }
@@ -327,6 +347,11 @@ func (csr *ClusterStateRegistry) RegisterFailedScaleDown(_ cloudprovider.NodeGro
 
 func (csr *ClusterStateRegistry) registerFailedScaleUpNoLock(nodeGroup cloudprovider.NodeGroup, reason metrics.FailedScaleUpReason, errorInfo cloudprovider.InstanceErrorInfo, gpuResourceName, gpuType string, currentTime time.Time) {
 	csr.scaleUpFailures[nodeGroup.Id()] = append(csr.scaleUpFailures[nodeGroup.Id()], ScaleUpFailure{NodeGroup: nodeGroup, Reason: reason, Time: currentTime})
+	csr.registerFailedScaleUpHistoryNoLock(nodeGroup.Id(), FailedScaleUp{
+		Reason:       reason,
+		ErrorMessage: truncateIfExceedMaxLength(errorInfo.ErrorMessage, maxErrorMessageSize),
+		Time:         currentTime,
+	})
 	metrics.RegisterFailedScaleUp(reason, gpuResourceName, gpuType)
 	csr.backoffNodeGroup(nodeGroup, errorInfo, currentTime)
 }
@@ -350,6 +375,9 @@ func (csr *ClusterStateRegistry) UpdateNodes(nodes []*apiv1.Node, nodeInfosForGr
 	csr.Lock()
 	defer csr.Unlock()
 
+	// Interruptions are found by comparing with the previous state, so they
+	// have to be registered before it's overwritten.
+	csr.updateInterruptedNodes(nodes, cloudProviderNodesRemoved, currentTime)
 	csr.nodes = nodes
 	csr.nodeInfosForGroups = nodeInfosForGroups
 	csr.previousCloudProviderNodeInstances = csr.cloudProviderNodeInstances
@@ -785,6 +813,7 @@ func (csr *ClusterStateRegistry) GetStatus(now time.Time) *api.ClusterAutoscaler
 		// Health.
 		nodeGroupStatus.Health = buildHealthStatusNodeGroup(
 			csr.IsNodeGroupHealthy(nodeGroup.Id()), readiness, acceptable, nodeGroup.MinSize(), nodeGroup.MaxSize(), nodeGroupLastStatus.Health)
+		nodeGroupStatus.Health.ScheduledMinSize = csr.scheduledMinSize(nodeGroup, now)
 
 		// Scale up.
 		nodeGroupStatus.ScaleUp = csr.buildScaleUpStatusNodeGroup(
@@ -809,6 +838,23 @@ func (csr *ClusterStateRegistry) GetStatus(now time.Time) *api.ClusterAutoscaler
 	return result
 }
 
+// scheduledMinSize returns the min size of the node group if it's raised by
+// an active min size schedule, 0 otherwise.
+func (csr *ClusterStateRegistry) scheduledMinSize(nodeGroup cloudprovider.NodeGroup, now time.Time) int {
+	if csr.nodeGroupConfigProcessor == nil {
+		return 0
+	}
+	minSize, err := csr.nodeGroupConfigProcessor.GetMinSize(nodeGroup, now)
+	if err != nil {
+		klog.Warningf("Failed to get min size for node group %s: %v", nodeGroup.Id(), err)
+		return 0
+	}
+	if minSize <= nodeGroup.MinSize() {
+		return 0
+	}
+	return minSize
+}
+
 // GetClusterReadiness returns current readiness stats of cluster
 func (csr *ClusterStateRegistry) GetClusterReadiness() Readiness {
 	return csr.totalReadiness
@@ -1058,6 +1104,96 @@ func (csr *ClusterStateRegistry) hasCloudProviderInstance(node *apiv1.Node) bool
 	return !taints.HasToBeDeletedTaint(node)
 }
 
+// updateInterruptedNodes registers an interruption for every node which had one of the
+// interruption taints and got removed, either from the cluster or from the cloud provider.
+// Nodes being deleted by Cluster Autoscaler aren't interruptions. To be executed under a lock.
+func (csr *ClusterStateRegistry) updateInterruptedNodes(nodes []*apiv1.Node, cloudProviderNodesRemoved []*apiv1.Node, currentTime time.Time) {
+	if len(csr.config.InterruptionTaints) == 0 {
+		return
+	}
+	currentNodes := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		currentNodes[node.Name] = true
+	}
+	removedNodes := append([]*apiv1.Node{}, cloudProviderNodesRemoved...)
+	for _, node := range csr.nodes {
+		if !currentNodes[node.Name] {
+			removedNodes = append(removedNodes, node)
+		}
+	}
+
+	for _, node := range removedNodes {
+		if _, found := csr.interruptedNodes[node.Name]; found || !csr.isInterrupted(node) {
+			continue
+		}
+		csr.interruptedNodes[node.Name] = struct{}{}
+		nodeGroup := csr.nodeGroupForRemovedNode(node)
+		if nodeGroup == nil || reflect.ValueOf(nodeGroup).IsNil() {
+			continue
+		}
+		errorInfo := cloudprovider.InstanceErrorInfo{
+			ErrorClass:   cloudprovider.OutOfResourcesErrorClass,
+			ErrorCode:    NodeInterruptedErrorCode,
+			ErrorMessage: fmt.Sprintf("node %s was interrupted", node.Name),
+		}
+		csr.registerInterruptionNoLock(nodeGroup, errorInfo, currentTime)
+	}
+
+	// Nodes are remembered until they're gone from the cluster, so that nodes removed
+	// from the cloud provider first aren't counted twice.
+	for name := range csr.interruptedNodes {
+		if !currentNodes[name] {
+			delete(csr.interruptedNodes, name)
+		}
+	}
+}
+
+func (csr *ClusterStateRegistry) isInterrupted(node *apiv1.Node) bool {
+	if taints.HasToBeDeletedTaint(node) {
+		return false
+	}
+	for _, taintKey := range csr.config.InterruptionTaints {
+		if taints.HasTaint(node, taintKey) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeGroupForRemovedNode finds the node group of a node whose instance may be already
+// gone from the cloud provider, using the instances listed in the previous loop.
+func (csr *ClusterStateRegistry) nodeGroupForRemovedNode(node *apiv1.Node) cloudprovider.NodeGroup {
+	for nodeGroupId, instances := range csr.cloudProviderNodeInstances {
+		for _, instance := range instances {
+			if instance.Id != node.Spec.ProviderID {
+				continue
+			}
+			for _, nodeGroup := range csr.cloudProvider.NodeGroups() {
+				if nodeGroup.Id() == nodeGroupId {
+					return nodeGroup
+				}
+			}
+		}
+	}
+	nodeGroup, err := csr.cloudProvider.NodeGroupForNode(node)
+	if err != nil {
+		klog.V(4).Infof("Failed to find node group of interrupted node %s: %v", node.Name, err)
+		return nil
+	}
+	return nodeGroup
+}
+
+// To be executed under a lock.
+func (csr *ClusterStateRegistry) registerInterruptionNoLock(nodeGroup cloudprovider.NodeGroup, errorInfo cloudprovider.InstanceErrorInfo, currentTime time.Time) {
+	nodeGroupInfo := csr.nodeInfosForGroups[nodeGroup.Id()]
+	status := csr.backoff.RegisterInterruption(nodeGroup, nodeGroupInfo, errorInfo, currentTime)
+	interruptions := csr.backoff.InterruptionCount(nodeGroup, nodeGroupInfo, currentTime)
+	klog.V(1).Infof("Registered interruption of node group %v (%d recent interruptions): %v", nodeGroup.Id(), interruptions, errorInfo.ErrorMessage)
+	if status.IsBackedOff {
+		klog.Warningf("Disabling scale-up for node group %v after %d recent interruptions; errorClass=%v; errorCode=%v", nodeGroup.Id(), interruptions, status.ErrorInfo.ErrorClass, status.ErrorInfo.ErrorCode)
+	}
+}
+
 // GetAutoscaledNodesCount calculates and returns the actual and the target number of nodes
 // belonging to autoscaled node groups in the cluster.
 func (csr *ClusterStateRegistry) GetAutoscaledNodesCount() (currentSize, targetSize int) {
@@ -1136,11 +1272,17 @@ func (csr *ClusterStateRegistry) handleInstanceCreationErrorsForNodeGroup(
 			// Decrease the scale up request by the number of deleted nodes
 			csr.registerOrUpdateScaleUpNoLock(nodeGroup, -len(unseenInstanceIds), currentTime)
 
-			csr.registerFailedScaleUpNoLock(nodeGroup, metrics.FailedScaleUpReason(errorCode.code), cloudprovider.InstanceErrorInfo{
+			errorInfo := cloudprovider.InstanceErrorInfo{
 				ErrorClass:   errorCode.class,
 				ErrorCode:    errorCode.code,
 				ErrorMessage: csr.buildErrorMessageEventString(currentUniqueErrorMessagesForErrorCode[errorCode]),
-			}, gpuResource, gpuType, currentTime)
+			}
+			csr.registerFailedScaleUpNoLock(nodeGroup, metrics.FailedScaleUpReason(errorCode.code), errorInfo, gpuResource, gpuType, currentTime)
+			// Out of capacity errors are a sign of an unstable pool, they count towards
+			// the interruptions of the node group.
+			if errorCode.class == cloudprovider.OutOfResourcesErrorClass {
+				csr.registerInterruptionNoLock(nodeGroup, errorInfo, currentTime)
+			}
 		}
 	}
 }
@@ -1266,6 +1408,26 @@ func (csr *ClusterStateRegistry) GetScaleUpFailures() map[string][]ScaleUpFailur
 	return result
 }
 
+// To be executed under a lock.
+func (csr *ClusterStateRegistry) registerFailedScaleUpHistoryNoLock(nodeGroupId string, failure FailedScaleUp) {
+	history := append(csr.failedScaleUpHistory[nodeGroupId], failure)
+	if len(history) > maxFailedScaleUpHistory {
+		history = history[len(history)-maxFailedScaleUpHistory:]
+	}
+	csr.failedScaleUpHistory[nodeGroupId] = history
+}
+
+// GetFailedScaleUpHistory returns the last failed scale-ups of each node group, oldest first.
+func (csr *ClusterStateRegistry) GetFailedScaleUpHistory() map[string][]FailedScaleUp {
+	csr.Lock()
+	defer csr.Unlock()
+	result := make(map[string][]FailedScaleUp)
+	for nodeGroupId, history := range csr.failedScaleUpHistory {
+		result[nodeGroupId] = append([]FailedScaleUp{}, history...)
+	}
+	return result
+}
+
 func truncateIfExceedMaxLength(s string, maxLength int) string {
 	if len(s) <= maxLength {
 		return s
@@ -135,6 +135,42 @@ func TestEmptyOK(t *testing.T) {
 	assert.True(t, clusterstate.HasNodeGroupStartedScaleUp("ng1"))
 }
 
+func TestScheduledMinSizeStatus(t *testing.T) {
+	// Monday, 10:00 UTC.
+	now := time.Date(2024, time.January, 8, 10, 0, 0, 0, time.UTC)
+
+	provider := testprovider.NewTestCloudProvider(nil, nil)
+	provider.AddNodeGroup("ng1", 1, 10, 1)
+	provider.AddNodeGroup("ng2", 1, 10, 1)
+	provider.GetNodeGroup("ng1").(*testprovider.TestNodeGroup).SetOptions(&config.NodeGroupAutoscalingOptions{
+		MinSizeSchedules: []config.MinSizeSchedule{{Days: []time.Weekday{time.Monday}, Start: 8 * time.Hour, End: 18 * time.Hour, MinSize: 5}},
+	})
+	provider.GetNodeGroup("ng2").(*testprovider.TestNodeGroup).SetOptions(&config.NodeGroupAutoscalingOptions{
+		MinSizeSchedules: []config.MinSizeSchedule{{Days: []time.Weekday{time.Tuesday}, Start: 8 * time.Hour, End: 18 * time.Hour, MinSize: 5}},
+	})
+
+	fakeClient := &fake.Clientset{}
+	fakeLogRecorder, _ := utils.NewStatusMapRecorder(fakeClient, "kube-system", kube_record.NewFakeRecorder(5), false, "my-cool-configmap")
+	clusterstate := NewClusterStateRegistry(provider, ClusterStateRegistryConfig{
+		MaxTotalUnreadyPercentage: 10,
+		OkTotalUnreadyCount:       1,
+	}, fakeLogRecorder, newBackoff(), nodegroupconfig.NewDefaultNodeGroupConfigProcessor(config.NodeGroupAutoscalingOptions{MaxNodeProvisionTime: time.Minute}))
+	err := clusterstate.UpdateNodes([]*apiv1.Node{}, nil, now)
+	assert.NoError(t, err)
+
+	status := clusterstate.GetStatus(now)
+	assert.Equal(t, 2, len(status.NodeGroups))
+	for _, ng := range status.NodeGroups {
+		assert.Equal(t, 1, ng.Health.MinSize)
+		switch ng.Name {
+		case "ng1":
+			assert.Equal(t, 5, ng.Health.ScheduledMinSize)
+		case "ng2":
+			assert.Equal(t, 0, ng.Health.ScheduledMinSize)
+		}
+	}
+}
+
 func TestHasNodeGroupStartedScaleUp(t *testing.T) {
 	tests := map[string]struct {
 		initialSize int
@@ -1090,6 +1126,66 @@ func TestScaleUpFailures(t *testing.T) {
 	assert.Empty(t, clusterstate.GetScaleUpFailures())
 }
 
+func TestNodeInterruptions(t *testing.T) {
+	now := time.Now()
+	interruptionTaint := apiv1.Taint{Key: "aws-node-termination-handler/spot-itn", Effect: apiv1.TaintEffectNoSchedule}
+
+	var nodes []*apiv1.Node
+	provider := testprovider.NewTestCloudProvider(nil, nil)
+	provider.AddNodeGroup("spot", 0, 10, 4)
+	for _, name := range []string{"spot-1", "spot-2", "spot-3", "spot-4"} {
+		node := BuildTestNode(name, 1000, 1000)
+		SetNodeReadyState(node, true, now.Add(-time.Hour))
+		provider.AddNode("spot", node)
+		nodes = append(nodes, node)
+	}
+	spot := provider.GetNodeGroup("spot")
+
+	fakeClient := &fake.Clientset{}
+	fakeLogRecorder, _ := utils.NewStatusMapRecorder(fakeClient, "kube-system", kube_record.NewFakeRecorder(5), false, "my-cool-configmap")
+	nodeGroupBackoff := backoff.NewIdBasedExponentialBackoffWithInterruptions(5*time.Minute, 30*time.Minute, 3*time.Hour,
+		backoff.InterruptionPolicy{Window: time.Hour, Threshold: 2})
+	clusterstate := NewClusterStateRegistry(provider, ClusterStateRegistryConfig{
+		MaxTotalUnreadyPercentage: 10,
+		OkTotalUnreadyCount:       1,
+		InterruptionTaints:        []string{interruptionTaint.Key},
+	}, fakeLogRecorder, nodeGroupBackoff, nodegroupconfig.NewDefaultNodeGroupConfigProcessor(config.NodeGroupAutoscalingOptions{MaxNodeProvisionTime: 15 * time.Minute}))
+	assert.NoError(t, clusterstate.UpdateNodes(nodes, nil, now))
+
+	// A node removed without an interruption taint isn't an interruption.
+	provider.DeleteNode(nodes[0])
+	assert.NoError(t, clusterstate.UpdateNodes(nodes[1:], nil, now))
+	assert.Equal(t, 0, nodeGroupBackoff.InterruptionCount(spot, nil, now))
+
+	// Neither is a node deleted by Cluster Autoscaler.
+	nodes[1].Spec.Taints = []apiv1.Taint{interruptionTaint, {Key: taints.ToBeDeletedTaint, Effect: apiv1.TaintEffectNoSchedule}}
+	assert.NoError(t, clusterstate.UpdateNodes(nodes[1:], nil, now))
+	provider.DeleteNode(nodes[1])
+	assert.NoError(t, clusterstate.UpdateNodes(nodes[2:], nil, now))
+	assert.Equal(t, 0, nodeGroupBackoff.InterruptionCount(spot, nil, now))
+
+	// An interrupted node is counted once, when its instance is gone and again
+	// when the node object is removed.
+	nodes[2].Spec.Taints = []apiv1.Taint{interruptionTaint}
+	assert.NoError(t, clusterstate.UpdateNodes(nodes[2:], nil, now))
+	provider.DeleteNode(nodes[2])
+	assert.NoError(t, clusterstate.UpdateNodes(nodes[2:], nil, now))
+	assert.Equal(t, 1, nodeGroupBackoff.InterruptionCount(spot, nil, now))
+	assert.False(t, clusterstate.NodeGroupScaleUpSafety(spot, now).BackoffStatus.IsBackedOff)
+	assert.NoError(t, clusterstate.UpdateNodes(nodes[3:], nil, now))
+	assert.Equal(t, 1, nodeGroupBackoff.InterruptionCount(spot, nil, now))
+
+	// The node group is backed off once it reaches the threshold.
+	nodes[3].Spec.Taints = []apiv1.Taint{interruptionTaint}
+	assert.NoError(t, clusterstate.UpdateNodes(nodes[3:], nil, now))
+	provider.DeleteNode(nodes[3])
+	assert.NoError(t, clusterstate.UpdateNodes(nil, nil, now))
+	assert.Equal(t, 2, nodeGroupBackoff.InterruptionCount(spot, nil, now))
+	backoffStatus := clusterstate.NodeGroupScaleUpSafety(spot, now).BackoffStatus
+	assert.True(t, backoffStatus.IsBackedOff)
+	assert.Equal(t, NodeInterruptedErrorCode, backoffStatus.ErrorInfo.ErrorCode)
+}
+
 func newBackoff() backoff.Backoff {
 	return backoff.NewIdBasedExponentialBackoff(5*time.Minute, /*InitialNodeGroupBackoffDuration*/
 		30*time.Minute /*MaxNodeGroupBackoffDuration*/, 3*time.Hour /*NodeGroupBackoffResetTimeout*/)
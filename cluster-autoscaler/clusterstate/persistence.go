@@ -0,0 +1,142 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterstate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/autoscaler/cluster-autoscaler/clusterstate/statestore"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/backoff"
+
+	klog "k8s.io/klog/v2"
+)
+
+// PersistedState is the part of the ClusterStateRegistry state which survives
+// restarts and leader failovers.
+type PersistedState struct {
+	// Backoff is the state of the node group backoff, if it can be persisted.
+	Backoff *backoff.State `json:"backoff,omitempty"`
+	// FailedScaleUpHistory contains the last failed scale-ups of each node group.
+	FailedScaleUpHistory map[string][]FailedScaleUp `json:"failedScaleUpHistory,omitempty"`
+}
+
+// PersistedState returns the current state to persist.
+func (csr *ClusterStateRegistry) PersistedState() PersistedState {
+	csr.Lock()
+	defer csr.Unlock()
+	state := PersistedState{
+		FailedScaleUpHistory: make(map[string][]FailedScaleUp, len(csr.failedScaleUpHistory)),
+	}
+	if persistable, ok := csr.backoff.(backoff.Persistable); ok {
+		backoffState := persistable.State()
+		state.Backoff = &backoffState
+	}
+	for nodeGroupId, history := range csr.failedScaleUpHistory {
+		state.FailedScaleUpHistory[nodeGroupId] = append([]FailedScaleUp{}, history...)
+	}
+	return state
+}
+
+// RestoreState restores a previously persisted state. The state collected since
+// the start takes precedence over the restored one.
+func (csr *ClusterStateRegistry) RestoreState(state PersistedState) {
+	csr.Lock()
+	defer csr.Unlock()
+	if persistable, ok := csr.backoff.(backoff.Persistable); ok && state.Backoff != nil {
+		persistable.RestoreState(*state.Backoff)
+	}
+	for nodeGroupId, history := range state.FailedScaleUpHistory {
+		if _, found := csr.failedScaleUpHistory[nodeGroupId]; found {
+			continue
+		}
+		for _, failure := range history {
+			csr.registerFailedScaleUpHistoryNoLock(nodeGroupId, failure)
+		}
+	}
+}
+
+// StatePersister saves the persisted state of a ClusterStateRegistry to a store
+// and restores it after a restart.
+type StatePersister struct {
+	clusterStateRegistry *ClusterStateRegistry
+	store                statestore.Store
+	restored             bool
+	lastSaved            []byte
+}
+
+// NewStatePersister creates a StatePersister for the given registry and store.
+func NewStatePersister(clusterStateRegistry *ClusterStateRegistry, store statestore.Store) *StatePersister {
+	return &StatePersister{
+		clusterStateRegistry: clusterStateRegistry,
+		store:                store,
+	}
+}
+
+// Restore loads the saved state into the registry. The state is only restored
+// once, subsequent calls after a successful restore are no-ops.
+func (p *StatePersister) Restore() error {
+	if p.restored {
+		return nil
+	}
+	data, err := p.store.Load()
+	if err != nil {
+		return err
+	}
+	if data != nil {
+		var state PersistedState
+		if err := json.Unmarshal(data, &state); err != nil {
+			// A corrupted state shouldn't block the autoscaler forever, it's overwritten on the next save.
+			klog.Errorf("Failed to decode persisted cluster state, ignoring it: %v", err)
+		} else {
+			p.clusterStateRegistry.RestoreState(state)
+			klog.V(1).Infof("Restored persisted cluster state: %d node group backoffs, failed scale-ups of %d node groups", backoffEntries(state.Backoff), len(state.FailedScaleUpHistory))
+		}
+	}
+	p.restored = true
+	p.lastSaved = data
+	return nil
+}
+
+// Save writes the state of the registry to the store if it changed since the
+// last save. Nothing is saved until the previous state was restored, so that it
+// isn't overwritten.
+func (p *StatePersister) Save() error {
+	if !p.restored {
+		return nil
+	}
+	data, err := json.Marshal(p.clusterStateRegistry.PersistedState())
+	if err != nil {
+		return fmt.Errorf("failed to encode cluster state: %v", err)
+	}
+	if bytes.Equal(data, p.lastSaved) {
+		return nil
+	}
+	if err := p.store.Save(data); err != nil {
+		return err
+	}
+	p.lastSaved = data
+	return nil
+}
+
+func backoffEntries(state *backoff.State) int {
+	if state == nil {
+		return 0
+	}
+	return len(state.Entries)
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterstate
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/clusterstate/utils"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"k8s.io/autoscaler/cluster-autoscaler/metrics"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroupconfig"
+	"k8s.io/client-go/kubernetes/fake"
+	kube_record "k8s.io/client-go/tools/record"
+)
+
+type memoryStore struct {
+	data  []byte
+	saves int
+	err   error
+}
+
+func (s *memoryStore) Load() ([]byte, error) {
+	return s.data, s.err
+}
+
+func (s *memoryStore) Save(data []byte) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.data = data
+	s.saves++
+	return nil
+}
+
+func newTestRegistry(provider cloudprovider.CloudProvider) *ClusterStateRegistry {
+	fakeLogRecorder, _ := utils.NewStatusMapRecorder(&fake.Clientset{}, "kube-system", kube_record.NewFakeRecorder(5), false, "my-cool-configmap")
+	return NewClusterStateRegistry(provider, ClusterStateRegistryConfig{
+		MaxTotalUnreadyPercentage: 10,
+		OkTotalUnreadyCount:       1,
+	}, fakeLogRecorder, newBackoff(), nodegroupconfig.NewDefaultNodeGroupConfigProcessor(config.NodeGroupAutoscalingOptions{MaxNodeProvisionTime: 15 * time.Minute}))
+}
+
+func TestStatePersister(t *testing.T) {
+	now := time.Now()
+	provider := testprovider.NewTestCloudProvider(nil, nil)
+	provider.AddNodeGroup("ng1", 1, 10, 1)
+	provider.AddNodeGroup("ng2", 1, 10, 1)
+	ng1 := provider.GetNodeGroup("ng1")
+	ng2 := provider.GetNodeGroup("ng2")
+	store := &memoryStore{}
+
+	csr := newTestRegistry(provider)
+	persister := NewStatePersister(csr, store)
+	csr.RegisterFailedScaleUp(ng1, string(metrics.Timeout), "timed out", "", "", now)
+	// Nothing is saved before the previous state is restored.
+	assert.NoError(t, persister.Save())
+	assert.Equal(t, 0, store.saves)
+
+	assert.NoError(t, persister.Restore())
+	assert.NoError(t, persister.Save())
+	assert.Equal(t, 1, store.saves)
+	// Unchanged state isn't saved again.
+	assert.NoError(t, persister.Save())
+	assert.Equal(t, 1, store.saves)
+
+	// A new leader resumes with the same backoff and failure history.
+	restarted := newTestRegistry(provider)
+	assert.NoError(t, NewStatePersister(restarted, store).Restore())
+	assert.True(t, restarted.BackoffStatusForNodeGroup(ng1, now.Add(time.Minute)).IsBackedOff)
+	assert.Equal(t, "timed out", restarted.BackoffStatusForNodeGroup(ng1, now.Add(time.Minute)).ErrorInfo.ErrorMessage)
+	assert.False(t, restarted.BackoffStatusForNodeGroup(ng2, now.Add(time.Minute)).IsBackedOff)
+	assert.False(t, restarted.BackoffStatusForNodeGroup(ng1, now.Add(6*time.Minute)).IsBackedOff)
+	history := restarted.GetFailedScaleUpHistory()
+	assert.Equal(t, 1, len(history["ng1"]))
+	assert.Equal(t, metrics.Timeout, history["ng1"][0].Reason)
+	assert.True(t, history["ng1"][0].Time.Equal(now))
+	assert.Empty(t, history["ng2"])
+}
+
+func TestStatePersisterErrors(t *testing.T) {
+	provider := testprovider.NewTestCloudProvider(nil, nil)
+	provider.AddNodeGroup("ng1", 1, 10, 1)
+
+	// Restoring is retried until the store can be read.
+	store := &memoryStore{err: fmt.Errorf("unavailable")}
+	persister := NewStatePersister(newTestRegistry(provider), store)
+	assert.Error(t, persister.Restore())
+	assert.NoError(t, persister.Save())
+	store.err = nil
+	assert.NoError(t, persister.Restore())
+
+	// A corrupted state is ignored and overwritten.
+	store = &memoryStore{data: []byte("{not json")}
+	csr := newTestRegistry(provider)
+	persister = NewStatePersister(csr, store)
+	assert.NoError(t, persister.Restore())
+	csr.RegisterFailedScaleUp(provider.GetNodeGroup("ng1"), string(metrics.Timeout), "timed out", "", "", time.Now())
+	assert.NoError(t, persister.Save())
+	assert.Equal(t, 1, store.saves)
+}
+
+func TestFailedScaleUpHistoryLimit(t *testing.T) {
+	now := time.Now()
+	provider := testprovider.NewTestCloudProvider(nil, nil)
+	provider.AddNodeGroup("ng1", 1, 10, 1)
+	csr := newTestRegistry(provider)
+	for i := 0; i < maxFailedScaleUpHistory+5; i++ {
+		csr.RegisterFailedScaleUp(provider.GetNodeGroup("ng1"), string(metrics.Timeout), fmt.Sprintf("failure %d", i), "", "", now.Add(time.Duration(i)*time.Minute))
+	}
+	history := csr.GetFailedScaleUpHistory()["ng1"]
+	assert.Equal(t, maxFailedScaleUpHistory, len(history))
+	assert.Equal(t, "failure 5", history[0].ErrorMessage)
+	assert.Equal(t, fmt.Sprintf("failure %d", maxFailedScaleUpHistory+4), history[len(history)-1].ErrorMessage)
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statestore
+
+import (
+	"context"
+	"fmt"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apiv1 "k8s.io/api/core/v1"
+	kube_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kube_client "k8s.io/client-go/kubernetes"
+)
+
+const (
+	// ConfigMapStoreType keeps the state in a ConfigMap.
+	ConfigMapStoreType = "configmap"
+	// LeaseStoreType keeps the state in an annotation of a Lease.
+	LeaseStoreType = "lease"
+
+	// ConfigMapStateKey is the ConfigMap key holding the state.
+	ConfigMapStateKey = "state"
+	// LeaseStateAnnotation is the Lease annotation holding the state.
+	LeaseStateAnnotation = "cluster-autoscaler.kubernetes.io/state"
+)
+
+// Store persists state of Cluster Autoscaler which should survive restarts and
+// leader failovers.
+type Store interface {
+	// Load returns the saved state, or nil if nothing was saved yet.
+	Load() ([]byte, error)
+	// Save overwrites the saved state.
+	Save(data []byte) error
+}
+
+// NewStore creates a store of the given type, keeping the state in an object
+// with the given name and namespace.
+func NewStore(storeType string, kubeClient kube_client.Interface, namespace, name string) (Store, error) {
+	switch storeType {
+	case ConfigMapStoreType:
+		return NewConfigMapStore(kubeClient, namespace, name), nil
+	case LeaseStoreType:
+		return NewLeaseStore(kubeClient, namespace, name), nil
+	}
+	return nil, fmt.Errorf("unknown state store type %q, expected %s or %s", storeType, ConfigMapStoreType, LeaseStoreType)
+}
+
+// ConfigMapStore keeps the state in a ConfigMap.
+type ConfigMapStore struct {
+	kubeClient kube_client.Interface
+	namespace  string
+	name       string
+}
+
+// NewConfigMapStore creates a store keeping the state in a ConfigMap.
+func NewConfigMapStore(kubeClient kube_client.Interface, namespace, name string) *ConfigMapStore {
+	return &ConfigMapStore{
+		kubeClient: kubeClient,
+		namespace:  namespace,
+		name:       name,
+	}
+}
+
+// Load returns the state saved in the ConfigMap.
+func (s *ConfigMapStore) Load() ([]byte, error) {
+	configMap, err := s.kubeClient.CoreV1().ConfigMaps(s.namespace).Get(context.TODO(), s.name, metav1.GetOptions{})
+	if kube_errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state ConfigMap %s/%s: %v", s.namespace, s.name, err)
+	}
+	data, found := configMap.Data[ConfigMapStateKey]
+	if !found {
+		return nil, nil
+	}
+	return []byte(data), nil
+}
+
+// Save writes the state to the ConfigMap, creating it if needed.
+func (s *ConfigMapStore) Save(data []byte) error {
+	configMaps := s.kubeClient.CoreV1().ConfigMaps(s.namespace)
+	configMap, err := configMaps.Get(context.TODO(), s.name, metav1.GetOptions{})
+	if kube_errors.IsNotFound(err) {
+		configMap = &apiv1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: s.namespace,
+				Name:      s.name,
+			},
+			Data: map[string]string{ConfigMapStateKey: string(data)},
+		}
+		if _, err := configMaps.Create(context.TODO(), configMap, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create state ConfigMap %s/%s: %v", s.namespace, s.name, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get state ConfigMap %s/%s: %v", s.namespace, s.name, err)
+	}
+	if configMap.Data == nil {
+		configMap.Data = make(map[string]string)
+	}
+	configMap.Data[ConfigMapStateKey] = string(data)
+	if _, err := configMaps.Update(context.TODO(), configMap, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update state ConfigMap %s/%s: %v", s.namespace, s.name, err)
+	}
+	return nil
+}
+
+// LeaseStore keeps the state in an annotation of a Lease. The Lease must not be
+// the one used for leader election, since the leader election client overwrites
+// annotations it doesn't know about.
+type LeaseStore struct {
+	kubeClient kube_client.Interface
+	namespace  string
+	name       string
+}
+
+// NewLeaseStore creates a store keeping the state in an annotation of a Lease.
+func NewLeaseStore(kubeClient kube_client.Interface, namespace, name string) *LeaseStore {
+	return &LeaseStore{
+		kubeClient: kubeClient,
+		namespace:  namespace,
+		name:       name,
+	}
+}
+
+// Load returns the state saved in the Lease annotation.
+func (s *LeaseStore) Load() ([]byte, error) {
+	lease, err := s.kubeClient.CoordinationV1().Leases(s.namespace).Get(context.TODO(), s.name, metav1.GetOptions{})
+	if kube_errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state Lease %s/%s: %v", s.namespace, s.name, err)
+	}
+	data, found := lease.Annotations[LeaseStateAnnotation]
+	if !found {
+		return nil, nil
+	}
+	return []byte(data), nil
+}
+
+// Save writes the state to the Lease annotation, creating the Lease if needed.
+func (s *LeaseStore) Save(data []byte) error {
+	leases := s.kubeClient.CoordinationV1().Leases(s.namespace)
+	lease, err := leases.Get(context.TODO(), s.name, metav1.GetOptions{})
+	if kube_errors.IsNotFound(err) {
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   s.namespace,
+				Name:        s.name,
+				Annotations: map[string]string{LeaseStateAnnotation: string(data)},
+			},
+		}
+		if _, err := leases.Create(context.TODO(), lease, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create state Lease %s/%s: %v", s.namespace, s.name, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get state Lease %s/%s: %v", s.namespace, s.name, err)
+	}
+	if lease.Annotations == nil {
+		lease.Annotations = make(map[string]string)
+	}
+	lease.Annotations[LeaseStateAnnotation] = string(data)
+	if _, err := leases.Update(context.TODO(), lease, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update state Lease %s/%s: %v", s.namespace, s.name, err)
+	}
+	return nil
+}
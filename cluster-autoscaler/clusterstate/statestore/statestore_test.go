@@ -0,0 +1,69 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statestore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestStores(t *testing.T) {
+	for _, storeType := range []string{ConfigMapStoreType, LeaseStoreType} {
+		t.Run(storeType, func(t *testing.T) {
+			store, err := NewStore(storeType, fake.NewSimpleClientset(), "kube-system", "cluster-autoscaler-state")
+			assert.NoError(t, err)
+
+			data, err := store.Load()
+			assert.NoError(t, err)
+			assert.Nil(t, data)
+
+			assert.NoError(t, store.Save([]byte(`{"v":1}`)))
+			data, err = store.Load()
+			assert.NoError(t, err)
+			assert.Equal(t, `{"v":1}`, string(data))
+
+			assert.NoError(t, store.Save([]byte(`{"v":2}`)))
+			data, err = store.Load()
+			assert.NoError(t, err)
+			assert.Equal(t, `{"v":2}`, string(data))
+		})
+	}
+
+	_, err := NewStore("etcd", fake.NewSimpleClientset(), "kube-system", "cluster-autoscaler-state")
+	assert.Error(t, err)
+}
+
+func TestConfigMapStoreKeepsOtherKeys(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(&apiv1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "cluster-autoscaler-state"},
+		Data:       map[string]string{"other": "value"},
+	})
+	store := NewConfigMapStore(kubeClient, "kube-system", "cluster-autoscaler-state")
+	data, err := store.Load()
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+
+	assert.NoError(t, store.Save([]byte("state")))
+	configMap, err := kubeClient.CoreV1().ConfigMaps("kube-system").Get(context.TODO(), "cluster-autoscaler-state", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"other": "value", ConfigMapStateKey: "state"}, configMap.Data)
+}
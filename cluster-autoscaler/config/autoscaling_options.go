@@ -54,6 +54,8 @@ type NodeGroupAutoscalingOptions struct {
 	ZeroOrMaxNodeScaling bool
 	// IgnoreDaemonSetsUtilization sets if daemonsets utilization should be considered during node scale-down
 	IgnoreDaemonSetsUtilization bool
+	// MinSizeSchedules raise the node group min size during the declared time windows.
+	MinSizeSchedules []MinSizeSchedule
 }
 
 // GCEOptions contain autoscaling options specific to GCE cloud provider.
@@ -116,6 +118,8 @@ type AutoscalingOptions struct {
 	MaxMemoryTotal int64
 	// MinMemoryTotal sets the maximum memory (in bytes) in the whole cluster
 	MinMemoryTotal int64
+	// TenantQuotas limit the resources requested by pods of each tenant which scale-up can make room for. Not limited if nil.
+	TenantQuotas *TenantQuotas
 	// GpuTotal is a list of strings with configuration of min/max limits for different GPUs.
 	GpuTotal []GpuLimits
 	// NodeGroupAutoDiscovery represents one or more definition(s) of node group auto-discovery
@@ -124,10 +128,21 @@ type AutoscalingOptions struct {
 	EstimatorName string
 	// ExpanderNames sets the chain of node group expanders to be used in scale up
 	ExpanderNames string
+	// ExpanderWeights are the weights of expanders combined by the weighted expander, keyed by expander name
+	ExpanderWeights map[string]float64
 	// GRPCExpanderCert is the location of the cert passed to the gRPC server for TLS when using the gRPC expander
 	GRPCExpanderCert string
 	// GRPCExpanderURL is the url of the gRPC server when using the gRPC expander
 	GRPCExpanderURL string
+	// GRPCProcessorsCert is the location of the cert passed to the gRPC processors server for TLS
+	GRPCProcessorsCert string
+	// GRPCProcessorsURL is the url of the gRPC server customizing pod list, scale down set and node group set processing
+	GRPCProcessorsURL string
+	// PricingConfigFile is the path to a price table used by the price expander when the cloud provider doesn't implement pricing.
+	PricingConfigFile string
+	// PricingConfigMapName is the name of a ConfigMap in ConfigNamespace holding a price table used by the price expander
+	// when the cloud provider doesn't implement pricing. Ignored if PricingConfigFile is set.
+	PricingConfigMapName string
 	// IgnoreMirrorPodsUtilization is whether CA will ignore Mirror pods when calculating resource utilization for scaling down
 	IgnoreMirrorPodsUtilization bool
 	// MaxGracefulTerminationSec is maximum number of seconds scale down waits for pods to terminate before
@@ -183,6 +198,20 @@ type AutoscalingOptions struct {
 	// ScaleDownSimulationTimeout defines the maximum time that can be
 	// spent on scale down simulation.
 	ScaleDownSimulationTimeout time.Duration
+	// ConsolidationEnabled is used to allow CA to replace under-utilized nodes with fewer or cheaper nodes of another node group
+	ConsolidationEnabled bool
+	// ConsolidationUtilizationThreshold sets the utilization below which nodes are considered for consolidation
+	ConsolidationUtilizationThreshold float64
+	// ConsolidationMinSavings is the minimum fraction of the hourly price of the replaced nodes which a consolidation has to save
+	ConsolidationMinSavings float64
+	// ConsolidationMaxNodes is the maximum number of nodes replaced by a single consolidation
+	ConsolidationMaxNodes int
+	// RebalanceEnabled is used to allow CA to move nodes of fallback node groups back to the node groups preferred by the priority expander
+	RebalanceEnabled bool
+	// RebalanceInterval is how often CA looks for nodes to move to preferred node groups
+	RebalanceInterval time.Duration
+	// RebalanceMaxNodes is the maximum number of nodes moved to a preferred node group at once
+	RebalanceMaxNodes int
 	// SchedulerConfig allows changing configuration of in-tree
 	// scheduler plugins acting on PreFilter and Filter extension points
 	SchedulerConfig *scheduler_config.KubeSchedulerConfiguration
@@ -218,6 +247,13 @@ type AutoscalingOptions struct {
 	MaxBulkSoftTaintTime time.Duration
 	// MaxPodEvictionTime sets the maximum time CA tries to evict a pod before giving up.
 	MaxPodEvictionTime time.Duration
+	// PreEvictionHookTimeout is the maximum time scale-down waits for pods
+	// with a pre-eviction hook to acknowledge it before evicting them anyway.
+	// Value of 0 turns pre-eviction hooks off.
+	PreEvictionHookTimeout time.Duration
+	// PreEvictionHookAllowedHosts are the hosts HTTP pre-eviction hooks with
+	// absolute URLs can point to. Hooks relative to the pod IP are always allowed.
+	PreEvictionHookAllowedHosts []string
 	// StartupTaints is a list of taints CA considers to reflect transient node
 	// status that should be removed when creating a node template for scheduling.
 	// startup taints are expected to appear during node startup.
@@ -255,10 +291,46 @@ type AutoscalingOptions struct {
 	MaxNodeGroupBackoffDuration time.Duration
 	// NodeGroupBackoffResetTimeout is the time after last failed scale-up when the backoff duration is reset.
 	NodeGroupBackoffResetTimeout time.Duration
+	// InterruptionTaints are the taints marking nodes about to be interrupted, e.g. reclaimed spot instances.
+	// Nodes removed while having one of them count as interruptions of their node group.
+	InterruptionTaints []string
+	// InterruptionWindow is how long interruptions of a node group are remembered.
+	InterruptionWindow time.Duration
+	// InterruptionBackoffThreshold is the number of interruptions within InterruptionWindow after which
+	// a node group is backed off. 0 disables backing off because of interruptions.
+	InterruptionBackoffThreshold int
+	// DynamicOptionsFile is the path of a file with options which are reloaded without restarting CA.
+	DynamicOptionsFile string
+	// DynamicOptionsConfigMapName is the name of a ConfigMap in ConfigNamespace with options which are
+	// reloaded without restarting CA. Ignored if DynamicOptionsFile is set.
+	DynamicOptionsConfigMapName string
+	// MaintenanceWindowsFile is the path of a file with maintenance windows during which scale-down, or all
+	// actuation, is suspended. It is reloaded every loop.
+	MaintenanceWindowsFile string
+	// MaintenanceWindowsConfigMapName is the name of a ConfigMap in ConfigNamespace with maintenance windows.
+	// Ignored if MaintenanceWindowsFile is set.
+	MaintenanceWindowsConfigMapName string
+	// StateStoreType is the type of store ("configmap" or "lease") in which node group backoffs and failed
+	// scale-up history are persisted across restarts. Nothing is persisted if empty.
+	StateStoreType string
+	// StateStoreName is the name of the ConfigMap or Lease in ConfigNamespace keeping the persisted state.
+	StateStoreName string
+	// DryRun makes CA plan scale-ups and scale-downs as usual, but only record the planned actions in
+	// events, metrics and the status ConfigMap instead of changing node groups, nodes or pods.
+	DryRun bool
+	// AuditLogFile is the path of a file to which a JSON line describing the decisions of every loop is appended.
+	AuditLogFile string
+	// AuditLogGRPCURL is the URL of a gRPC server to which a record describing the decisions of every loop is sent.
+	AuditLogGRPCURL string
+	// AuditLogGRPCCert is the path to the certificate used to verify the audit log gRPC server.
+	AuditLogGRPCCert string
 	// MaxScaleDownParallelism is the maximum number of nodes (both empty and needing drain) that can be deleted in parallel.
 	MaxScaleDownParallelism int
 	// MaxDrainParallelism is the maximum number of nodes needing drain, that can be drained and deleted in parallel.
 	MaxDrainParallelism int
+	// DisruptionBudgets limit the number of nodes deleted in parallel in node groups or sets of nodes selected
+	// by labels, on top of MaxScaleDownParallelism and MaxDrainParallelism. Not limited if nil.
+	DisruptionBudgets *DisruptionBudgets
 	// RecordDuplicatedEvents controls whether events should be duplicated within a 5 minute window.
 	RecordDuplicatedEvents bool
 	// MaxNodesPerScaleUp controls how many nodes can be added in a single scale-up.
@@ -271,6 +343,9 @@ type AutoscalingOptions struct {
 	// MaxBinpackingTime is the maximum time spend on binpacking for a single scale-up.
 	// If binpacking is limited by this, scale-up will continue with the already calculated scale-up options.
 	MaxBinpackingTime time.Duration
+	// ParallelEstimationWorkers is the maximum number of node groups binpacked concurrently during a single scale-up,
+	// each on its own copy of the cluster snapshot. 1 binpacks node groups one by one.
+	ParallelEstimationWorkers int
 	// NodeDeletionBatcherInterval is a time for how long CA ScaleDown gather nodes to delete them in batch.
 	NodeDeletionBatcherInterval time.Duration
 	// SkipNodesWithSystemPods tells if nodes with pods from kube-system should be deleted (except for DaemonSet or mirror pods)
@@ -295,6 +370,11 @@ type AutoscalingOptions struct {
 	BypassedSchedulers map[string]bool
 	// ProvisioningRequestEnabled tells if CA processes ProvisioningRequest.
 	ProvisioningRequestEnabled bool
+	// NodeGroupAutoscalingConfigsEnabled tells if CA takes per node group options from NodeGroupAutoscalingConfig objects.
+	NodeGroupAutoscalingConfigsEnabled bool
+	// DynamicResourceAllocationEnabled tells if CA simulates allocation of ResourceClaims of pods using Dynamic Resource
+	// Allocation with structured parameters, based on the ResourceSlices published for nodes.
+	DynamicResourceAllocationEnabled bool
 }
 
 // KubeClientOptions specify options for kube client
@@ -40,6 +40,8 @@ const (
 	DefaultMaxNodeProvisionTimeKey = "maxnodeprovisiontime"
 	// DefaultIgnoreDaemonSetsUtilizationKey identifies IgnoreDaemonSetsUtilization autoscaling option
 	DefaultIgnoreDaemonSetsUtilizationKey = "ignoredaemonsetsutilization"
+	// DefaultMinSizeSchedulesKey identifies MinSizeSchedules autoscaling option
+	DefaultMinSizeSchedulesKey = "minsizeschedules"
 
 	// DefaultScaleDownUnneededTime is the default time duration for which CA waits before deleting an unneeded node
 	DefaultScaleDownUnneededTime = 10 * time.Minute
@@ -51,6 +53,27 @@ const (
 	DefaultScaleDownGpuUtilizationThreshold = 0.5
 	// DefaultScaleDownDelayAfterFailure is the default value for ScaleDownDelayAfterFailure autoscaling option
 	DefaultScaleDownDelayAfterFailure = 3 * time.Minute
+	// DefaultConsolidationUtilizationThreshold is the default value for ConsolidationUtilizationThreshold autoscaling option
+	DefaultConsolidationUtilizationThreshold = 0.7
+	// DefaultConsolidationMinSavings is the default value for ConsolidationMinSavings autoscaling option
+	DefaultConsolidationMinSavings = 0.2
+	// DefaultConsolidationMaxNodes is the default value for ConsolidationMaxNodes autoscaling option
+	DefaultConsolidationMaxNodes = 5
+	// DefaultRebalanceInterval is the default value for RebalanceInterval autoscaling option
+	DefaultRebalanceInterval = 5 * time.Minute
+	// DefaultRebalanceMaxNodes is the default value for RebalanceMaxNodes autoscaling option
+	DefaultRebalanceMaxNodes = 1
 	// DefaultScanInterval is the default scan interval for CA
 	DefaultScanInterval = 10 * time.Second
 )
+
+var (
+	// DefaultInterruptionTaints are the taints put on nodes about to be interrupted: by GKE on
+	// preempted nodes, by the AWS node termination handler on reclaimed spot instances and by
+	// the cloud node lifecycle controller on nodes whose instances were shut down.
+	DefaultInterruptionTaints = []string{
+		"cloud.google.com/impending-node-termination",
+		"aws-node-termination-handler/spot-itn",
+		"node.cloudprovider.kubernetes.io/shutdown",
+	}
+)
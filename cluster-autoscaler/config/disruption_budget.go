@@ -0,0 +1,187 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/yaml"
+)
+
+// DisruptionBudgets limit the number of nodes which scale-down deletes at
+// once in parts of the cluster, on top of MaxScaleDownParallelism and
+// MaxDrainParallelism.
+type DisruptionBudgets struct {
+	Budgets []DisruptionBudget `json:"budgets"`
+}
+
+// DisruptionBudget limits the number of nodes of a domain being deleted at
+// once. A budget with NodeGroups applies to each matching node group
+// separately, a budget without them applies to all the nodes it selects
+// together.
+type DisruptionBudget struct {
+	// Name identifies the budget in logs and in the status ConfigMap.
+	Name string `json:"name"`
+	// Nodes is the maximum number ("3") or percentage ("10%", rounded up)
+	// of nodes of the domain being deleted at once. "0" blocks scale-down.
+	Nodes *intstr.IntOrString `json:"nodes"`
+	// NodeGroups are regular expressions matching the ids of the node groups
+	// the budget applies to, each of them separately.
+	NodeGroups []string `json:"nodeGroups,omitempty"`
+	// NodeSelector selects the nodes the budget applies to, e.g. the nodes
+	// of a zone. All nodes are selected if it's not set.
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+	// Schedule limits the budget to a recurring window in the format of
+	// maintenance window schedules, e.g. "weekdays 08:00-18:00 Europe/Warsaw".
+	// The budget always applies if it's not set.
+	Schedule string `json:"schedule,omitempty"`
+
+	schedule   *MinSizeSchedule
+	nodeGroups []*regexp.Regexp
+	selector   labels.Selector
+}
+
+// ParseDisruptionBudgets parses disruption budgets in YAML or JSON format.
+func ParseDisruptionBudgets(data []byte) (*DisruptionBudgets, error) {
+	budgets := &DisruptionBudgets{}
+	if err := yaml.UnmarshalStrict(data, budgets); err != nil {
+		return nil, fmt.Errorf("failed to parse disruption budgets: %v", err)
+	}
+	if err := budgets.validate(); err != nil {
+		return nil, fmt.Errorf("invalid disruption budgets: %v", err)
+	}
+	return budgets, nil
+}
+
+// LoadDisruptionBudgets reads disruption budgets from a file.
+func LoadDisruptionBudgets(path string) (*DisruptionBudgets, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read disruption budgets %s: %v", path, err)
+	}
+	return ParseDisruptionBudgets(data)
+}
+
+func (b *DisruptionBudgets) validate() error {
+	if len(b.Budgets) == 0 {
+		return fmt.Errorf("no budgets defined")
+	}
+	names := map[string]bool{}
+	for i := range b.Budgets {
+		budget := &b.Budgets[i]
+		if budget.Name == "" {
+			return fmt.Errorf("budget %d has no name", i)
+		}
+		if names[budget.Name] {
+			return fmt.Errorf("duplicate budget %s", budget.Name)
+		}
+		names[budget.Name] = true
+		if err := budget.validate(); err != nil {
+			return fmt.Errorf("budget %s: %v", budget.Name, err)
+		}
+	}
+	return nil
+}
+
+func (b *DisruptionBudget) validate() error {
+	if b.Nodes == nil {
+		return fmt.Errorf("nodes not set")
+	}
+	switch b.Nodes.Type {
+	case intstr.Int:
+		if b.Nodes.IntVal < 0 {
+			return fmt.Errorf("nodes can't be negative, got %d", b.Nodes.IntVal)
+		}
+	case intstr.String:
+		percent, isPercent := strings.CutSuffix(b.Nodes.StrVal, "%")
+		value, err := strconv.Atoi(percent)
+		if !isPercent || err != nil || value < 0 || value > 100 {
+			return fmt.Errorf("invalid nodes %q, expected a number or a percentage", b.Nodes.StrVal)
+		}
+	}
+	if b.Schedule != "" {
+		schedule, err := parseRecurringWindow(b.Schedule)
+		if err != nil {
+			return err
+		}
+		b.schedule = &schedule
+	}
+	for _, pattern := range b.NodeGroups {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid node group pattern %q: %v", pattern, err)
+		}
+		b.nodeGroups = append(b.nodeGroups, re)
+	}
+	b.selector = labels.Everything()
+	if b.NodeSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(b.NodeSelector)
+		if err != nil {
+			return fmt.Errorf("invalid node selector: %v", err)
+		}
+		b.selector = selector
+	}
+	return nil
+}
+
+// Active returns true if the budget applies at the given time.
+func (b *DisruptionBudget) Active(now time.Time) bool {
+	return b.schedule == nil || b.schedule.Active(now)
+}
+
+// PerNodeGroup returns true if the budget applies to each matching node
+// group separately.
+func (b *DisruptionBudget) PerNodeGroup() bool {
+	return len(b.NodeGroups) > 0
+}
+
+// Selects returns true if the budget applies to the node with the given
+// labels, belonging to the given node group.
+func (b *DisruptionBudget) Selects(nodeLabels map[string]string, nodeGroupId string) bool {
+	if b.PerNodeGroup() && !b.matchesNodeGroup(nodeGroupId) {
+		return false
+	}
+	return b.selector == nil || b.selector.Matches(labels.Set(nodeLabels))
+}
+
+func (b *DisruptionBudget) matchesNodeGroup(nodeGroupId string) bool {
+	for _, re := range b.nodeGroups {
+		if re.FindStringIndex(nodeGroupId) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Allowed returns the maximum number of nodes of a domain with the given
+// number of nodes which can be deleted at once.
+func (b *DisruptionBudget) Allowed(nodes int) int {
+	allowed, err := intstr.GetScaledValueFromIntOrPercent(b.Nodes, nodes, true)
+	if err != nil {
+		// Validated when parsing.
+		return 0
+	}
+	return allowed
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDisruptionBudgets(t *testing.T) {
+	budgets, err := ParseDisruptionBudgets([]byte(`
+budgets:
+  - name: per-group
+    nodes: 10%
+    nodeGroups: ["^pool-"]
+  - name: zone-a
+    nodes: 2
+    nodeSelector:
+      matchLabels:
+        topology.kubernetes.io/zone: zone-a
+  - name: business-hours
+    nodes: 0
+    schedule: weekdays 08:00-18:00
+`))
+	assert.NoError(t, err)
+	if !assert.Len(t, budgets.Budgets, 3) {
+		return
+	}
+	perGroup, zone, hours := &budgets.Budgets[0], &budgets.Budgets[1], &budgets.Budgets[2]
+
+	assert.True(t, perGroup.PerNodeGroup())
+	assert.True(t, perGroup.Selects(nil, "pool-a"))
+	assert.False(t, perGroup.Selects(nil, "default"))
+	assert.Equal(t, 1, perGroup.Allowed(3))
+	assert.Equal(t, 2, perGroup.Allowed(11))
+	assert.Equal(t, 0, perGroup.Allowed(0))
+
+	assert.False(t, zone.PerNodeGroup())
+	assert.True(t, zone.Selects(map[string]string{"topology.kubernetes.io/zone": "zone-a"}, "default"))
+	assert.False(t, zone.Selects(map[string]string{"topology.kubernetes.io/zone": "zone-b"}, "default"))
+	assert.Equal(t, 2, zone.Allowed(100))
+	assert.True(t, zone.Active(time.Now()))
+
+	assert.True(t, hours.Selects(nil, "default"))
+	assert.Equal(t, 0, hours.Allowed(100))
+	assert.True(t, hours.Active(time.Date(2024, 6, 3, 9, 0, 0, 0, time.UTC)))
+	assert.False(t, hours.Active(time.Date(2024, 6, 3, 19, 0, 0, 0, time.UTC)))
+}
+
+func TestParseDisruptionBudgetsErrors(t *testing.T) {
+	for name, data := range map[string]string{
+		"no budgets":            "budgets: []",
+		"no name":               "budgets:\n  - nodes: 1",
+		"duplicate name":        "budgets:\n  - name: a\n    nodes: 1\n  - name: a\n    nodes: 2",
+		"no nodes":              "budgets:\n  - name: a",
+		"negative nodes":        "budgets:\n  - name: a\n    nodes: -1",
+		"invalid percentage":    "budgets:\n  - name: a\n    nodes: 150%",
+		"not a percentage":      "budgets:\n  - name: a\n    nodes: lots",
+		"invalid schedule":      "budgets:\n  - name: a\n    nodes: 1\n    schedule: someday 01:00-02:00",
+		"invalid node groups":   "budgets:\n  - name: a\n    nodes: 1\n    nodeGroups: [\"(\"]",
+		"invalid node selector": "budgets:\n  - name: a\n    nodes: 1\n    nodeSelector:\n      matchExpressions:\n        - {key: zone, operator: Sideways}",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseDisruptionBudgets([]byte(data))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// Freeze is the kind of actuation suspended by a maintenance window.
+type Freeze string
+
+const (
+	// FreezeScaleDown suspends scale-down, scale-up still happens.
+	FreezeScaleDown Freeze = "ScaleDown"
+	// FreezeAll suspends both scale-up and scale-down.
+	FreezeAll Freeze = "All"
+)
+
+// MaintenanceWindows are time windows during which Cluster Autoscaler doesn't
+// change the size of the cluster, or of some of its node groups.
+type MaintenanceWindows struct {
+	Windows []MaintenanceWindow `json:"windows"`
+}
+
+// MaintenanceWindow is a one-off or recurring time window during which
+// scale-down, or all actuation, is suspended.
+type MaintenanceWindow struct {
+	// Name identifies the window in events and in the status ConfigMap.
+	Name string `json:"name"`
+	// Start and End bound a one-off window.
+	Start *metav1.Time `json:"start,omitempty"`
+	End   *metav1.Time `json:"end,omitempty"`
+	// Schedule defines a recurring window in the format
+	// "<days> <HH:MM>-<HH:MM> [<time zone>]", with days as in min size
+	// schedules, e.g. "fri 18:00-08:00 Europe/Warsaw". It can't be combined
+	// with Start and End.
+	Schedule string `json:"schedule,omitempty"`
+	// Freeze is the kind of actuation suspended, ScaleDown if not set.
+	Freeze Freeze `json:"freeze,omitempty"`
+	// NodeGroups are regular expressions matching the ids of the node groups
+	// the window applies to. The window applies to the whole cluster if it's
+	// empty.
+	NodeGroups []string `json:"nodeGroups,omitempty"`
+
+	schedule   *MinSizeSchedule
+	nodeGroups []*regexp.Regexp
+}
+
+// ParseMaintenanceWindows parses maintenance windows in YAML or JSON format.
+func ParseMaintenanceWindows(data []byte) (*MaintenanceWindows, error) {
+	windows := &MaintenanceWindows{}
+	if err := yaml.UnmarshalStrict(data, windows); err != nil {
+		return nil, fmt.Errorf("failed to parse maintenance windows: %v", err)
+	}
+	if err := windows.validate(); err != nil {
+		return nil, fmt.Errorf("invalid maintenance windows: %v", err)
+	}
+	return windows, nil
+}
+
+// LoadMaintenanceWindows reads maintenance windows from a file.
+func LoadMaintenanceWindows(path string) (*MaintenanceWindows, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read maintenance windows %s: %v", path, err)
+	}
+	return ParseMaintenanceWindows(data)
+}
+
+func (w *MaintenanceWindows) validate() error {
+	names := map[string]bool{}
+	for i := range w.Windows {
+		window := &w.Windows[i]
+		if window.Name == "" {
+			return fmt.Errorf("window %d has no name", i)
+		}
+		if names[window.Name] {
+			return fmt.Errorf("duplicate window %s", window.Name)
+		}
+		names[window.Name] = true
+		if err := window.validate(); err != nil {
+			return fmt.Errorf("window %s: %v", window.Name, err)
+		}
+	}
+	return nil
+}
+
+func (w *MaintenanceWindow) validate() error {
+	switch w.Freeze {
+	case "":
+		w.Freeze = FreezeScaleDown
+	case FreezeScaleDown, FreezeAll:
+	default:
+		return fmt.Errorf("unknown freeze %q, expected %s or %s", w.Freeze, FreezeScaleDown, FreezeAll)
+	}
+	if w.Schedule != "" {
+		if w.Start != nil || w.End != nil {
+			return fmt.Errorf("schedule can't be combined with start and end")
+		}
+		schedule, err := parseRecurringWindow(w.Schedule)
+		if err != nil {
+			return err
+		}
+		w.schedule = &schedule
+	} else {
+		if w.Start == nil || w.End == nil {
+			return fmt.Errorf("either schedule or both start and end have to be set")
+		}
+		if !w.End.After(w.Start.Time) {
+			return fmt.Errorf("end has to be after start")
+		}
+	}
+	for _, pattern := range w.NodeGroups {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid node group pattern %q: %v", pattern, err)
+		}
+		w.nodeGroups = append(w.nodeGroups, re)
+	}
+	return nil
+}
+
+// parseRecurringWindow parses a window in the format of a min size schedule,
+// without the min size.
+func parseRecurringWindow(value string) (MinSizeSchedule, error) {
+	fields := strings.Fields(strings.ReplaceAll(value, "–", "-"))
+	if len(fields) != 2 && len(fields) != 3 {
+		return MinSizeSchedule{}, fmt.Errorf("invalid schedule %q: expected \"<days> <HH:MM>-<HH:MM> [<time zone>]\"", value)
+	}
+	schedule := MinSizeSchedule{Location: time.UTC}
+	var err error
+	if schedule.Days, err = parseDays(fields[0]); err != nil {
+		return MinSizeSchedule{}, fmt.Errorf("invalid schedule %q: %v", value, err)
+	}
+	if schedule.Start, schedule.End, err = parseWindow(fields[1]); err != nil {
+		return MinSizeSchedule{}, fmt.Errorf("invalid schedule %q: %v", value, err)
+	}
+	if len(fields) == 3 {
+		if schedule.Location, err = time.LoadLocation(fields[2]); err != nil {
+			return MinSizeSchedule{}, fmt.Errorf("invalid schedule %q: %v", value, err)
+		}
+	}
+	return schedule, nil
+}
+
+// Active returns true if the window covers the given time.
+func (w *MaintenanceWindow) Active(now time.Time) bool {
+	if w.schedule != nil {
+		return w.schedule.Active(now)
+	}
+	if w.Start == nil || w.End == nil {
+		return false
+	}
+	return !now.Before(w.Start.Time) && now.Before(w.End.Time)
+}
+
+// ClusterWide returns true if the window applies to all node groups.
+func (w *MaintenanceWindow) ClusterWide() bool {
+	return len(w.NodeGroups) == 0
+}
+
+// AppliesTo returns true if the window applies to the node group.
+func (w *MaintenanceWindow) AppliesTo(nodeGroupId string) bool {
+	if w.ClusterWide() {
+		return true
+	}
+	for _, re := range w.nodeGroups {
+		if re.FindStringIndex(nodeGroupId) != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMaintenanceWindows(t *testing.T) {
+	windows, err := ParseMaintenanceWindows([]byte(`
+windows:
+  - name: release-freeze
+    start: "2024-12-20T00:00:00Z"
+    end: "2025-01-06T00:00:00Z"
+    freeze: All
+  - name: batch-weekend
+    schedule: "fri 18:00-08:00 UTC"
+    nodeGroups:
+      - ^batch-
+`))
+	assert.NoError(t, err)
+	if !assert.Len(t, windows.Windows, 2) {
+		return
+	}
+	freeze, batch := &windows.Windows[0], &windows.Windows[1]
+	assert.Equal(t, FreezeAll, freeze.Freeze)
+	assert.True(t, freeze.ClusterWide())
+	assert.False(t, freeze.Active(time.Date(2024, 12, 19, 23, 59, 0, 0, time.UTC)))
+	assert.True(t, freeze.Active(time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, freeze.Active(time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)))
+
+	assert.Equal(t, FreezeScaleDown, batch.Freeze)
+	assert.False(t, batch.ClusterWide())
+	assert.True(t, batch.AppliesTo("batch-pool"))
+	assert.False(t, batch.AppliesTo("default-batch-pool"))
+	// Friday evening and Saturday morning.
+	assert.True(t, batch.Active(time.Date(2024, 6, 7, 20, 0, 0, 0, time.UTC)))
+	assert.True(t, batch.Active(time.Date(2024, 6, 8, 7, 0, 0, 0, time.UTC)))
+	assert.False(t, batch.Active(time.Date(2024, 6, 8, 9, 0, 0, 0, time.UTC)))
+}
+
+func TestParseMaintenanceWindowsErrors(t *testing.T) {
+	for name, data := range map[string]string{
+		"no name":             "windows:\n  - schedule: daily 01:00-02:00",
+		"duplicate name":      "windows:\n  - name: a\n    schedule: daily 01:00-02:00\n  - name: a\n    schedule: daily 03:00-04:00",
+		"no time":             "windows:\n  - name: a",
+		"schedule and start":  "windows:\n  - name: a\n    schedule: daily 01:00-02:00\n    start: \"2024-01-01T00:00:00Z\"",
+		"end before start":    "windows:\n  - name: a\n    start: \"2024-01-02T00:00:00Z\"\n    end: \"2024-01-01T00:00:00Z\"",
+		"invalid schedule":    "windows:\n  - name: a\n    schedule: someday 01:00-02:00",
+		"unknown freeze":      "windows:\n  - name: a\n    schedule: daily 01:00-02:00\n    freeze: ScaleUp",
+		"invalid node groups": "windows:\n  - name: a\n    schedule: daily 01:00-02:00\n    nodeGroups: [\"(\"]",
+		"unknown field":       "windows:\n  - name: a\n    schedule: daily 01:00-02:00\n    nodegroup: a",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseMaintenanceWindows([]byte(data))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MinSizeSchedule raises the min size of a node group during a recurring time window.
+type MinSizeSchedule struct {
+	// Days on which the window starts.
+	Days []time.Weekday
+	// Start of the window, as an offset from midnight.
+	Start time.Duration
+	// End of the window, as an offset from midnight. If End is not after Start,
+	// the window ends on the following day.
+	End time.Duration
+	// Location in which Days, Start and End are interpreted. UTC if not set.
+	Location *time.Location
+	// MinSize is the min size of the node group while the window is active.
+	MinSize int
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// ParseMinSizeSchedules parses a semicolon separated list of min size schedules,
+// see ParseMinSizeSchedule for the format of a single schedule.
+func ParseMinSizeSchedules(value string) ([]MinSizeSchedule, error) {
+	var schedules []MinSizeSchedule
+	for _, part := range strings.Split(value, ";") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		schedule, err := ParseMinSizeSchedule(part)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, nil
+}
+
+// ParseMinSizeSchedule parses a min size schedule in the format
+// "<days> <HH:MM>-<HH:MM> [<time zone>] min=<size>", e.g.
+// "weekdays 08:00-18:00 Europe/Warsaw min=10". Days can be "daily", "weekdays",
+// "weekends" or a comma separated list of days and day ranges, e.g. "mon-wed,fri".
+func ParseMinSizeSchedule(value string) (MinSizeSchedule, error) {
+	fields := strings.Fields(strings.ReplaceAll(value, "–", "-"))
+	if len(fields) != 3 && len(fields) != 4 {
+		return MinSizeSchedule{}, fmt.Errorf("invalid min size schedule %q: expected \"<days> <HH:MM>-<HH:MM> [<time zone>] min=<size>\"", value)
+	}
+	schedule := MinSizeSchedule{Location: time.UTC}
+	var err error
+	if schedule.Days, err = parseDays(fields[0]); err != nil {
+		return MinSizeSchedule{}, fmt.Errorf("invalid min size schedule %q: %v", value, err)
+	}
+	if schedule.Start, schedule.End, err = parseWindow(fields[1]); err != nil {
+		return MinSizeSchedule{}, fmt.Errorf("invalid min size schedule %q: %v", value, err)
+	}
+	if len(fields) == 4 {
+		if schedule.Location, err = time.LoadLocation(fields[2]); err != nil {
+			return MinSizeSchedule{}, fmt.Errorf("invalid min size schedule %q: %v", value, err)
+		}
+	}
+	sizeField := fields[len(fields)-1]
+	if !strings.HasPrefix(sizeField, "min=") {
+		return MinSizeSchedule{}, fmt.Errorf("invalid min size schedule %q: expected min=<size>, got %q", value, sizeField)
+	}
+	if schedule.MinSize, err = strconv.Atoi(strings.TrimPrefix(sizeField, "min=")); err != nil || schedule.MinSize < 0 {
+		return MinSizeSchedule{}, fmt.Errorf("invalid min size schedule %q: invalid size %q", value, sizeField)
+	}
+	return schedule, nil
+}
+
+func parseDays(value string) ([]time.Weekday, error) {
+	switch strings.ToLower(value) {
+	case "daily", "*":
+		return []time.Weekday{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday}, nil
+	case "weekdays":
+		return []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}, nil
+	case "weekends":
+		return []time.Weekday{time.Saturday, time.Sunday}, nil
+	}
+	var days []time.Weekday
+	for _, part := range strings.Split(strings.ToLower(value), ",") {
+		from, to, isRange := strings.Cut(part, "-")
+		first, found := weekdays[from]
+		if !found {
+			return nil, fmt.Errorf("unknown day %q", from)
+		}
+		last := first
+		if isRange {
+			if last, found = weekdays[to]; !found {
+				return nil, fmt.Errorf("unknown day %q", to)
+			}
+		}
+		for day := first; ; day = (day + 1) % 7 {
+			days = append(days, day)
+			if day == last {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+func parseWindow(value string) (time.Duration, time.Duration, error) {
+	from, to, found := strings.Cut(value, "-")
+	if !found {
+		return 0, 0, fmt.Errorf("invalid time window %q, expected <HH:MM>-<HH:MM>", value)
+	}
+	start, err := parseTimeOfDay(from)
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := parseTimeOfDay(to)
+	if err != nil {
+		return 0, 0, err
+	}
+	return start, end, nil
+}
+
+func parseTimeOfDay(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", value)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Active returns true if the schedule window covers the given time.
+func (s MinSizeSchedule) Active(now time.Time) bool {
+	location := s.Location
+	if location == nil {
+		location = time.UTC
+	}
+	local := now.In(location)
+	sinceMidnight := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute + time.Duration(local.Second())*time.Second
+	if s.End > s.Start {
+		return s.startsOn(local.Weekday()) && sinceMidnight >= s.Start && sinceMidnight < s.End
+	}
+	// The window wraps around midnight, it's either the evening of a start day
+	// or the morning of the following day.
+	if s.startsOn(local.Weekday()) && sinceMidnight >= s.Start {
+		return true
+	}
+	return s.startsOn((local.Weekday()+6)%7) && sinceMidnight < s.End
+}
+
+func (s MinSizeSchedule) startsOn(day time.Weekday) bool {
+	for _, d := range s.Days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// ScheduledMinSize returns the highest min size of the schedules active at the
+// given time, and false if none of them is active.
+func ScheduledMinSize(schedules []MinSizeSchedule, now time.Time) (int, bool) {
+	minSize, active := 0, false
+	for _, schedule := range schedules {
+		if schedule.Active(now) {
+			active = true
+			if schedule.MinSize > minSize {
+				minSize = schedule.MinSize
+			}
+		}
+	}
+	return minSize, active
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMinSizeSchedule(t *testing.T) {
+	warsaw, err := time.LoadLocation("Europe/Warsaw")
+	assert.NoError(t, err)
+	testCases := map[string]struct {
+		value   string
+		want    MinSizeSchedule
+		wantErr bool
+	}{
+		"weekdays with time zone": {
+			value: "weekdays 08:00-18:00 Europe/Warsaw min=10",
+			want: MinSizeSchedule{
+				Days:     []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+				Start:    8 * time.Hour,
+				End:      18 * time.Hour,
+				Location: warsaw,
+				MinSize:  10,
+			},
+		},
+		"day ranges and en dash": {
+			value: "fri-sun,tue 22:30–06:00 min=3",
+			want: MinSizeSchedule{
+				Days:     []time.Weekday{time.Friday, time.Saturday, time.Sunday, time.Tuesday},
+				Start:    22*time.Hour + 30*time.Minute,
+				End:      6 * time.Hour,
+				Location: time.UTC,
+				MinSize:  3,
+			},
+		},
+		"unknown day": {
+			value:   "someday 08:00-18:00 min=1",
+			wantErr: true,
+		},
+		"bad window": {
+			value:   "daily 8-18 min=1",
+			wantErr: true,
+		},
+		"unknown time zone": {
+			value:   "daily 08:00-18:00 Nowhere/Special min=1",
+			wantErr: true,
+		},
+		"missing size": {
+			value:   "daily 08:00-18:00 UTC",
+			wantErr: true,
+		},
+		"negative size": {
+			value:   "daily 08:00-18:00 min=-1",
+			wantErr: true,
+		},
+	}
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			got, err := ParseMinSizeSchedule(tc.value)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestParseMinSizeSchedules(t *testing.T) {
+	schedules, err := ParseMinSizeSchedules("weekdays 08:00-18:00 min=10; weekends 10:00-14:00 min=2;")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(schedules))
+	assert.Equal(t, 10, schedules[0].MinSize)
+	assert.Equal(t, 2, schedules[1].MinSize)
+
+	_, err = ParseMinSizeSchedules("weekdays 08:00-18:00 min=10;bad")
+	assert.Error(t, err)
+}
+
+func TestScheduledMinSize(t *testing.T) {
+	office := MinSizeSchedule{Days: []time.Weekday{time.Monday}, Start: 8 * time.Hour, End: 18 * time.Hour, MinSize: 10}
+	night := MinSizeSchedule{Days: []time.Weekday{time.Monday}, Start: 22 * time.Hour, End: 6 * time.Hour, MinSize: 3}
+	schedules := []MinSizeSchedule{office, night}
+	testCases := map[string]struct {
+		now        time.Time
+		wantSize   int
+		wantActive bool
+	}{
+		"during office hours": {
+			now:        time.Date(2024, time.January, 8, 9, 0, 0, 0, time.UTC),
+			wantSize:   10,
+			wantActive: true,
+		},
+		"end of window is exclusive": {
+			now: time.Date(2024, time.January, 8, 18, 0, 0, 0, time.UTC),
+		},
+		"monday night": {
+			now:        time.Date(2024, time.January, 8, 23, 0, 0, 0, time.UTC),
+			wantSize:   3,
+			wantActive: true,
+		},
+		"tuesday morning after monday night": {
+			now:        time.Date(2024, time.January, 9, 5, 59, 0, 0, time.UTC),
+			wantSize:   3,
+			wantActive: true,
+		},
+		"tuesday office hours": {
+			now: time.Date(2024, time.January, 9, 9, 0, 0, 0, time.UTC),
+		},
+		"monday early morning": {
+			now: time.Date(2024, time.January, 8, 5, 0, 0, 0, time.UTC),
+		},
+	}
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			size, active := ScheduledMinSize(schedules, tc.now)
+			assert.Equal(t, tc.wantSize, size)
+			assert.Equal(t, tc.wantActive, active)
+		})
+	}
+}
+
+func TestMinSizeScheduleActiveInLocation(t *testing.T) {
+	warsaw, err := time.LoadLocation("Europe/Warsaw")
+	assert.NoError(t, err)
+	schedule := MinSizeSchedule{Days: []time.Weekday{time.Monday}, Start: 8 * time.Hour, End: 18 * time.Hour, Location: warsaw, MinSize: 1}
+	// 07:30 UTC is 08:30 in Warsaw in January.
+	assert.True(t, schedule.Active(time.Date(2024, time.January, 8, 7, 30, 0, 0, time.UTC)))
+	// 17:30 UTC is 18:30 in Warsaw in January.
+	assert.False(t, schedule.Active(time.Date(2024, time.January, 8, 17, 30, 0, 0, time.UTC)))
+}
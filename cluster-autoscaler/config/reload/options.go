@@ -0,0 +1,190 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reload
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"sigs.k8s.io/yaml"
+)
+
+// DynamicOptions is the subset of AutoscalingOptions which can be changed
+// without restarting Cluster Autoscaler. All of them are read from the
+// AutoscalingContext every loop. Options use the names of the corresponding
+// flags; the ones which are not set keep their values from the flags.
+type DynamicOptions struct {
+	MaxNodesTotal                    *int             `json:"max-nodes-total,omitempty"`
+	ScaleDownEnabled                 *bool            `json:"scale-down-enabled,omitempty"`
+	ScaleDownDelayAfterAdd           *metav1.Duration `json:"scale-down-delay-after-add,omitempty"`
+	ScaleDownDelayAfterDelete        *metav1.Duration `json:"scale-down-delay-after-delete,omitempty"`
+	ScaleDownDelayAfterFailure       *metav1.Duration `json:"scale-down-delay-after-failure,omitempty"`
+	ScaleDownNonEmptyCandidatesCount *int             `json:"scale-down-non-empty-candidates-count,omitempty"`
+	ScaleDownCandidatesPoolRatio     *float64         `json:"scale-down-candidates-pool-ratio,omitempty"`
+	ScaleDownCandidatesPoolMinCount  *int             `json:"scale-down-candidates-pool-min-count,omitempty"`
+	MaxScaleDownParallelism          *int             `json:"max-scale-down-parallelism,omitempty"`
+	MaxDrainParallelism              *int             `json:"max-drain-parallelism,omitempty"`
+	NewPodScaleUpDelay               *metav1.Duration `json:"new-pod-scale-up-delay,omitempty"`
+	ExpanderNames                    *string          `json:"expander,omitempty"`
+}
+
+// ParseDynamicOptions parses dynamic options in YAML or JSON format.
+func ParseDynamicOptions(data []byte) (*DynamicOptions, error) {
+	options := &DynamicOptions{}
+	if err := yaml.UnmarshalStrict(data, options); err != nil {
+		return nil, fmt.Errorf("failed to parse dynamic options: %v", err)
+	}
+	return options, nil
+}
+
+// ApplyTo returns the base options with the dynamic options set.
+func (o *DynamicOptions) ApplyTo(base config.AutoscalingOptions) config.AutoscalingOptions {
+	result := base
+	if o.MaxNodesTotal != nil {
+		result.MaxNodesTotal = *o.MaxNodesTotal
+	}
+	if o.ScaleDownEnabled != nil {
+		result.ScaleDownEnabled = *o.ScaleDownEnabled
+	}
+	if o.ScaleDownDelayAfterAdd != nil {
+		result.ScaleDownDelayAfterAdd = o.ScaleDownDelayAfterAdd.Duration
+	}
+	if o.ScaleDownDelayAfterDelete != nil {
+		result.ScaleDownDelayAfterDelete = o.ScaleDownDelayAfterDelete.Duration
+	}
+	if o.ScaleDownDelayAfterFailure != nil {
+		result.ScaleDownDelayAfterFailure = o.ScaleDownDelayAfterFailure.Duration
+	}
+	if o.ScaleDownNonEmptyCandidatesCount != nil {
+		result.ScaleDownNonEmptyCandidatesCount = *o.ScaleDownNonEmptyCandidatesCount
+	}
+	if o.ScaleDownCandidatesPoolRatio != nil {
+		result.ScaleDownCandidatesPoolRatio = *o.ScaleDownCandidatesPoolRatio
+	}
+	if o.ScaleDownCandidatesPoolMinCount != nil {
+		result.ScaleDownCandidatesPoolMinCount = *o.ScaleDownCandidatesPoolMinCount
+	}
+	if o.MaxScaleDownParallelism != nil {
+		result.MaxScaleDownParallelism = *o.MaxScaleDownParallelism
+	}
+	if o.MaxDrainParallelism != nil {
+		result.MaxDrainParallelism = *o.MaxDrainParallelism
+	}
+	if o.NewPodScaleUpDelay != nil {
+		result.NewPodScaleUpDelay = o.NewPodScaleUpDelay.Duration
+	}
+	if o.ExpanderNames != nil {
+		result.ExpanderNames = *o.ExpanderNames
+	}
+	return result
+}
+
+// Validate checks if the dynamic subset of the options is valid.
+func Validate(options config.AutoscalingOptions) error {
+	if options.MaxNodesTotal < 0 {
+		return fmt.Errorf("max-nodes-total can't be negative, got %d", options.MaxNodesTotal)
+	}
+	for name, value := range map[string]int{
+		"scale-down-non-empty-candidates-count": options.ScaleDownNonEmptyCandidatesCount,
+		"scale-down-candidates-pool-min-count":  options.ScaleDownCandidatesPoolMinCount,
+	} {
+		if value < 0 {
+			return fmt.Errorf("%s can't be negative, got %d", name, value)
+		}
+	}
+	if options.ScaleDownCandidatesPoolRatio < 0 || options.ScaleDownCandidatesPoolRatio > 1 {
+		return fmt.Errorf("scale-down-candidates-pool-ratio must be between 0 and 1, got %v", options.ScaleDownCandidatesPoolRatio)
+	}
+	for name, value := range map[string]int{
+		"max-scale-down-parallelism": options.MaxScaleDownParallelism,
+		"max-drain-parallelism":      options.MaxDrainParallelism,
+	} {
+		if value < 1 {
+			return fmt.Errorf("%s must be positive, got %d", name, value)
+		}
+	}
+	if options.MaxDrainParallelism > 1 && !options.ParallelDrain {
+		return fmt.Errorf("max-drain-parallelism above 1 requires --parallel-drain")
+	}
+	for name, value := range map[string]time.Duration{
+		"scale-down-delay-after-add":     options.ScaleDownDelayAfterAdd,
+		"scale-down-delay-after-delete":  options.ScaleDownDelayAfterDelete,
+		"scale-down-delay-after-failure": options.ScaleDownDelayAfterFailure,
+		"new-pod-scale-up-delay":         options.NewPodScaleUpDelay,
+	} {
+		if value < 0 {
+			return fmt.Errorf("%s can't be negative, got %v", name, value)
+		}
+	}
+	if strings.TrimSpace(options.ExpanderNames) == "" {
+		return fmt.Errorf("expander can't be empty")
+	}
+	return nil
+}
+
+// Change is a change of a single dynamic option.
+type Change struct {
+	// Option is the name of the changed option.
+	Option string
+	// OldValue is the value before the change.
+	OldValue string
+	// NewValue is the value after the change.
+	NewValue string
+}
+
+// Diff returns changes of the dynamic options between the two sets of options.
+func Diff(oldOptions, newOptions config.AutoscalingOptions) []Change {
+	var changes []Change
+	add := func(option string, oldValue, newValue interface{}) {
+		if oldValue != newValue {
+			changes = append(changes, Change{Option: option, OldValue: fmt.Sprint(oldValue), NewValue: fmt.Sprint(newValue)})
+		}
+	}
+	add("max-nodes-total", oldOptions.MaxNodesTotal, newOptions.MaxNodesTotal)
+	add("scale-down-enabled", oldOptions.ScaleDownEnabled, newOptions.ScaleDownEnabled)
+	add("scale-down-delay-after-add", oldOptions.ScaleDownDelayAfterAdd, newOptions.ScaleDownDelayAfterAdd)
+	add("scale-down-delay-after-delete", oldOptions.ScaleDownDelayAfterDelete, newOptions.ScaleDownDelayAfterDelete)
+	add("scale-down-delay-after-failure", oldOptions.ScaleDownDelayAfterFailure, newOptions.ScaleDownDelayAfterFailure)
+	add("scale-down-non-empty-candidates-count", oldOptions.ScaleDownNonEmptyCandidatesCount, newOptions.ScaleDownNonEmptyCandidatesCount)
+	add("scale-down-candidates-pool-ratio", oldOptions.ScaleDownCandidatesPoolRatio, newOptions.ScaleDownCandidatesPoolRatio)
+	add("scale-down-candidates-pool-min-count", oldOptions.ScaleDownCandidatesPoolMinCount, newOptions.ScaleDownCandidatesPoolMinCount)
+	add("max-scale-down-parallelism", oldOptions.MaxScaleDownParallelism, newOptions.MaxScaleDownParallelism)
+	add("max-drain-parallelism", oldOptions.MaxDrainParallelism, newOptions.MaxDrainParallelism)
+	add("new-pod-scale-up-delay", oldOptions.NewPodScaleUpDelay, newOptions.NewPodScaleUpDelay)
+	add("expander", oldOptions.ExpanderNames, newOptions.ExpanderNames)
+	return changes
+}
+
+// Apply copies the dynamic subset of the source options to the target options,
+// leaving all other target options unchanged.
+func Apply(target *config.AutoscalingOptions, source config.AutoscalingOptions) {
+	target.MaxNodesTotal = source.MaxNodesTotal
+	target.ScaleDownEnabled = source.ScaleDownEnabled
+	target.ScaleDownDelayAfterAdd = source.ScaleDownDelayAfterAdd
+	target.ScaleDownDelayAfterDelete = source.ScaleDownDelayAfterDelete
+	target.ScaleDownDelayAfterFailure = source.ScaleDownDelayAfterFailure
+	target.ScaleDownNonEmptyCandidatesCount = source.ScaleDownNonEmptyCandidatesCount
+	target.ScaleDownCandidatesPoolRatio = source.ScaleDownCandidatesPoolRatio
+	target.ScaleDownCandidatesPoolMinCount = source.ScaleDownCandidatesPoolMinCount
+	target.MaxScaleDownParallelism = source.MaxScaleDownParallelism
+	target.MaxDrainParallelism = source.MaxDrainParallelism
+	target.NewPodScaleUpDelay = source.NewPodScaleUpDelay
+	target.ExpanderNames = source.ExpanderNames
+}
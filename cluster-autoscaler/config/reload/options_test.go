@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reload
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+)
+
+func testFlagOptions() config.AutoscalingOptions {
+	return config.AutoscalingOptions{
+		MaxNodesTotal:                    100,
+		ScaleDownEnabled:                 true,
+		ScaleDownDelayAfterAdd:           10 * time.Minute,
+		ScaleDownCandidatesPoolRatio:     0.1,
+		ScaleDownCandidatesPoolMinCount:  50,
+		ScaleDownNonEmptyCandidatesCount: 30,
+		MaxScaleDownParallelism:          10,
+		MaxDrainParallelism:              1,
+		ExpanderNames:                    "random",
+		MaxNodesPerScaleUp:               1000,
+	}
+}
+
+func TestDynamicOptionsApplyTo(t *testing.T) {
+	dynamicOptions, err := ParseDynamicOptions([]byte("max-nodes-total: 50\nscale-down-delay-after-add: 1h\nexpander: least-waste,random"))
+	assert.NoError(t, err)
+	options := dynamicOptions.ApplyTo(testFlagOptions())
+	assert.Equal(t, 50, options.MaxNodesTotal)
+	assert.Equal(t, time.Hour, options.ScaleDownDelayAfterAdd)
+	assert.Equal(t, "least-waste,random", options.ExpanderNames)
+	// Options which are not set keep the flag values.
+	assert.True(t, options.ScaleDownEnabled)
+	assert.Equal(t, 0.1, options.ScaleDownCandidatesPoolRatio)
+
+	_, err = ParseDynamicOptions([]byte("max-node-provision-time: 1h"))
+	assert.Error(t, err, "only dynamic options can be set")
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate(testFlagOptions()))
+	for name, modify := range map[string]func(*config.AutoscalingOptions){
+		"negative max nodes":       func(o *config.AutoscalingOptions) { o.MaxNodesTotal = -1 },
+		"pool ratio above 1":       func(o *config.AutoscalingOptions) { o.ScaleDownCandidatesPoolRatio = 1.5 },
+		"zero parallelism":         func(o *config.AutoscalingOptions) { o.MaxScaleDownParallelism = 0 },
+		"drain without parallel":   func(o *config.AutoscalingOptions) { o.MaxDrainParallelism = 2 },
+		"negative delay":           func(o *config.AutoscalingOptions) { o.ScaleDownDelayAfterAdd = -time.Minute },
+		"no expander":              func(o *config.AutoscalingOptions) { o.ExpanderNames = " " },
+		"negative candidate count": func(o *config.AutoscalingOptions) { o.ScaleDownNonEmptyCandidatesCount = -5 },
+	} {
+		t.Run(name, func(t *testing.T) {
+			options := testFlagOptions()
+			modify(&options)
+			assert.Error(t, Validate(options))
+		})
+	}
+}
+
+func TestDiffAndApply(t *testing.T) {
+	oldOptions := testFlagOptions()
+	newOptions := testFlagOptions()
+	newOptions.MaxNodesTotal = 10
+	newOptions.ScaleDownEnabled = false
+	newOptions.MaxNodesPerScaleUp = 5
+	assert.Equal(t, []Change{
+		{Option: "max-nodes-total", OldValue: "100", NewValue: "10"},
+		{Option: "scale-down-enabled", OldValue: "true", NewValue: "false"},
+	}, Diff(oldOptions, newOptions))
+
+	Apply(&oldOptions, newOptions)
+	assert.Equal(t, 10, oldOptions.MaxNodesTotal)
+	assert.False(t, oldOptions.ScaleDownEnabled)
+	// Other options are never applied.
+	assert.Equal(t, 1000, oldOptions.MaxNodesPerScaleUp)
+}
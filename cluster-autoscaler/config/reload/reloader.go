@@ -0,0 +1,185 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reload
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"k8s.io/autoscaler/cluster-autoscaler/expander"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
+	kube_client "k8s.io/client-go/kubernetes"
+	v1lister "k8s.io/client-go/listers/core/v1"
+)
+
+// DynamicOptionsConfigMapKey is the ConfigMap key holding the dynamic options.
+const DynamicOptionsConfigMapKey = "options"
+
+// Source provides serialized DynamicOptions.
+type Source interface {
+	// Load returns the current dynamic options, or nil if there are none.
+	Load() ([]byte, error)
+}
+
+type fileSource struct {
+	path string
+}
+
+// NewFileSource returns a source reading dynamic options from a file, e.g.
+// one mounted from a ConfigMap.
+func NewFileSource(path string) Source {
+	return &fileSource{path: path}
+}
+
+func (s *fileSource) Load() ([]byte, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dynamic options file %s: %v", s.path, err)
+	}
+	return data, nil
+}
+
+type configMapSource struct {
+	lister        v1lister.ConfigMapNamespaceLister
+	configMapName string
+}
+
+// NewConfigMapSource returns a source reading dynamic options from the
+// DynamicOptionsConfigMapKey key of a ConfigMap.
+func NewConfigMapSource(lister v1lister.ConfigMapNamespaceLister, configMapName string) Source {
+	return &configMapSource{lister: lister, configMapName: configMapName}
+}
+
+func (s *configMapSource) Load() ([]byte, error) {
+	cm, err := s.lister.Get(s.configMapName)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dynamic options ConfigMap %s: %v", s.configMapName, err)
+	}
+	data, found := cm.Data[DynamicOptionsConfigMapKey]
+	if !found {
+		return nil, nil
+	}
+	return []byte(data), nil
+}
+
+// NewSource returns the source of dynamic options configured by the options:
+// DynamicOptionsFile, or the DynamicOptionsConfigMapName ConfigMap in
+// ConfigNamespace. It returns nil if neither is set.
+func NewSource(opts config.AutoscalingOptions, kubeClient kube_client.Interface) Source {
+	if opts.DynamicOptionsFile != "" {
+		return NewFileSource(opts.DynamicOptionsFile)
+	}
+	if opts.DynamicOptionsConfigMapName != "" {
+		// The lister runs for the whole lifetime of the process, same as the
+		// one used by the priority expander.
+		stopChannel := make(chan struct{})
+		lister := kubernetes.NewConfigMapListerForNamespace(kubeClient, stopChannel, opts.ConfigNamespace)
+		return NewConfigMapSource(lister.ConfigMaps(opts.ConfigNamespace), opts.DynamicOptionsConfigMapName)
+	}
+	return nil
+}
+
+// ExpanderBuilder builds an expander strategy from a list of expander names.
+type ExpanderBuilder func(names []string) (expander.Strategy, error)
+
+// Update is a validated change of the dynamic options.
+type Update struct {
+	// Options are the flag options with the dynamic options applied.
+	Options config.AutoscalingOptions
+	// Changes lists the options which changed.
+	Changes []Change
+	// ExpanderStrategy is the strategy built for the new expander names, nil
+	// if they didn't change.
+	ExpanderStrategy expander.Strategy
+}
+
+// Reloader reads dynamic options from a source and turns them into updates
+// of the options Cluster Autoscaler is running with.
+type Reloader struct {
+	source        Source
+	flagOptions   config.AutoscalingOptions
+	buildExpander ExpanderBuilder
+	loaded        bool
+	lastData      []byte
+}
+
+// NewReloader returns a Reloader applying dynamic options read from the
+// source on top of the options set by flags. Expanders can only be changed
+// if buildExpander is not nil.
+func NewReloader(source Source, flagOptions config.AutoscalingOptions, buildExpander ExpanderBuilder) *Reloader {
+	return &Reloader{
+		source:        source,
+		flagOptions:   flagOptions,
+		buildExpander: buildExpander,
+	}
+}
+
+// Reload reads the source and returns the update of the current options. It
+// returns nil if the source or the options didn't change since the last call.
+// An invalid source is reported once, the current options should be kept
+// until the source changes.
+func (r *Reloader) Reload(current config.AutoscalingOptions) (*Update, error) {
+	data, err := r.source.Load()
+	if err != nil {
+		return nil, err
+	}
+	if r.loaded && bytes.Equal(data, r.lastData) {
+		return nil, nil
+	}
+	r.loaded = true
+	r.lastData = data
+
+	dynamicOptions := &DynamicOptions{}
+	if data != nil {
+		dynamicOptions, err = ParseDynamicOptions(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	options := dynamicOptions.ApplyTo(r.flagOptions)
+	if err := Validate(options); err != nil {
+		return nil, fmt.Errorf("invalid dynamic options: %v", err)
+	}
+	update := &Update{
+		Options: options,
+		Changes: Diff(current, options),
+	}
+	if len(update.Changes) == 0 {
+		return nil, nil
+	}
+	if options.ExpanderNames != current.ExpanderNames {
+		if r.buildExpander == nil {
+			return nil, fmt.Errorf("invalid dynamic options: expander can't be changed")
+		}
+		strategy, err := r.buildExpander(strings.Split(options.ExpanderNames, ","))
+		if err != nil {
+			return nil, fmt.Errorf("invalid dynamic options: %v", err)
+		}
+		update.ExpanderStrategy = strategy
+	}
+	return update, nil
+}
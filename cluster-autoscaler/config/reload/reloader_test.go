@@ -0,0 +1,141 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reload
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/expander"
+	"k8s.io/autoscaler/cluster-autoscaler/expander/random"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/errors"
+	"k8s.io/client-go/kubernetes/fake"
+	v1lister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+type memorySource struct {
+	data []byte
+	err  error
+}
+
+func (s *memorySource) Load() ([]byte, error) {
+	return s.data, s.err
+}
+
+func TestReloader(t *testing.T) {
+	var builtExpanders []string
+	buildExpander := func(names []string) (expander.Strategy, error) {
+		if names[0] == "unknown" {
+			return nil, errors.NewAutoscalerError(errors.InternalError, "Expander unknown not supported")
+		}
+		builtExpanders = append(builtExpanders, strings.Join(names, ","))
+		return random.NewStrategy(), nil
+	}
+	source := &memorySource{}
+	reloader := NewReloader(source, testFlagOptions(), buildExpander)
+	current := testFlagOptions()
+
+	// No dynamic options, nothing changes.
+	update, err := reloader.Reload(current)
+	assert.NoError(t, err)
+	assert.Nil(t, update)
+
+	source.data = []byte("max-nodes-total: 10\nexpander: least-waste")
+	update, err = reloader.Reload(current)
+	assert.NoError(t, err)
+	if assert.NotNil(t, update) {
+		assert.Len(t, update.Changes, 2)
+		assert.NotNil(t, update.ExpanderStrategy)
+		assert.Equal(t, []string{"least-waste"}, builtExpanders)
+		Apply(&current, update.Options)
+	}
+
+	// Unchanged source.
+	update, err = reloader.Reload(current)
+	assert.NoError(t, err)
+	assert.Nil(t, update)
+
+	// Invalid options are reported once and rejected as a whole.
+	source.data = []byte("max-nodes-total: 20\nexpander: unknown")
+	_, err = reloader.Reload(current)
+	assert.Error(t, err)
+	update, err = reloader.Reload(current)
+	assert.NoError(t, err)
+	assert.Nil(t, update)
+	assert.Equal(t, 10, current.MaxNodesTotal)
+
+	source.data = []byte("max-nodes-total: -1")
+	_, err = reloader.Reload(current)
+	assert.Error(t, err)
+
+	// Removing an option reverts it to the flag value.
+	source.data = []byte("expander: least-waste")
+	update, err = reloader.Reload(current)
+	assert.NoError(t, err)
+	if assert.NotNil(t, update) {
+		assert.Equal(t, []Change{{Option: "max-nodes-total", OldValue: "10", NewValue: "100"}}, update.Changes)
+		assert.Nil(t, update.ExpanderStrategy)
+	}
+}
+
+func TestReloaderWithoutExpanderBuilder(t *testing.T) {
+	source := &memorySource{data: []byte("expander: least-waste")}
+	_, err := NewReloader(source, testFlagOptions(), nil).Reload(testFlagOptions())
+	assert.Error(t, err)
+}
+
+func TestFileSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "options.yaml")
+	source := NewFileSource(path)
+	data, err := source.Load()
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+
+	assert.NoError(t, os.WriteFile(path, []byte("max-nodes-total: 5"), 0644))
+	data, err = source.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "max-nodes-total: 5", string(data))
+}
+
+func TestConfigMapSource(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	source := NewConfigMapSource(v1lister.NewConfigMapLister(indexer).ConfigMaps("kube-system"), "autoscaler-options")
+	data, err := source.Load()
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+
+	assert.NoError(t, indexer.Add(&apiv1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "autoscaler-options"},
+		Data:       map[string]string{DynamicOptionsConfigMapKey: "scale-down-enabled: false"},
+	}))
+	data, err = source.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "scale-down-enabled: false", string(data))
+}
+
+func TestNewSource(t *testing.T) {
+	assert.Nil(t, NewSource(testFlagOptions(), fake.NewSimpleClientset()))
+	options := testFlagOptions()
+	options.DynamicOptionsFile = "/etc/autoscaler/options.yaml"
+	assert.NotNil(t, NewSource(options, fake.NewSimpleClientset()))
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"os"
+
+	apiv1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// TenantQuotas limit the resources requested by the pods of each tenant that
+// scale-up can make room for. Pods are attributed to tenants by namespace, or
+// by the value of TenantLabel if it is set.
+type TenantQuotas struct {
+	// TenantLabel is the pod label identifying the tenant of a pod. Pods are
+	// attributed to their namespace if it's empty. Pods without the label
+	// belong to the "" tenant.
+	TenantLabel string `json:"tenantLabel,omitempty"`
+	// Quotas maps tenants to the maximum total requests of their pods. The
+	// "pods" resource limits the number of pods.
+	Quotas map[string]apiv1.ResourceList `json:"quotas"`
+	// DefaultQuota applies to tenants not listed in Quotas. Such tenants
+	// aren't limited if it's not set.
+	DefaultQuota apiv1.ResourceList `json:"defaultQuota,omitempty"`
+}
+
+// ParseTenantQuotas parses tenant quotas in YAML or JSON format.
+func ParseTenantQuotas(data []byte) (*TenantQuotas, error) {
+	quotas := &TenantQuotas{}
+	if err := yaml.UnmarshalStrict(data, quotas); err != nil {
+		return nil, fmt.Errorf("failed to parse tenant quotas: %v", err)
+	}
+	if err := quotas.validate(); err != nil {
+		return nil, fmt.Errorf("invalid tenant quotas: %v", err)
+	}
+	return quotas, nil
+}
+
+// LoadTenantQuotas reads tenant quotas from a file.
+func LoadTenantQuotas(path string) (*TenantQuotas, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tenant quotas %s: %v", path, err)
+	}
+	return ParseTenantQuotas(data)
+}
+
+func (q *TenantQuotas) validate() error {
+	if len(q.Quotas) == 0 && q.DefaultQuota == nil {
+		return fmt.Errorf("no quotas defined")
+	}
+	for tenant, quota := range q.Quotas {
+		if err := validateQuota(quota); err != nil {
+			return fmt.Errorf("quota of tenant %s: %v", tenant, err)
+		}
+	}
+	if err := validateQuota(q.DefaultQuota); err != nil {
+		return fmt.Errorf("default quota: %v", err)
+	}
+	return nil
+}
+
+func validateQuota(quota apiv1.ResourceList) error {
+	for name, value := range quota {
+		if value.Sign() < 0 {
+			return fmt.Errorf("%s can't be negative, got %s", name, value.String())
+		}
+	}
+	return nil
+}
+
+// Tenant returns the tenant of the pod.
+func (q *TenantQuotas) Tenant(pod *apiv1.Pod) string {
+	if q.TenantLabel != "" {
+		return pod.Labels[q.TenantLabel]
+	}
+	return pod.Namespace
+}
+
+// Quota returns the quota of the tenant, or false if the tenant isn't limited.
+func (q *TenantQuotas) Quota(tenant string) (apiv1.ResourceList, bool) {
+	if quota, found := q.Quotas[tenant]; found {
+		return quota, true
+	}
+	return q.DefaultQuota, q.DefaultQuota != nil
+}
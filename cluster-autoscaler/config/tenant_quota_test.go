@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseTenantQuotas(t *testing.T) {
+	quotas, err := ParseTenantQuotas([]byte(`
+tenantLabel: team
+quotas:
+  team-a:
+    cpu: "100"
+    memory: 400Gi
+    nvidia.com/gpu: 8
+defaultQuota:
+  pods: 50
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, "team", quotas.TenantLabel)
+	assert.Equal(t, resource.MustParse("400Gi"), quotas.Quotas["team-a"][apiv1.ResourceMemory])
+	assert.Equal(t, resource.MustParse("8"), quotas.Quotas["team-a"]["nvidia.com/gpu"])
+
+	quota, limited := quotas.Quota("team-a")
+	assert.True(t, limited)
+	assert.Equal(t, resource.MustParse("100"), quota[apiv1.ResourceCPU])
+	quota, limited = quotas.Quota("team-b")
+	assert.True(t, limited)
+	assert.Equal(t, resource.MustParse("50"), quota[apiv1.ResourcePods])
+
+	pod := &apiv1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Labels: map[string]string{"team": "team-a"}}}
+	assert.Equal(t, "team-a", quotas.Tenant(pod))
+	quotas.TenantLabel = ""
+	assert.Equal(t, "ns", quotas.Tenant(pod))
+
+	quotas.DefaultQuota = nil
+	_, limited = quotas.Quota("team-b")
+	assert.False(t, limited)
+}
+
+func TestParseTenantQuotasErrors(t *testing.T) {
+	for name, data := range map[string]string{
+		"no quotas":      `tenantLabel: team`,
+		"negative value": "quotas:\n  a:\n    cpu: -1",
+		"invalid value":  "quotas:\n  a:\n    cpu: lots",
+		"unknown field":  "quota:\n  a:\n    cpu: 1",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseTenantQuotas([]byte(data))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditlog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	gRPCTimeout = 5 * time.Second
+	// writeMethod is the full name of the Write method of the AuditLog service
+	// defined in protos/auditlog.proto.
+	writeMethod = "/auditlog.AuditLog/Write"
+)
+
+// GRPCSink sends records to a gRPC server implementing the AuditLog service.
+type GRPCSink struct {
+	conn *grpc.ClientConn
+}
+
+// NewGRPCSink returns a sink sending records to the server at url, over a
+// TLS connection verified with the given certificate.
+func NewGRPCSink(url string, cert string) (*GRPCSink, error) {
+	if cert == "" {
+		return nil, fmt.Errorf("audit log gRPC cert not specified, insecure connections not allowed")
+	}
+	creds, err := credentials.NewClientTLSFromFile(cert, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create TLS credentials: %v", err)
+	}
+	klog.V(2).Infof("Dialing audit log server: %s", url)
+	conn, err := grpc.Dial(url, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial audit log server %s: %v", url, err)
+	}
+	return newGRPCSink(conn), nil
+}
+
+func newGRPCSink(conn *grpc.ClientConn) *GRPCSink {
+	return &GRPCSink{conn: conn}
+}
+
+// Write sends the record to the server.
+func (s *GRPCSink) Write(record *Record) error {
+	request, err := recordToStruct(record)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), gRPCTimeout)
+	defer cancel()
+	return s.conn.Invoke(ctx, writeMethod, request, &emptypb.Empty{})
+}
+
+// Close closes the connection to the server.
+func (s *GRPCSink) Close() error {
+	return s.conn.Close()
+}
+
+func recordToStruct(record *Record) (*structpb.Struct, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return structpb.NewStruct(fields)
+}
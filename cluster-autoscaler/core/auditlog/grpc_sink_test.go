@@ -0,0 +1,76 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditlog
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// fakeAuditLogServer implements the AuditLog service from protos/auditlog.proto.
+type fakeAuditLogServer struct {
+	records chan *structpb.Struct
+}
+
+func (s *fakeAuditLogServer) write(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+	record := &structpb.Struct{}
+	if err := dec(record); err != nil {
+		return nil, err
+	}
+	s.records <- record
+	return &emptypb.Empty{}, nil
+}
+
+func TestGRPCSink(t *testing.T) {
+	fakeServer := &fakeAuditLogServer{records: make(chan *structpb.Struct, 1)}
+	server := grpc.NewServer()
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "auditlog.AuditLog",
+		HandlerType: (*interface{})(nil),
+		Methods:     []grpc.MethodDesc{{MethodName: "Write", Handler: fakeServer.write}},
+	}, fakeServer)
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	sink := newGRPCSink(conn)
+	defer sink.Close()
+
+	err = sink.Write(&Record{
+		Time:    time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC),
+		ScaleUp: ScaleUpRecord{Result: "ScaleUpSuccessful", ChosenNodeGroup: "ng1"},
+	})
+	assert.NoError(t, err)
+	record := <-fakeServer.records
+	assert.Equal(t, "2024-05-06T07:08:09Z", record.Fields["time"].GetStringValue())
+	scaleUp := record.Fields["scaleUp"].GetStructValue()
+	assert.Equal(t, "ScaleUpSuccessful", scaleUp.Fields["result"].GetStringValue())
+	assert.Equal(t, "ng1", scaleUp.Fields["chosenNodeGroup"].GetStringValue())
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	klog "k8s.io/klog/v2"
+)
+
+// queueSize is the number of records waiting to be written before new ones
+// are dropped.
+const queueSize = 100
+
+// Sink is a destination of audit log records.
+type Sink interface {
+	// Write writes a single record.
+	Write(record *Record) error
+	// Close releases resources held by the sink.
+	Close() error
+}
+
+// FileSink writes records to a file as JSON lines.
+type FileSink struct {
+	file    *os.File
+	encoder *json.Encoder
+}
+
+// NewFileSink returns a sink appending records to the file at path, creating
+// it if needed.
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log file %s: %v", path, err)
+	}
+	return &FileSink{file: file, encoder: json.NewEncoder(file)}, nil
+}
+
+// Write appends the record to the file as a single line.
+func (s *FileSink) Write(record *Record) error {
+	return s.encoder.Encode(record)
+}
+
+// Close closes the file.
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
+
+// Logger writes records to sinks in the background, so that a slow sink
+// doesn't delay the autoscaling loop. Records are dropped if the sinks can't
+// keep up.
+type Logger struct {
+	sinks   []Sink
+	records chan *Record
+	wg      sync.WaitGroup
+	// mutex guards stopped, so that records aren't sent to the closed channel.
+	mutex   sync.Mutex
+	stopped bool
+}
+
+// NewLogger returns a logger writing records to all the given sinks.
+func NewLogger(sinks ...Sink) *Logger {
+	l := &Logger{
+		sinks:   sinks,
+		records: make(chan *Record, queueSize),
+	}
+	l.wg.Add(1)
+	go l.run()
+	return l
+}
+
+// NewLoggerFromOptions returns a logger writing to the sinks configured in
+// the options, or nil if none is configured.
+func NewLoggerFromOptions(opts config.AutoscalingOptions) (*Logger, error) {
+	var sinks []Sink
+	if opts.AuditLogFile != "" {
+		sink, err := NewFileSink(opts.AuditLogFile)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	if opts.AuditLogGRPCURL != "" {
+		sink, err := NewGRPCSink(opts.AuditLogGRPCURL, opts.AuditLogGRPCCert)
+		if err != nil {
+			for _, s := range sinks {
+				s.Close()
+			}
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	if len(sinks) == 0 {
+		return nil, nil
+	}
+	return NewLogger(sinks...), nil
+}
+
+// Log queues the record for writing. Records logged after Stop are dropped.
+func (l *Logger) Log(record *Record) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.stopped {
+		klog.Warningf("Audit log is stopped, dropping record from %v", record.Time)
+		return
+	}
+	select {
+	case l.records <- record:
+	default:
+		klog.Warningf("Audit log queue is full, dropping record from %v", record.Time)
+	}
+}
+
+// Stop writes all queued records and closes the sinks. Subsequent calls are
+// no-ops.
+func (l *Logger) Stop() {
+	l.mutex.Lock()
+	if l.stopped {
+		l.mutex.Unlock()
+		return
+	}
+	l.stopped = true
+	close(l.records)
+	l.mutex.Unlock()
+	l.wg.Wait()
+	for _, sink := range l.sinks {
+		if err := sink.Close(); err != nil {
+			klog.Errorf("Failed to close audit log sink: %v", err)
+		}
+	}
+}
+
+func (l *Logger) run() {
+	defer l.wg.Done()
+	for record := range l.records {
+		for _, sink := range l.sinks {
+			if err := sink.Write(record); err != nil {
+				klog.Errorf("Failed to write audit log record: %v", err)
+			}
+		}
+	}
+}
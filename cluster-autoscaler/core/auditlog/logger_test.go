@@ -0,0 +1,104 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+)
+
+func TestLoggerWritesJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	logger, err := NewLoggerFromOptions(config.AutoscalingOptions{AuditLogFile: path})
+	assert.NoError(t, err)
+
+	start := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		logger.Log(&Record{
+			Time:    start.Add(time.Duration(i) * time.Minute),
+			ScaleUp: ScaleUpRecord{Result: "ScaleUpNotNeeded"},
+			ScaleDown: ScaleDownRecord{
+				Result: "ScaleDownNoNodeDeleted",
+				Nodes:  []NodeRecord{{Node: "n1", NodeGroup: "ng1", Verdict: NodeUnneeded}},
+			},
+		})
+	}
+	logger.Stop()
+
+	file, err := os.Open(path)
+	assert.NoError(t, err)
+	defer file.Close()
+	var records []Record
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record Record
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	if assert.Len(t, records, 3) {
+		assert.Equal(t, start.Add(2*time.Minute), records[2].Time)
+		assert.Equal(t, []NodeRecord{{Node: "n1", NodeGroup: "ng1", Verdict: NodeUnneeded}}, records[0].ScaleDown.Nodes)
+	}
+}
+
+type recordingSink struct {
+	records []*Record
+	closed  int
+}
+
+func (s *recordingSink) Write(record *Record) error {
+	s.records = append(s.records, record)
+	return nil
+}
+
+func (s *recordingSink) Close() error {
+	s.closed++
+	return nil
+}
+
+func TestLoggerLogAfterStop(t *testing.T) {
+	sink := &recordingSink{}
+	logger := NewLogger(sink)
+	logger.Log(&Record{ScaleUp: ScaleUpRecord{Result: "ScaleUpNotNeeded"}})
+	logger.Stop()
+
+	assert.NotPanics(t, func() { logger.Log(&Record{ScaleUp: ScaleUpRecord{Result: "ScaleUpSuccessful"}}) })
+	assert.NotPanics(t, logger.Stop)
+	if assert.Len(t, sink.records, 1) {
+		assert.Equal(t, "ScaleUpNotNeeded", sink.records[0].ScaleUp.Result)
+	}
+	assert.Equal(t, 1, sink.closed)
+}
+
+func TestNewLoggerFromOptions(t *testing.T) {
+	logger, err := NewLoggerFromOptions(config.AutoscalingOptions{})
+	assert.NoError(t, err)
+	assert.Nil(t, logger)
+
+	_, err = NewLoggerFromOptions(config.AutoscalingOptions{AuditLogFile: filepath.Join(t.TempDir(), "missing", "audit.log")})
+	assert.Error(t, err)
+
+	_, err = NewLoggerFromOptions(config.AutoscalingOptions{AuditLogGRPCURL: "localhost:1234"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,380 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditlog
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	scaledownstatus "k8s.io/autoscaler/cluster-autoscaler/core/scaledown/status"
+	"k8s.io/autoscaler/cluster-autoscaler/expander"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/status"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator"
+)
+
+// NodeVerdict is the scale-down decision made for a node.
+type NodeVerdict string
+
+const (
+	// NodeRemoved - the node's deletion was started in the loop.
+	NodeRemoved NodeVerdict = "Removed"
+	// NodeUnneeded - the node was found unneeded, but wasn't removed yet.
+	NodeUnneeded NodeVerdict = "Unneeded"
+	// NodeUnremovable - the node can't be removed, see the reason.
+	NodeUnremovable NodeVerdict = "Unremovable"
+)
+
+// Record describes the decisions made in a single autoscaling loop iteration.
+type Record struct {
+	Time      time.Time       `json:"time"`
+	ScaleUp   ScaleUpRecord   `json:"scaleUp"`
+	ScaleDown ScaleDownRecord `json:"scaleDown"`
+}
+
+// ScaleUpRecord describes the scale-up decision.
+type ScaleUpRecord struct {
+	Result string `json:"result"`
+	Error  string `json:"error,omitempty"`
+	// CandidateNodeGroups are ids of all node groups considered for the scale-up.
+	CandidateNodeGroups []string `json:"candidateNodeGroups,omitempty"`
+	// Options are the estimator results for node groups which could help
+	// at least one pod.
+	Options []OptionRecord `json:"options,omitempty"`
+	// Expander lists results of the expander filters, in the order they were run.
+	Expander []ExpanderFilterRecord `json:"expander,omitempty"`
+	// ChosenNodeGroup is the id of the node group picked by the expander.
+	ChosenNodeGroup   string              `json:"chosenNodeGroup,omitempty"`
+	ScaleUps          []ScaleUpInfoRecord `json:"scaleUps,omitempty"`
+	TriggeringPods    []string            `json:"triggeringPods,omitempty"`
+	UnschedulablePods []PodRecord         `json:"unschedulablePods,omitempty"`
+}
+
+// OptionRecord describes an expansion option computed by the estimator.
+type OptionRecord struct {
+	NodeGroup         string   `json:"nodeGroup"`
+	SimilarNodeGroups []string `json:"similarNodeGroups,omitempty"`
+	NodeCount         int      `json:"nodeCount"`
+	Pods              []string `json:"pods,omitempty"`
+}
+
+// ExpanderFilterRecord describes how a single expander filter narrowed down
+// the options.
+type ExpanderFilterRecord struct {
+	Name         string             `json:"name"`
+	Scores       map[string]float64 `json:"scores,omitempty"`
+	Explanations map[string]string  `json:"explanations,omitempty"`
+	Options      []string           `json:"options"`
+}
+
+// ScaleUpInfoRecord describes a resize of a single node group.
+type ScaleUpInfoRecord struct {
+	NodeGroup   string `json:"nodeGroup"`
+	CurrentSize int    `json:"currentSize"`
+	NewSize     int    `json:"newSize"`
+	MaxSize     int    `json:"maxSize"`
+}
+
+// PodRecord describes a pending pod which didn't trigger a scale-up, along
+// with the reasons reported by every node group.
+type PodRecord struct {
+	Pod                string              `json:"pod"`
+	RejectedNodeGroups map[string][]string `json:"rejectedNodeGroups,omitempty"`
+	SkippedNodeGroups  map[string][]string `json:"skippedNodeGroups,omitempty"`
+}
+
+// ScaleDownRecord describes the scale-down decision.
+type ScaleDownRecord struct {
+	Result            string               `json:"result"`
+	Nodes             []NodeRecord         `json:"nodes,omitempty"`
+	RemovedNodeGroups []string             `json:"removedNodeGroups,omitempty"`
+	DeleteResults     []DeleteResultRecord `json:"deleteResults,omitempty"`
+}
+
+// NodeRecord describes the scale-down verdict for a single node.
+type NodeRecord struct {
+	Node        string      `json:"node"`
+	NodeGroup   string      `json:"nodeGroup,omitempty"`
+	Verdict     NodeVerdict `json:"verdict"`
+	Utilization *float64    `json:"utilization,omitempty"`
+	// Reason is the reason why an unremovable node can't be removed.
+	Reason            string   `json:"reason,omitempty"`
+	BlockingPod       string   `json:"blockingPod,omitempty"`
+	BlockingPodReason string   `json:"blockingPodReason,omitempty"`
+	EvictedPods       []string `json:"evictedPods,omitempty"`
+}
+
+// DeleteResultRecord describes the result of a node deletion finished since
+// the previous loop.
+type DeleteResultRecord struct {
+	Node   string `json:"node"`
+	Result string `json:"result"`
+	Error  string `json:"error,omitempty"`
+}
+
+// NewRecord builds the record of a loop iteration from the statuses of the
+// scale-up and scale-down, and the nodes found unneeded by the scale-down
+// planner.
+func NewRecord(now time.Time, scaleUpStatus *status.ScaleUpStatus, scaleDownStatus *scaledownstatus.ScaleDownStatus, unneededNodes []*apiv1.Node, cp cloudprovider.CloudProvider) *Record {
+	record := &Record{Time: now}
+	if scaleUpStatus != nil {
+		record.ScaleUp = newScaleUpRecord(scaleUpStatus)
+	}
+	if scaleDownStatus != nil {
+		record.ScaleDown = newScaleDownRecord(scaleDownStatus, unneededNodes, cp)
+	}
+	return record
+}
+
+func newScaleUpRecord(s *status.ScaleUpStatus) ScaleUpRecord {
+	record := ScaleUpRecord{Result: scaleUpResultName(s.Result)}
+	if s.ScaleUpError != nil && *s.ScaleUpError != nil {
+		record.Error = (*s.ScaleUpError).Error()
+	}
+	for _, nodeGroup := range s.ConsideredNodeGroups {
+		record.CandidateNodeGroups = append(record.CandidateNodeGroups, nodeGroup.Id())
+	}
+	for _, option := range s.ExpansionOptions {
+		record.Options = append(record.Options, newOptionRecord(option))
+	}
+	for _, result := range s.ExpanderResults {
+		record.Expander = append(record.Expander, ExpanderFilterRecord{
+			Name:         result.Name,
+			Scores:       result.Scores,
+			Explanations: result.Explanations,
+			Options:      result.Options,
+		})
+	}
+	if s.BestOption != nil && s.BestOption.NodeGroup != nil {
+		record.ChosenNodeGroup = s.BestOption.NodeGroup.Id()
+	}
+	for _, info := range s.ScaleUpInfos {
+		record.ScaleUps = append(record.ScaleUps, ScaleUpInfoRecord{
+			NodeGroup:   info.Group.Id(),
+			CurrentSize: info.CurrentSize,
+			NewSize:     info.NewSize,
+			MaxSize:     info.MaxSize,
+		})
+	}
+	record.TriggeringPods = podNames(s.PodsTriggeredScaleUp)
+	for _, noScaleUp := range s.PodsRemainUnschedulable {
+		record.UnschedulablePods = append(record.UnschedulablePods, PodRecord{
+			Pod:                podName(noScaleUp.Pod),
+			RejectedNodeGroups: reasonsByNodeGroup(noScaleUp.RejectedNodeGroups),
+			SkippedNodeGroups:  reasonsByNodeGroup(noScaleUp.SkippedNodeGroups),
+		})
+	}
+	return record
+}
+
+func newOptionRecord(option expander.Option) OptionRecord {
+	record := OptionRecord{
+		NodeCount: option.NodeCount,
+		Pods:      podNames(option.Pods),
+	}
+	if option.NodeGroup != nil {
+		record.NodeGroup = option.NodeGroup.Id()
+	}
+	for _, nodeGroup := range option.SimilarNodeGroups {
+		record.SimilarNodeGroups = append(record.SimilarNodeGroups, nodeGroup.Id())
+	}
+	return record
+}
+
+func newScaleDownRecord(s *scaledownstatus.ScaleDownStatus, unneededNodes []*apiv1.Node, cp cloudprovider.CloudProvider) ScaleDownRecord {
+	record := ScaleDownRecord{Result: scaleDownResultName(s.Result)}
+	removed := map[string]bool{}
+	for _, node := range s.ScaledDownNodes {
+		removed[node.Node.Name] = true
+		utilization := node.UtilInfo.Utilization
+		record.Nodes = append(record.Nodes, NodeRecord{
+			Node:        node.Node.Name,
+			NodeGroup:   nodeGroupId(node.NodeGroup),
+			Verdict:     NodeRemoved,
+			Utilization: &utilization,
+			EvictedPods: podNames(node.EvictedPods),
+		})
+	}
+	for _, node := range unneededNodes {
+		if removed[node.Name] {
+			continue
+		}
+		nodeRecord := NodeRecord{Node: node.Name, Verdict: NodeUnneeded}
+		if cp != nil {
+			if nodeGroup, err := cp.NodeGroupForNode(node); err == nil {
+				nodeRecord.NodeGroup = nodeGroupId(nodeGroup)
+			}
+		}
+		record.Nodes = append(record.Nodes, nodeRecord)
+	}
+	for _, node := range s.UnremovableNodes {
+		nodeRecord := NodeRecord{
+			Node:      node.Node.Name,
+			NodeGroup: nodeGroupId(node.NodeGroup),
+			Verdict:   NodeUnremovable,
+			Reason:    unremovableReasonName(node.Reason),
+		}
+		if node.UtilInfo != nil {
+			utilization := node.UtilInfo.Utilization
+			nodeRecord.Utilization = &utilization
+		}
+		if node.BlockingPod != nil && node.BlockingPod.Pod != nil {
+			nodeRecord.BlockingPod = podName(node.BlockingPod.Pod)
+			nodeRecord.BlockingPodReason = node.BlockingPod.Reason.String()
+		}
+		record.Nodes = append(record.Nodes, nodeRecord)
+	}
+	sort.SliceStable(record.Nodes, func(i, j int) bool { return record.Nodes[i].Node < record.Nodes[j].Node })
+
+	for _, nodeGroup := range s.RemovedNodeGroups {
+		record.RemovedNodeGroups = append(record.RemovedNodeGroups, nodeGroup.Id())
+	}
+	for node, result := range s.NodeDeleteResults {
+		deleteResult := DeleteResultRecord{Node: node, Result: nodeDeleteResultName(result.ResultType)}
+		if result.Err != nil {
+			deleteResult.Error = result.Err.Error()
+		}
+		record.DeleteResults = append(record.DeleteResults, deleteResult)
+	}
+	sort.Slice(record.DeleteResults, func(i, j int) bool { return record.DeleteResults[i].Node < record.DeleteResults[j].Node })
+	return record
+}
+
+func nodeGroupId(nodeGroup cloudprovider.NodeGroup) string {
+	if nodeGroup == nil {
+		return ""
+	}
+	return nodeGroup.Id()
+}
+
+func podName(pod *apiv1.Pod) string {
+	return pod.Namespace + "/" + pod.Name
+}
+
+func podNames(pods []*apiv1.Pod) []string {
+	var names []string
+	for _, pod := range pods {
+		names = append(names, podName(pod))
+	}
+	return names
+}
+
+func reasonsByNodeGroup(reasons map[string]status.Reasons) map[string][]string {
+	if len(reasons) == 0 {
+		return nil
+	}
+	result := make(map[string][]string, len(reasons))
+	for nodeGroup, r := range reasons {
+		if r == nil {
+			continue
+		}
+		result[nodeGroup] = r.Reasons()
+	}
+	return result
+}
+
+func scaleUpResultName(result status.ScaleUpResult) string {
+	switch result {
+	case status.ScaleUpSuccessful:
+		return "ScaleUpSuccessful"
+	case status.ScaleUpError:
+		return "ScaleUpError"
+	case status.ScaleUpNoOptionsAvailable:
+		return "ScaleUpNoOptionsAvailable"
+	case status.ScaleUpNotNeeded:
+		return "ScaleUpNotNeeded"
+	case status.ScaleUpNotTried:
+		return "ScaleUpNotTried"
+	case status.ScaleUpInCooldown:
+		return "ScaleUpInCooldown"
+	}
+	return fmt.Sprintf("ScaleUpResult(%d)", result)
+}
+
+func scaleDownResultName(result scaledownstatus.ScaleDownResult) string {
+	switch result {
+	case scaledownstatus.ScaleDownError:
+		return "ScaleDownError"
+	case scaledownstatus.ScaleDownNoUnneeded:
+		return "ScaleDownNoUnneeded"
+	case scaledownstatus.ScaleDownNoNodeDeleted:
+		return "ScaleDownNoNodeDeleted"
+	case scaledownstatus.ScaleDownNodeDeleteStarted:
+		return "ScaleDownNodeDeleteStarted"
+	case scaledownstatus.ScaleDownNotTried:
+		return "ScaleDownNotTried"
+	case scaledownstatus.ScaleDownInCooldown:
+		return "ScaleDownInCooldown"
+	case scaledownstatus.ScaleDownInProgress:
+		return "ScaleDownInProgress"
+	}
+	return fmt.Sprintf("ScaleDownResult(%d)", result)
+}
+
+func nodeDeleteResultName(result scaledownstatus.NodeDeleteResultType) string {
+	switch result {
+	case scaledownstatus.NodeDeleteOk:
+		return "NodeDeleteOk"
+	case scaledownstatus.NodeDeleteErrorFailedToMarkToBeDeleted:
+		return "NodeDeleteErrorFailedToMarkToBeDeleted"
+	case scaledownstatus.NodeDeleteErrorFailedToEvictPods:
+		return "NodeDeleteErrorFailedToEvictPods"
+	case scaledownstatus.NodeDeleteErrorFailedToDelete:
+		return "NodeDeleteErrorFailedToDelete"
+	case scaledownstatus.NodeDeleteErrorInternal:
+		return "NodeDeleteErrorInternal"
+	}
+	return fmt.Sprintf("NodeDeleteResultType(%d)", result)
+}
+
+func unremovableReasonName(reason simulator.UnremovableReason) string {
+	switch reason {
+	case simulator.NoReason:
+		return "NoReason"
+	case simulator.ScaleDownDisabledAnnotation:
+		return "ScaleDownDisabledAnnotation"
+	case simulator.ScaleDownUnreadyDisabled:
+		return "ScaleDownUnreadyDisabled"
+	case simulator.NotAutoscaled:
+		return "NotAutoscaled"
+	case simulator.NotUnneededLongEnough:
+		return "NotUnneededLongEnough"
+	case simulator.NotUnreadyLongEnough:
+		return "NotUnreadyLongEnough"
+	case simulator.NodeGroupMinSizeReached:
+		return "NodeGroupMinSizeReached"
+	case simulator.MinimalResourceLimitExceeded:
+		return "MinimalResourceLimitExceeded"
+	case simulator.CurrentlyBeingDeleted:
+		return "CurrentlyBeingDeleted"
+	case simulator.NotUnderutilized:
+		return "NotUnderutilized"
+	case simulator.NotUnneededOtherReason:
+		return "NotUnneededOtherReason"
+	case simulator.RecentlyUnremovable:
+		return "RecentlyUnremovable"
+	case simulator.NoPlaceToMovePods:
+		return "NoPlaceToMovePods"
+	case simulator.BlockedByPod:
+		return "BlockedByPod"
+	case simulator.UnexpectedError:
+		return "UnexpectedError"
+	}
+	return fmt.Sprintf("UnremovableReason(%d)", reason)
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auditlog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	scaledownstatus "k8s.io/autoscaler/cluster-autoscaler/core/scaledown/status"
+	"k8s.io/autoscaler/cluster-autoscaler/expander"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroupset"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/status"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/utilization"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/drain"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/errors"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+)
+
+type testReasons []string
+
+func (r testReasons) Reasons() []string {
+	return r
+}
+
+func TestNewRecord(t *testing.T) {
+	now := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	provider := testprovider.NewTestCloudProvider(nil, nil)
+	provider.AddNodeGroup("ng1", 0, 10, 1)
+	provider.AddNodeGroup("ng2", 0, 10, 2)
+	ng1 := provider.GetNodeGroup("ng1")
+	ng2 := provider.GetNodeGroup("ng2")
+	n1 := BuildTestNode("n1", 1000, 1000)
+	n2 := BuildTestNode("n2", 1000, 1000)
+	n3 := BuildTestNode("n3", 1000, 1000)
+	provider.AddNode("ng1", n1)
+	provider.AddNode("ng2", n2)
+	provider.AddNode("ng2", n3)
+
+	p1 := BuildTestPod("p1", 100, 0)
+	p1.Namespace = "default"
+	p2 := BuildTestPod("p2", 100, 0)
+	p2.Namespace = "default"
+	option1 := expander.Option{NodeGroup: ng1, NodeCount: 1, Pods: []*apiv1.Pod{p1}}
+	option2 := expander.Option{NodeGroup: ng2, NodeCount: 2, Pods: []*apiv1.Pod{p1}}
+	scaleUpStatus := &status.ScaleUpStatus{
+		Result:               status.ScaleUpSuccessful,
+		ConsideredNodeGroups: []cloudprovider.NodeGroup{ng1, ng2},
+		ExpansionOptions:     []expander.Option{option1, option2},
+		ExpanderResults: []expander.FilterResult{
+			{Name: "least-nodes", Scores: map[string]float64{"ng1": 1, "ng2": 2}, Explanations: map[string]string{"ng2": "more nodes"}, Options: []string{"ng1"}},
+		},
+		BestOption:           &option1,
+		ScaleUpInfos:         []nodegroupset.ScaleUpInfo{{Group: ng1, CurrentSize: 1, NewSize: 2, MaxSize: 10}},
+		PodsTriggeredScaleUp: []*apiv1.Pod{p1},
+		PodsRemainUnschedulable: []status.NoScaleUpInfo{{
+			Pod:                p2,
+			RejectedNodeGroups: map[string]status.Reasons{"ng1": testReasons{"too big"}},
+		}},
+	}
+
+	blockingPod := BuildTestPod("blocking", 100, 0)
+	blockingPod.Namespace = "kube-system"
+	scaleDownStatus := &scaledownstatus.ScaleDownStatus{
+		Result: scaledownstatus.ScaleDownNodeDeleteStarted,
+		ScaledDownNodes: []*scaledownstatus.ScaleDownNode{
+			{Node: n2, NodeGroup: ng2, EvictedPods: []*apiv1.Pod{p2}, UtilInfo: utilization.Info{Utilization: 0.25}},
+		},
+		UnremovableNodes: []*scaledownstatus.UnremovableNode{
+			{Node: n1, NodeGroup: ng1, Reason: simulator.BlockedByPod, BlockingPod: &drain.BlockingPod{Pod: blockingPod, Reason: drain.NotReplicated}},
+		},
+		NodeDeleteResults: map[string]scaledownstatus.NodeDeleteResult{
+			"n4": {ResultType: scaledownstatus.NodeDeleteErrorFailedToDelete, Err: errors.NewAutoscalerError(errors.CloudProviderError, "boom")},
+		},
+	}
+
+	record := NewRecord(now, scaleUpStatus, scaleDownStatus, []*apiv1.Node{n2, n3}, provider)
+	removedUtilization := 0.25
+	assert.Equal(t, &Record{
+		Time: now,
+		ScaleUp: ScaleUpRecord{
+			Result:              "ScaleUpSuccessful",
+			CandidateNodeGroups: []string{"ng1", "ng2"},
+			Options: []OptionRecord{
+				{NodeGroup: "ng1", NodeCount: 1, Pods: []string{"default/p1"}},
+				{NodeGroup: "ng2", NodeCount: 2, Pods: []string{"default/p1"}},
+			},
+			Expander: []ExpanderFilterRecord{
+				{Name: "least-nodes", Scores: map[string]float64{"ng1": 1, "ng2": 2}, Explanations: map[string]string{"ng2": "more nodes"}, Options: []string{"ng1"}},
+			},
+			ChosenNodeGroup: "ng1",
+			ScaleUps:        []ScaleUpInfoRecord{{NodeGroup: "ng1", CurrentSize: 1, NewSize: 2, MaxSize: 10}},
+			TriggeringPods:  []string{"default/p1"},
+			UnschedulablePods: []PodRecord{
+				{Pod: "default/p2", RejectedNodeGroups: map[string][]string{"ng1": {"too big"}}},
+			},
+		},
+		ScaleDown: ScaleDownRecord{
+			Result: "ScaleDownNodeDeleteStarted",
+			Nodes: []NodeRecord{
+				{Node: "n1", NodeGroup: "ng1", Verdict: NodeUnremovable, Reason: "BlockedByPod", BlockingPod: "kube-system/blocking", BlockingPodReason: drain.NotReplicated.String()},
+				{Node: "n2", NodeGroup: "ng2", Verdict: NodeRemoved, Utilization: &removedUtilization, EvictedPods: []string{"default/p2"}},
+				{Node: "n3", NodeGroup: "ng2", Verdict: NodeUnneeded},
+			},
+			DeleteResults: []DeleteResultRecord{
+				{Node: "n4", Result: "NodeDeleteErrorFailedToDelete", Error: "boom"},
+			},
+		},
+	}, record)
+}
+
+func TestNewRecordScaleUpError(t *testing.T) {
+	scaleUpStatus, _ := status.UpdateScaleUpError(&status.ScaleUpStatus{}, errors.NewAutoscalerError(errors.InternalError, "failed"))
+	record := NewRecord(time.Now(), scaleUpStatus, &scaledownstatus.ScaleDownStatus{Result: scaledownstatus.ScaleDownNotTried}, nil, nil)
+	assert.Equal(t, ScaleUpRecord{Result: "ScaleUpError", Error: "failed"}, record.ScaleUp)
+	assert.Equal(t, ScaleDownRecord{Result: "ScaleDownNotTried"}, record.ScaleDown)
+	assert.Equal(t, "ScaleUpResult(42)", scaleUpResultName(status.ScaleUpResult(42)))
+	assert.Equal(t, "ScaleDownResult(42)", scaleDownResultName(scaledownstatus.ScaleDownResult(42)))
+}
@@ -22,8 +22,12 @@ import (
 
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
 	cloudBuilder "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/builder"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/pricing"
+	"k8s.io/autoscaler/cluster-autoscaler/clusterstate/statestore"
 	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"k8s.io/autoscaler/cluster-autoscaler/config/reload"
 	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/core/auditlog"
 	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown/pdb"
 	"k8s.io/autoscaler/cluster-autoscaler/core/scaleup"
 	"k8s.io/autoscaler/cluster-autoscaler/debuggingsnapshot"
@@ -32,8 +36,10 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/expander/factory"
 	"k8s.io/autoscaler/cluster-autoscaler/observers/loopstart"
 	ca_processors "k8s.io/autoscaler/cluster-autoscaler/processors"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/maintenance"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/drainability/rules"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/dynamicresources"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/options"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/predicatechecker"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/backoff"
@@ -61,6 +67,15 @@ type AutoscalerOptions struct {
 	ScaleUpOrchestrator    scaleup.Orchestrator
 	DeleteOptions          options.NodeDeleteOptions
 	DrainabilityRules      rules.Rules
+	StateStore             statestore.Store
+	OptionsReloader        *reload.Reloader
+	AuditLogger            *auditlog.Logger
+	// DynamicResourcesProvider lists Dynamic Resource Allocation objects, nil unless
+	// DynamicResourceAllocationEnabled is set.
+	DynamicResourcesProvider *dynamicresources.Provider
+	// FallbackPricingModel prices nodes for the expander and consolidation if the
+	// cloud provider doesn't implement pricing, nil if no price table is configured.
+	FallbackPricingModel cloudprovider.PricingModel
 }
 
 // Autoscaler is the main component of CA which scales up/down node groups according to its configuration
@@ -100,6 +115,11 @@ func NewAutoscaler(opts AutoscalerOptions, informerFactory informers.SharedInfor
 		opts.ScaleUpOrchestrator,
 		opts.DeleteOptions,
 		opts.DrainabilityRules,
+		opts.StateStore,
+		opts.OptionsReloader,
+		opts.AuditLogger,
+		opts.DynamicResourcesProvider,
+		opts.FallbackPricingModel,
 	), nil
 }
 
@@ -117,20 +137,42 @@ func initializeDefaultOptions(opts *AutoscalerOptions, informerFactory informers
 	if opts.ClusterSnapshot == nil {
 		opts.ClusterSnapshot = clustersnapshot.NewBasicClusterSnapshot()
 	}
+	if opts.DynamicResourceAllocationEnabled && opts.DynamicResourcesProvider == nil {
+		opts.DynamicResourcesProvider = dynamicresources.NewProviderFromInformers(informerFactory)
+	}
 	if opts.RemainingPdbTracker == nil {
 		opts.RemainingPdbTracker = pdb.NewBasicRemainingPdbTracker()
 	}
 	if opts.CloudProvider == nil {
 		opts.CloudProvider = cloudBuilder.NewCloudProvider(opts.AutoscalingOptions, informerFactory)
 	}
+	if opts.Backoff == nil {
+		interruptionPolicy := backoff.InterruptionPolicy{
+			Window:    opts.InterruptionWindow,
+			Threshold: opts.InterruptionBackoffThreshold,
+		}
+		opts.Backoff =
+			backoff.NewIdBasedExponentialBackoffWithInterruptions(opts.InitialNodeGroupBackoffDuration, opts.MaxNodeGroupBackoffDuration, opts.NodeGroupBackoffResetTimeout, interruptionPolicy)
+	}
+	if opts.FallbackPricingModel == nil {
+		fallbackPricingModel, err := pricing.NewFallbackModel(opts.AutoscalingOptions, opts.KubeClient)
+		if err != nil {
+			return err
+		}
+		opts.FallbackPricingModel = fallbackPricingModel
+	}
+	var buildExpander reload.ExpanderBuilder
 	if opts.ExpanderStrategy == nil {
 		expanderFactory := factory.NewFactory()
-		expanderFactory.RegisterDefaultExpanders(opts.CloudProvider, opts.AutoscalingKubeClients, opts.KubeClient, opts.ConfigNamespace, opts.GRPCExpanderCert, opts.GRPCExpanderURL)
+		expanderFactory.RegisterDefaultExpanders(pricing.WithFallbackPricing(opts.CloudProvider, opts.FallbackPricingModel), opts.AutoscalingKubeClients, opts.KubeClient, opts.ConfigNamespace, opts.GRPCExpanderCert, opts.GRPCExpanderURL, opts.Backoff, opts.ExpanderWeights)
 		expanderStrategy, err := expanderFactory.Build(strings.Split(opts.ExpanderNames, ","))
 		if err != nil {
 			return err
 		}
 		opts.ExpanderStrategy = expanderStrategy
+		buildExpander = func(names []string) (expander.Strategy, error) {
+			return expanderFactory.Build(names)
+		}
 	}
 	if opts.EstimatorBuilder == nil {
 		thresholds := []estimator.Threshold{
@@ -149,13 +191,37 @@ func initializeDefaultOptions(opts *AutoscalerOptions, informerFactory informers
 		}
 		opts.EstimatorBuilder = estimatorBuilder
 	}
-	if opts.Backoff == nil {
-		opts.Backoff =
-			backoff.NewIdBasedExponentialBackoff(opts.InitialNodeGroupBackoffDuration, opts.MaxNodeGroupBackoffDuration, opts.NodeGroupBackoffResetTimeout)
+	if opts.StateStore == nil && opts.StateStoreType != "" {
+		stateStore, err := statestore.NewStore(opts.StateStoreType, opts.KubeClient, opts.ConfigNamespace, opts.StateStoreName)
+		if err != nil {
+			return err
+		}
+		opts.StateStore = stateStore
+	}
+	if opts.OptionsReloader == nil {
+		if source := reload.NewSource(opts.AutoscalingOptions, opts.KubeClient); source != nil {
+			opts.OptionsReloader = reload.NewReloader(source, opts.AutoscalingOptions, buildExpander)
+		}
+	}
+	if opts.Processors.MaintenanceWindows == nil {
+		if source := maintenance.NewSource(opts.AutoscalingOptions, opts.KubeClient); source != nil {
+			tracker := maintenance.NewTracker(source)
+			opts.Processors.MaintenanceWindows = tracker
+			opts.Processors.ActionableClusterProcessor = maintenance.NewActionableClusterProcessor(opts.Processors.ActionableClusterProcessor, tracker)
+			opts.Processors.ScaleDownNodeProcessor = maintenance.NewScaleDownNodeProcessor(opts.Processors.ScaleDownNodeProcessor, tracker)
+			opts.Processors.NodeGroupListProcessor = maintenance.NewNodeGroupListProcessor(opts.Processors.NodeGroupListProcessor, tracker)
+		}
 	}
 	if opts.DrainabilityRules == nil {
 		opts.DrainabilityRules = rules.Default(opts.DeleteOptions)
 	}
+	if opts.AuditLogger == nil {
+		auditLogger, err := auditlog.NewLoggerFromOptions(opts.AutoscalingOptions)
+		if err != nil {
+			return err
+		}
+		opts.AuditLogger = auditLogger
+	}
 
 	return nil
 }
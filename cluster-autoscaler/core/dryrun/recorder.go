@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dryrun
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/clusterstate/api"
+	"k8s.io/autoscaler/cluster-autoscaler/clusterstate/utils"
+	"k8s.io/autoscaler/cluster-autoscaler/metrics"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// ScaleDownReason is the reason of deleting nodes which are unneeded.
+	ScaleDownReason = "ScaleDown"
+	// UnregisteredReason is the reason of deleting nodes which didn't register in time.
+	UnregisteredReason = "Unregistered"
+	// CreateErrorReason is the reason of deleting nodes which failed to be created.
+	CreateErrorReason = "CreateError"
+	// IncorrectSizeReason is the reason of decreasing the target size of a node group
+	// which had fewer nodes than expected for too long.
+	IncorrectSizeReason = "IncorrectSize"
+
+	// maxRecordedActions is the number of most recent actions kept for the status ConfigMap.
+	maxRecordedActions = 100
+)
+
+// Recorder records actions which Cluster Autoscaler would have executed if it
+// wasn't running in dry-run mode. Each action is logged, emitted as an event on
+// the status ConfigMap and counted in metrics, and the most recent ones are
+// kept for the status ConfigMap.
+type Recorder struct {
+	logRecorder *utils.LogEventRecorder
+	now         func() time.Time
+
+	mutex   sync.Mutex
+	actions []api.DryRunAction
+}
+
+// NewRecorder returns a new Recorder emitting events with the given recorder.
+func NewRecorder(logRecorder *utils.LogEventRecorder) *Recorder {
+	return &Recorder{
+		logRecorder: logRecorder,
+		now:         time.Now,
+	}
+}
+
+// RecordCreateNodeGroup records creation of a node group.
+func (r *Recorder) RecordCreateNodeGroup(nodeGroup string) {
+	r.record(api.DryRunAction{Type: api.DryRunCreateNodeGroup, NodeGroup: nodeGroup},
+		fmt.Sprintf("would create node group %s", nodeGroup))
+}
+
+// RecordIncreaseSize records an increase of a node group size by delta.
+func (r *Recorder) RecordIncreaseSize(nodeGroup string, delta int) {
+	r.record(api.DryRunAction{Type: api.DryRunIncreaseSize, NodeGroup: nodeGroup, Delta: delta},
+		fmt.Sprintf("would increase size of node group %s by %d", nodeGroup, delta))
+}
+
+// RecordDecreaseTargetSize records a decrease of a node group target size by
+// delta, which is negative.
+func (r *Recorder) RecordDecreaseTargetSize(nodeGroup string, delta int, reason string) {
+	r.record(api.DryRunAction{Type: api.DryRunDecreaseTargetSize, NodeGroup: nodeGroup, Delta: delta, Reason: reason},
+		fmt.Sprintf("would decrease target size of node group %s by %d (%s)", nodeGroup, -delta, reason))
+}
+
+// RecordDeleteNodes records deletion of nodes from a node group, after
+// tainting them and evicting the given pods.
+func (r *Recorder) RecordDeleteNodes(nodeGroup string, nodes []*apiv1.Node, evictedPods []*apiv1.Pod, reason string) {
+	action := api.DryRunAction{Type: api.DryRunDeleteNodes, NodeGroup: nodeGroup, Reason: reason}
+	for _, node := range nodes {
+		action.Nodes = append(action.Nodes, node.Name)
+	}
+	for _, pod := range evictedPods {
+		action.EvictedPods = append(action.EvictedPods, pod.Namespace+"/"+pod.Name)
+	}
+	message := fmt.Sprintf("would delete nodes %s from node group %s (%s)", strings.Join(action.Nodes, ","), nodeGroup, reason)
+	if len(action.EvictedPods) > 0 {
+		message += fmt.Sprintf(", evicting pods %s", strings.Join(action.EvictedPods, ","))
+	}
+	r.record(action, message)
+}
+
+// Actions returns the most recently recorded actions, oldest first.
+func (r *Recorder) Actions() []api.DryRunAction {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]api.DryRunAction(nil), r.actions...)
+}
+
+func (r *Recorder) record(action api.DryRunAction, message string) {
+	action.Time = metav1.NewTime(r.now())
+	r.mutex.Lock()
+	r.actions = append(r.actions, action)
+	if len(r.actions) > maxRecordedActions {
+		r.actions = r.actions[len(r.actions)-maxRecordedActions:]
+	}
+	r.mutex.Unlock()
+
+	klog.V(0).Infof("Dry run: %s", message)
+	r.logRecorder.Eventf(apiv1.EventTypeNormal, "DryRun"+string(action.Type), "Dry run: %s", message)
+	metrics.RegisterDryRunAction(string(action.Type))
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dryrun
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/clusterstate/api"
+	"k8s.io/autoscaler/cluster-autoscaler/clusterstate/utils"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+	"k8s.io/client-go/kubernetes/fake"
+	kube_record "k8s.io/client-go/tools/record"
+)
+
+func newTestRecorder(t *testing.T, recordEvents bool) (*Recorder, *kube_record.FakeRecorder) {
+	fakeRecorder := kube_record.NewFakeRecorder(10)
+	logRecorder, err := utils.NewStatusMapRecorder(fake.NewSimpleClientset(), "kube-system", fakeRecorder, recordEvents, "cluster-autoscaler-status")
+	assert.NoError(t, err)
+	return NewRecorder(logRecorder), fakeRecorder
+}
+
+func TestRecorder(t *testing.T) {
+	recorder, fakeRecorder := newTestRecorder(t, true)
+	now := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	recorder.now = func() time.Time { return now }
+
+	pod := BuildTestPod("p1", 100, 0)
+	pod.Namespace = "default"
+	recorder.RecordCreateNodeGroup("ng-new")
+	recorder.RecordIncreaseSize("ng1", 3)
+	recorder.RecordDecreaseTargetSize("ng2", -2, IncorrectSizeReason)
+	recorder.RecordDeleteNodes("ng1", []*apiv1.Node{BuildTestNode("n1", 1000, 1000), BuildTestNode("n2", 1000, 1000)}, []*apiv1.Pod{pod}, ScaleDownReason)
+
+	assert.Equal(t, []api.DryRunAction{
+		{Type: api.DryRunCreateNodeGroup, Time: metav1.NewTime(now), NodeGroup: "ng-new"},
+		{Type: api.DryRunIncreaseSize, Time: metav1.NewTime(now), NodeGroup: "ng1", Delta: 3},
+		{Type: api.DryRunDecreaseTargetSize, Time: metav1.NewTime(now), NodeGroup: "ng2", Delta: -2, Reason: IncorrectSizeReason},
+		{Type: api.DryRunDeleteNodes, Time: metav1.NewTime(now), NodeGroup: "ng1", Nodes: []string{"n1", "n2"}, EvictedPods: []string{"default/p1"}, Reason: ScaleDownReason},
+	}, recorder.Actions())
+
+	var events []string
+	for len(fakeRecorder.Events) > 0 {
+		events = append(events, <-fakeRecorder.Events)
+	}
+	assert.Equal(t, []string{
+		"Normal DryRunCreateNodeGroup Dry run: would create node group ng-new",
+		"Normal DryRunIncreaseSize Dry run: would increase size of node group ng1 by 3",
+		"Normal DryRunDecreaseTargetSize Dry run: would decrease target size of node group ng2 by 2 (IncorrectSize)",
+		"Normal DryRunDeleteNodes Dry run: would delete nodes n1,n2 from node group ng1 (ScaleDown), evicting pods default/p1",
+	}, events)
+}
+
+func TestRecorderKeepsMostRecentActions(t *testing.T) {
+	recorder, _ := newTestRecorder(t, false)
+	for i := 0; i < maxRecordedActions+5; i++ {
+		recorder.RecordIncreaseSize(fmt.Sprintf("ng%d", i), 1)
+	}
+	actions := recorder.Actions()
+	assert.Len(t, actions, maxRecordedActions)
+	assert.Equal(t, "ng5", actions[0].NodeGroup)
+	assert.Equal(t, fmt.Sprintf("ng%d", maxRecordedActions+4), actions[len(actions)-1].NodeGroup)
+}
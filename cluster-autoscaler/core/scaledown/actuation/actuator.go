@@ -23,6 +23,7 @@ import (
 	apiv1 "k8s.io/api/core/v1"
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
 	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/core/dryrun"
 	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown"
 	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown/budgets"
 	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown/deletiontracker"
@@ -68,6 +69,8 @@ type Actuator struct {
 type actuatorNodeGroupConfigGetter interface {
 	// GetIgnoreDaemonSetsUtilization returns IgnoreDaemonSetsUtilization value that should be used for a given NodeGroup.
 	GetIgnoreDaemonSetsUtilization(nodeGroup cloudprovider.NodeGroup) (bool, error)
+	// GetZeroOrMaxNodeScaling returns ZeroOrMaxNodeScaling value that should be used for a given NodeGroup.
+	GetZeroOrMaxNodeScaling(nodeGroup cloudprovider.NodeGroup) (bool, error)
 }
 
 // NewActuator returns a new instance of Actuator.
@@ -83,8 +86,8 @@ func NewActuator(ctx *context.AutoscalingContext, scaleStateNotifier nodegroupch
 	return &Actuator{
 		ctx:                       ctx,
 		nodeDeletionTracker:       ndt,
-		nodeDeletionScheduler:     NewGroupDeletionScheduler(ctx, ndt, ndb, evictor),
-		budgetProcessor:           budgets.NewScaleDownBudgetProcessor(ctx),
+		nodeDeletionScheduler:     NewGroupDeletionScheduler(ctx, ndt, ndb, evictor, configGetter),
+		budgetProcessor:           budgets.NewScaleDownBudgetProcessor(ctx, configGetter),
 		deleteOptions:             deleteOptions,
 		drainabilityRules:         drainabilityRules,
 		configGetter:              configGetter,
@@ -93,6 +96,12 @@ func NewActuator(ctx *context.AutoscalingContext, scaleStateNotifier nodegroupch
 	}
 }
 
+// BudgetProcessor returns the processor keeping deletions started by the
+// actuator within the scale-down budgets.
+func (a *Actuator) BudgetProcessor() *budgets.ScaleDownBudgetProcessor {
+	return a.budgetProcessor
+}
+
 // CheckStatus should returns an immutable snapshot of ongoing deletions.
 func (a *Actuator) CheckStatus() scaledown.ActuationStatus {
 	return a.nodeDeletionTracker.Snapshot()
@@ -121,6 +130,10 @@ func (a *Actuator) StartDeletion(empty, drain []*apiv1.Node) (status.ScaleDownRe
 		return status.ScaleDownNoNodeDeleted, nil, nil
 	}
 
+	if a.ctx.DryRunRecorder != nil {
+		return status.ScaleDownNodeDeleteStarted, a.recordDeletions(emptyToDelete, drainToDelete), nil
+	}
+
 	if len(emptyToDelete) > 0 {
 		// Taint all empty nodes synchronously
 		nodeDeleteDelayAfterTaint, err := a.taintNodesSync(emptyToDelete)
@@ -148,6 +161,34 @@ func (a *Actuator) StartDeletion(empty, drain []*apiv1.Node) (status.ScaleDownRe
 	return status.ScaleDownNodeDeleteStarted, scaledDownNodes, nil
 }
 
+// recordDeletions records deletions of the nodes in dry-run mode, without tainting, draining or deleting them.
+// The returned nodes are reported as if their deletion started.
+func (a *Actuator) recordDeletions(emptyToDelete, drainToDelete []*budgets.NodeGroupView) (reportedSDNodes []*status.ScaleDownNode) {
+	for _, bucket := range emptyToDelete {
+		for _, node := range bucket.Nodes {
+			if sdNode, err := a.scaleDownNodeToReport(node, false); err == nil {
+				reportedSDNodes = append(reportedSDNodes, sdNode)
+			} else {
+				klog.Errorf("Scale-down: couldn't report scaled down node, err: %v", err)
+			}
+		}
+		a.ctx.DryRunRecorder.RecordDeleteNodes(bucket.Group.Id(), bucket.Nodes, nil, dryrun.ScaleDownReason)
+	}
+	for _, bucket := range drainToDelete {
+		var evictedPods []*apiv1.Pod
+		for _, node := range bucket.Nodes {
+			if sdNode, err := a.scaleDownNodeToReport(node, true); err == nil {
+				reportedSDNodes = append(reportedSDNodes, sdNode)
+				evictedPods = append(evictedPods, sdNode.EvictedPods...)
+			} else {
+				klog.Errorf("Scale-down: couldn't report scaled down node, err: %v", err)
+			}
+		}
+		a.ctx.DryRunRecorder.RecordDeleteNodes(bucket.Group.Id(), bucket.Nodes, evictedPods, dryrun.ScaleDownReason)
+	}
+	return reportedSDNodes
+}
+
 // deleteAsyncEmpty immediately starts deletions asynchronously.
 // scaledDownNodes return value contains all nodes for which deletion successfully started.
 func (a *Actuator) deleteAsyncEmpty(NodeGroupViews []*budgets.NodeGroupView, nodeDeleteDelayAfterTaint time.Duration) (reportedSDNodes []*status.ScaleDownNode) {
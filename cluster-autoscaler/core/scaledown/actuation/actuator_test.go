@@ -24,6 +24,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/stretchr/testify/assert"
 	appsv1 "k8s.io/api/apps/v1"
 	apiv1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
@@ -36,6 +37,7 @@ import (
 	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
 	"k8s.io/autoscaler/cluster-autoscaler/clusterstate"
 	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"k8s.io/autoscaler/cluster-autoscaler/core/dryrun"
 	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown/budgets"
 	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown/deletiontracker"
 	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown/status"
@@ -1193,11 +1195,12 @@ func TestStartDeletion(t *testing.T) {
 				ndb := NewNodeDeletionBatcher(&ctx, scaleStateNotifier, ndt, 0*time.Second)
 				legacyFlagDrainConfig := SingleRuleDrainConfig(ctx.MaxGracefulTerminationSec)
 				evictor := Evictor{EvictionRetryTime: 0, PodEvictionHeadroom: DefaultPodEvictionHeadroom, shutdownGracePeriodByPodPriority: legacyFlagDrainConfig, fullDsEviction: false}
+				configGetter := nodegroupconfig.NewDefaultNodeGroupConfigProcessor(ctx.NodeGroupDefaults)
 				actuator := Actuator{
 					ctx: &ctx, nodeDeletionTracker: ndt,
-					nodeDeletionScheduler: NewGroupDeletionScheduler(&ctx, ndt, ndb, evictor),
-					budgetProcessor:       budgets.NewScaleDownBudgetProcessor(&ctx),
-					configGetter:          nodegroupconfig.NewDefaultNodeGroupConfigProcessor(ctx.NodeGroupDefaults),
+					nodeDeletionScheduler: NewGroupDeletionScheduler(&ctx, ndt, ndb, evictor, configGetter),
+					budgetProcessor:       budgets.NewScaleDownBudgetProcessor(&ctx, configGetter),
+					configGetter:          configGetter,
 				}
 				gotResult, gotScaleDownNodes, gotErr := actuator.StartDeletion(allEmptyNodes, allDrainNodes)
 				if diff := cmp.Diff(tc.wantErr, gotErr, cmpopts.EquateErrors()); diff != "" {
@@ -1432,10 +1435,11 @@ func TestStartDeletionInBatchBasic(t *testing.T) {
 			ndb := NewNodeDeletionBatcher(&ctx, scaleStateNotifier, ndt, deleteInterval)
 			legacyFlagDrainConfig := SingleRuleDrainConfig(ctx.MaxGracefulTerminationSec)
 			evictor := Evictor{EvictionRetryTime: 0, PodEvictionHeadroom: DefaultPodEvictionHeadroom, shutdownGracePeriodByPodPriority: legacyFlagDrainConfig}
+			configGetter := nodegroupconfig.NewDefaultNodeGroupConfigProcessor(ctx.NodeGroupDefaults)
 			actuator := Actuator{
 				ctx: &ctx, nodeDeletionTracker: ndt,
-				nodeDeletionScheduler: NewGroupDeletionScheduler(&ctx, ndt, ndb, evictor),
-				budgetProcessor:       budgets.NewScaleDownBudgetProcessor(&ctx),
+				nodeDeletionScheduler: NewGroupDeletionScheduler(&ctx, ndt, ndb, evictor, configGetter),
+				budgetProcessor:       budgets.NewScaleDownBudgetProcessor(&ctx, configGetter),
 			}
 
 			for _, nodes := range deleteNodes {
@@ -1467,6 +1471,69 @@ func TestStartDeletionInBatchBasic(t *testing.T) {
 	}
 }
 
+func TestStartDeletionDryRun(t *testing.T) {
+	fakeClient := &fake.Clientset{}
+	fakeClient.Fake.AddReactor("*", "*", func(action core.Action) (bool, runtime.Object, error) {
+		if action.GetVerb() != "get" && action.GetVerb() != "list" {
+			t.Errorf("Unexpected %s of %s in dry-run mode", action.GetVerb(), action.GetResource().Resource)
+		}
+		return false, nil, nil
+	})
+	provider := testprovider.NewTestCloudProvider(nil, func(nodeGroup string, node string) error {
+		t.Errorf("Unexpected deletion of node %s in dry-run mode", node)
+		return nil
+	})
+	emptyGroup := sizedNodeGroup("empty", 3, false, false)
+	drainGroup := sizedNodeGroup("drain", 3, false, false)
+	emptyNodes := generateNodes(0, 2, "empty")
+	drainNodes := generateNodes(0, 1, "drain")
+	pods := removablePods(2, "drain-node-0")
+	for _, bucket := range []*budgets.NodeGroupView{{Group: emptyGroup, Nodes: emptyNodes}, {Group: drainGroup, Nodes: drainNodes}} {
+		bucket.Group.(*testprovider.TestNodeGroup).SetCloudProvider(provider)
+		provider.InsertNodeGroup(bucket.Group)
+		for _, node := range bucket.Nodes {
+			provider.AddNode(bucket.Group.Id(), node)
+		}
+	}
+
+	opts := config.AutoscalingOptions{
+		MaxScaleDownParallelism: 10,
+		MaxDrainParallelism:     5,
+	}
+	registry := kube_util.NewListerRegistry(nil, nil, kube_util.NewTestPodLister(pods), kube_util.NewTestPodDisruptionBudgetLister(nil), nil, nil, nil, nil, nil)
+	ctx, err := NewScaleTestAutoscalingContext(opts, fakeClient, registry, provider, nil, nil)
+	if err != nil {
+		t.Fatalf("Couldn't set up autoscaling context: %v", err)
+	}
+	ctx.DryRunRecorder = dryrun.NewRecorder(ctx.LogRecorder)
+	for _, node := range emptyNodes {
+		assert.NoError(t, ctx.ClusterSnapshot.AddNode(node))
+	}
+	assert.NoError(t, ctx.ClusterSnapshot.AddNodeWithPods(drainNodes[0], pods))
+
+	ndt := deletiontracker.NewNodeDeletionTracker(0)
+	ndb := NewNodeDeletionBatcher(&ctx, nodegroupchange.NewNodeGroupChangeObserversList(), ndt, 0*time.Second)
+	configGetter := nodegroupconfig.NewDefaultNodeGroupConfigProcessor(ctx.NodeGroupDefaults)
+	actuator := Actuator{
+		ctx: &ctx, nodeDeletionTracker: ndt,
+		nodeDeletionScheduler: NewGroupDeletionScheduler(&ctx, ndt, ndb, Evictor{}, configGetter),
+		budgetProcessor:       budgets.NewScaleDownBudgetProcessor(&ctx, configGetter),
+		configGetter:          configGetter,
+	}
+	result, scaledDownNodes, err := actuator.StartDeletion(emptyNodes, drainNodes)
+	assert.NoError(t, err)
+	assert.Equal(t, status.ScaleDownNodeDeleteStarted, result)
+	assert.Len(t, scaledDownNodes, 3)
+	assert.Equal(t, 0, ndt.DeletionsCount("empty")+ndt.DeletionsCount("drain"))
+
+	actions := ctx.DryRunRecorder.Actions()
+	assert.Len(t, actions, 2)
+	assert.Equal(t, []string{"empty-node-0", "empty-node-1"}, actions[0].Nodes)
+	assert.Empty(t, actions[0].EvictedPods)
+	assert.Equal(t, []string{"drain-node-0"}, actions[1].Nodes)
+	assert.Equal(t, []string{"default/drain-node-0-pod-0", "default/drain-node-0-pod-1"}, actions[1].EvictedPods)
+}
+
 func sizedNodeGroup(id string, size int, atomic, ignoreDaemonSetUtil bool) *testprovider.TestNodeGroup {
 	ng := testprovider.NewTestNodeGroup(id, 1000, 0, size, true, false, "n1-standard-2", nil, nil)
 	ng.SetOptions(&config.NodeGroupAutoscalingOptions{
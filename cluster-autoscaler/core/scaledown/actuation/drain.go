@@ -54,6 +54,7 @@ type evictionRegister interface {
 type Evictor struct {
 	EvictionRetryTime                time.Duration
 	PodEvictionHeadroom              time.Duration
+	PreEvictionHookRetryTime         time.Duration
 	evictionRegister                 evictionRegister
 	shutdownGracePeriodByPodPriority []kubelet_config.ShutdownGracePeriodByPodPriority
 	fullDsEviction                   bool
@@ -68,6 +69,7 @@ func NewEvictor(evictionRegister evictionRegister, shutdownGracePeriodByPodPrior
 	return Evictor{
 		EvictionRetryTime:                DefaultEvictionRetryTime,
 		PodEvictionHeadroom:              DefaultPodEvictionHeadroom,
+		PreEvictionHookRetryTime:         DefaultPreEvictionHookRetryTime,
 		evictionRegister:                 evictionRegister,
 		shutdownGracePeriodByPodPriority: shutdownGracePeriodByPodPriority,
 		fullDsEviction:                   fullDsEviction,
@@ -109,23 +111,22 @@ func (e Evictor) drainNodeWithPodsBasedOnPodPriority(ctx *acontext.AutoscalingCo
 		}
 	}
 
-	for _, group := range groups {
-		// If there are no pods in a particular range,
-		// then do not wait for pods in that priority range.
-		if len(group.FullEvictionPods) == 0 && len(group.BestEffortEvictionPods) == 0 {
-			continue
-		}
-
-		var err error
-		evictionResults, err = e.initiateEviction(ctx, node, group.FullEvictionPods, group.BestEffortEvictionPods, evictionResults, group.ShutdownGracePeriodSeconds)
-		if err != nil {
-			return evictionResults, err
-		}
+	for _, priorityGroup := range groups {
+		// Pods of a priority group are evicted in batches of the same drain order.
+		// If there are no pods in a particular range, then there are no batches
+		// and we do not wait for pods in that priority range.
+		for _, group := range groupByDrainOrder(priorityGroup) {
+			var err error
+			evictionResults, err = e.initiateEviction(ctx, node, group.FullEvictionPods, group.BestEffortEvictionPods, evictionResults, group.ShutdownGracePeriodSeconds)
+			if err != nil {
+				return evictionResults, err
+			}
 
-		// Evictions created successfully, wait ShutdownGracePeriodSeconds + podEvictionHeadroom to see if fullEviction pods really disappeared.
-		evictionResults, err = e.waitPodsToDisappear(ctx, node, group.FullEvictionPods, evictionResults, group.ShutdownGracePeriodSeconds)
-		if err != nil {
-			return evictionResults, err
+			// Evictions created successfully, wait ShutdownGracePeriodSeconds + podEvictionHeadroom to see if fullEviction pods really disappeared.
+			evictionResults, err = e.waitPodsToDisappear(ctx, node, group.FullEvictionPods, evictionResults, group.ShutdownGracePeriodSeconds)
+			if err != nil {
+				return evictionResults, err
+			}
 		}
 	}
 	klog.V(1).Infof("All pods removed from %s", node.Name)
@@ -216,6 +217,8 @@ func (e Evictor) initiateEviction(ctx *acontext.AutoscalingContext, node *apiv1.
 }
 
 func (e Evictor) evictPod(ctx *acontext.AutoscalingContext, podToEvict *apiv1.Pod, retryUntil time.Time, maxTermination int64, fullEvictionPod bool) status.PodEvictionResult {
+	// Time spent waiting for the pre-eviction hook doesn't count towards MaxPodEvictionTime.
+	retryUntil = retryUntil.Add(e.runPreEvictionHook(ctx, podToEvict))
 	ctx.Recorder.Eventf(podToEvict, apiv1.EventTypeNormal, "ScaleDown", "deleting pod for node scale down")
 
 	termination := int64(apiv1.DefaultTerminationGracePeriodSeconds)
@@ -24,7 +24,6 @@ import (
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
-	"k8s.io/autoscaler/cluster-autoscaler/config"
 	"k8s.io/autoscaler/cluster-autoscaler/context"
 	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown/deletiontracker"
 	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown/status"
@@ -44,17 +43,19 @@ type GroupDeletionScheduler struct {
 	nodeDeletionTracker *deletiontracker.NodeDeletionTracker
 	nodeDeletionBatcher batcher
 	evictor             Evictor
+	configGetter        actuatorNodeGroupConfigGetter
 	nodeQueue           map[string][]*apiv1.Node
 	failuresForGroup    map[string]bool
 }
 
 // NewGroupDeletionScheduler creates an instance of GroupDeletionScheduler.
-func NewGroupDeletionScheduler(ctx *context.AutoscalingContext, ndt *deletiontracker.NodeDeletionTracker, b batcher, evictor Evictor) *GroupDeletionScheduler {
+func NewGroupDeletionScheduler(ctx *context.AutoscalingContext, ndt *deletiontracker.NodeDeletionTracker, b batcher, evictor Evictor, configGetter actuatorNodeGroupConfigGetter) *GroupDeletionScheduler {
 	return &GroupDeletionScheduler{
 		ctx:                 ctx,
 		nodeDeletionTracker: ndt,
 		nodeDeletionBatcher: b,
 		evictor:             evictor,
+		configGetter:        configGetter,
 		nodeQueue:           map[string][]*apiv1.Node{},
 		failuresForGroup:    map[string]bool{},
 	}
@@ -77,15 +78,12 @@ func (ds *GroupDeletionScheduler) ResetAndReportMetrics() {
 // ScheduleDeletion schedules deletion of the node. Nodes that should be deleted in groups are queued until whole group is scheduled for deletion,
 // other nodes are passed over to NodeDeletionBatcher immediately.
 func (ds *GroupDeletionScheduler) ScheduleDeletion(nodeInfo *framework.NodeInfo, nodeGroup cloudprovider.NodeGroup, batchSize int, drain bool) {
-	opts, err := nodeGroup.GetOptions(ds.ctx.NodeGroupDefaults)
-	if err != nil && err != cloudprovider.ErrNotImplemented {
-		nodeDeleteResult := status.NodeDeleteResult{ResultType: status.NodeDeleteErrorInternal, Err: errors.NewAutoscalerError(errors.InternalError, "GetOptions returned error %v", err)}
+	zeroOrMaxNodeScaling, err := ds.configGetter.GetZeroOrMaxNodeScaling(nodeGroup)
+	if err != nil {
+		nodeDeleteResult := status.NodeDeleteResult{ResultType: status.NodeDeleteErrorInternal, Err: errors.NewAutoscalerError(errors.InternalError, "GetZeroOrMaxNodeScaling returned error %v", err)}
 		ds.AbortNodeDeletion(nodeInfo.Node(), nodeGroup.Id(), drain, "failed to get autoscaling options for a node group", nodeDeleteResult)
 		return
 	}
-	if opts == nil {
-		opts = &config.NodeGroupAutoscalingOptions{}
-	}
 
 	nodeDeleteResult := ds.prepareNodeForDeletion(nodeInfo, drain)
 	if nodeDeleteResult.Err != nil {
@@ -93,7 +91,7 @@ func (ds *GroupDeletionScheduler) ScheduleDeletion(nodeInfo *framework.NodeInfo,
 		return
 	}
 
-	ds.addToBatcher(nodeInfo, nodeGroup, batchSize, drain, opts.ZeroOrMaxNodeScaling)
+	ds.addToBatcher(nodeInfo, nodeGroup, batchSize, drain, zeroOrMaxNodeScaling)
 }
 
 // prepareNodeForDeletion is a long-running operation, so it needs to avoid locking the AtomicDeletionScheduler object
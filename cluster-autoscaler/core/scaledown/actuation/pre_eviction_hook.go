@@ -0,0 +1,227 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package actuation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	kube_errors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	acontext "k8s.io/autoscaler/cluster-autoscaler/context"
+)
+
+const (
+	// PreEvictionHookKey is the annotation with which pods opt into a
+	// pre-eviction hook. Its value is either PreEvictionHookAnnotation for the
+	// annotation handshake, or the URL of an HTTP endpoint. URLs starting with
+	// ":" or "/", e.g. ":8080/handoff", are relative to the pod IP, other URLs
+	// have to point to one of PreEvictionHookAllowedHosts.
+	PreEvictionHookKey = "cluster-autoscaler.kubernetes.io/pre-eviction-hook"
+	// PreEvictionHookAnnotation is the PreEvictionHookKey value selecting the
+	// annotation handshake: CA sets PreEvictionRequestedKey on the pod and
+	// waits for the pod's owner to set PreEvictionAcknowledgedKey.
+	PreEvictionHookAnnotation = "annotation"
+	// PreEvictionRequestedKey is the annotation CA sets on pods using the
+	// annotation handshake, with the time of the request, before evicting them.
+	PreEvictionRequestedKey = "cluster-autoscaler.kubernetes.io/pre-eviction-requested"
+	// PreEvictionAcknowledgedKey is the annotation the owner of a pod sets, with
+	// any value, once the pod is ready to be evicted.
+	PreEvictionAcknowledgedKey = "cluster-autoscaler.kubernetes.io/pre-eviction-acknowledged"
+	// DefaultPreEvictionHookRetryTime is the time after CA retries a failed
+	// HTTP pre-eviction hook or checks the pod for an acknowledgement again.
+	DefaultPreEvictionHookRetryTime = 5 * time.Second
+	// preEvictionHookRequestTimeout is the maximum time a single HTTP
+	// pre-eviction hook call can take.
+	preEvictionHookRequestTimeout = 10 * time.Second
+)
+
+// preEvictionHookClient is the client calling HTTP pre-eviction hooks. Hooks
+// are set by pod authors, so redirects, which could point anywhere, aren't
+// followed.
+var preEvictionHookClient = &http.Client{
+	Timeout: preEvictionHookRequestTimeout,
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// preEvictionHookRequest is the body of HTTP pre-eviction hook calls.
+type preEvictionHookRequest struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	Node      string `json:"node"`
+}
+
+// runPreEvictionHook notifies the pod about its eviction if it opted into a
+// pre-eviction hook and waits for the acknowledgement up to
+// PreEvictionHookTimeout. The pod is evicted anyway if the hook fails or times
+// out. Returns the time spent waiting.
+func (e Evictor) runPreEvictionHook(ctx *acontext.AutoscalingContext, pod *apiv1.Pod) time.Duration {
+	hook, found := pod.Annotations[PreEvictionHookKey]
+	if !found || ctx.PreEvictionHookTimeout <= 0 {
+		return 0
+	}
+	start := time.Now()
+	deadline := start.Add(ctx.PreEvictionHookTimeout)
+	var err error
+	if hook == PreEvictionHookAnnotation {
+		err = e.preEvictionHandshake(ctx, pod, deadline)
+	} else {
+		err = e.callPreEvictionHook(pod, hook, ctx.PreEvictionHookAllowedHosts, deadline)
+	}
+	if err != nil {
+		klog.Warningf("Pre-eviction hook of pod %s/%s not acknowledged, evicting it anyway: %v", pod.Namespace, pod.Name, err)
+		ctx.Recorder.Eventf(pod, apiv1.EventTypeWarning, "PreEvictionHookFailed", "pre-eviction hook not acknowledged: %v", err)
+	} else {
+		klog.V(1).Infof("Pod %s/%s acknowledged pre-eviction hook", pod.Namespace, pod.Name)
+	}
+	return time.Since(start)
+}
+
+// preEvictionHandshake requests the acknowledgement of the eviction through
+// pod annotations and waits for it until the deadline.
+func (e Evictor) preEvictionHandshake(ctx *acontext.AutoscalingContext, pod *apiv1.Pod, deadline time.Time) error {
+	patch := fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, PreEvictionRequestedKey, time.Now().Format(time.RFC3339))
+	_, err := ctx.ClientSet.CoreV1().Pods(pod.Namespace).Patch(context.TODO(), pod.Name, types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to request acknowledgement: %v", err)
+	}
+	for ; time.Now().Before(deadline); time.Sleep(e.PreEvictionHookRetryTime) {
+		current, err := ctx.ClientSet.CoreV1().Pods(pod.Namespace).Get(context.TODO(), pod.Name, metav1.GetOptions{})
+		if kube_errors.IsNotFound(err) {
+			// Nothing left to evict.
+			return nil
+		}
+		if err != nil {
+			klog.Errorf("Failed to check pre-eviction acknowledgement of pod %s/%s: %v", pod.Namespace, pod.Name, err)
+			continue
+		}
+		if _, acknowledged := current.Annotations[PreEvictionAcknowledgedKey]; acknowledged {
+			return nil
+		}
+	}
+	return fmt.Errorf("no acknowledgement within %v", ctx.PreEvictionHookTimeout)
+}
+
+// callPreEvictionHook calls the HTTP pre-eviction hook of the pod until it
+// responds with a 2xx status or the deadline passes.
+func (e Evictor) callPreEvictionHook(pod *apiv1.Pod, hook string, allowedHosts []string, deadline time.Time) error {
+	hookURL, err := preEvictionHookURL(pod, hook, allowedHosts)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(preEvictionHookRequest{Namespace: pod.Namespace, Pod: pod.Name, Node: pod.Spec.NodeName})
+	if err != nil {
+		return err
+	}
+	requestCtx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	var lastError error
+	for ; time.Now().Before(deadline); time.Sleep(e.PreEvictionHookRetryTime) {
+		request, err := http.NewRequestWithContext(requestCtx, http.MethodPost, hookURL, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		request.Header.Set("Content-Type", "application/json")
+		response, err := preEvictionHookClient.Do(request)
+		if err != nil {
+			lastError = err
+			continue
+		}
+		response.Body.Close()
+		if response.StatusCode >= 200 && response.StatusCode < 300 {
+			return nil
+		}
+		lastError = fmt.Errorf("%s responded with %s", hookURL, response.Status)
+	}
+	return fmt.Errorf("no acknowledgement within deadline (last error: %v)", lastError)
+}
+
+// preEvictionHookURL returns the URL of the HTTP pre-eviction hook, resolving
+// URLs relative to the pod IP. Absolute URLs are only allowed if their host is
+// one of allowedHosts, so that pods can't make CA call arbitrary endpoints.
+func preEvictionHookURL(pod *apiv1.Pod, hook string, allowedHosts []string) (string, error) {
+	if strings.HasPrefix(hook, ":") || strings.HasPrefix(hook, "/") {
+		return relativePreEvictionHookURL(pod, hook)
+	}
+	hookURL, err := url.Parse(hook)
+	if err != nil || (hookURL.Scheme != "http" && hookURL.Scheme != "https") || hookURL.Host == "" {
+		return "", fmt.Errorf("invalid pre-eviction hook %q, expected %q or an HTTP URL", hook, PreEvictionHookAnnotation)
+	}
+	if !isPreEvictionHookHostAllowed(hookURL.Hostname(), allowedHosts) {
+		return "", fmt.Errorf("host of pre-eviction hook %q isn't allowed, hooks have to be relative to the pod IP or point to an allowed host", hook)
+	}
+	return hookURL.String(), nil
+}
+
+// relativePreEvictionHookURL builds the URL of a hook relative to the pod IP,
+// ":<port>/<path>" or "/<path>", from its parts rather than by parsing the
+// hook appended to the pod IP, so that the hook can't change the host.
+func relativePreEvictionHookURL(pod *apiv1.Pod, hook string) (string, error) {
+	podIP := pod.Status.PodIP
+	if podIP == "" {
+		return "", fmt.Errorf("pre-eviction hook %q is relative to the pod IP, but the pod has no IP", hook)
+	}
+	host := podIP
+	if strings.Contains(podIP, ":") {
+		host = "[" + podIP + "]"
+	}
+	path := hook
+	if strings.HasPrefix(hook, ":") {
+		port := hook[1:]
+		path = ""
+		if i := strings.Index(port, "/"); i >= 0 {
+			port, path = port[:i], port[i:]
+		}
+		if number, err := strconv.Atoi(port); err != nil || number < 1 || number > 65535 {
+			return "", fmt.Errorf("invalid port in pre-eviction hook %q", hook)
+		}
+		host = net.JoinHostPort(podIP, port)
+	}
+	ref, err := url.Parse(path)
+	if err != nil || ref.Scheme != "" || ref.User != nil || ref.Host != "" || (ref.Path != "" && !strings.HasPrefix(ref.Path, "/")) {
+		return "", fmt.Errorf("invalid path in pre-eviction hook %q", hook)
+	}
+	hookURL := url.URL{Scheme: "http", Host: host, Path: ref.Path, RawQuery: ref.RawQuery}
+	if hookURL.User != nil || hookURL.Hostname() != podIP {
+		return "", fmt.Errorf("pre-eviction hook %q doesn't point to the pod IP", hook)
+	}
+	return hookURL.String(), nil
+}
+
+func isPreEvictionHookHostAllowed(host string, allowedHosts []string) bool {
+	for _, allowedHost := range allowedHosts {
+		if strings.EqualFold(host, allowedHost) {
+			return true
+		}
+	}
+	return false
+}
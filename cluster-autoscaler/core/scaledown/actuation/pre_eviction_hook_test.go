@@ -0,0 +1,234 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package actuation
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	. "k8s.io/autoscaler/cluster-autoscaler/core/test"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+	"k8s.io/client-go/kubernetes/fake"
+	core "k8s.io/client-go/testing"
+)
+
+// fakePodApi tracks the pods of a node through the fake client: evicted pods
+// disappear and pods get acknowledged when the pre-eviction handshake is
+// requested, if ackHandshake is set.
+type fakePodApi struct {
+	sync.Mutex
+	pods         map[string]*apiv1.Pod
+	evicted      []string
+	requested    []string
+	ackHandshake bool
+}
+
+func newFakePodApi(fakeClient *fake.Clientset, ackHandshake bool, pods ...*apiv1.Pod) *fakePodApi {
+	api := &fakePodApi{pods: map[string]*apiv1.Pod{}, ackHandshake: ackHandshake}
+	for _, pod := range pods {
+		api.pods[pod.Name] = pod.DeepCopy()
+	}
+	fakeClient.Fake.AddReactor("get", "pods", func(action core.Action) (bool, runtime.Object, error) {
+		api.Lock()
+		defer api.Unlock()
+		name := action.(core.GetAction).GetName()
+		pod, found := api.pods[name]
+		if !found {
+			return true, nil, errors.NewNotFound(apiv1.Resource("pod"), name)
+		}
+		return true, pod.DeepCopy(), nil
+	})
+	fakeClient.Fake.AddReactor("patch", "pods", func(action core.Action) (bool, runtime.Object, error) {
+		api.Lock()
+		defer api.Unlock()
+		name := action.(core.PatchAction).GetName()
+		pod, found := api.pods[name]
+		if !found {
+			return true, nil, errors.NewNotFound(apiv1.Resource("pod"), name)
+		}
+		api.requested = append(api.requested, name)
+		if api.ackHandshake {
+			pod.Annotations[PreEvictionAcknowledgedKey] = "true"
+		}
+		return true, pod.DeepCopy(), nil
+	})
+	fakeClient.Fake.AddReactor("create", "pods", func(action core.Action) (bool, runtime.Object, error) {
+		api.Lock()
+		defer api.Unlock()
+		eviction := action.(core.CreateAction).GetObject().(*policyv1beta1.Eviction)
+		api.evicted = append(api.evicted, eviction.Name)
+		delete(api.pods, eviction.Name)
+		return true, nil, nil
+	})
+	return api
+}
+
+func TestDrainNodeWithPreEvictionHooks(t *testing.T) {
+	var hookCalls []preEvictionHookRequest
+	var hookCallsLock sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request preEvictionHookRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		hookCallsLock.Lock()
+		defer hookCallsLock.Unlock()
+		hookCalls = append(hookCalls, request)
+		// Acknowledge on the second call.
+		if len(hookCalls) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	n1 := BuildTestNode("n1", 1000, 1000)
+	leader := BuildTestPod("leader", 100, 0, WithNodeName(n1.Name))
+	leader.Annotations = map[string]string{PreEvictionHookKey: PreEvictionHookAnnotation, DrainOrderKey: "1"}
+	replica := BuildTestPod("replica", 100, 0, WithNodeName(n1.Name))
+	replica.Annotations = map[string]string{PreEvictionHookKey: server.URL + "/handoff"}
+	web := BuildTestPod("web", 100, 0, WithNodeName(n1.Name))
+
+	fakeClient := &fake.Clientset{}
+	api := newFakePodApi(fakeClient, true, leader, replica, web)
+	options := config.AutoscalingOptions{
+		MaxGracefulTerminationSec:   20,
+		MaxPodEvictionTime:          5 * time.Second,
+		PreEvictionHookTimeout:      5 * time.Second,
+		PreEvictionHookAllowedHosts: []string{"127.0.0.1"},
+	}
+	ctx, err := NewScaleTestAutoscalingContext(options, fakeClient, nil, nil, nil, nil)
+	assert.NoError(t, err)
+	evictor := Evictor{
+		PodEvictionHeadroom:              DefaultPodEvictionHeadroom,
+		PreEvictionHookRetryTime:         10 * time.Millisecond,
+		shutdownGracePeriodByPodPriority: SingleRuleDrainConfig(ctx.MaxGracefulTerminationSec),
+	}
+	clustersnapshot.InitializeClusterSnapshotOrDie(t, ctx.ClusterSnapshot, []*apiv1.Node{n1}, []*apiv1.Pod{leader, replica, web})
+	nodeInfo, err := ctx.ClusterSnapshot.NodeInfos().Get(n1.Name)
+	assert.NoError(t, err)
+
+	results, err := evictor.DrainNode(&ctx, nodeInfo)
+	assert.NoError(t, err)
+	for _, result := range results {
+		assert.True(t, result.WasEvictionSuccessful())
+	}
+
+	// The leader has a higher drain order, so it's evicted last.
+	assert.Len(t, api.evicted, 3)
+	assert.ElementsMatch(t, []string{"replica", "web"}, api.evicted[:2])
+	assert.Equal(t, "leader", api.evicted[2])
+	assert.Equal(t, []string{"leader"}, api.requested)
+	assert.Equal(t, []preEvictionHookRequest{
+		{Namespace: "default", Pod: "replica", Node: "n1"},
+		{Namespace: "default", Pod: "replica", Node: "n1"},
+	}, hookCalls)
+}
+
+func TestDrainNodeWithPreEvictionHookTimeout(t *testing.T) {
+	n1 := BuildTestNode("n1", 1000, 1000)
+	p1 := BuildTestPod("p1", 100, 0, WithNodeName(n1.Name))
+	p1.Annotations = map[string]string{PreEvictionHookKey: PreEvictionHookAnnotation}
+
+	fakeClient := &fake.Clientset{}
+	api := newFakePodApi(fakeClient, false, p1)
+	options := config.AutoscalingOptions{
+		MaxGracefulTerminationSec: 20,
+		MaxPodEvictionTime:        5 * time.Second,
+		PreEvictionHookTimeout:    100 * time.Millisecond,
+	}
+	ctx, err := NewScaleTestAutoscalingContext(options, fakeClient, nil, nil, nil, nil)
+	assert.NoError(t, err)
+	evictor := Evictor{
+		PodEvictionHeadroom:              DefaultPodEvictionHeadroom,
+		PreEvictionHookRetryTime:         10 * time.Millisecond,
+		shutdownGracePeriodByPodPriority: SingleRuleDrainConfig(ctx.MaxGracefulTerminationSec),
+	}
+	clustersnapshot.InitializeClusterSnapshotOrDie(t, ctx.ClusterSnapshot, []*apiv1.Node{n1}, []*apiv1.Pod{p1})
+	nodeInfo, err := ctx.ClusterSnapshot.NodeInfos().Get(n1.Name)
+	assert.NoError(t, err)
+
+	// The pod is evicted even though it never acknowledged the eviction.
+	_, err = evictor.DrainNode(&ctx, nodeInfo)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"p1"}, api.requested)
+	assert.Equal(t, []string{"p1"}, api.evicted)
+}
+
+func TestCallPreEvictionHookDoesNotFollowRedirects(t *testing.T) {
+	redirectTarget := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Redirect of pre-eviction hook followed")
+	}))
+	defer redirectTarget.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, redirectTarget.URL, http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	evictor := Evictor{PreEvictionHookRetryTime: 10 * time.Millisecond}
+	pod := BuildTestPod("p1", 100, 0)
+	err := evictor.callPreEvictionHook(pod, server.URL+"/handoff", []string{"127.0.0.1"}, time.Now().Add(50*time.Millisecond))
+	assert.Error(t, err)
+}
+
+func TestPreEvictionHookURL(t *testing.T) {
+	allowedHosts := []string{"leader.example.com"}
+	testCases := []struct {
+		name    string
+		hook    string
+		podIP   string
+		wantURL string
+		wantErr bool
+	}{
+		{name: "absolute with allowed host", hook: "https://leader.example.com/handoff", wantURL: "https://leader.example.com/handoff"},
+		{name: "allowed host with port", hook: "http://Leader.example.com:8080/handoff", wantURL: "http://Leader.example.com:8080/handoff"},
+		{name: "absolute with other host", hook: "http://169.254.169.254/latest/meta-data", wantErr: true},
+		{name: "absolute with allowed host as user info", hook: "http://leader.example.com@10.0.0.2/handoff", wantErr: true},
+		{name: "port relative to pod IP", hook: ":8080/handoff", podIP: "10.0.0.1", wantURL: "http://10.0.0.1:8080/handoff"},
+		{name: "path relative to pod IP", hook: "/handoff", podIP: "10.0.0.1", wantURL: "http://10.0.0.1/handoff"},
+		{name: "IPv6 pod IP", hook: ":8080/handoff", podIP: "fd00::1", wantURL: "http://[fd00::1]:8080/handoff"},
+		{name: "relative without pod IP", hook: ":8080/handoff", wantErr: true},
+		{name: "relative with other host as user info", hook: ":8080@evil.example.com/x", podIP: "10.0.0.1", wantErr: true},
+		{name: "relative with empty port and user info", hook: ":@evil/x", podIP: "10.0.0.1", wantErr: true},
+		{name: "relative with other host after path", hook: "//evil.example.com/x", podIP: "10.0.0.1", wantErr: true},
+		{name: "relative with query", hook: ":8080/handoff?drain=true", podIP: "10.0.0.1", wantURL: "http://10.0.0.1:8080/handoff?drain=true"},
+		{name: "port only", hook: ":8080", podIP: "10.0.0.1", wantURL: "http://10.0.0.1:8080"},
+		{name: "not a URL", hook: "handshake", wantErr: true},
+		{name: "unsupported scheme", hook: "ftp://leader/handoff", wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pod := BuildTestPod("p1", 100, 0)
+			pod.Status.PodIP = tc.podIP
+			hookURL, err := preEvictionHookURL(pod, tc.hook, allowedHosts)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantURL, hookURL)
+		})
+	}
+}
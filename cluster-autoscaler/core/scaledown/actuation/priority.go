@@ -27,6 +27,14 @@ import (
 	kubelet_config "k8s.io/kubernetes/pkg/kubelet/apis/config"
 )
 
+const (
+	// DrainOrderKey is the annotation which orders the eviction of pods in the
+	// same priority group. Pods with a lower drain order are evicted, and
+	// have to terminate, before pods with a higher one. Pods without the
+	// annotation have drain order 0.
+	DrainOrderKey = "cluster-autoscaler.kubernetes.io/drain-order"
+)
+
 func groupByPriority(shutdownGracePeriodByPodPriority []kubelet_config.ShutdownGracePeriodByPodPriority, fullEvictionPods, bestEffortEvictionPods []*apiv1.Pod) []podEvictionGroup {
 	groups := make([]podEvictionGroup, 0, len(shutdownGracePeriodByPodPriority))
 	for _, period := range shutdownGracePeriodByPodPriority {
@@ -46,6 +54,51 @@ func groupByPriority(shutdownGracePeriodByPodPriority []kubelet_config.ShutdownG
 	return groups
 }
 
+// groupByDrainOrder splits a priority group into groups of pods with the same
+// drain order, in ascending drain order.
+func groupByDrainOrder(group podEvictionGroup) []podEvictionGroup {
+	byOrder := map[int]*podEvictionGroup{}
+	var orders []int
+	groupOf := func(pod *apiv1.Pod) *podEvictionGroup {
+		order := drainOrder(pod)
+		orderGroup, found := byOrder[order]
+		if !found {
+			orderGroup = &podEvictionGroup{ShutdownGracePeriodByPodPriority: group.ShutdownGracePeriodByPodPriority}
+			byOrder[order] = orderGroup
+			orders = append(orders, order)
+		}
+		return orderGroup
+	}
+	for _, pod := range group.FullEvictionPods {
+		orderGroup := groupOf(pod)
+		orderGroup.FullEvictionPods = append(orderGroup.FullEvictionPods, pod)
+	}
+	for _, pod := range group.BestEffortEvictionPods {
+		orderGroup := groupOf(pod)
+		orderGroup.BestEffortEvictionPods = append(orderGroup.BestEffortEvictionPods, pod)
+	}
+
+	sort.Ints(orders)
+	groups := make([]podEvictionGroup, 0, len(orders))
+	for _, order := range orders {
+		groups = append(groups, *byOrder[order])
+	}
+	return groups
+}
+
+func drainOrder(pod *apiv1.Pod) int {
+	value, found := pod.Annotations[DrainOrderKey]
+	if !found {
+		return 0
+	}
+	order, err := strconv.Atoi(value)
+	if err != nil {
+		klog.Warningf("Ignoring invalid %s annotation of pod %s/%s: %v", DrainOrderKey, pod.Namespace, pod.Name, err)
+		return 0
+	}
+	return order
+}
+
 func groupIndex(pod *apiv1.Pod, groups []podEvictionGroup) int {
 	var priority int32
 	if pod.Spec.Priority != nil {
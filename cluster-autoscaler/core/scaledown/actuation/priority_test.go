@@ -184,6 +184,44 @@ func TestGroupByPriority(t *testing.T) {
 	assert.Equal(t, wantGroups, groups)
 }
 
+func TestGroupByDrainOrder(t *testing.T) {
+	p1 := BuildTestPod("p1", 100, 0)
+	p2 := BuildTestPod("p2", 100, 0)
+	p3 := BuildTestPod("p3", 100, 0)
+	p4 := BuildTestPod("p4", 100, 0)
+	p5 := BuildTestPod("p5", 100, 0)
+	p1.Annotations = map[string]string{DrainOrderKey: "10"}
+	p2.Annotations = map[string]string{DrainOrderKey: "-1"}
+	p4.Annotations = map[string]string{DrainOrderKey: "invalid"}
+	p5.Annotations = map[string]string{DrainOrderKey: "10"}
+
+	period := kubelet_config.ShutdownGracePeriodByPodPriority{Priority: 10, ShutdownGracePeriodSeconds: 4}
+	wantGroups := []podEvictionGroup{
+		{
+			ShutdownGracePeriodByPodPriority: period,
+			FullEvictionPods:                 []*apiv1.Pod{p2},
+		},
+		{
+			ShutdownGracePeriodByPodPriority: period,
+			FullEvictionPods:                 []*apiv1.Pod{p3},
+			BestEffortEvictionPods:           []*apiv1.Pod{p4},
+		},
+		{
+			ShutdownGracePeriodByPodPriority: period,
+			FullEvictionPods:                 []*apiv1.Pod{p1},
+			BestEffortEvictionPods:           []*apiv1.Pod{p5},
+		},
+	}
+
+	groups := groupByDrainOrder(podEvictionGroup{
+		ShutdownGracePeriodByPodPriority: period,
+		FullEvictionPods:                 []*apiv1.Pod{p1, p2, p3},
+		BestEffortEvictionPods:           []*apiv1.Pod{p4, p5},
+	})
+	assert.Equal(t, wantGroups, groups)
+	assert.Empty(t, groupByDrainOrder(podEvictionGroup{ShutdownGracePeriodByPodPriority: period}))
+}
+
 func TestParseShutdownGracePeriodsAndPriorities(t *testing.T) {
 	testCases := []struct {
 		name  string
@@ -29,6 +29,10 @@ import (
 
 // UpdateSoftDeletionTaints manages soft taints of unneeded nodes.
 func UpdateSoftDeletionTaints(context *context.AutoscalingContext, uneededNodes, neededNodes []*apiv1.Node) (errors []error) {
+	if context.DryRunRecorder != nil {
+		// Soft taints are only a hint for the scheduler, they are skipped in dry-run mode.
+		return nil
+	}
 	defer metrics.UpdateDurationFromStart(metrics.ScaleDownSoftTaintUnneeded, time.Now())
 	b := &budgetTracker{
 		apiCallBudget: context.AutoscalingOptions.MaxBulkSoftTaintCount,
@@ -18,6 +18,7 @@ package budgets
 
 import (
 	"reflect"
+	"time"
 
 	apiv1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
@@ -38,20 +39,32 @@ type NodeGroupView struct {
 
 // ScaleDownBudgetProcessor is responsible for keeping the number of nodes deleted in parallel within defined limits.
 type ScaleDownBudgetProcessor struct {
-	ctx *context.AutoscalingContext
+	ctx          *context.AutoscalingContext
+	configGetter nodeGroupConfigGetter
+	nodes        []*apiv1.Node
+	currentTime  time.Time
+}
+
+// nodeGroupConfigGetter is an interface to limit the functions that can be used
+// from NodeGroupConfigProcessor interface
+type nodeGroupConfigGetter interface {
+	// GetZeroOrMaxNodeScaling returns ZeroOrMaxNodeScaling value that should be used for a given NodeGroup.
+	GetZeroOrMaxNodeScaling(nodeGroup cloudprovider.NodeGroup) (bool, error)
 }
 
 // NewScaleDownBudgetProcessor creates a ScaleDownBudgetProcessor instance.
-func NewScaleDownBudgetProcessor(ctx *context.AutoscalingContext) *ScaleDownBudgetProcessor {
+func NewScaleDownBudgetProcessor(ctx *context.AutoscalingContext, configGetter nodeGroupConfigGetter) *ScaleDownBudgetProcessor {
 	return &ScaleDownBudgetProcessor{
-		ctx: ctx,
+		ctx:          ctx,
+		configGetter: configGetter,
 	}
 }
 
-// CropNodes crops the provided node lists to respect scale-down max parallelism budgets.
-// The returned nodes are grouped by a node group.
+// CropNodes crops the provided node lists to respect scale-down max parallelism budgets
+// and disruption budgets. The returned nodes are grouped by a node group.
 // This function assumes that each node group may occur at most once in each of the "empty" and "drain" lists.
 func (bp *ScaleDownBudgetProcessor) CropNodes(as scaledown.ActuationStatus, empty, drain []*apiv1.Node) (emptyToDelete, drainToDelete []*NodeGroupView) {
+	empty, drain = bp.cropToDisruptionBudgets(as, empty, drain)
 	emptyIndividual, emptyAtomic := bp.categorize(bp.group(empty))
 	drainIndividual, drainAtomic := bp.categorize(bp.group(drain))
 
@@ -197,12 +210,12 @@ func (bp *ScaleDownBudgetProcessor) group(nodes []*apiv1.Node) []*NodeGroupView
 
 func (bp *ScaleDownBudgetProcessor) categorize(groups []*NodeGroupView) (individual, atomic []*NodeGroupView) {
 	for _, view := range groups {
-		autoscalingOptions, err := view.Group.GetOptions(bp.ctx.NodeGroupDefaults)
-		if err != nil && err != cloudprovider.ErrNotImplemented {
+		zeroOrMaxNodeScaling, err := bp.configGetter.GetZeroOrMaxNodeScaling(view.Group)
+		if err != nil {
 			klog.Errorf("Failed to get autoscaling options for node group %s: %v", view.Group.Id(), err)
 			continue
 		}
-		if autoscalingOptions != nil && autoscalingOptions.ZeroOrMaxNodeScaling {
+		if zeroOrMaxNodeScaling {
 			atomic = append(atomic, view)
 		} else {
 			individual = append(individual, view)
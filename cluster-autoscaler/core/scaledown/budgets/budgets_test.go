@@ -23,15 +23,19 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/stretchr/testify/assert"
 	apiv1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
 	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/clusterstate/api"
 	"k8s.io/autoscaler/cluster-autoscaler/config"
 	"k8s.io/autoscaler/cluster-autoscaler/context"
 	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown/deletiontracker"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroupconfig"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot"
 )
 
 func TestCropNodesToBudgets(t *testing.T) {
@@ -455,7 +459,7 @@ func TestCropNodesToBudgets(t *testing.T) {
 				drainList = append(drainList, bucket.Nodes...)
 			}
 
-			budgeter := NewScaleDownBudgetProcessor(ctx)
+			budgeter := NewScaleDownBudgetProcessor(ctx, nodegroupconfig.NewDefaultNodeGroupConfigProcessor(ctx.NodeGroupDefaults))
 			gotEmpty, gotDrain := budgeter.CropNodes(ndt, emptyList, drainList)
 			if diff := cmp.Diff(tc.wantEmpty, gotEmpty, cmpopts.EquateEmpty(), transformNodeGroupView); diff != "" {
 				t.Errorf("cropNodesToBudgets empty nodes diff (-want +got):\n%s", diff)
@@ -518,3 +522,152 @@ func generateNode(name string) *apiv1.Node {
 		},
 	}
 }
+
+func TestCropNodesToDisruptionBudgets(t *testing.T) {
+	const budgets = `
+budgets:
+  - name: per-group
+    nodes: 25%
+    nodeGroups: [".*"]
+  - name: zone-a
+    nodes: 1
+    nodeSelector:
+      matchLabels:
+        zone: a
+`
+	for tn, tc := range map[string]struct {
+		budgets          string
+		now              time.Time
+		upcoming         int
+		drainInProgress  []string
+		empty            []string
+		drain            []string
+		wantEmpty        []string
+		wantDrain        []string
+		wantBudgetStatus []api.DisruptionBudgetStatus
+	}{
+		"one node per node group": {
+			budgets:   `budgets: [{name: per-group, nodes: 25%, nodeGroups: [".*"]}]`,
+			drain:     []string{"a-1", "a-2", "b-1", "b-2"},
+			wantDrain: []string{"a-1", "b-1"},
+		},
+		"one node per zone": {
+			budgets:   `budgets: [{name: zone-a, nodes: 1, nodeSelector: {matchLabels: {zone: a}}}]`,
+			drain:     []string{"a-1", "a-2", "b-1", "b-2"},
+			wantDrain: []string{"a-1", "b-1", "b-2"},
+		},
+		"deletion in progress": {
+			budgets:         budgets,
+			drainInProgress: []string{"a-3"},
+			drain:           []string{"a-1", "b-1", "b-2"},
+			wantDrain:       []string{"b-1"},
+			wantBudgetStatus: []api.DisruptionBudgetStatus{
+				{Name: "per-group", NodeGroup: "ng-a", Nodes: 4, Allowed: 1, Disrupting: 1},
+				{Name: "per-group", NodeGroup: "ng-b", Nodes: 4, Allowed: 1},
+				{Name: "zone-a", Nodes: 4, Allowed: 1, Disrupting: 1},
+			},
+		},
+		"empty nodes first": {
+			budgets:   budgets,
+			empty:     []string{"b-1"},
+			drain:     []string{"a-1", "b-2"},
+			wantEmpty: []string{"b-1"},
+			wantDrain: []string{"a-1"},
+		},
+		"zero budget": {
+			budgets: `budgets: [{name: freeze, nodes: 0}]`,
+			empty:   []string{"a-1"},
+			drain:   []string{"b-1"},
+		},
+		"upcoming nodes not counted": {
+			budgets:   `budgets: [{name: per-group, nodes: 50%, nodeGroups: [".*"]}]`,
+			upcoming:  4,
+			drain:     []string{"a-1", "a-2", "a-3"},
+			wantDrain: []string{"a-1", "a-2"},
+			wantBudgetStatus: []api.DisruptionBudgetStatus{
+				{Name: "per-group", NodeGroup: "ng-a", Nodes: 4, Allowed: 2},
+				{Name: "per-group", NodeGroup: "ng-b", Nodes: 4, Allowed: 2},
+			},
+		},
+		"budget outside of its schedule": {
+			budgets:   `budgets: [{name: business-hours, nodes: 0, schedule: "daily 08:00-18:00 UTC"}]`,
+			now:       time.Date(2024, time.May, 6, 20, 0, 0, 0, time.UTC),
+			drain:     []string{"a-1", "b-1"},
+			wantDrain: []string{"a-1", "b-1"},
+		},
+		"budget within its schedule": {
+			budgets: `budgets: [{name: business-hours, nodes: 0, schedule: "daily 08:00-18:00 UTC"}]`,
+			now:     time.Date(2024, time.May, 6, 12, 0, 0, 0, time.UTC),
+			drain:   []string{"a-1", "b-1"},
+		},
+	} {
+		t.Run(tn, func(t *testing.T) {
+			provider := testprovider.NewTestCloudProvider(nil, nil)
+			snapshot := clustersnapshot.NewBasicClusterSnapshot()
+			nodes := map[string]*apiv1.Node{}
+			var registered []*apiv1.Node
+			for _, zone := range []string{"a", "b"} {
+				provider.AddNodeGroup("ng-"+zone, 0, 10, 4)
+				for i := 1; i <= 4; i++ {
+					node := generateNode(fmt.Sprintf("%s-%d", zone, i))
+					node.Labels = map[string]string{"zone": zone}
+					provider.AddNode("ng-"+zone, node)
+					assert.NoError(t, snapshot.AddNode(node))
+					nodes[node.Name] = node
+					registered = append(registered, node)
+				}
+			}
+			for i := 1; i <= tc.upcoming; i++ {
+				node := generateNode(fmt.Sprintf("upcoming-%d", i))
+				node.Labels = map[string]string{"zone": "a"}
+				provider.AddNode("ng-a", node)
+				assert.NoError(t, snapshot.AddNode(node))
+			}
+			now := tc.now
+			if now.IsZero() {
+				now = time.Now()
+			}
+			disruptionBudgets, err := config.ParseDisruptionBudgets([]byte(tc.budgets))
+			assert.NoError(t, err)
+			ctx := &context.AutoscalingContext{
+				AutoscalingOptions: config.AutoscalingOptions{
+					MaxScaleDownParallelism: 10,
+					MaxDrainParallelism:     10,
+					DisruptionBudgets:       disruptionBudgets,
+				},
+				CloudProvider:   provider,
+				ClusterSnapshot: snapshot,
+			}
+			ndt := deletiontracker.NewNodeDeletionTracker(1 * time.Hour)
+			for _, name := range tc.drainInProgress {
+				ndt.StartDeletionWithDrain("ng-a", name)
+			}
+			var emptyList, drainList []*apiv1.Node
+			for _, name := range tc.empty {
+				emptyList = append(emptyList, nodes[name])
+			}
+			for _, name := range tc.drain {
+				drainList = append(drainList, nodes[name])
+			}
+
+			budgeter := NewScaleDownBudgetProcessor(ctx, nodegroupconfig.NewDefaultNodeGroupConfigProcessor(ctx.NodeGroupDefaults))
+			budgeter.UpdateClusterState(registered, now)
+			gotEmpty, gotDrain := budgeter.CropNodes(ndt, emptyList, drainList)
+			assert.ElementsMatch(t, tc.wantEmpty, viewNodeNames(gotEmpty))
+			assert.ElementsMatch(t, tc.wantDrain, viewNodeNames(gotDrain))
+			if tc.wantBudgetStatus != nil {
+				assert.Equal(t, tc.wantBudgetStatus, budgeter.DisruptionBudgetsStatus(ndt))
+			}
+		})
+	}
+}
+
+func viewNodeNames(views []*NodeGroupView) []string {
+	var names []string
+	for _, view := range views {
+		for _, node := range view.Nodes {
+			names = append(names, node.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package budgets
+
+import (
+	"reflect"
+	"sort"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"k8s.io/autoscaler/cluster-autoscaler/clusterstate/api"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown"
+)
+
+// disruptionDomain is the set of nodes a disruption budget applies to
+// together: all nodes selected by the budget, or the selected nodes of one
+// node group for budgets applying to each node group separately.
+type disruptionDomain struct {
+	budget     *config.DisruptionBudget
+	nodeGroup  string
+	nodes      int
+	disrupting int
+}
+
+func (d *disruptionDomain) remaining() int {
+	return d.budget.Allowed(d.nodes) - d.disrupting
+}
+
+// UpdateClusterState sets the registered nodes of the cluster and the time of
+// the current loop, against which disruption budgets are evaluated. Upcoming
+// nodes of the cluster snapshot don't count towards percentage budgets.
+func (bp *ScaleDownBudgetProcessor) UpdateClusterState(nodes []*apiv1.Node, currentTime time.Time) {
+	bp.nodes = nodes
+	bp.currentTime = currentTime
+}
+
+// disruptionDomains returns the disruption budgets active at the current time
+// and their domains, keyed by domainKey, with the registered nodes and the
+// deletions in progress counted. It returns nil if no budget is active.
+func (bp *ScaleDownBudgetProcessor) disruptionDomains(as scaledown.ActuationStatus) ([]*config.DisruptionBudget, map[string]*disruptionDomain) {
+	if bp.ctx.DisruptionBudgets == nil {
+		return nil, nil
+	}
+	var active []*config.DisruptionBudget
+	for i := range bp.ctx.DisruptionBudgets.Budgets {
+		if budget := &bp.ctx.DisruptionBudgets.Budgets[i]; budget.Active(bp.currentTime) {
+			active = append(active, budget)
+		}
+	}
+	if len(active) == 0 {
+		return nil, nil
+	}
+
+	domains := map[string]*disruptionDomain{}
+	nodesByName := make(map[string]*apiv1.Node, len(bp.nodes))
+	for _, node := range bp.nodes {
+		nodesByName[node.Name] = node
+		for _, domain := range bp.domainsOf(active, domains, node) {
+			domain.nodes++
+		}
+	}
+	emptyInProgress, drainInProgress := as.DeletionsInProgress()
+	for _, name := range append(emptyInProgress, drainInProgress...) {
+		node, found := nodesByName[name]
+		if !found {
+			// The node is already gone.
+			continue
+		}
+		for _, domain := range bp.domainsOf(active, domains, node) {
+			domain.disrupting++
+		}
+	}
+	return active, domains
+}
+
+// domainsOf returns the domains of the budgets the node belongs to, adding
+// the missing ones to domains.
+func (bp *ScaleDownBudgetProcessor) domainsOf(budgets []*config.DisruptionBudget, domains map[string]*disruptionDomain, node *apiv1.Node) []*disruptionDomain {
+	nodeGroupId := ""
+	nodeGroup, err := bp.ctx.CloudProvider.NodeGroupForNode(node)
+	if err == nil && nodeGroup != nil && !reflect.ValueOf(nodeGroup).IsNil() {
+		nodeGroupId = nodeGroup.Id()
+	}
+	var result []*disruptionDomain
+	for _, budget := range budgets {
+		if !budget.Selects(node.Labels, nodeGroupId) {
+			continue
+		}
+		key := domainKey(budget, nodeGroupId)
+		domain, found := domains[key]
+		if !found {
+			domain = &disruptionDomain{budget: budget}
+			if budget.PerNodeGroup() {
+				domain.nodeGroup = nodeGroupId
+			}
+			domains[key] = domain
+		}
+		result = append(result, domain)
+	}
+	return result
+}
+
+func domainKey(budget *config.DisruptionBudget, nodeGroupId string) string {
+	if budget.PerNodeGroup() {
+		return budget.Name + "/" + nodeGroupId
+	}
+	return budget.Name
+}
+
+// cropToDisruptionBudgets drops the nodes whose deletion would exceed one of
+// the disruption budgets they belong to. Empty nodes take precedence.
+func (bp *ScaleDownBudgetProcessor) cropToDisruptionBudgets(as scaledown.ActuationStatus, empty, drain []*apiv1.Node) ([]*apiv1.Node, []*apiv1.Node) {
+	active, domains := bp.disruptionDomains(as)
+	if len(active) == 0 {
+		return empty, drain
+	}
+	crop := func(nodes []*apiv1.Node) []*apiv1.Node {
+		result := make([]*apiv1.Node, 0, len(nodes))
+	nodeLoop:
+		for _, node := range nodes {
+			nodeDomains := bp.domainsOf(active, domains, node)
+			for _, domain := range nodeDomains {
+				if domain.remaining() < 1 {
+					klog.V(4).Infof("Skipping deletion of %s, disruption budget %s exhausted", node.Name, domainKey(domain.budget, domain.nodeGroup))
+					continue nodeLoop
+				}
+			}
+			for _, domain := range nodeDomains {
+				domain.disrupting++
+			}
+			result = append(result, node)
+		}
+		return result
+	}
+	return crop(empty), crop(drain)
+}
+
+// DisruptionBudgetsStatus returns the state of the disruption budgets active
+// at the current time, for the status ConfigMap.
+func (bp *ScaleDownBudgetProcessor) DisruptionBudgetsStatus(as scaledown.ActuationStatus) []api.DisruptionBudgetStatus {
+	_, domains := bp.disruptionDomains(as)
+	keys := make([]string, 0, len(domains))
+	for key := range domains {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var result []api.DisruptionBudgetStatus
+	for _, key := range keys {
+		domain := domains[key]
+		result = append(result, api.DisruptionBudgetStatus{
+			Name:       domain.budget.Name,
+			NodeGroup:  domain.nodeGroup,
+			Nodes:      domain.nodes,
+			Allowed:    domain.budget.Allowed(domain.nodes),
+			Disrupting: domain.disrupting,
+		})
+	}
+	return result
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package consolidation replaces nodes with nodes of another node group: the
+// other node group is scaled up first, and the replaced nodes are drained once
+// the new nodes are ready. Consolidator replaces under-utilized nodes with
+// fewer or cheaper ones, and Rebalancer moves nodes of fallback node groups
+// back to the node groups preferred by the priority expander.
+package consolidation
+
+import (
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/pricing"
+	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown"
+	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown/status"
+	"k8s.io/autoscaler/cluster-autoscaler/estimator"
+	"k8s.io/autoscaler/cluster-autoscaler/observers/nodegroupchange"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/maintenance"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroupconfig"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/drainability/rules"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/options"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/errors"
+	"k8s.io/klog/v2"
+	schedulerframework "k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// Consolidator finds and executes consolidation plans. A plan is proven to be
+// cheaper with the pricing model of the cloud provider, and schedulable with
+// the estimator, before the new nodes are requested. Only one plan is
+// executed at a time.
+type Consolidator struct {
+	*executor
+	pricingProvider cloudprovider.CloudProvider
+}
+
+// NewConsolidator returns a new Consolidator. The fallback pricing model, if
+// not nil, is used for cloud providers which don't implement pricing.
+func NewConsolidator(
+	context *context.AutoscalingContext,
+	estimatorBuilder estimator.EstimatorBuilder,
+	deleteOptions options.NodeDeleteOptions,
+	drainabilityRules rules.Rules,
+	scaleStateNotifier nodegroupchange.NodeGroupChangeObserver,
+	scaleUpTracker ScaleUpTracker,
+	nodeGroupConfigProcessor nodegroupconfig.NodeGroupConfigProcessor,
+	maintenanceWindows *maintenance.Tracker,
+	fallbackPricingModel cloudprovider.PricingModel,
+) *Consolidator {
+	return &Consolidator{
+		executor:        newExecutor("Consolidation", context, estimatorBuilder, deleteOptions, drainabilityRules, scaleStateNotifier, scaleUpTracker, nodeGroupConfigProcessor, maintenanceWindows),
+		pricingProvider: pricing.WithFallbackPricing(context.CloudProvider, fallbackPricingModel),
+	}
+}
+
+// RunOnce finishes the plan in progress once its new nodes are ready, by
+// starting the deletion of the replaced nodes with the actuator, or starts a
+// new plan if there is none. It returns the nodes whose deletion started.
+func (c *Consolidator) RunOnce(candidates []*apiv1.Node, nodeInfos map[string]*schedulerframework.NodeInfo, actuator scaledown.Actuator, now time.Time) ([]*status.ScaleDownNode, errors.AutoscalerError) {
+	return c.runOnce(func(scaledown.ActuationStatus) *Plan {
+		plan := c.FindPlan(candidates, nodeInfos, now)
+		if plan == nil {
+			klog.V(4).Info("Consolidation: no cheaper layout found")
+		}
+		return plan
+	}, actuator, now)
+}
+
+// FindPlan returns the plan saving the most, or nil if no plan saves at least
+// ConsolidationMinSavings of the price of the replaced nodes. Only
+// candidates below ConsolidationUtilizationThreshold, whose pods can all be
+// moved, are replaced.
+func (c *Consolidator) FindPlan(candidates []*apiv1.Node, nodeInfos map[string]*schedulerframework.NodeInfo, now time.Time) *Plan {
+	pricingModel, err := c.pricingProvider.Pricing()
+	if err != nil {
+		klog.V(4).Infof("Consolidation: pricing not available: %v", err)
+		return nil
+	}
+
+	nodeGroups := map[string]cloudprovider.NodeGroup{}
+	bySource := map[string][]*candidate{}
+	for _, node := range candidates {
+		cand := c.candidate(node, now)
+		if cand == nil || cand.utilization >= c.context.ConsolidationUtilizationThreshold {
+			continue
+		}
+		price, err := pricingModel.NodePrice(node, now, now.Add(time.Hour))
+		if err != nil {
+			klog.V(4).Infof("Consolidation: no price of node %s: %v", node.Name, err)
+			continue
+		}
+		cand.price = price
+		nodeGroups[cand.nodeGroup.Id()] = cand.nodeGroup
+		bySource[cand.nodeGroup.Id()] = append(bySource[cand.nodeGroup.Id()], cand)
+	}
+
+	var best *Plan
+	for _, id := range sortedKeys(bySource) {
+		sourceCandidates := c.limitCandidates(nodeGroups[id], bySource[id], c.context.ConsolidationMaxNodes, now)
+		if len(sourceCandidates) == 0 {
+			continue
+		}
+		plan := c.planFor(id, sourceCandidates, nodeInfos, pricingModel, now)
+		if plan != nil && (best == nil || plan.CurrentPrice-plan.NewPrice > best.CurrentPrice-best.NewPrice) {
+			best = plan
+		}
+	}
+	return best
+}
+
+func (c *Consolidator) planFor(sourceId string, candidates []*candidate, nodeInfos map[string]*schedulerframework.NodeInfo, pricingModel cloudprovider.PricingModel, now time.Time) *Plan {
+	var nodesToRemove []*apiv1.Node
+	var pods []*apiv1.Pod
+	currentPrice := 0.0
+	for _, cand := range candidates {
+		nodesToRemove = append(nodesToRemove, cand.node)
+		pods = append(pods, cand.pods...)
+		currentPrice += cand.price
+	}
+
+	c.context.ClusterSnapshot.Fork()
+	defer c.context.ClusterSnapshot.Revert()
+	remainingPods := c.unschedulablePodsInFork(nodesToRemove, pods, func(*schedulerframework.NodeInfo) bool { return true })
+	if len(remainingPods) == 0 {
+		// The nodes can be removed without replacement, which is what the
+		// regular scale-down does.
+		return nil
+	}
+
+	var best *Plan
+	for _, nodeGroup := range c.context.CloudProvider.NodeGroups() {
+		if nodeGroup.Id() == sourceId || !c.canScaleUp(nodeGroup, now) {
+			continue
+		}
+		nodeInfo, found := nodeInfos[nodeGroup.Id()]
+		if !found {
+			continue
+		}
+		price, err := pricingModel.NodePrice(nodeInfo.Node(), now, now.Add(time.Hour))
+		if err != nil {
+			klog.V(4).Infof("Consolidation: no price of node group %s: %v", nodeGroup.Id(), err)
+			continue
+		}
+		newNodes, fits := c.estimateInFork(remainingPods, nodeGroup, nodeInfo)
+		if !fits {
+			continue
+		}
+		newPrice := price * float64(newNodes)
+		if newPrice > currentPrice*(1-c.context.ConsolidationMinSavings) {
+			klog.V(4).Infof("Consolidation: %d nodes of %s cost %.3f per hour, not enough savings on %.3f per hour", newNodes, nodeGroup.Id(), newPrice, currentPrice)
+			continue
+		}
+		if best == nil || newPrice < best.NewPrice {
+			best = &Plan{NodesToRemove: nodesToRemove, NodeGroup: nodeGroup, NewNodes: newNodes, CurrentPrice: currentPrice, NewPrice: newPrice}
+		}
+	}
+	return best
+}
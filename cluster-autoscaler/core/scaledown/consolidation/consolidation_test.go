@@ -0,0 +1,487 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package consolidation
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/clusterstate/api"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/core/dryrun"
+	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown"
+	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown/status"
+	. "k8s.io/autoscaler/cluster-autoscaler/core/test"
+	"k8s.io/autoscaler/cluster-autoscaler/estimator"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroupconfig"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/options"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/backoff"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/errors"
+	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+	"k8s.io/client-go/kubernetes/fake"
+	schedulerframework "k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+type fakePricingModel struct {
+	prices map[string]float64
+}
+
+func (m *fakePricingModel) NodePrice(node *apiv1.Node, startTime time.Time, endTime time.Time) (float64, error) {
+	price, found := m.prices[node.Name]
+	if !found {
+		return 0, fmt.Errorf("unknown node %s", node.Name)
+	}
+	return price * endTime.Sub(startTime).Hours(), nil
+}
+
+func (m *fakePricingModel) PodPrice(*apiv1.Pod, time.Time, time.Time) (float64, error) {
+	return 0, nil
+}
+
+type fakeScaleUpTracker struct {
+	unhealthy map[string]bool
+	scalingUp map[string]bool
+	backedOff map[string]bool
+	// maxNodeProvisionTime overrides the default of 15 minutes per node group.
+	maxNodeProvisionTime map[string]time.Duration
+}
+
+func (t *fakeScaleUpTracker) IsNodeGroupHealthy(nodeGroupName string) bool {
+	return !t.unhealthy[nodeGroupName]
+}
+
+func (t *fakeScaleUpTracker) IsNodeGroupScalingUp(nodeGroupName string) bool {
+	return t.scalingUp[nodeGroupName]
+}
+
+func (t *fakeScaleUpTracker) BackoffStatusForNodeGroup(nodeGroup cloudprovider.NodeGroup, _ time.Time) backoff.Status {
+	return backoff.Status{IsBackedOff: t.backedOff[nodeGroup.Id()]}
+}
+
+func (t *fakeScaleUpTracker) MaxNodeProvisionTime(nodeGroup cloudprovider.NodeGroup) (time.Duration, error) {
+	if maxNodeProvisionTime, found := t.maxNodeProvisionTime[nodeGroup.Id()]; found {
+		return maxNodeProvisionTime, nil
+	}
+	return 15 * time.Minute, nil
+}
+
+type fakeScaleStateNotifier struct {
+	scaleUps       map[string]int
+	failedScaleUps []string
+}
+
+func (n *fakeScaleStateNotifier) RegisterScaleUp(nodeGroup cloudprovider.NodeGroup, delta int, _ time.Time) {
+	n.scaleUps[nodeGroup.Id()] += delta
+}
+
+func (n *fakeScaleStateNotifier) RegisterScaleDown(cloudprovider.NodeGroup, string, time.Time, time.Time) {
+}
+
+func (n *fakeScaleStateNotifier) RegisterFailedScaleUp(nodeGroup cloudprovider.NodeGroup, _ string, _ string, _, _ string, _ time.Time) {
+	n.failedScaleUps = append(n.failedScaleUps, nodeGroup.Id())
+}
+
+func (n *fakeScaleStateNotifier) RegisterFailedScaleDown(cloudprovider.NodeGroup, string, time.Time) {
+}
+
+type fakeActuationStatus struct {
+	drained []string
+}
+
+func (s *fakeActuationStatus) DeletionsInProgress() ([]string, []string) {
+	return nil, s.drained
+}
+
+func (s *fakeActuationStatus) DeletionsCount(string) int {
+	return len(s.drained)
+}
+
+func (s *fakeActuationStatus) RecentEvictions() []*apiv1.Pod {
+	return nil
+}
+
+type fakeActuator struct {
+	status  *fakeActuationStatus
+	deleted []string
+}
+
+func (a *fakeActuator) StartDeletion(empty, needDrain []*apiv1.Node) (status.ScaleDownResult, []*status.ScaleDownNode, errors.AutoscalerError) {
+	var nodes []*status.ScaleDownNode
+	for _, node := range append(empty, needDrain...) {
+		a.deleted = append(a.deleted, node.Name)
+		nodes = append(nodes, &status.ScaleDownNode{Node: node})
+	}
+	return status.ScaleDownNodeDeleteStarted, nodes, nil
+}
+
+func (a *fakeActuator) CheckStatus() scaledown.ActuationStatus {
+	return a.status
+}
+
+func (a *fakeActuator) ClearResultsNotNewerThan(time.Time) {}
+
+func (a *fakeActuator) DeletionResults() (map[string]status.NodeDeleteResult, time.Time) {
+	return nil, time.Time{}
+}
+
+type testSetup struct {
+	context   *context.AutoscalingContext
+	provider  *testprovider.TestCloudProvider
+	nodes     []*apiv1.Node
+	nodeInfos map[string]*schedulerframework.NodeInfo
+	tracker   *fakeScaleUpTracker
+	notifier  *fakeScaleStateNotifier
+	scaleUps  map[string]int
+	// fallbackPricing is passed to the consolidator as the fallback pricing model.
+	fallbackPricing cloudprovider.PricingModel
+}
+
+// newTestSetup builds a cluster with two large nodes in ng-large, each with a
+// single 1000m pod, and an empty ng-small of 2000m nodes. The large nodes cost
+// 1 per hour, the small ones smallPrice.
+func newTestSetup(t *testing.T, smallPrice float64, opts config.AutoscalingOptions) *testSetup {
+	s := &testSetup{
+		tracker:  &fakeScaleUpTracker{unhealthy: map[string]bool{}, scalingUp: map[string]bool{}, backedOff: map[string]bool{}, maxNodeProvisionTime: map[string]time.Duration{}},
+		notifier: &fakeScaleStateNotifier{scaleUps: map[string]int{}},
+		scaleUps: map[string]int{},
+	}
+	s.provider = testprovider.NewTestCloudProvider(func(id string, delta int) error {
+		s.scaleUps[id] += delta
+		return nil
+	}, nil)
+	s.provider.AddNodeGroup("ng-large", 0, 10, 2)
+	s.provider.AddNodeGroup("ng-small", 0, 10, 0)
+	s.provider.SetPricingModel(&fakePricingModel{prices: map[string]float64{
+		"n1": 1, "n2": 1, "large-template": 1, "small-template": smallPrice,
+	}})
+
+	n1 := BuildTestNode("n1", 4000, 8*1024*1024*1024)
+	n2 := BuildTestNode("n2", 4000, 8*1024*1024*1024)
+	SetNodeReadyState(n1, true, time.Time{})
+	SetNodeReadyState(n2, true, time.Time{})
+	s.provider.AddNode("ng-large", n1)
+	s.provider.AddNode("ng-large", n2)
+	s.nodes = []*apiv1.Node{n1, n2}
+	pods := []*apiv1.Pod{
+		SetRSPodSpec(BuildScheduledTestPod("p1", 1000, 1024, "n1"), "rs"),
+		SetRSPodSpec(BuildScheduledTestPod("p2", 1000, 1024, "n2"), "rs"),
+	}
+
+	largeTemplate := BuildTestNode("large-template", 4000, 8*1024*1024*1024)
+	SetNodeReadyState(largeTemplate, true, time.Time{})
+	smallTemplate := BuildTestNode("small-template", 2000, 4*1024*1024*1024)
+	SetNodeReadyState(smallTemplate, true, time.Time{})
+	s.nodeInfos = map[string]*schedulerframework.NodeInfo{
+		"ng-large": schedulerframework.NewNodeInfo(),
+		"ng-small": schedulerframework.NewNodeInfo(),
+	}
+	s.nodeInfos["ng-large"].SetNode(largeTemplate)
+	s.nodeInfos["ng-small"].SetNode(smallTemplate)
+
+	replicas := int32(2)
+	rsLister, err := kube_util.NewTestReplicaSetLister([]*appsv1.ReplicaSet{{
+		ObjectMeta: metav1.ObjectMeta{Name: "rs", Namespace: "default", UID: types.UID("rs")},
+		Spec:       appsv1.ReplicaSetSpec{Replicas: &replicas},
+	}})
+	assert.NoError(t, err)
+	registry := kube_util.NewListerRegistry(nil, nil, nil, nil, nil, nil, nil, rsLister, nil)
+	opts.NodeGroupDefaults.MaxNodeProvisionTime = 15 * time.Minute
+	autoscalingContext, err := NewScaleTestAutoscalingContext(opts, &fake.Clientset{}, registry, s.provider, nil, nil)
+	assert.NoError(t, err)
+	s.context = &autoscalingContext
+	clustersnapshot.InitializeClusterSnapshotOrDie(t, s.context.ClusterSnapshot, s.nodes, pods)
+	return s
+}
+
+func (s *testSetup) consolidator() *Consolidator {
+	estimatorBuilder, _ := estimator.NewEstimatorBuilder(
+		estimator.BinpackingEstimatorName,
+		estimator.NewThresholdBasedEstimationLimiter(nil),
+		estimator.NewDecreasingPodOrderer(),
+		nil,
+	)
+	return NewConsolidator(s.context, estimatorBuilder, options.NodeDeleteOptions{}, nil, s.notifier, s.tracker, nodegroupconfig.NewDefaultNodeGroupConfigProcessor(s.context.NodeGroupDefaults), nil, s.fallbackPricing)
+}
+
+func defaultOptions() config.AutoscalingOptions {
+	return config.AutoscalingOptions{
+		ConsolidationEnabled:              true,
+		ConsolidationUtilizationThreshold: 0.7,
+		ConsolidationMinSavings:           0.2,
+		ConsolidationMaxNodes:             5,
+	}
+}
+
+func nodeNames(nodes []*apiv1.Node) []string {
+	var names []string
+	for _, node := range nodes {
+		names = append(names, node.Name)
+	}
+	return names
+}
+
+func TestFindPlan(t *testing.T) {
+	now := time.Now()
+	testCases := []struct {
+		name             string
+		smallPrice       float64
+		options          func(*config.AutoscalingOptions)
+		minSizeSchedules string
+		backedOff        []string
+		wantRemoved      []string
+		wantNodes        int
+		wantPrice        float64
+	}{
+		{
+			name:        "two large nodes replaced with a small one",
+			smallPrice:  0.3,
+			wantRemoved: []string{"n1", "n2"},
+			wantNodes:   1,
+			wantPrice:   0.3,
+		},
+		{
+			name:       "not enough savings",
+			smallPrice: 1.7,
+		},
+		{
+			name:       "nodes above the utilization threshold",
+			smallPrice: 0.3,
+			options:    func(o *config.AutoscalingOptions) { o.ConsolidationUtilizationThreshold = 0.2 },
+		},
+		{
+			name:       "target node group backed off",
+			smallPrice: 0.3,
+			backedOff:  []string{"ng-small"},
+		},
+		{
+			name:             "scheduled min size keeps the nodes",
+			smallPrice:       0.3,
+			minSizeSchedules: "daily 00:00-00:00 min=2",
+		},
+		{
+			name:       "one node at a time fits on the other one",
+			smallPrice: 0.3,
+			options:    func(o *config.AutoscalingOptions) { o.ConsolidationMaxNodes = 1 },
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := defaultOptions()
+			if tc.options != nil {
+				tc.options(&opts)
+			}
+			if tc.minSizeSchedules != "" {
+				schedules, err := config.ParseMinSizeSchedules(tc.minSizeSchedules)
+				assert.NoError(t, err)
+				opts.NodeGroupDefaults.MinSizeSchedules = schedules
+			}
+			s := newTestSetup(t, tc.smallPrice, opts)
+			for _, id := range tc.backedOff {
+				s.tracker.backedOff[id] = true
+			}
+			plan := s.consolidator().FindPlan(s.nodes, s.nodeInfos, now)
+			if tc.wantRemoved == nil {
+				assert.Nil(t, plan)
+				return
+			}
+			if assert.NotNil(t, plan) {
+				assert.ElementsMatch(t, tc.wantRemoved, nodeNames(plan.NodesToRemove))
+				assert.Equal(t, "ng-small", plan.NodeGroup.Id())
+				assert.Equal(t, tc.wantNodes, plan.NewNodes)
+				assert.InDelta(t, 2.0, plan.CurrentPrice, 1e-9)
+				assert.InDelta(t, tc.wantPrice, plan.NewPrice, 1e-9)
+			}
+		})
+	}
+}
+
+func TestFindPlanFallbackPricing(t *testing.T) {
+	now := time.Now()
+	s := newTestSetup(t, 0.3, defaultOptions())
+	pricingModel, err := s.provider.Pricing()
+	assert.NoError(t, err)
+	s.provider.SetPricingModel(nil)
+	assert.Nil(t, s.consolidator().FindPlan(s.nodes, s.nodeInfos, now))
+
+	s.fallbackPricing = pricingModel
+	plan := s.consolidator().FindPlan(s.nodes, s.nodeInfos, now)
+	if assert.NotNil(t, plan) {
+		assert.ElementsMatch(t, []string{"n1", "n2"}, nodeNames(plan.NodesToRemove))
+		assert.Equal(t, "ng-small", plan.NodeGroup.Id())
+		assert.InDelta(t, 0.3, plan.NewPrice, 1e-9)
+	}
+}
+
+func TestRunOnce(t *testing.T) {
+	now := time.Now()
+	s := newTestSetup(t, 0.3, defaultOptions())
+	c := s.consolidator()
+	actuator := &fakeActuator{status: &fakeActuationStatus{}}
+
+	// The plan starts with a scale-up of the cheaper node group.
+	nodes, err := c.RunOnce(s.nodes, s.nodeInfos, actuator, now)
+	assert.NoError(t, err)
+	assert.Empty(t, nodes)
+	assert.Equal(t, map[string]int{"ng-small": 1}, s.scaleUps)
+	assert.Equal(t, map[string]int{"ng-small": 1}, s.notifier.scaleUps)
+	if assert.NotNil(t, c.InProgress()) {
+		assert.Equal(t, "ng-small", c.InProgress().NodeGroup.Id())
+	}
+	assert.Empty(t, c.FilterOutInProgress(s.nodes))
+
+	// Nothing is removed while the new node isn't ready.
+	s.tracker.scalingUp["ng-small"] = true
+	nodes, err = c.RunOnce(s.nodes, s.nodeInfos, actuator, now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Empty(t, nodes)
+	assert.Empty(t, actuator.deleted)
+	assert.NotNil(t, c.InProgress())
+
+	// Once the new node is there, the replaced nodes are deleted.
+	s.tracker.scalingUp["ng-small"] = false
+	newNode := BuildTestNode("new", 2000, 4*1024*1024*1024)
+	SetNodeReadyState(newNode, true, time.Time{})
+	s.provider.AddNode("ng-small", newNode)
+	assert.NoError(t, s.context.ClusterSnapshot.AddNode(newNode))
+	nodes, err = c.RunOnce(s.nodes, s.nodeInfos, actuator, now.Add(2*time.Minute))
+	assert.NoError(t, err)
+	assert.Len(t, nodes, 2)
+	assert.ElementsMatch(t, []string{"n1", "n2"}, actuator.deleted)
+	assert.Nil(t, c.InProgress())
+}
+
+func TestRunOnceAbandon(t *testing.T) {
+	now := time.Now()
+	testCases := []struct {
+		name                 string
+		scalingUp            bool
+		backedOff            bool
+		maxNodeProvisionTime time.Duration
+		finishTime           time.Duration
+		inProgress           bool
+	}{
+		{
+			name:       "scale-up failed",
+			backedOff:  true,
+			finishTime: time.Minute,
+		},
+		{
+			name:       "new nodes not ready in time",
+			scalingUp:  true,
+			finishTime: 20 * time.Minute,
+		},
+		{
+			name:                 "node group with longer max node provision time",
+			scalingUp:            true,
+			maxNodeProvisionTime: 30 * time.Minute,
+			finishTime:           20 * time.Minute,
+			inProgress:           true,
+		},
+		{
+			name:       "new nodes still provisioning",
+			scalingUp:  true,
+			finishTime: 5 * time.Minute,
+			inProgress: true,
+		},
+		{
+			name:       "new nodes missing",
+			finishTime: time.Minute,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newTestSetup(t, 0.3, defaultOptions())
+			c := s.consolidator()
+			actuator := &fakeActuator{status: &fakeActuationStatus{}}
+			_, err := c.RunOnce(s.nodes, s.nodeInfos, actuator, now)
+			assert.NoError(t, err)
+			assert.NotNil(t, c.InProgress())
+
+			s.tracker.scalingUp["ng-small"] = tc.scalingUp
+			s.tracker.backedOff["ng-small"] = tc.backedOff
+			if tc.maxNodeProvisionTime != 0 {
+				s.tracker.maxNodeProvisionTime["ng-small"] = tc.maxNodeProvisionTime
+			}
+			nodes, err := c.RunOnce(s.nodes, s.nodeInfos, actuator, now.Add(tc.finishTime))
+			assert.NoError(t, err)
+			assert.Empty(t, nodes)
+			assert.Empty(t, actuator.deleted)
+			assert.Equal(t, tc.inProgress, c.InProgress() != nil)
+		})
+	}
+}
+
+func TestRunOnceDeletionsInProgress(t *testing.T) {
+	s := newTestSetup(t, 0.3, defaultOptions())
+	c := s.consolidator()
+	actuator := &fakeActuator{status: &fakeActuationStatus{drained: []string{"n3"}}}
+	nodes, err := c.RunOnce(s.nodes, s.nodeInfos, actuator, time.Now())
+	assert.NoError(t, err)
+	assert.Empty(t, nodes)
+	assert.Nil(t, c.InProgress())
+	assert.Empty(t, s.scaleUps)
+}
+
+func TestRunOnceDryRun(t *testing.T) {
+	now := time.Now()
+	s := newTestSetup(t, 0.3, defaultOptions())
+	s.context.DryRunRecorder = dryrun.NewRecorder(s.context.LogRecorder)
+	c := s.consolidator()
+	actuator := &fakeActuator{status: &fakeActuationStatus{}}
+
+	// The scale-up is recorded once and the plan is in progress.
+	_, err := c.RunOnce(s.nodes, s.nodeInfos, actuator, now)
+	assert.NoError(t, err)
+	assert.Empty(t, s.scaleUps)
+	assert.Empty(t, s.notifier.scaleUps)
+	if assert.NotNil(t, c.InProgress()) {
+		assert.ElementsMatch(t, []string{"n1", "n2"}, nodeNames(c.InProgress().NodesToRemove))
+	}
+	assert.Empty(t, c.FilterOutInProgress(s.nodes))
+	actions := s.context.DryRunRecorder.Actions()
+	if assert.Len(t, actions, 1) {
+		assert.Equal(t, api.DryRunIncreaseSize, actions[0].Type)
+		assert.Equal(t, "ng-small", actions[0].NodeGroup)
+		assert.Equal(t, 1, actions[0].Delta)
+	}
+
+	// The deletion of the replaced nodes is recorded instead of started.
+	nodes, err := c.RunOnce(s.nodes, s.nodeInfos, actuator, now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Empty(t, nodes)
+	assert.Empty(t, actuator.deleted)
+	assert.Nil(t, c.InProgress())
+	actions = s.context.DryRunRecorder.Actions()
+	if assert.Len(t, actions, 2) {
+		assert.Equal(t, api.DryRunDeleteNodes, actions[1].Type)
+		assert.Equal(t, "ng-large", actions[1].NodeGroup)
+		assert.ElementsMatch(t, []string{"n1", "n2"}, actions[1].Nodes)
+		assert.ElementsMatch(t, []string{"default/p1", "default/p2"}, actions[1].EvictedPods)
+		assert.Equal(t, "Consolidation", actions[1].Reason)
+	}
+}
@@ -0,0 +1,455 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package consolidation
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown"
+	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown/eligibility"
+	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown/status"
+	"k8s.io/autoscaler/cluster-autoscaler/core/scaleup/equivalence"
+	"k8s.io/autoscaler/cluster-autoscaler/estimator"
+	"k8s.io/autoscaler/cluster-autoscaler/observers/nodegroupchange"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/maintenance"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/drainability/rules"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/options"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/scheduling"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/utilization"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/backoff"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/errors"
+	"k8s.io/klog/v2"
+	schedulerframework "k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// ScaleUpTracker tracks the scale-ups of node groups.
+type ScaleUpTracker interface {
+	// IsNodeGroupHealthy returns true if the node group doesn't have too many unready nodes.
+	IsNodeGroupHealthy(nodeGroupName string) bool
+	// IsNodeGroupScalingUp returns true if the node group is waiting for new nodes.
+	IsNodeGroupScalingUp(nodeGroupName string) bool
+	// BackoffStatusForNodeGroup returns the backoff status of the node group.
+	BackoffStatusForNodeGroup(nodeGroup cloudprovider.NodeGroup, now time.Time) backoff.Status
+	// MaxNodeProvisionTime returns the time the new nodes of the node group have to get ready in.
+	MaxNodeProvisionTime(nodeGroup cloudprovider.NodeGroup) (time.Duration, error)
+}
+
+// nodeGroupConfigGetter is an interface to limit the functions that can be used
+// from NodeGroupConfigProcessor interface
+type nodeGroupConfigGetter interface {
+	// GetMinSize returns the min size that should be used for a given NodeGroup at a given time.
+	GetMinSize(nodeGroup cloudprovider.NodeGroup, now time.Time) (int, error)
+}
+
+// Plan replaces nodes of one node group with new nodes of another one.
+type Plan struct {
+	// NodesToRemove are drained and deleted once the new nodes are ready.
+	NodesToRemove []*apiv1.Node
+	// NodeGroup is the node group the new nodes are added to.
+	NodeGroup cloudprovider.NodeGroup
+	// NewNodes is the number of nodes added to NodeGroup. It's 0 if the pods
+	// of NodesToRemove fit on the existing nodes.
+	NewNodes int
+	// CurrentPrice is the hourly price of NodesToRemove, if known.
+	CurrentPrice float64
+	// NewPrice is the hourly price of the new nodes, if known.
+	NewPrice float64
+}
+
+// String describes the plan.
+func (p *Plan) String() string {
+	names := make([]string, 0, len(p.NodesToRemove))
+	for _, node := range p.NodesToRemove {
+		names = append(names, node.Name)
+	}
+	if p.CurrentPrice == 0 && p.NewPrice == 0 {
+		return fmt.Sprintf("replace %d nodes [%s] with %d nodes of %s",
+			len(p.NodesToRemove), strings.Join(names, ", "), p.NewNodes, p.NodeGroup.Id())
+	}
+	return fmt.Sprintf("replace %d nodes [%s] costing %.3f per hour with %d nodes of %s costing %.3f per hour",
+		len(p.NodesToRemove), strings.Join(names, ", "), p.CurrentPrice, p.NewNodes, p.NodeGroup.Id(), p.NewPrice)
+}
+
+// executor executes one plan at a time: it scales up the node group of the
+// plan, waits for the new nodes and starts the deletion of the replaced nodes.
+// Its name prefixes logs and the reasons of events.
+type executor struct {
+	name               string
+	context            *context.AutoscalingContext
+	estimatorBuilder   estimator.EstimatorBuilder
+	deleteOptions      options.NodeDeleteOptions
+	drainabilityRules  rules.Rules
+	scaleStateNotifier nodegroupchange.NodeGroupChangeObserver
+	scaleUpTracker     ScaleUpTracker
+	configGetter       nodeGroupConfigGetter
+	maintenanceWindows *maintenance.Tracker
+	schedulingSim      *scheduling.HintingSimulator
+
+	inProgress *Plan
+	startTime  time.Time
+}
+
+func newExecutor(
+	name string,
+	context *context.AutoscalingContext,
+	estimatorBuilder estimator.EstimatorBuilder,
+	deleteOptions options.NodeDeleteOptions,
+	drainabilityRules rules.Rules,
+	scaleStateNotifier nodegroupchange.NodeGroupChangeObserver,
+	scaleUpTracker ScaleUpTracker,
+	configGetter nodeGroupConfigGetter,
+	maintenanceWindows *maintenance.Tracker,
+) *executor {
+	return &executor{
+		name:               name,
+		context:            context,
+		estimatorBuilder:   estimatorBuilder,
+		deleteOptions:      deleteOptions,
+		drainabilityRules:  drainabilityRules,
+		scaleStateNotifier: scaleStateNotifier,
+		scaleUpTracker:     scaleUpTracker,
+		configGetter:       configGetter,
+		maintenanceWindows: maintenanceWindows,
+		schedulingSim:      scheduling.NewHintingSimulator(context.PredicateChecker),
+	}
+}
+
+// InProgress returns the plan waiting for its new nodes, or nil.
+func (e *executor) InProgress() *Plan {
+	return e.inProgress
+}
+
+// FilterOutInProgress removes the nodes taking part in the plan in progress
+// from the scale-down candidates, so that the new nodes aren't removed as
+// empty before they take over the pods of the replaced nodes.
+func (e *executor) FilterOutInProgress(nodes []*apiv1.Node) []*apiv1.Node {
+	if e.inProgress == nil {
+		return nodes
+	}
+	toRemove := make(map[string]bool, len(e.inProgress.NodesToRemove))
+	for _, node := range e.inProgress.NodesToRemove {
+		toRemove[node.Name] = true
+	}
+	var result []*apiv1.Node
+	for _, node := range nodes {
+		if toRemove[node.Name] {
+			continue
+		}
+		if nodeGroup, err := e.context.CloudProvider.NodeGroupForNode(node); err == nil && !isNil(nodeGroup) && nodeGroup.Id() == e.inProgress.NodeGroup.Id() {
+			continue
+		}
+		result = append(result, node)
+	}
+	return result
+}
+
+// runOnce finishes the plan in progress, or starts the plan returned by
+// findPlan if there is no plan in progress and no node deletion in progress.
+func (e *executor) runOnce(findPlan func(scaledown.ActuationStatus) *Plan, actuator scaledown.Actuator, now time.Time) ([]*status.ScaleDownNode, errors.AutoscalerError) {
+	if e.inProgress != nil {
+		return e.finish(actuator, now)
+	}
+	actuationStatus := actuator.CheckStatus()
+	if empty, drained := actuationStatus.DeletionsInProgress(); len(empty)+len(drained) > 0 {
+		klog.V(4).Infof("%s: skipped, %d node deletions in progress", e.name, len(empty)+len(drained))
+		return nil, nil
+	}
+	plan := findPlan(actuationStatus)
+	if plan == nil {
+		return nil, nil
+	}
+	return nil, e.start(plan, now)
+}
+
+// canScaleUp returns true if new nodes can be added to the node group.
+func (e *executor) canScaleUp(nodeGroup cloudprovider.NodeGroup, now time.Time) bool {
+	if !nodeGroup.Exist() || e.maintenanceWindows.ScaleUpFrozen(nodeGroup.Id()) {
+		return false
+	}
+	targetSize, err := nodeGroup.TargetSize()
+	if err != nil || targetSize >= nodeGroup.MaxSize() {
+		return false
+	}
+	return e.scaleUpTracker.IsNodeGroupHealthy(nodeGroup.Id()) && !e.scaleUpTracker.BackoffStatusForNodeGroup(nodeGroup, now).IsBackedOff
+}
+
+func (e *executor) start(plan *Plan, now time.Time) errors.AutoscalerError {
+	klog.V(0).Infof("%s: %s", e.name, plan)
+	if e.context.DryRunRecorder != nil {
+		if plan.NewNodes > 0 {
+			e.context.DryRunRecorder.RecordIncreaseSize(plan.NodeGroup.Id(), plan.NewNodes)
+		}
+	} else if plan.NewNodes > 0 {
+		if err := plan.NodeGroup.IncreaseSize(plan.NewNodes); err != nil {
+			e.context.LogRecorder.Eventf(apiv1.EventTypeWarning, "FailedToScaleUpGroup", "%s scale-up failed for group %s: %v", e.name, plan.NodeGroup.Id(), err)
+			aErr := errors.ToAutoscalerError(errors.CloudProviderError, err).AddPrefix("failed to increase node group size: ")
+			e.scaleStateNotifier.RegisterFailedScaleUp(plan.NodeGroup, string(aErr.Type()), aErr.Error(), "", "", now)
+			return aErr
+		}
+		e.scaleStateNotifier.RegisterScaleUp(plan.NodeGroup, plan.NewNodes, now)
+	}
+	e.context.LogRecorder.Eventf(apiv1.EventTypeNormal, e.name+"Started", "%s: %s", e.name, plan)
+	e.inProgress = plan
+	e.startTime = now
+	return nil
+}
+
+func (e *executor) finish(actuator scaledown.Actuator, now time.Time) ([]*status.ScaleDownNode, errors.AutoscalerError) {
+	plan := e.inProgress
+	dryRun := e.context.DryRunRecorder != nil
+	// In dry-run mode no new nodes are added, so there is nothing to wait for.
+	if plan.NewNodes > 0 && !dryRun {
+		if e.scaleUpTracker.BackoffStatusForNodeGroup(plan.NodeGroup, now).IsBackedOff {
+			e.abandon("scale-up of %s failed", plan.NodeGroup.Id())
+			return nil, nil
+		}
+		if e.scaleUpTracker.IsNodeGroupScalingUp(plan.NodeGroup.Id()) {
+			maxNodeProvisionTime, err := e.scaleUpTracker.MaxNodeProvisionTime(plan.NodeGroup)
+			if err != nil {
+				klog.Warningf("%s: failed to get max node provision time of %s, using the default: %v", e.name, plan.NodeGroup.Id(), err)
+				maxNodeProvisionTime = e.context.NodeGroupDefaults.MaxNodeProvisionTime
+			}
+			if now.Sub(e.startTime) > maxNodeProvisionTime {
+				e.abandon("new nodes of %s not ready after %v", plan.NodeGroup.Id(), now.Sub(e.startTime))
+			} else {
+				klog.V(2).Infof("%s: waiting for %d new nodes of %s", e.name, plan.NewNodes, plan.NodeGroup.Id())
+			}
+			return nil, nil
+		}
+	}
+
+	// Make sure the pods still fit elsewhere now that the new nodes are there.
+	var nodesToRemove []*apiv1.Node
+	var pods []*apiv1.Pod
+	for _, node := range plan.NodesToRemove {
+		nodeInfo, err := e.context.ClusterSnapshot.NodeInfos().Get(node.Name)
+		if err != nil {
+			continue
+		}
+		podsToMove, _, blockingPod, err := simulator.GetPodsToMove(nodeInfo, e.deleteOptions, e.drainabilityRules, e.context.ListerRegistry, e.context.RemainingPdbTracker, now)
+		if err != nil || blockingPod != nil {
+			e.abandon("pods of %s can't be moved anymore", node.Name)
+			return nil, nil
+		}
+		nodesToRemove = append(nodesToRemove, nodeInfo.Node())
+		pods = append(pods, podsToMove...)
+	}
+	if !dryRun && len(e.unschedulablePods(nodesToRemove, pods)) > 0 {
+		e.abandon("pods of the replaced nodes don't fit on the remaining nodes")
+		return nil, nil
+	}
+
+	e.inProgress = nil
+	if len(nodesToRemove) == 0 {
+		return nil, nil
+	}
+	klog.V(0).Infof("%s: new nodes of %s ready, removing %d nodes", e.name, plan.NodeGroup.Id(), len(nodesToRemove))
+	e.context.LogRecorder.Eventf(apiv1.EventTypeNormal, e.name+"Finished", "%s: new nodes of %s ready, removing %d nodes", e.name, plan.NodeGroup.Id(), len(nodesToRemove))
+	if dryRun {
+		e.recordDeletions(nodesToRemove, pods)
+		return nil, nil
+	}
+	_, scaledDownNodes, err := actuator.StartDeletion(nil, nodesToRemove)
+	return scaledDownNodes, err
+}
+
+// recordDeletions records the deletion of the nodes, and the eviction of their
+// pods, per node group in dry-run mode. The name of the executor is the reason.
+func (e *executor) recordDeletions(nodes []*apiv1.Node, pods []*apiv1.Pod) {
+	nodesByGroup := map[string][]*apiv1.Node{}
+	groupOfNode := map[string]string{}
+	for _, node := range nodes {
+		nodeGroup, err := e.context.CloudProvider.NodeGroupForNode(node)
+		if err != nil || isNil(nodeGroup) {
+			klog.Errorf("%s: no node group of %s: %v", e.name, node.Name, err)
+			continue
+		}
+		nodesByGroup[nodeGroup.Id()] = append(nodesByGroup[nodeGroup.Id()], node)
+		groupOfNode[node.Name] = nodeGroup.Id()
+	}
+	podsByGroup := map[string][]*apiv1.Pod{}
+	for _, pod := range pods {
+		if id, found := groupOfNode[pod.Spec.NodeName]; found {
+			podsByGroup[id] = append(podsByGroup[id], pod)
+		}
+	}
+	ids := make([]string, 0, len(nodesByGroup))
+	for id := range nodesByGroup {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		e.context.DryRunRecorder.RecordDeleteNodes(id, nodesByGroup[id], podsByGroup[id], e.name)
+	}
+}
+
+// abandon gives up the plan in progress. Its new nodes, if any, are removed by
+// the regular scale-down once they are unneeded.
+func (e *executor) abandon(reason string, args ...interface{}) {
+	message := fmt.Sprintf(reason, args...)
+	klog.Warningf("%s: abandoned plan to %s: %s", e.name, e.inProgress, message)
+	e.context.LogRecorder.Eventf(apiv1.EventTypeWarning, e.name+"Abandoned", "%s abandoned: %s", e.name, message)
+	e.inProgress = nil
+}
+
+// unschedulablePods returns the pods which don't fit on the nodes remaining
+// after removing nodesToRemove.
+func (e *executor) unschedulablePods(nodesToRemove []*apiv1.Node, pods []*apiv1.Pod) []*apiv1.Pod {
+	e.context.ClusterSnapshot.Fork()
+	defer e.context.ClusterSnapshot.Revert()
+	return e.unschedulablePodsInFork(nodesToRemove, pods, func(*schedulerframework.NodeInfo) bool { return true })
+}
+
+// unschedulablePodsInFork removes nodesToRemove from the forked snapshot and
+// schedules as many of the pods as possible on the remaining nodes accepted by
+// isDestination.
+func (e *executor) unschedulablePodsInFork(nodesToRemove []*apiv1.Node, pods []*apiv1.Pod, isDestination func(*schedulerframework.NodeInfo) bool) []*apiv1.Pod {
+	for _, node := range nodesToRemove {
+		if err := e.context.ClusterSnapshot.RemoveNode(node.Name); err != nil {
+			klog.Errorf("%s: failed to simulate removal of %s: %v", e.name, node.Name, err)
+		}
+	}
+	newPods := make([]*apiv1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		newPod := *pod
+		newPod.Spec.NodeName = ""
+		newPods = append(newPods, &newPod)
+	}
+	statuses, _, err := e.schedulingSim.TrySchedulePods(e.context.ClusterSnapshot, newPods, isDestination, false)
+	if err != nil {
+		klog.Errorf("%s: failed to simulate scheduling: %v", e.name, err)
+		return newPods
+	}
+	scheduled := make(map[*apiv1.Pod]bool, len(statuses))
+	for _, s := range statuses {
+		scheduled[s.Pod] = true
+	}
+	var remaining []*apiv1.Pod
+	for _, pod := range newPods {
+		if !scheduled[pod] {
+			remaining = append(remaining, pod)
+		}
+	}
+	return remaining
+}
+
+// candidate is a node which can be replaced.
+type candidate struct {
+	node        *apiv1.Node
+	nodeGroup   cloudprovider.NodeGroup
+	pods        []*apiv1.Pod
+	utilization float64
+	price       float64
+}
+
+// candidate returns the node as a candidate, or nil if the node can't be
+// replaced: it has the scale-down disabled annotation, doesn't belong to a
+// node group, or some of its pods can't be moved.
+func (e *executor) candidate(node *apiv1.Node, now time.Time) *candidate {
+	if eligibility.HasNoScaleDownAnnotation(node) {
+		return nil
+	}
+	nodeGroup, err := e.context.CloudProvider.NodeGroupForNode(node)
+	if err != nil || isNil(nodeGroup) {
+		return nil
+	}
+	nodeInfo, err := e.context.ClusterSnapshot.NodeInfos().Get(node.Name)
+	if err != nil {
+		return nil
+	}
+	utilInfo, err := utilization.Calculate(nodeInfo, e.context.NodeGroupDefaults.IgnoreDaemonSetsUtilization, e.context.IgnoreMirrorPodsUtilization, e.context.CloudProvider.GetNodeGpuConfig(node), now)
+	if err != nil {
+		return nil
+	}
+	pods, _, blockingPod, err := simulator.GetPodsToMove(nodeInfo, e.deleteOptions, e.drainabilityRules, e.context.ListerRegistry, e.context.RemainingPdbTracker, now)
+	if err != nil || blockingPod != nil {
+		return nil
+	}
+	return &candidate{node: node, nodeGroup: nodeGroup, pods: pods, utilization: utilInfo.Utilization}
+}
+
+// limitCandidates returns the least utilized candidates, at most maxNodes if
+// it's positive, which can be removed without going below the min size of the
+// node group at the given time.
+func (e *executor) limitCandidates(nodeGroup cloudprovider.NodeGroup, candidates []*candidate, maxNodes int, now time.Time) []*candidate {
+	targetSize, err := nodeGroup.TargetSize()
+	if err != nil {
+		return nil
+	}
+	minSize, err := e.configGetter.GetMinSize(nodeGroup, now)
+	if err != nil {
+		klog.Errorf("%s: failed to get min size of %s: %v", e.name, nodeGroup.Id(), err)
+		return nil
+	}
+	limit := targetSize - minSize
+	if maxNodes > 0 && limit > maxNodes {
+		limit = maxNodes
+	}
+	if limit <= 0 {
+		return nil
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].utilization < candidates[j].utilization })
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	return candidates
+}
+
+// estimateInFork returns the number of nodes of the node group needed by the
+// pods in the forked snapshot, and whether all of them fit without exceeding
+// the max size of the node group.
+func (e *executor) estimateInFork(pods []*apiv1.Pod, nodeGroup cloudprovider.NodeGroup, nodeInfo *schedulerframework.NodeInfo) (int, bool) {
+	targetSize, err := nodeGroup.TargetSize()
+	if err != nil {
+		return 0, false
+	}
+	snapshotNodes, err := e.context.ClusterSnapshot.NodeInfos().List()
+	if err != nil {
+		return 0, false
+	}
+	var podGroups []estimator.PodEquivalenceGroup
+	for _, group := range equivalence.BuildPodGroups(pods) {
+		podGroups = append(podGroups, estimator.PodEquivalenceGroup{Pods: group.Pods})
+	}
+	estimationContext := estimator.NewEstimationContext(e.context.MaxNodesTotal, nil, len(snapshotNodes), nil)
+	newNodes, scheduledPods := e.estimatorBuilder(e.context.PredicateChecker, e.context.ClusterSnapshot, estimationContext).Estimate(podGroups, nodeInfo, nodeGroup)
+	if newNodes == 0 || len(scheduledPods) < len(pods) || targetSize+newNodes > nodeGroup.MaxSize() {
+		return 0, false
+	}
+	return newNodes, true
+}
+
+func sortedKeys(m map[string][]*candidate) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func isNil(nodeGroup cloudprovider.NodeGroup) bool {
+	return nodeGroup == nil || reflect.ValueOf(nodeGroup).IsNil()
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package consolidation
+
+import (
+	"sort"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown"
+	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown/budgets"
+	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown/status"
+	"k8s.io/autoscaler/cluster-autoscaler/estimator"
+	"k8s.io/autoscaler/cluster-autoscaler/expander/priority"
+	"k8s.io/autoscaler/cluster-autoscaler/observers/nodegroupchange"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/maintenance"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroupconfig"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/drainability/rules"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/options"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/errors"
+	v1lister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+	schedulerframework "k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// Rebalancer moves capacity from fallback node groups, which the priority
+// expander scaled up because the preferred ones couldn't be, back to the
+// preferred node groups once they are healthy again. Every RebalanceInterval,
+// a few nodes of the lowest priority node group are replaced with nodes of the
+// highest priority node group their pods fit on. Nodes whose pods can't be
+// moved without violating PodDisruptionBudgets are left alone, and the number
+// of replaced nodes is limited by the scale-down budgets.
+type Rebalancer struct {
+	*executor
+	configMapLister v1lister.ConfigMapNamespaceLister
+	budgetProcessor *budgets.ScaleDownBudgetProcessor
+	lastRun         time.Time
+}
+
+// NewRebalancer returns a new Rebalancer reading node group priorities from
+// the priority expander ConfigMap. The budget processor should be the one of
+// the actuator deleting the replaced nodes.
+func NewRebalancer(
+	context *context.AutoscalingContext,
+	estimatorBuilder estimator.EstimatorBuilder,
+	deleteOptions options.NodeDeleteOptions,
+	drainabilityRules rules.Rules,
+	configMapLister v1lister.ConfigMapNamespaceLister,
+	scaleStateNotifier nodegroupchange.NodeGroupChangeObserver,
+	scaleUpTracker ScaleUpTracker,
+	nodeGroupConfigProcessor nodegroupconfig.NodeGroupConfigProcessor,
+	budgetProcessor *budgets.ScaleDownBudgetProcessor,
+	maintenanceWindows *maintenance.Tracker,
+) *Rebalancer {
+	return &Rebalancer{
+		executor:        newExecutor("Rebalancing", context, estimatorBuilder, deleteOptions, drainabilityRules, scaleStateNotifier, scaleUpTracker, nodeGroupConfigProcessor, maintenanceWindows),
+		configMapLister: configMapLister,
+		budgetProcessor: budgetProcessor,
+	}
+}
+
+// RunOnce finishes the plan in progress once its new nodes are ready, by
+// starting the deletion of the replaced nodes with the actuator, or starts a
+// new plan if there is none and RebalanceInterval passed since the last one
+// was looked for. It returns the nodes whose deletion started.
+func (r *Rebalancer) RunOnce(candidates []*apiv1.Node, nodeInfos map[string]*schedulerframework.NodeInfo, actuator scaledown.Actuator, now time.Time) ([]*status.ScaleDownNode, errors.AutoscalerError) {
+	if r.inProgress == nil && now.Sub(r.lastRun) < r.context.RebalanceInterval {
+		return nil, nil
+	}
+	return r.runOnce(func(actuationStatus scaledown.ActuationStatus) *Plan {
+		r.lastRun = now
+		plan := r.FindPlan(candidates, nodeInfos, actuationStatus, now)
+		if plan == nil {
+			klog.V(4).Info("Rebalancing: no nodes to move to preferred node groups")
+		}
+		return plan
+	}, actuator, now)
+}
+
+// FindPlan returns a plan replacing up to RebalanceMaxNodes nodes of the
+// lowest priority node group which has nodes whose pods can be moved to node
+// groups of a higher priority, or nil if there is none.
+func (r *Rebalancer) FindPlan(candidates []*apiv1.Node, nodeInfos map[string]*schedulerframework.NodeInfo, actuationStatus scaledown.ActuationStatus, now time.Time) *Plan {
+	nodeGroups := r.context.CloudProvider.NodeGroups()
+	ids := make([]string, 0, len(nodeGroups))
+	for _, nodeGroup := range nodeGroups {
+		ids = append(ids, nodeGroup.Id())
+	}
+	priorities, err := priority.NodeGroupPriorities(r.configMapLister, ids)
+	if err != nil {
+		klog.V(4).Infof("Rebalancing: priorities not available: %v", err)
+		return nil
+	}
+
+	// Node groups which can get new nodes, highest priority first.
+	var preferred []cloudprovider.NodeGroup
+	for _, nodeGroup := range nodeGroups {
+		if _, found := priorities[nodeGroup.Id()]; !found {
+			continue
+		}
+		if _, found := nodeInfos[nodeGroup.Id()]; !found {
+			continue
+		}
+		if r.canScaleUp(nodeGroup, now) && !r.scaleUpTracker.IsNodeGroupScalingUp(nodeGroup.Id()) {
+			preferred = append(preferred, nodeGroup)
+		}
+	}
+	if len(preferred) == 0 {
+		return nil
+	}
+	sort.SliceStable(preferred, func(i, j int) bool { return priorities[preferred[i].Id()] > priorities[preferred[j].Id()] })
+	maxPriority := priorities[preferred[0].Id()]
+
+	sourceGroups := map[string]cloudprovider.NodeGroup{}
+	bySource := map[string][]*candidate{}
+	for _, node := range candidates {
+		cand := r.candidate(node, now)
+		if cand == nil {
+			continue
+		}
+		if prio, found := priorities[cand.nodeGroup.Id()]; !found || prio >= maxPriority {
+			continue
+		}
+		sourceGroups[cand.nodeGroup.Id()] = cand.nodeGroup
+		bySource[cand.nodeGroup.Id()] = append(bySource[cand.nodeGroup.Id()], cand)
+	}
+
+	// Lowest priority node groups are moved first.
+	sourceIds := sortedKeys(bySource)
+	sort.SliceStable(sourceIds, func(i, j int) bool { return priorities[sourceIds[i]] < priorities[sourceIds[j]] })
+	for _, id := range sourceIds {
+		sourceCandidates := r.cropToBudget(actuationStatus, r.limitCandidates(sourceGroups[id], bySource[id], r.context.RebalanceMaxNodes, now))
+		if len(sourceCandidates) == 0 {
+			continue
+		}
+		if plan := r.planFor(priorities, priorities[id], sourceCandidates, preferred, nodeInfos); plan != nil {
+			return plan
+		}
+	}
+	return nil
+}
+
+// cropToBudget returns the candidates which can be drained within the
+// scale-down parallelism budgets.
+func (r *Rebalancer) cropToBudget(actuationStatus scaledown.ActuationStatus, candidates []*candidate) []*candidate {
+	nodes := make([]*apiv1.Node, 0, len(candidates))
+	for _, cand := range candidates {
+		nodes = append(nodes, cand.node)
+	}
+	_, drain := r.budgetProcessor.CropNodes(actuationStatus, nil, nodes)
+	allowed := map[string]bool{}
+	for _, view := range drain {
+		for _, node := range view.Nodes {
+			allowed[node.Name] = true
+		}
+	}
+	var result []*candidate
+	for _, cand := range candidates {
+		if allowed[cand.node.Name] {
+			result = append(result, cand)
+		}
+	}
+	return result
+}
+
+func (r *Rebalancer) planFor(priorities map[string]int, sourcePriority int, candidates []*candidate, preferred []cloudprovider.NodeGroup, nodeInfos map[string]*schedulerframework.NodeInfo) *Plan {
+	var nodesToRemove []*apiv1.Node
+	var pods []*apiv1.Pod
+	for _, cand := range candidates {
+		nodesToRemove = append(nodesToRemove, cand.node)
+		pods = append(pods, cand.pods...)
+	}
+
+	r.context.ClusterSnapshot.Fork()
+	defer r.context.ClusterSnapshot.Revert()
+	// Pods are only moved to existing nodes of node groups with a higher
+	// priority, so that they don't have to be moved again.
+	remainingPods := r.unschedulablePodsInFork(nodesToRemove, pods, func(nodeInfo *schedulerframework.NodeInfo) bool {
+		nodeGroup, err := r.context.CloudProvider.NodeGroupForNode(nodeInfo.Node())
+		if err != nil || isNil(nodeGroup) {
+			return false
+		}
+		prio, found := priorities[nodeGroup.Id()]
+		return found && prio > sourcePriority
+	})
+	if len(remainingPods) == 0 {
+		return &Plan{NodesToRemove: nodesToRemove, NodeGroup: preferred[0]}
+	}
+
+	for _, nodeGroup := range preferred {
+		if priorities[nodeGroup.Id()] <= sourcePriority {
+			break
+		}
+		if newNodes, fits := r.estimateInFork(remainingPods, nodeGroup, nodeInfos[nodeGroup.Id()]); fits {
+			return &Plan{NodesToRemove: nodesToRemove, NodeGroup: nodeGroup, NewNodes: newNodes}
+		}
+	}
+	return nil
+}
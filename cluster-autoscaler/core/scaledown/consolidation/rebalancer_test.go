@@ -0,0 +1,210 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package consolidation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown/budgets"
+	"k8s.io/autoscaler/cluster-autoscaler/estimator"
+	"k8s.io/autoscaler/cluster-autoscaler/expander/priority"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroupconfig"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/options"
+	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+)
+
+const (
+	// ng-small is preferred, ng-large is the fallback.
+	testPriorities = `
+10:
+  - ng-large
+50:
+  - ng-small
+`
+)
+
+func rebalanceOptions() config.AutoscalingOptions {
+	return config.AutoscalingOptions{
+		RebalanceEnabled:        true,
+		RebalanceInterval:       5 * time.Minute,
+		RebalanceMaxNodes:       1,
+		MaxScaleDownParallelism: 10,
+		MaxDrainParallelism:     10,
+	}
+}
+
+func (s *testSetup) rebalancer(t *testing.T, priorities string) *Rebalancer {
+	var configMaps []*apiv1.ConfigMap
+	if priorities != "" {
+		configMaps = append(configMaps, &apiv1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: priority.PriorityConfigMapName},
+			Data:       map[string]string{priority.ConfigMapKey: priorities},
+		})
+	}
+	lister, err := kube_util.NewTestConfigMapLister(configMaps)
+	assert.NoError(t, err)
+	estimatorBuilder, _ := estimator.NewEstimatorBuilder(
+		estimator.BinpackingEstimatorName,
+		estimator.NewThresholdBasedEstimationLimiter(nil),
+		estimator.NewDecreasingPodOrderer(),
+		nil,
+	)
+	configProcessor := nodegroupconfig.NewDefaultNodeGroupConfigProcessor(s.context.NodeGroupDefaults)
+	return NewRebalancer(s.context, estimatorBuilder, options.NodeDeleteOptions{}, nil, lister.ConfigMaps("kube-system"), s.notifier, s.tracker, configProcessor, budgets.NewScaleDownBudgetProcessor(s.context, configProcessor), nil)
+}
+
+func TestRebalancerFindPlan(t *testing.T) {
+	now := time.Now()
+	testCases := []struct {
+		name            string
+		priorities      string
+		options         func(*config.AutoscalingOptions)
+		setup           func(*testSetup)
+		drainInProgress []string
+		wantRemoved     []string
+		wantNewNodes    int
+	}{
+		{
+			name:         "one fallback node moved to the preferred node group",
+			priorities:   testPriorities,
+			wantRemoved:  []string{"n1"},
+			wantNewNodes: 1,
+		},
+		{
+			name:         "more nodes moved at once",
+			priorities:   testPriorities,
+			options:      func(o *config.AutoscalingOptions) { o.RebalanceMaxNodes = 2 },
+			wantRemoved:  []string{"n1", "n2"},
+			wantNewNodes: 1,
+		},
+		{
+			name:       "pods fit on existing nodes of the preferred node group",
+			priorities: testPriorities,
+			setup: func(s *testSetup) {
+				node := BuildTestNode("small", 2000, 4*1024*1024*1024)
+				SetNodeReadyState(node, true, time.Time{})
+				s.provider.AddNode("ng-small", node)
+				s.provider.GetNodeGroup("ng-small").(*testprovider.TestNodeGroup).SetTargetSize(1)
+				assert.NoError(t, s.context.ClusterSnapshot.AddNode(node))
+			},
+			wantRemoved: []string{"n1"},
+		},
+		{
+			name:       "no priorities",
+			priorities: "",
+		},
+		{
+			name: "fallback node group is preferred",
+			priorities: `
+50:
+  - ng-large
+10:
+  - ng-small
+`,
+		},
+		{
+			name:       "preferred node group backed off",
+			priorities: testPriorities,
+			setup:      func(s *testSetup) { s.tracker.backedOff["ng-small"] = true },
+		},
+		{
+			name:       "preferred node group unhealthy",
+			priorities: testPriorities,
+			setup:      func(s *testSetup) { s.tracker.unhealthy["ng-small"] = true },
+		},
+		{
+			name:       "preferred node group still scaling up",
+			priorities: testPriorities,
+			setup:      func(s *testSetup) { s.tracker.scalingUp["ng-small"] = true },
+		},
+		{
+			name:            "drain budget exhausted",
+			priorities:      testPriorities,
+			options:         func(o *config.AutoscalingOptions) { o.MaxDrainParallelism = 1 },
+			drainInProgress: []string{"n3"},
+		},
+		{
+			name:       "fallback node group at min size",
+			priorities: testPriorities,
+			setup: func(s *testSetup) {
+				s.provider.AddNodeGroup("ng-large", 2, 10, 2)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := rebalanceOptions()
+			if tc.options != nil {
+				tc.options(&opts)
+			}
+			s := newTestSetup(t, 0.3, opts)
+			if tc.setup != nil {
+				tc.setup(s)
+			}
+			r := s.rebalancer(t, tc.priorities)
+			plan := r.FindPlan(s.nodes, s.nodeInfos, &fakeActuationStatus{drained: tc.drainInProgress}, now)
+			if tc.wantRemoved == nil {
+				assert.Nil(t, plan)
+				return
+			}
+			if assert.NotNil(t, plan) {
+				assert.Equal(t, tc.wantRemoved, nodeNames(plan.NodesToRemove))
+				assert.Equal(t, "ng-small", plan.NodeGroup.Id())
+				assert.Equal(t, tc.wantNewNodes, plan.NewNodes)
+			}
+		})
+	}
+}
+
+func TestRebalancerRunOnce(t *testing.T) {
+	now := time.Now()
+	s := newTestSetup(t, 0.3, rebalanceOptions())
+	r := s.rebalancer(t, testPriorities)
+	actuator := &fakeActuator{status: &fakeActuationStatus{}}
+
+	nodes, err := r.RunOnce(s.nodes, s.nodeInfos, actuator, now)
+	assert.NoError(t, err)
+	assert.Empty(t, nodes)
+	assert.Equal(t, map[string]int{"ng-small": 1}, s.scaleUps)
+	assert.NotNil(t, r.InProgress())
+
+	// The new node is ready, the fallback node is deleted.
+	newNode := BuildTestNode("new", 2000, 4*1024*1024*1024)
+	SetNodeReadyState(newNode, true, time.Time{})
+	s.provider.AddNode("ng-small", newNode)
+	assert.NoError(t, s.context.ClusterSnapshot.AddNode(newNode))
+	nodes, err = r.RunOnce(s.nodes, s.nodeInfos, actuator, now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Len(t, nodes, 1)
+	assert.Equal(t, []string{"n1"}, actuator.deleted)
+	assert.Nil(t, r.InProgress())
+
+	// The next node is only moved after the rebalance interval.
+	_, err = r.RunOnce(s.nodes[1:], s.nodeInfos, actuator, now.Add(2*time.Minute))
+	assert.NoError(t, err)
+	assert.Nil(t, r.InProgress())
+	_, err = r.RunOnce(s.nodes[1:], s.nodeInfos, actuator, now.Add(6*time.Minute))
+	assert.NoError(t, err)
+	assert.NotNil(t, r.InProgress())
+}
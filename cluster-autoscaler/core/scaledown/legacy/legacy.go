@@ -395,8 +395,13 @@ func (sd *ScaleDown) getEmptyNodesToRemove(candidates []string, resourcesLimits
 				klog.Errorf("Failed to get size for %s: %v ", nodeGroup.Id(), err)
 				continue
 			}
+			minSize, err := sd.processors.NodeGroupConfigProcessor.GetMinSize(nodeGroup, timestamp)
+			if err != nil {
+				klog.Errorf("Failed to get min size for %s: %v ", nodeGroup.Id(), err)
+				continue
+			}
 			deletionsInProgress := sd.nodeDeletionTracker.DeletionsCount(nodeGroup.Id())
-			available = size - nodeGroup.MinSize() - deletionsInProgress
+			available = size - minSize - deletionsInProgress
 			if available < 0 {
 				available = 0
 			}
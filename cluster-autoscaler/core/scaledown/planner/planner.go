@@ -23,7 +23,6 @@ import (
 
 	apiv1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
 	"k8s.io/autoscaler/cluster-autoscaler/context"
 	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown"
 	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown/eligibility"
@@ -32,6 +31,7 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown/unneeded"
 	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown/unremovable"
 	"k8s.io/autoscaler/cluster-autoscaler/processors"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroupconfig"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/nodes"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot"
@@ -77,6 +77,7 @@ type Planner struct {
 	resourceLimitsFinder  *resource.LimitsFinder
 	cc                    controllerReplicasCalculator
 	scaleDownSetProcessor nodes.ScaleDownSetProcessor
+	configGetter          nodegroupconfig.NodeGroupConfigProcessor
 }
 
 // New creates a new Planner object.
@@ -97,6 +98,7 @@ func New(context *context.AutoscalingContext, processors *processors.Autoscaling
 		resourceLimitsFinder:  resourceLimitsFinder,
 		cc:                    newControllerReplicasCalculator(context.ListerRegistry),
 		scaleDownSetProcessor: processors.ScaleDownSetProcessor,
+		configGetter:          processors.NodeGroupConfigProcessor,
 		minUpdateInterval:     minUpdateInterval,
 	}
 }
@@ -312,15 +314,12 @@ func (p *Planner) atomicScaleDownNode(node *simulator.NodeToBeRemoved) bool {
 		klog.Errorf("failed to get node info for %v: %s", node.Node.Name, err)
 		return false
 	}
-	autoscalingOptions, err := nodeGroup.GetOptions(p.context.NodeGroupDefaults)
-	if err != nil && err != cloudprovider.ErrNotImplemented {
+	zeroOrMaxNodeScaling, err := p.configGetter.GetZeroOrMaxNodeScaling(nodeGroup)
+	if err != nil {
 		klog.Errorf("Failed to get autoscaling options for node group %s: %v", nodeGroup.Id(), err)
 		return false
 	}
-	if autoscalingOptions != nil && autoscalingOptions.ZeroOrMaxNodeScaling {
-		return true
-	}
-	return false
+	return zeroOrMaxNodeScaling
 }
 
 // unneededNodesLimit returns the number of nodes after which calculating more
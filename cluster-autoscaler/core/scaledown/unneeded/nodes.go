@@ -52,6 +52,8 @@ type scaleDownTimeGetter interface {
 	GetScaleDownUnneededTime(nodeGroup cloudprovider.NodeGroup) (time.Duration, error)
 	// GetScaleDownUnreadyTime returns ScaleDownUnreadyTime value that should be used for a given NodeGroup.
 	GetScaleDownUnreadyTime(nodeGroup cloudprovider.NodeGroup) (time.Duration, error)
+	// GetMinSize returns the min size that should be used for a given NodeGroup at a given time.
+	GetMinSize(nodeGroup cloudprovider.NodeGroup, now time.Time) (int, error)
 }
 
 // NewNodes returns a new initialized Nodes object.
@@ -182,7 +184,12 @@ func (n *Nodes) unremovableReason(context *context.AutoscalingContext, v *node,
 		}
 	}
 
-	if reason := verifyMinSize(node.Name, nodeGroup, nodeGroupSize, as); reason != simulator.NoReason {
+	minSize, err := n.sdtg.GetMinSize(nodeGroup, ts)
+	if err != nil {
+		klog.Errorf("Error trying to get min size for node %s (in group: %s)", node.Name, nodeGroup.Id())
+		return simulator.UnexpectedError
+	}
+	if reason := verifyMinSize(node.Name, nodeGroup, minSize, nodeGroupSize, as); reason != simulator.NoReason {
 		return reason
 	}
 
@@ -225,14 +232,14 @@ func (n *Nodes) splitEmptyAndNonEmptyNodes() (empty, needDrain map[string]*node)
 	return
 }
 
-func verifyMinSize(nodeName string, nodeGroup cloudprovider.NodeGroup, nodeGroupSize map[string]int, as scaledown.ActuationStatus) simulator.UnremovableReason {
+func verifyMinSize(nodeName string, nodeGroup cloudprovider.NodeGroup, minSize int, nodeGroupSize map[string]int, as scaledown.ActuationStatus) simulator.UnremovableReason {
 	size, found := nodeGroupSize[nodeGroup.Id()]
 	if !found {
 		klog.Errorf("Error while checking node group size %s: group size not found in cache", nodeGroup.Id())
 		return simulator.UnexpectedError
 	}
 	deletionsInProgress := as.DeletionsCount(nodeGroup.Id())
-	if size-deletionsInProgress <= nodeGroup.MinSize() {
+	if size-deletionsInProgress <= minSize {
 		klog.V(1).Infof("Skipping %s - node group min size reached", nodeName)
 		return simulator.NodeGroupMinSizeReached
 	}
@@ -133,6 +133,7 @@ func TestRemovableAt(t *testing.T) {
 		numEmpty            int
 		numDrain            int
 		minSize             int
+		scheduledMinSize    int
 		targetSize          int
 		numOngoingDeletions int
 		numEmptyToRemove    int
@@ -168,6 +169,16 @@ func TestRemovableAt(t *testing.T) {
 			numEmptyToRemove:    2,
 			numDrainToRemove:    0,
 		},
+		{
+			name:             "Scheduled min size is reached",
+			numEmpty:         3,
+			numDrain:         2,
+			minSize:          1,
+			scheduledMinSize: 8,
+			targetSize:       10,
+			numEmptyToRemove: 2,
+			numDrainToRemove: 0,
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -201,7 +212,7 @@ func TestRemovableAt(t *testing.T) {
 			ctx, err := NewScaleTestAutoscalingContext(config.AutoscalingOptions{ScaleDownSimulationTimeout: 5 * time.Minute}, &fake.Clientset{}, registry, provider, nil, nil)
 			assert.NoError(t, err)
 
-			n := NewNodes(&fakeScaleDownTimeGetter{}, &resource.LimitsFinder{})
+			n := NewNodes(&fakeScaleDownTimeGetter{scheduledMinSize: tc.scheduledMinSize}, &resource.LimitsFinder{})
 			n.Update(nodes, time.Now())
 			gotEmptyToRemove, gotDrainToRemove, _ := n.RemovableAt(&ctx, time.Now(), resource.Limits{}, []string{}, as)
 			if len(gotDrainToRemove) != tc.numDrainToRemove || len(gotEmptyToRemove) != tc.numEmptyToRemove {
@@ -232,7 +243,9 @@ func (f *fakeActuationStatus) DeletionsCount(nodeGroup string) int {
 	return f.deletionCount[nodeGroup]
 }
 
-type fakeScaleDownTimeGetter struct{}
+type fakeScaleDownTimeGetter struct {
+	scheduledMinSize int
+}
 
 func (f *fakeScaleDownTimeGetter) GetScaleDownUnneededTime(cloudprovider.NodeGroup) (time.Duration, error) {
 	return 0 * time.Second, nil
@@ -241,3 +254,10 @@ func (f *fakeScaleDownTimeGetter) GetScaleDownUnneededTime(cloudprovider.NodeGro
 func (f *fakeScaleDownTimeGetter) GetScaleDownUnreadyTime(cloudprovider.NodeGroup) (time.Duration, error) {
 	return 0 * time.Second, nil
 }
+
+func (f *fakeScaleDownTimeGetter) GetMinSize(ng cloudprovider.NodeGroup, _ time.Time) (int, error) {
+	if f.scheduledMinSize > ng.MinSize() {
+		return f.scheduledMinSize, nil
+	}
+	return ng.MinSize(), nil
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orchestrator
+
+import (
+	"sync"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	schedulerframework "k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/estimator"
+	"k8s.io/autoscaler/cluster-autoscaler/expander"
+	"k8s.io/autoscaler/cluster-autoscaler/metrics"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/predicatechecker"
+)
+
+// estimationWorker estimates one node group at a time. Workers running
+// concurrently have their own predicate checkers and cluster snapshots.
+type estimationWorker struct {
+	predicateChecker predicatechecker.PredicateChecker
+	clusterSnapshot  clustersnapshot.ClusterSnapshot
+}
+
+// sequentialEstimationWorker returns the worker estimating on the predicate
+// checker and cluster snapshot of the autoscaling context.
+func (o *ScaleUpOrchestrator) sequentialEstimationWorker() *estimationWorker {
+	return &estimationWorker{
+		predicateChecker: o.autoscalingContext.PredicateChecker,
+		clusterSnapshot:  o.autoscalingContext.ClusterSnapshot,
+	}
+}
+
+// estimationWorkers returns the workers to estimate the given number of node
+// groups with, up to ParallelEstimationWorkers of them. Parallel workers get
+// clones of the cluster snapshot of the autoscaling context if it's a
+// PersistentClusterSnapshot, their snapshots are synced with it otherwise.
+// Falls back to sequential estimation if parallel workers can't be set up.
+func (o *ScaleUpOrchestrator) estimationWorkers(nodeGroupCount int) []*estimationWorker {
+	count := o.autoscalingContext.ParallelEstimationWorkers
+	if count > nodeGroupCount {
+		count = nodeGroupCount
+	}
+	if count <= 1 {
+		return []*estimationWorker{o.sequentialEstimationWorker()}
+	}
+	for len(o.estimationWorkerPool) < count {
+		copyable, ok := o.autoscalingContext.PredicateChecker.(predicatechecker.CopyablePredicateChecker)
+		if !ok {
+			klog.Warningf("Predicate checker can't be copied, estimating node groups sequentially")
+			return []*estimationWorker{o.sequentialEstimationWorker()}
+		}
+		predicateChecker, err := copyable.Copy()
+		if err != nil {
+			klog.Errorf("Failed to copy predicate checker, estimating node groups sequentially: %v", err)
+			return []*estimationWorker{o.sequentialEstimationWorker()}
+		}
+		o.estimationWorkerPool = append(o.estimationWorkerPool, &estimationWorker{predicateChecker: predicateChecker})
+	}
+
+	workers := o.estimationWorkerPool[:count]
+	persistentSnapshot, isPersistent := o.autoscalingContext.ClusterSnapshot.(*clustersnapshot.PersistentClusterSnapshot)
+	for _, worker := range workers {
+		if isPersistent {
+			worker.clusterSnapshot = persistentSnapshot.Clone()
+			continue
+		}
+		if _, isClone := worker.clusterSnapshot.(*clustersnapshot.PersistentClusterSnapshot); isClone || worker.clusterSnapshot == nil {
+			worker.clusterSnapshot = clustersnapshot.NewDeltaClusterSnapshot()
+		}
+		if err := syncClusterSnapshot(worker.clusterSnapshot, o.autoscalingContext.ClusterSnapshot); err != nil {
+			klog.Errorf("Failed to copy cluster snapshot, estimating node groups sequentially: %v", err)
+			return []*estimationWorker{o.sequentialEstimationWorker()}
+		}
+	}
+	return workers
+}
+
+// syncClusterSnapshot replaces the contents of the snapshot with the nodes,
+// pods and Dynamic Resource Allocation state of the source snapshot.
+func syncClusterSnapshot(snapshot, source clustersnapshot.ClusterSnapshot) error {
+	nodeInfos, err := source.NodeInfos().List()
+	if err != nil {
+		return err
+	}
+	snapshot.Clear()
+	snapshot.SetDynamicResources(source.DynamicResources().Clone())
+	for _, nodeInfo := range nodeInfos {
+		pods := make([]*apiv1.Pod, 0, len(nodeInfo.Pods))
+		for _, podInfo := range nodeInfo.Pods {
+			pods = append(pods, podInfo.Pod)
+		}
+		if err := snapshot.AddNodeWithPods(nodeInfo.Node(), pods); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// computeExpansionOptions computes the expansion options of the node groups,
+// estimating each of them concurrently on its own worker. There can't be more
+// node groups than workers. The options are returned in the order of the node
+// groups.
+func (o *ScaleUpOrchestrator) computeExpansionOptions(
+	workers []*estimationWorker,
+	nodeGroups []cloudprovider.NodeGroup,
+	schedulablePodGroups map[string][]estimator.PodEquivalenceGroup,
+	nodeInfos map[string]*schedulerframework.NodeInfo,
+	currentNodeCount int,
+	now time.Time,
+	allOrNothing bool,
+) []expander.Option {
+	options := make([]expander.Option, len(nodeGroups))
+	var estimated []int
+	for i, nodeGroup := range nodeGroups {
+		options[i] = expander.Option{NodeGroup: nodeGroup}
+		if len(schedulablePodGroups[nodeGroup.Id()]) == 0 {
+			continue
+		}
+		// Cluster state is only accessed sequentially.
+		options[i].SimilarNodeGroups = o.ComputeSimilarNodeGroups(nodeGroup, nodeInfos, schedulablePodGroups, now)
+		estimated = append(estimated, i)
+	}
+
+	if len(estimated) == 1 {
+		i := estimated[0]
+		o.estimate(workers[0], &options[i], schedulablePodGroups[nodeGroups[i].Id()], nodeInfos, currentNodeCount)
+	} else {
+		var wg sync.WaitGroup
+		for w, i := range estimated {
+			wg.Add(1)
+			go func(worker *estimationWorker, option *expander.Option) {
+				defer wg.Done()
+				o.estimate(worker, option, schedulablePodGroups[option.NodeGroup.Id()], nodeInfos, currentNodeCount)
+			}(workers[w], &options[i])
+		}
+		wg.Wait()
+	}
+
+	for _, i := range estimated {
+		o.capZeroOrMaxNodeScaling(&options[i], allOrNothing)
+	}
+	return options
+}
+
+// estimate fills in the node count and the pods of the option, as well as the
+// node counts of similar node groups if the estimator splits them.
+func (o *ScaleUpOrchestrator) estimate(worker *estimationWorker, option *expander.Option, podGroups []estimator.PodEquivalenceGroup, nodeInfos map[string]*schedulerframework.NodeInfo, currentNodeCount int) {
+	estimateStart := time.Now()
+	expansionEstimator := o.estimatorBuilder(
+		worker.predicateChecker,
+		worker.clusterSnapshot,
+		estimator.NewEstimationContext(o.autoscalingContext.MaxNodesTotal, option.SimilarNodeGroups, currentNodeCount, nodeInfos),
+	)
+	option.NodeCount, option.Pods = expansionEstimator.Estimate(podGroups, nodeInfos[option.NodeGroup.Id()], option.NodeGroup)
+	if splitting, ok := expansionEstimator.(estimator.NodeGroupSplittingEstimator); ok {
+		option.NodeGroupCounts = splitting.NodeGroupCounts()
+	}
+	metrics.UpdateDurationFromStart(metrics.Estimate, estimateStart)
+	metrics.UpdateNodeGroupEstimationDuration(time.Since(estimateStart))
+}
@@ -0,0 +1,276 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orchestrator
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/clusterstate"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"k8s.io/autoscaler/cluster-autoscaler/context"
+	. "k8s.io/autoscaler/cluster-autoscaler/core/test"
+	"k8s.io/autoscaler/cluster-autoscaler/expander"
+	ca_processors "k8s.io/autoscaler/cluster-autoscaler/processors"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroupconfig"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroupset"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/nodeinfosprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/errors"
+	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/taints"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+)
+
+// runEstimationTest scales up a cluster with node groups of different sizes
+// and returns the orchestrator and the expansion options it considered.
+func runEstimationTest(t *testing.T, workers int, limitBinpacking bool) (*ScaleUpOrchestrator, []GroupSizeChange) {
+	now := time.Now()
+	provider := testprovider.NewTestCloudProvider(func(nodeGroup string, increase int) error {
+		return nil
+	}, nil)
+	var nodes []*apiv1.Node
+	var pods []*apiv1.Pod
+	for i := 1; i <= 6; i++ {
+		name := fmt.Sprintf("ng%d", i)
+		node := BuildTestNode(name+"-n1", int64(i)*1000, int64(i)*1000)
+		SetNodeReadyState(node, true, now.Add(-2*time.Minute))
+		provider.AddNodeGroup(name, 1, 10, 1)
+		provider.AddNode(name, node)
+		nodes = append(nodes, node)
+		pods = append(pods, BuildTestPod(name+"-p1", int64(i)*500, 0, WithNodeName(node.Name)))
+	}
+
+	options := defaultOptions
+	options.ParallelEstimationWorkers = workers
+	listers := kube_util.NewListerRegistry(nil, nil, kube_util.NewTestPodLister(pods), nil, nil, nil, nil, nil, nil)
+	context, err := NewScaleTestAutoscalingContext(options, &fake.Clientset{}, listers, provider, nil, nil)
+	assert.NoError(t, err)
+	clustersnapshot.InitializeClusterSnapshotOrDie(t, context.ClusterSnapshot, nodes, pods)
+
+	nodeInfos, err := nodeinfosprovider.NewDefaultTemplateNodeInfoProvider(nil, false).
+		Process(&context, nodes, []*appsv1.DaemonSet{}, taints.TaintConfig{}, now)
+	assert.NoError(t, err)
+	clusterState := clusterstate.NewClusterStateRegistry(provider, clusterstate.ClusterStateRegistryConfig{}, context.LogRecorder, NewBackoff(), nodegroupconfig.NewDefaultNodeGroupConfigProcessor(config.NodeGroupAutoscalingOptions{MaxNodeProvisionTime: 15 * time.Minute}))
+	clusterState.UpdateNodes(nodes, nodeInfos, now)
+
+	processors := NewTestProcessors(&context)
+	if limitBinpacking {
+		processors.BinpackingLimiter = &MockBinpackingLimiter{}
+	}
+	suOrchestrator := New()
+	suOrchestrator.Initialize(&context, processors, clusterState, newEstimatorBuilder(), taints.TaintConfig{})
+	expander := NewMockRepotingStrategy(t, nil)
+	context.ExpanderStrategy = expander
+
+	var extraPods []*apiv1.Pod
+	for i := 0; i < 4; i++ {
+		extraPods = append(extraPods, BuildTestPod(fmt.Sprintf("p-new-%d", i), 900, 0))
+	}
+	scaleUpStatus, err := suOrchestrator.ScaleUp(extraPods, nodes, []*appsv1.DaemonSet{}, nodeInfos, false)
+	assert.NoError(t, err)
+	assert.True(t, scaleUpStatus.WasSuccessful())
+	return suOrchestrator, expander.LastInputOptions()
+}
+
+func TestParallelEstimation(t *testing.T) {
+	_, sequentialOptions := runEstimationTest(t, 1, false)
+	assert.Len(t, sequentialOptions, 6)
+	sortOptions(sequentialOptions)
+
+	for _, workers := range []int{2, 4, 16} {
+		t.Run(fmt.Sprintf("%d workers", workers), func(t *testing.T) {
+			suOrchestrator, options := runEstimationTest(t, workers, false)
+			sortOptions(options)
+			assert.Equal(t, sequentialOptions, options)
+			wantPool := workers
+			if wantPool > 6 {
+				wantPool = 6
+			}
+			assert.Len(t, suOrchestrator.estimationWorkerPool, wantPool)
+		})
+	}
+}
+
+func TestParallelEstimationStopsBinpacking(t *testing.T) {
+	// Options of the whole batch are estimated, but the ones after the
+	// limiter stops binpacking are dropped, same as with sequential estimation.
+	_, options := runEstimationTest(t, 4, true)
+	assert.Len(t, options, 1)
+}
+
+func TestSyncClusterSnapshot(t *testing.T) {
+	n1 := BuildTestNode("n1", 1000, 1000)
+	n2 := BuildTestNode("n2", 1000, 1000)
+	p1 := BuildTestPod("p1", 100, 0, WithNodeName(n1.Name))
+	source := clustersnapshot.NewBasicClusterSnapshot()
+	clustersnapshot.InitializeClusterSnapshotOrDie(t, source, []*apiv1.Node{n1}, []*apiv1.Pod{p1})
+	snapshot := clustersnapshot.NewDeltaClusterSnapshot()
+	clustersnapshot.InitializeClusterSnapshotOrDie(t, snapshot, []*apiv1.Node{n2}, nil)
+
+	assert.NoError(t, syncClusterSnapshot(snapshot, source))
+	nodeInfos, err := snapshot.NodeInfos().List()
+	assert.NoError(t, err)
+	if assert.Len(t, nodeInfos, 1) {
+		assert.Equal(t, n1, nodeInfos[0].Node())
+		assert.Len(t, nodeInfos[0].Pods, 1)
+	}
+}
+
+func TestEstimationWorkersClonePersistentSnapshot(t *testing.T) {
+	n1 := BuildTestNode("n1", 1000, 1000)
+	options := defaultOptions
+	options.ParallelEstimationWorkers = 2
+	context, err := NewScaleTestAutoscalingContext(options, &fake.Clientset{}, nil, testprovider.NewTestCloudProvider(nil, nil), nil, nil)
+	assert.NoError(t, err)
+	snapshot := clustersnapshot.NewPersistentClusterSnapshot()
+	clustersnapshot.InitializeClusterSnapshotOrDie(t, snapshot, []*apiv1.Node{n1}, nil)
+	context.ClusterSnapshot = snapshot
+	suOrchestrator := &ScaleUpOrchestrator{autoscalingContext: &context}
+
+	workers := suOrchestrator.estimationWorkers(3)
+	assert.Len(t, workers, 2)
+	for i, worker := range workers {
+		assert.NotSame(t, snapshot, worker.clusterSnapshot)
+		assert.NoError(t, worker.clusterSnapshot.AddNode(BuildTestNode(fmt.Sprintf("tmp-%d", i), 1000, 1000)))
+		nodeInfos, err := worker.clusterSnapshot.NodeInfos().List()
+		assert.NoError(t, err)
+		assert.Len(t, nodeInfos, 2)
+	}
+	nodeInfos, err := snapshot.NodeInfos().List()
+	assert.NoError(t, err)
+	assert.Len(t, nodeInfos, 1)
+}
+
+func TestEstimatedScaleUps(t *testing.T) {
+	provider := testprovider.NewTestCloudProvider(nil, nil)
+	provider.AddNodeGroup("ng1", 0, 10, 2)
+	provider.AddNodeGroup("ng2", 0, 3, 1)
+	ng1 := provider.GetNodeGroup("ng1")
+	ng2 := provider.GetNodeGroup("ng2")
+	testCases := []struct {
+		name            string
+		nodeGroupCounts map[string]int
+		newNodes        int
+		want            []nodegroupset.ScaleUpInfo
+	}{
+		{
+			name:     "no split",
+			newNodes: 3,
+		},
+		{
+			name:            "split between similar node groups",
+			nodeGroupCounts: map[string]int{"ng1": 1, "ng2": 2},
+			newNodes:        3,
+			want: []nodegroupset.ScaleUpInfo{
+				{Group: ng1, CurrentSize: 2, NewSize: 3, MaxSize: 10},
+				{Group: ng2, CurrentSize: 1, NewSize: 3, MaxSize: 3},
+			},
+		},
+		{
+			name:            "capped scale-up",
+			nodeGroupCounts: map[string]int{"ng1": 1, "ng2": 2},
+			newNodes:        2,
+		},
+		{
+			name:            "max size exceeded",
+			nodeGroupCounts: map[string]int{"ng1": 1, "ng2": 3},
+			newNodes:        4,
+		},
+		{
+			name:            "unknown node group",
+			nodeGroupCounts: map[string]int{"ng1": 1, "ng3": 1},
+			newNodes:        2,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			option := &expander.Option{
+				NodeGroup:         ng1,
+				SimilarNodeGroups: []cloudprovider.NodeGroup{ng2},
+				NodeCount:         tc.newNodes,
+				NodeGroupCounts:   tc.nodeGroupCounts,
+			}
+			scaleUpInfos, err := splitScaleUps(option, tc.newNodes)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, scaleUpInfos)
+		})
+	}
+}
+
+type estimatedScaleUpProcessor struct {
+	nodegroupset.NodeGroupSetProcessor
+	groups []cloudprovider.NodeGroup
+}
+
+// ProcessEstimatedScaleUps moves a node from the last scale-up to the first one.
+func (p *estimatedScaleUpProcessor) ProcessEstimatedScaleUps(_ *context.AutoscalingContext, groups []cloudprovider.NodeGroup, scaleUpInfos []nodegroupset.ScaleUpInfo) ([]nodegroupset.ScaleUpInfo, errors.AutoscalerError) {
+	p.groups = groups
+	scaleUpInfos[0].NewSize++
+	scaleUpInfos[len(scaleUpInfos)-1].NewSize--
+	return scaleUpInfos, nil
+}
+
+func TestEstimatedScaleUpsProcessed(t *testing.T) {
+	provider := testprovider.NewTestCloudProvider(nil, nil)
+	provider.AddNodeGroup("ng1", 0, 10, 2)
+	provider.AddNodeGroup("ng2", 0, 3, 1)
+	ng1 := provider.GetNodeGroup("ng1")
+	ng2 := provider.GetNodeGroup("ng2")
+	option := &expander.Option{
+		NodeGroup:         ng1,
+		SimilarNodeGroups: []cloudprovider.NodeGroup{ng2},
+		NodeCount:         3,
+		NodeGroupCounts:   map[string]int{"ng1": 1, "ng2": 2},
+	}
+
+	// Estimated scale-ups are executed as is if the processor can't adjust them.
+	suOrchestrator := &ScaleUpOrchestrator{processors: &ca_processors.AutoscalingProcessors{
+		NodeGroupSetProcessor: nodegroupset.NewDefaultNodeGroupSetProcessor(nil, config.NodeGroupDifferenceRatios{}),
+	}}
+	scaleUpInfos, err := suOrchestrator.estimatedScaleUps(option, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []nodegroupset.ScaleUpInfo{
+		{Group: ng1, CurrentSize: 2, NewSize: 3, MaxSize: 10},
+		{Group: ng2, CurrentSize: 1, NewSize: 3, MaxSize: 3},
+	}, scaleUpInfos)
+
+	processor := &estimatedScaleUpProcessor{}
+	suOrchestrator.processors.NodeGroupSetProcessor = processor
+	scaleUpInfos, err = suOrchestrator.estimatedScaleUps(option, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []nodegroupset.ScaleUpInfo{
+		{Group: ng1, CurrentSize: 2, NewSize: 4, MaxSize: 10},
+		{Group: ng2, CurrentSize: 1, NewSize: 2, MaxSize: 3},
+	}, scaleUpInfos)
+	assert.Equal(t, []cloudprovider.NodeGroup{ng1, ng2}, processor.groups)
+}
+
+func sortOptions(options []GroupSizeChange) {
+	sort.Slice(options, func(i, j int) bool {
+		return options[i].GroupName < options[j].GroupName
+	})
+}
@@ -159,6 +159,10 @@ func (e *scaleUpExecutor) executeScaleUp(
 ) errors.AutoscalerError {
 	gpuConfig := e.autoscalingContext.CloudProvider.GetNodeGpuConfig(nodeInfo.Node())
 	gpuResourceName, gpuType := gpu.GetGpuInfoForMetrics(gpuConfig, availableGPUTypes, nodeInfo.Node(), nil)
+	if e.autoscalingContext.DryRunRecorder != nil {
+		e.autoscalingContext.DryRunRecorder.RecordIncreaseSize(info.Group.Id(), info.NewSize-info.CurrentSize)
+		return nil
+	}
 	klog.V(0).Infof("Scale-up: setting group %s size to %d", info.Group.Id(), info.NewSize)
 	e.autoscalingContext.LogRecorder.Eventf(apiv1.EventTypeNormal, "ScaledUpGroup",
 		"Scale-up: setting group %s size to %d instead of %d (max: %d)", info.Group.Id(), info.NewSize, info.CurrentSize, info.MaxSize)
@@ -17,6 +17,7 @@ limitations under the License.
 package orchestrator
 
 import (
+	"sort"
 	"strings"
 	"time"
 
@@ -25,6 +26,8 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/estimator"
 	"k8s.io/klog/v2"
 	schedulerframework "k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/names"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/volumebinding"
 
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
 	"k8s.io/autoscaler/cluster-autoscaler/clusterstate"
@@ -38,6 +41,7 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroups"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroupset"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/status"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/predicatechecker"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/errors"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/klogx"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/taints"
@@ -51,6 +55,7 @@ type ScaleUpOrchestrator struct {
 	clusterStateRegistry *clusterstate.ClusterStateRegistry
 	scaleUpExecutor      *scaleUpExecutor
 	estimatorBuilder     estimator.EstimatorBuilder
+	estimationWorkerPool []*estimationWorker
 	taintConfig          taints.TaintConfig
 	initialized          bool
 }
@@ -89,7 +94,7 @@ func (o *ScaleUpOrchestrator) ScaleUp(
 	daemonSets []*appsv1.DaemonSet,
 	nodeInfos map[string]*schedulerframework.NodeInfo,
 	allOrNothing bool, // Either request enough capacity for all unschedulablePods, or don't request it at all.
-) (*status.ScaleUpStatus, errors.AutoscalerError) {
+) (scaleUpStatus *status.ScaleUpStatus, aErr errors.AutoscalerError) {
 	if !o.initialized {
 		return status.UpdateScaleUpError(&status.ScaleUpStatus{}, errors.NewAutoscalerError(errors.InternalError, "ScaleUpOrchestrator is not initialized"))
 	}
@@ -100,8 +105,13 @@ func (o *ScaleUpOrchestrator) ScaleUp(
 	}
 	klogx.V(1).Over(loggingQuota).Infof("%v other pods are also unschedulable", -loggingQuota.Left())
 
+	scaleUpCandidates, quotaRejections, err := o.applyTenantQuotas(unschedulablePods)
+	if err != nil {
+		return status.UpdateScaleUpError(&status.ScaleUpStatus{}, errors.ToAutoscalerError(errors.InternalError, err).AddPrefix("could not apply tenant quotas: "))
+	}
+
 	buildPodEquivalenceGroupsStart := time.Now()
-	podEquivalenceGroups := equivalence.BuildPodGroups(unschedulablePods)
+	podEquivalenceGroups := equivalence.BuildPodGroups(scaleUpCandidates)
 	metrics.UpdateDurationFromStart(metrics.BuildPodEquivalenceGroups, buildPodEquivalenceGroupsStart)
 
 	upcomingNodes, aErr := o.UpcomingNodes(nodeInfos)
@@ -112,13 +122,20 @@ func (o *ScaleUpOrchestrator) ScaleUp(
 
 	nodeGroups := o.autoscalingContext.CloudProvider.NodeGroups()
 	if o.processors != nil && o.processors.NodeGroupListProcessor != nil {
-		var err error
-		nodeGroups, nodeInfos, err = o.processors.NodeGroupListProcessor.Process(o.autoscalingContext, nodeGroups, nodeInfos, unschedulablePods)
+		nodeGroups, nodeInfos, err = o.processors.NodeGroupListProcessor.Process(o.autoscalingContext, nodeGroups, nodeInfos, scaleUpCandidates)
 		if err != nil {
 			return status.UpdateScaleUpError(&status.ScaleUpStatus{}, errors.ToAutoscalerError(errors.InternalError, err))
 		}
 	}
 
+	// Pods rejected by tenant quotas remain unschedulable whatever the outcome.
+	quotaExceededGroups := tenantQuotaExceededGroups(quotaRejections, nodeGroups)
+	defer func() {
+		if scaleUpStatus != nil && len(quotaExceededGroups) > 0 {
+			scaleUpStatus.PodsRemainUnschedulable = append(scaleUpStatus.PodsRemainUnschedulable, GetRemainingPods(quotaExceededGroups, nil)...)
+		}
+	}()
+
 	// Initialise binpacking limiter.
 	o.processors.BinpackingLimiter.InitBinpacking(o.autoscalingContext, nodeGroups)
 
@@ -145,20 +162,32 @@ func (o *ScaleUpOrchestrator) ScaleUp(
 		schedulablePodGroups[nodeGroup.Id()] = o.SchedulablePodGroups(podEquivalenceGroups, nodeGroup, nodeInfos[nodeGroup.Id()])
 	}
 
-	for _, nodeGroup := range validNodeGroups {
-		option := o.ComputeExpansionOption(nodeGroup, schedulablePodGroups, nodeInfos, len(nodes)+len(upcomingNodes), now, allOrNothing)
-		o.processors.BinpackingLimiter.MarkProcessed(o.autoscalingContext, nodeGroup.Id())
-
-		if len(option.Pods) == 0 || option.NodeCount == 0 {
-			klog.V(4).Infof("No pod can fit to %s", nodeGroup.Id())
-		} else if allOrNothing && len(option.Pods) < len(unschedulablePods) {
-			klog.V(4).Infof("Some pods can't fit to %s, giving up due to all-or-nothing scale-up strategy", nodeGroup.Id())
-		} else {
-			options = append(options, option)
-		}
+	// Node groups are estimated in batches of up to one per estimation worker,
+	// the options are processed in the order of node groups.
+	workers := o.estimationWorkers(len(validNodeGroups))
+	stopBinpacking := false
+	for start := 0; start < len(validNodeGroups) && !stopBinpacking; start += len(workers) {
+		end := start + len(workers)
+		if end > len(validNodeGroups) {
+			end = len(validNodeGroups)
+		}
+		batchOptions := o.computeExpansionOptions(workers, validNodeGroups[start:end], schedulablePodGroups, nodeInfos, len(nodes)+len(upcomingNodes), now, allOrNothing)
+		for _, option := range batchOptions {
+			nodeGroup := option.NodeGroup
+			o.processors.BinpackingLimiter.MarkProcessed(o.autoscalingContext, nodeGroup.Id())
+
+			if len(option.Pods) == 0 || option.NodeCount == 0 {
+				klog.V(4).Infof("No pod can fit to %s", nodeGroup.Id())
+			} else if allOrNothing && len(option.Pods) < len(unschedulablePods) {
+				klog.V(4).Infof("Some pods can't fit to %s, giving up due to all-or-nothing scale-up strategy", nodeGroup.Id())
+			} else {
+				options = append(options, option)
+			}
 
-		if o.processors.BinpackingLimiter.StopBinpacking(o.autoscalingContext, options) {
-			break
+			if o.processors.BinpackingLimiter.StopBinpacking(o.autoscalingContext, options) {
+				stopBinpacking = true
+				break
+			}
 		}
 	}
 
@@ -175,7 +204,15 @@ func (o *ScaleUpOrchestrator) ScaleUp(
 	}
 
 	// Pick some expansion option.
-	bestOption := o.autoscalingContext.ExpanderStrategy.BestOption(options, nodeInfos)
+	bestOption, expanderResults := expander.ExplainBestOption(o.autoscalingContext.ExpanderStrategy, options, nodeInfos)
+	// Whatever the outcome, report how the option was chosen.
+	defer func() {
+		if scaleUpStatus != nil {
+			scaleUpStatus.ExpansionOptions = options
+			scaleUpStatus.ExpanderResults = expanderResults
+			scaleUpStatus.BestOption = bestOption
+		}
+	}()
 	if bestOption == nil || bestOption.NodeCount <= 0 {
 		return &status.ScaleUpStatus{
 			Result:                  status.ScaleUpNoOptionsAvailable,
@@ -222,14 +259,20 @@ func (o *ScaleUpOrchestrator) ScaleUp(
 			markedEquivalenceGroups := markAllGroupsAsUnschedulable(podEquivalenceGroups, AllOrNothingReason)
 			return buildNoOptionsAvailableStatus(markedEquivalenceGroups, skippedNodeGroups, nodeGroups), nil
 		}
-		var scaleUpStatus *status.ScaleUpStatus
-		createNodeGroupResults, scaleUpStatus, aErr = o.CreateNodeGroup(bestOption, nodeInfos, schedulablePodGroups, podEquivalenceGroups, daemonSets)
-		if aErr != nil {
-			return scaleUpStatus, aErr
+		if o.autoscalingContext.DryRunRecorder != nil {
+			o.autoscalingContext.DryRunRecorder.RecordCreateNodeGroup(bestOption.NodeGroup.Id())
+		} else {
+			createNodeGroupResults, scaleUpStatus, aErr = o.CreateNodeGroup(bestOption, nodeInfos, schedulablePodGroups, podEquivalenceGroups, daemonSets)
+			if aErr != nil {
+				return scaleUpStatus, aErr
+			}
 		}
 	}
 
-	scaleUpInfos, aErr := o.balanceScaleUps(now, bestOption.NodeGroup, newNodes, nodeInfos, schedulablePodGroups)
+	scaleUpInfos, aErr := o.estimatedScaleUps(bestOption, newNodes)
+	if scaleUpInfos == nil && aErr == nil {
+		scaleUpInfos, aErr = o.balanceScaleUps(now, bestOption.NodeGroup, newNodes, nodeInfos, schedulablePodGroups)
+	}
 	if aErr != nil {
 		return status.UpdateScaleUpError(
 			&status.ScaleUpStatus{CreateNodeGroupResults: createNodeGroupResults, PodsTriggeredScaleUp: bestOption.Pods},
@@ -288,9 +331,10 @@ func (o *ScaleUpOrchestrator) applyLimits(newNodes int, resourcesLeft resource.L
 }
 
 // ScaleUpToNodeGroupMinSize tries to scale up node groups that have less nodes
-// than the configured min size. The source of truth for the current node group
-// size is the TargetSize queried directly from cloud providers. Returns
-// appropriate status or error if an unexpected error occurred.
+// than the configured min size, raised by any active min size schedules. The
+// source of truth for the current node group size is the TargetSize queried
+// directly from cloud providers. Returns appropriate status or error if an
+// unexpected error occurred.
 func (o *ScaleUpOrchestrator) ScaleUpToNodeGroupMinSize(
 	nodes []*apiv1.Node,
 	nodeInfos map[string]*schedulerframework.NodeInfo,
@@ -314,14 +358,25 @@ func (o *ScaleUpOrchestrator) ScaleUpToNodeGroupMinSize(
 			continue
 		}
 
+		if o.processors.MaintenanceWindows.ScaleUpFrozen(ng.Id()) {
+			klog.V(4).Infof("ScaleUpToNodeGroupMinSize: NodeGroup %s is frozen by a maintenance window", ng.Id())
+			continue
+		}
+
 		targetSize, err := ng.TargetSize()
 		if err != nil {
 			klog.Warningf("ScaleUpToNodeGroupMinSize: failed to get target size of node group %s", ng.Id())
 			continue
 		}
 
-		klog.V(4).Infof("ScaleUpToNodeGroupMinSize: NodeGroup %s, TargetSize %d, MinSize %d, MaxSize %d", ng.Id(), targetSize, ng.MinSize(), ng.MaxSize())
-		if targetSize >= ng.MinSize() {
+		minSize, err := o.processors.NodeGroupConfigProcessor.GetMinSize(ng, now)
+		if err != nil {
+			klog.Warningf("ScaleUpToNodeGroupMinSize: failed to get min size of node group %s: %v", ng.Id(), err)
+			continue
+		}
+
+		klog.V(4).Infof("ScaleUpToNodeGroupMinSize: NodeGroup %s, TargetSize %d, MinSize %d, MaxSize %d", ng.Id(), targetSize, minSize, ng.MaxSize())
+		if targetSize >= minSize {
 			continue
 		}
 
@@ -341,7 +396,7 @@ func (o *ScaleUpOrchestrator) ScaleUpToNodeGroupMinSize(
 			continue
 		}
 
-		newNodeCount := ng.MinSize() - targetSize
+		newNodeCount := minSize - targetSize
 		newNodeCount, err = o.resourceManager.ApplyLimits(o.autoscalingContext, newNodeCount, resourcesLeft, nodeInfo, ng)
 		if err != nil {
 			klog.Warningf("ScaleUpToNodeGroupMinSize: failed to apply resource limits: %v", err)
@@ -415,12 +470,12 @@ func (o *ScaleUpOrchestrator) filterValidScaleUpNodeGroups(
 			skippedNodeGroups[nodeGroup.Id()] = MaxLimitReachedReason
 			continue
 		}
-		autoscalingOptions, err := nodeGroup.GetOptions(o.autoscalingContext.NodeGroupDefaults)
-		if err != nil && err != cloudprovider.ErrNotImplemented {
+		zeroOrMaxNodeScaling, err := o.processors.NodeGroupConfigProcessor.GetZeroOrMaxNodeScaling(nodeGroup)
+		if err != nil {
 			klog.Errorf("Couldn't get autoscaling options for ng: %v", nodeGroup.Id())
 		}
 		numNodes := 1
-		if autoscalingOptions != nil && autoscalingOptions.ZeroOrMaxNodeScaling {
+		if zeroOrMaxNodeScaling {
 			numNodes = nodeGroup.MaxSize() - currentTargetSize
 			if o.autoscalingContext.MaxNodesTotal != 0 && currentNodeCount+numNodes > o.autoscalingContext.MaxNodesTotal {
 				klog.V(4).Infof("Skipping node group %s - atomic scale-up exceeds cluster node count limit", nodeGroup.Id())
@@ -454,32 +509,21 @@ func (o *ScaleUpOrchestrator) ComputeExpansionOption(
 	now time.Time,
 	allOrNothing bool,
 ) expander.Option {
-	option := expander.Option{NodeGroup: nodeGroup}
-	podGroups := schedulablePodGroups[nodeGroup.Id()]
-	nodeInfo := nodeInfos[nodeGroup.Id()]
-
-	if len(podGroups) == 0 {
-		return option
-	}
-
-	option.SimilarNodeGroups = o.ComputeSimilarNodeGroups(nodeGroup, nodeInfos, schedulablePodGroups, now)
-
-	estimateStart := time.Now()
-	expansionEstimator := o.estimatorBuilder(
-		o.autoscalingContext.PredicateChecker,
-		o.autoscalingContext.ClusterSnapshot,
-		estimator.NewEstimationContext(o.autoscalingContext.MaxNodesTotal, option.SimilarNodeGroups, currentNodeCount),
-	)
-	option.NodeCount, option.Pods = expansionEstimator.Estimate(podGroups, nodeInfo, nodeGroup)
-	metrics.UpdateDurationFromStart(metrics.Estimate, estimateStart)
+	options := o.computeExpansionOptions([]*estimationWorker{o.sequentialEstimationWorker()}, []cloudprovider.NodeGroup{nodeGroup}, schedulablePodGroups, nodeInfos, currentNodeCount, now, allOrNothing)
+	return options[0]
+}
 
-	autoscalingOptions, err := nodeGroup.GetOptions(o.autoscalingContext.NodeGroupDefaults)
-	if err != nil && err != cloudprovider.ErrNotImplemented {
+// capZeroOrMaxNodeScaling adjusts the node count of the option for node groups
+// which only scale from zero to max.
+func (o *ScaleUpOrchestrator) capZeroOrMaxNodeScaling(option *expander.Option, allOrNothing bool) {
+	nodeGroup := option.NodeGroup
+	zeroOrMaxNodeScaling, err := o.processors.NodeGroupConfigProcessor.GetZeroOrMaxNodeScaling(nodeGroup)
+	if err != nil {
 		klog.Errorf("Failed to get autoscaling options for node group %s: %v", nodeGroup.Id(), err)
 	}
 
 	// Special handling for groups that only scale from zero to max.
-	if autoscalingOptions != nil && autoscalingOptions.ZeroOrMaxNodeScaling {
+	if zeroOrMaxNodeScaling {
 		// For zero-or-max scaling groups, the only valid value of node count is node group's max size.
 		if allOrNothing && option.NodeCount > nodeGroup.MaxSize() {
 			// We would have to cap the node count, which means not all pods will be
@@ -492,8 +536,6 @@ func (o *ScaleUpOrchestrator) ComputeExpansionOption(
 			option.NodeCount = nodeGroup.MaxSize()
 		}
 	}
-
-	return option
 }
 
 // CreateNodeGroup will try to create a new node group based on the initialOption.
@@ -587,13 +629,41 @@ func (o *ScaleUpOrchestrator) SchedulablePodGroups(
 			if podCount := len(eg.Pods); podCount > 1 {
 				klog.V(2).Infof("%d other pods similar to %s can't be scheduled on %s", podCount-1, samplePod.Name, nodeGroup.Id())
 			}
-			eg.SchedulingErrors[nodeGroup.Id()] = err
+			eg.SchedulingErrors[nodeGroup.Id()] = schedulingReasons(err)
 		}
 	}
 
 	return schedulablePodGroups
 }
 
+// schedulingReasons returns reasons why a pod failing predicates on the template
+// node of a node group can't be scheduled on nodes of the group. Only volume
+// topology mismatches, node affinity of bound PersistentVolumes and zones of
+// volumes, are reported as UnsatisfiableVolumes: new nodes of the group have the
+// same topology as the template node. Storage capacity and attachable volume
+// limits depend on the state of the cluster, so they are reported as other
+// predicate errors.
+func schedulingReasons(err *predicatechecker.PredicateError) status.Reasons {
+	if err.ErrorType() != predicatechecker.NotSchedulablePredicateError {
+		return err
+	}
+	switch err.PredicateName() {
+	case names.VolumeZone:
+		reasons := err.Reasons()
+		if len(reasons) == 0 {
+			reasons = []string{err.Message()}
+		}
+		return NewUnsatisfiableVolumes(err.PredicateName(), reasons)
+	case names.VolumeBinding:
+		for _, reason := range err.Reasons() {
+			if reason == string(volumebinding.ErrReasonNodeConflict) {
+				return NewUnsatisfiableVolumes(err.PredicateName(), []string{reason})
+			}
+		}
+	}
+	return err
+}
+
 // UpcomingNodes returns a list of nodes that are not ready but should be.
 func (o *ScaleUpOrchestrator) UpcomingNodes(nodeInfos map[string]*schedulerframework.NodeInfo) ([]*schedulerframework.NodeInfo, errors.AutoscalerError) {
 	upcomingCounts, _ := o.clusterStateRegistry.GetUpcomingNodes()
@@ -706,6 +776,66 @@ func (o *ScaleUpOrchestrator) balanceScaleUps(
 	return o.processors.NodeGroupSetProcessor.BalanceScaleUpBetweenGroups(o.autoscalingContext, targetNodeGroups, newNodes)
 }
 
+// estimatedScaleUps returns the scale-ups of the node groups as split by the
+// estimator and processed by the NodeGroupSetProcessor, if it implements
+// nodegroupset.EstimatedScaleUpProcessor. Returns nil if the estimator didn't
+// split the option, or its split can't be executed as is, e.g. because the
+// scale-up was capped by limits; such scale-ups are balanced as usual.
+func (o *ScaleUpOrchestrator) estimatedScaleUps(option *expander.Option, newNodes int) ([]nodegroupset.ScaleUpInfo, errors.AutoscalerError) {
+	scaleUpInfos, aErr := splitScaleUps(option, newNodes)
+	if scaleUpInfos == nil || aErr != nil {
+		return scaleUpInfos, aErr
+	}
+	processor, ok := o.processors.NodeGroupSetProcessor.(nodegroupset.EstimatedScaleUpProcessor)
+	if !ok {
+		return scaleUpInfos, nil
+	}
+	groups := append([]cloudprovider.NodeGroup{option.NodeGroup}, option.SimilarNodeGroups...)
+	return processor.ProcessEstimatedScaleUps(o.autoscalingContext, groups, scaleUpInfos)
+}
+
+// splitScaleUps returns the scale-ups of the node groups as split by the
+// estimator, or nil if there is no such split or it can't be executed as is.
+func splitScaleUps(option *expander.Option, newNodes int) ([]nodegroupset.ScaleUpInfo, errors.AutoscalerError) {
+	if len(option.NodeGroupCounts) == 0 {
+		return nil, nil
+	}
+	groups := map[string]cloudprovider.NodeGroup{option.NodeGroup.Id(): option.NodeGroup}
+	for _, ng := range option.SimilarNodeGroups {
+		groups[ng.Id()] = ng
+	}
+	var scaleUpInfos []nodegroupset.ScaleUpInfo
+	total := 0
+	for id, count := range option.NodeGroupCounts {
+		ng, found := groups[id]
+		if !found || !ng.Exist() {
+			return nil, nil
+		}
+		currentSize, err := ng.TargetSize()
+		if err != nil {
+			return nil, errors.NewAutoscalerError(errors.CloudProviderError, "failed to get node group size: %v", err)
+		}
+		if currentSize+count > ng.MaxSize() {
+			return nil, nil
+		}
+		scaleUpInfos = append(scaleUpInfos, nodegroupset.ScaleUpInfo{
+			Group:       ng,
+			CurrentSize: currentSize,
+			NewSize:     currentSize + count,
+			MaxSize:     ng.MaxSize(),
+		})
+		total += count
+	}
+	if total != newNodes {
+		return nil, nil
+	}
+	sort.Slice(scaleUpInfos, func(i, j int) bool {
+		return scaleUpInfos[i].Group.Id() < scaleUpInfos[j].Group.Id()
+	})
+	klog.V(1).Infof("Splitting scale-up between %v node groups as estimated", len(scaleUpInfos))
+	return scaleUpInfos, nil
+}
+
 // ComputeSimilarNodeGroups finds similar node groups which can schedule the same
 // set of pods as the main node group.
 func (o *ScaleUpOrchestrator) ComputeSimilarNodeGroups(
@@ -718,11 +848,11 @@ func (o *ScaleUpOrchestrator) ComputeSimilarNodeGroups(
 		return nil
 	}
 
-	autoscalingOptions, err := nodeGroup.GetOptions(o.autoscalingContext.NodeGroupDefaults)
-	if err != nil && err != cloudprovider.ErrNotImplemented {
+	zeroOrMaxNodeScaling, err := o.processors.NodeGroupConfigProcessor.GetZeroOrMaxNodeScaling(nodeGroup)
+	if err != nil {
 		klog.Errorf("Failed to get autoscaling options for node group %s: %v", nodeGroup.Id(), err)
 	}
-	if autoscalingOptions != nil && autoscalingOptions.ZeroOrMaxNodeScaling {
+	if zeroOrMaxNodeScaling {
 		return nil
 	}
 
@@ -20,6 +20,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"sync/atomic"
@@ -33,17 +35,21 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
 	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
 	"k8s.io/autoscaler/cluster-autoscaler/clusterstate"
+	"k8s.io/autoscaler/cluster-autoscaler/clusterstate/api"
 	"k8s.io/autoscaler/cluster-autoscaler/config"
 	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/core/dryrun"
 	"k8s.io/autoscaler/cluster-autoscaler/core/scaleup/resource"
 	. "k8s.io/autoscaler/cluster-autoscaler/core/test"
 	"k8s.io/autoscaler/cluster-autoscaler/core/utils"
 	"k8s.io/autoscaler/cluster-autoscaler/estimator"
 	"k8s.io/autoscaler/cluster-autoscaler/metrics"
 	"k8s.io/autoscaler/cluster-autoscaler/processors"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/maintenance"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroupset"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/nodeinfosprovider"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/status"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/predicatechecker"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/errors"
 	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/taints"
@@ -53,6 +59,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	apiv1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+	k8sresource "k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/client-go/kubernetes/fake"
 	schedulerframework "k8s.io/kubernetes/pkg/scheduler/framework"
 
@@ -861,6 +868,60 @@ func TestNoCreateNodeGroupMaxCoresLimitHit(t *testing.T) {
 	simpleNoScaleUpTest(t, config, results)
 }
 
+func TestScaleUpTenantQuotaHit(t *testing.T) {
+	options := defaultOptions
+	options.TenantQuotas = &config.TenantQuotas{Quotas: map[string]apiv1.ResourceList{
+		"default": {apiv1.ResourceCPU: k8sresource.MustParse("3")},
+	}}
+	config := &ScaleUpTestConfig{
+		Nodes: []NodeConfig{
+			{Name: "n1", Cpu: 2000, Memory: 100, Gpu: 0, Ready: true, Group: "ng1"},
+			{Name: "n2", Cpu: 4000, Memory: 1000, Gpu: 0, Ready: true, Group: "ng2"},
+		},
+		Pods: []PodConfig{},
+		ExtraPods: []PodConfig{
+			{Name: "p-new-1", Cpu: 2000, Memory: 0, Gpu: 0, Node: "", ToleratesGpu: false},
+			{Name: "p-new-2", Cpu: 2000, Memory: 0, Gpu: 0, Node: "", ToleratesGpu: false},
+		},
+		ExpansionOptionToChoose: &GroupSizeChange{GroupName: "ng1", SizeChange: 1},
+		Options:                 &options,
+	}
+	results := &ScaleTestResults{
+		FinalOption: GroupSizeChange{GroupName: "ng1", SizeChange: 1},
+		ScaleUpStatus: ScaleUpStatusInfo{
+			PodsTriggeredScaleUp:    []string{"p-new-1"},
+			PodsRemainUnschedulable: []string{"p-new-2"},
+		},
+	}
+
+	simpleScaleUpTest(t, config, results)
+}
+
+func TestNoScaleUpTenantQuotaHit(t *testing.T) {
+	options := defaultOptions
+	options.TenantQuotas = &config.TenantQuotas{Quotas: map[string]apiv1.ResourceList{
+		"default": {apiv1.ResourceCPU: k8sresource.MustParse("1")},
+	}}
+	config := &ScaleUpTestConfig{
+		Nodes: []NodeConfig{
+			{Name: "n1", Cpu: 2000, Memory: 100, Gpu: 0, Ready: true, Group: "ng1"},
+		},
+		Pods: []PodConfig{},
+		ExtraPods: []PodConfig{
+			{Name: "p-new-1", Cpu: 2000, Memory: 0, Gpu: 0, Node: "", ToleratesGpu: false},
+		},
+		Options: &options,
+	}
+	results := &ScaleTestResults{
+		NoScaleUpReason: `cpu quota of tenant "default" exceeded`,
+		ScaleUpStatus: ScaleUpStatusInfo{
+			PodsRemainUnschedulable: []string{"p-new-1"},
+		},
+	}
+
+	simpleNoScaleUpTest(t, config, results)
+}
+
 func TestAllOrNothing(t *testing.T) {
 	options := defaultOptions
 
@@ -1413,7 +1474,7 @@ func TestComputeSimilarNodeGroups(t *testing.T) {
 			assert.NoError(t, clusterState.UpdateNodes(nodes, nodeInfos, time.Now()))
 
 			suOrchestrator := &ScaleUpOrchestrator{}
-			suOrchestrator.Initialize(&ctx, &processors.AutoscalingProcessors{NodeGroupSetProcessor: nodeGroupSetProcessor}, clusterState, newEstimatorBuilder(), taints.TaintConfig{})
+			suOrchestrator.Initialize(&ctx, &processors.AutoscalingProcessors{NodeGroupSetProcessor: nodeGroupSetProcessor, NodeGroupConfigProcessor: nodegroupconfig.NewDefaultNodeGroupConfigProcessor(ctx.NodeGroupDefaults)}, clusterState, newEstimatorBuilder(), taints.TaintConfig{})
 			similarNodeGroups := suOrchestrator.ComputeSimilarNodeGroups(provider.GetNodeGroup(tc.nodeGroup), nodeInfos, tc.schedulablePodGroups, now)
 
 			var gotSimilarNodeGroups []string
@@ -1488,6 +1549,10 @@ func TestScaleUpBalanceGroups(t *testing.T) {
 
 	assert.NoError(t, typedErr)
 	assert.True(t, scaleUpStatus.WasSuccessful())
+	assert.NotEmpty(t, scaleUpStatus.ExpansionOptions)
+	if assert.NotNil(t, scaleUpStatus.BestOption) {
+		assert.Contains(t, scaleUpStatus.ExpansionOptions, *scaleUpStatus.BestOption)
+	}
 	groupMap := make(map[string]cloudprovider.NodeGroup, 3)
 	for _, group := range provider.NodeGroups() {
 		groupMap[group.Id()] = group
@@ -1553,6 +1618,69 @@ func TestScaleUpAutoprovisionedNodeGroup(t *testing.T) {
 	assert.Equal(t, "autoprovisioned-T1-1", utils.GetStringFromChan(expandedGroups))
 }
 
+func TestScaleUpAutoprovisionedNodeGroupDryRun(t *testing.T) {
+	createdGroups := make(chan string, 10)
+	expandedGroups := make(chan string, 10)
+
+	p1 := BuildTestPod("p1", 80, 0)
+
+	fakeClient := &fake.Clientset{}
+
+	t1 := BuildTestNode("t1", 4000, 1000000)
+	SetNodeReadyState(t1, true, time.Time{})
+	ti1 := schedulerframework.NewNodeInfo()
+	ti1.SetNode(t1)
+
+	provider := testprovider.NewTestAutoprovisioningCloudProvider(
+		func(nodeGroup string, increase int) error {
+			expandedGroups <- fmt.Sprintf("%s-%d", nodeGroup, increase)
+			return nil
+		}, nil, func(nodeGroup string) error {
+			createdGroups <- nodeGroup
+			return nil
+		}, nil, []string{"T1"}, map[string]*schedulerframework.NodeInfo{"T1": ti1})
+
+	options := config.AutoscalingOptions{
+		EstimatorName:                    estimator.BinpackingEstimatorName,
+		MaxCoresTotal:                    5000 * 64,
+		MaxMemoryTotal:                   5000 * 64 * 20,
+		NodeAutoprovisioningEnabled:      true,
+		MaxAutoprovisionedNodeGroupCount: 10,
+		DryRun:                           true,
+	}
+	podLister := kube_util.NewTestPodLister([]*apiv1.Pod{})
+	listers := kube_util.NewListerRegistry(nil, nil, podLister, nil, nil, nil, nil, nil, nil)
+	context, err := NewScaleTestAutoscalingContext(options, fakeClient, listers, provider, nil, nil)
+	assert.NoError(t, err)
+	context.DryRunRecorder = dryrun.NewRecorder(context.LogRecorder)
+
+	clusterState := clusterstate.NewClusterStateRegistry(provider, clusterstate.ClusterStateRegistryConfig{}, context.LogRecorder, NewBackoff(), nodegroupconfig.NewDefaultNodeGroupConfigProcessor(config.NodeGroupAutoscalingOptions{MaxNodeProvisionTime: 15 * time.Minute}))
+
+	processors := NewTestProcessors(&context)
+	processors.NodeGroupListProcessor = &MockAutoprovisioningNodeGroupListProcessor{T: t}
+	processors.NodeGroupManager = &MockAutoprovisioningNodeGroupManager{T: t, ExtraGroups: 0}
+
+	nodes := []*apiv1.Node{}
+	nodeInfos, _ := nodeinfosprovider.NewDefaultTemplateNodeInfoProvider(nil, false).Process(&context, nodes, []*appsv1.DaemonSet{}, taints.TaintConfig{}, time.Now())
+
+	suOrchestrator := New()
+	suOrchestrator.Initialize(&context, processors, clusterState, newEstimatorBuilder(), taints.TaintConfig{})
+	scaleUpStatus, err := suOrchestrator.ScaleUp([]*apiv1.Pod{p1}, nodes, []*appsv1.DaemonSet{}, nodeInfos, false)
+	assert.NoError(t, err)
+	assert.True(t, scaleUpStatus.WasSuccessful())
+	assert.Equal(t, utils.NothingReturned, utils.GetStringFromChan(createdGroups))
+	assert.Equal(t, utils.NothingReturned, utils.GetStringFromChan(expandedGroups))
+
+	actions := context.DryRunRecorder.Actions()
+	if assert.Len(t, actions, 2) {
+		assert.Equal(t, api.DryRunCreateNodeGroup, actions[0].Type)
+		assert.Equal(t, api.DryRunIncreaseSize, actions[1].Type)
+		assert.Equal(t, actions[0].NodeGroup, actions[1].NodeGroup)
+		assert.Equal(t, 1, actions[1].Delta)
+		assert.False(t, clusterState.IsNodeGroupScalingUp(actions[1].NodeGroup))
+	}
+}
+
 func TestScaleUpBalanceAutoprovisionedNodeGroups(t *testing.T) {
 	createdGroups := make(chan string, 10)
 	expandedGroups := make(chan string, 10)
@@ -1664,6 +1792,106 @@ func TestScaleUpToMeetNodeGroupMinSize(t *testing.T) {
 	assert.Equal(t, "ng1", scaleUpStatus.ScaleUpInfos[0].Group.Id())
 }
 
+func TestScaleUpToMeetNodeGroupMinSizeFrozen(t *testing.T) {
+	podLister := kube_util.NewTestPodLister([]*apiv1.Pod{})
+	listers := kube_util.NewListerRegistry(nil, nil, podLister, nil, nil, nil, nil, nil, nil)
+	provider := testprovider.NewTestCloudProvider(func(nodeGroup string, increase int) error {
+		t.Fatalf("Unexpected scale-up of %s by %d", nodeGroup, increase)
+		return nil
+	}, nil)
+
+	// ng1: current size 1, min size 3, frozen by a maintenance window => no scale up.
+	n1 := BuildTestNode("n1", 16000, 32)
+	SetNodeReadyState(n1, true, time.Now())
+	provider.AddNodeGroup("ng1", 3, 10, 1)
+	provider.AddNode("ng1", n1)
+
+	windowsFile := filepath.Join(t.TempDir(), "windows.yaml")
+	windows := `
+windows:
+  - name: freeze
+    schedule: "daily 00:00-00:00"
+    nodeGroups: ["^ng1$"]
+    freeze: All
+`
+	assert.NoError(t, os.WriteFile(windowsFile, []byte(windows), 0644))
+
+	options := config.AutoscalingOptions{
+		EstimatorName:  estimator.BinpackingEstimatorName,
+		MaxCoresTotal:  config.DefaultMaxClusterCores,
+		MaxMemoryTotal: config.DefaultMaxClusterMemory,
+	}
+	context, err := NewScaleTestAutoscalingContext(options, &fake.Clientset{}, listers, provider, nil, nil)
+	assert.NoError(t, err)
+
+	nodes := []*apiv1.Node{n1}
+	nodeInfos, _ := nodeinfosprovider.NewDefaultTemplateNodeInfoProvider(nil, false).Process(&context, nodes, []*appsv1.DaemonSet{}, taints.TaintConfig{}, time.Now())
+	processors := NewTestProcessors(&context)
+	processors.MaintenanceWindows = maintenance.NewTracker(maintenance.NewFileSource(windowsFile))
+	processors.MaintenanceWindows.Refresh(&context, time.Now())
+	clusterState := clusterstate.NewClusterStateRegistry(provider, clusterstate.ClusterStateRegistryConfig{}, context.LogRecorder, NewBackoff(), nodegroupconfig.NewDefaultNodeGroupConfigProcessor(config.NodeGroupAutoscalingOptions{MaxNodeProvisionTime: 15 * time.Minute}))
+	clusterState.UpdateNodes(nodes, nodeInfos, time.Now())
+
+	suOrchestrator := New()
+	suOrchestrator.Initialize(&context, processors, clusterState, newEstimatorBuilder(), taints.TaintConfig{})
+	scaleUpStatus, err := suOrchestrator.ScaleUpToNodeGroupMinSize(nodes, nodeInfos)
+	assert.NoError(t, err)
+	assert.False(t, scaleUpStatus.WasSuccessful())
+	assert.Empty(t, scaleUpStatus.ScaleUpInfos)
+}
+
+func TestScaleUpToMeetScheduledMinSize(t *testing.T) {
+	podLister := kube_util.NewTestPodLister([]*apiv1.Pod{})
+	listers := kube_util.NewListerRegistry(nil, nil, podLister, nil, nil, nil, nil, nil, nil)
+	provider := testprovider.NewTestCloudProvider(func(nodeGroup string, increase int) error {
+		assert.Equal(t, "ng1", nodeGroup)
+		assert.Equal(t, 2, increase)
+		return nil
+	}, nil)
+	resourceLimiter := cloudprovider.NewResourceLimiter(
+		map[string]int64{cloudprovider.ResourceNameCores: 0, cloudprovider.ResourceNameMemory: 0},
+		map[string]int64{cloudprovider.ResourceNameCores: 48, cloudprovider.ResourceNameMemory: 1000},
+	)
+	provider.SetResourceLimiter(resourceLimiter)
+
+	// ng1: current size 1, min size 1, always active schedule with min size 3 => scale up with 2 new nodes.
+	// ng2: current size 1, min size 1, no schedule => no scale up.
+	n1 := BuildTestNode("n1", 8000, 32)
+	SetNodeReadyState(n1, true, time.Now())
+	n2 := BuildTestNode("n2", 8000, 32)
+	SetNodeReadyState(n2, true, time.Now())
+	provider.AddNodeGroup("ng1", 1, 10, 1)
+	provider.AddNode("ng1", n1)
+	provider.AddNodeGroup("ng2", 1, 10, 1)
+	provider.AddNode("ng2", n2)
+	schedules, err := config.ParseMinSizeSchedules("daily 00:00-00:00 min=3")
+	assert.NoError(t, err)
+	provider.GetNodeGroup("ng1").(*testprovider.TestNodeGroup).SetOptions(&config.NodeGroupAutoscalingOptions{MinSizeSchedules: schedules})
+
+	options := config.AutoscalingOptions{
+		EstimatorName:  estimator.BinpackingEstimatorName,
+		MaxCoresTotal:  config.DefaultMaxClusterCores,
+		MaxMemoryTotal: config.DefaultMaxClusterMemory,
+	}
+	context, err := NewScaleTestAutoscalingContext(options, &fake.Clientset{}, listers, provider, nil, nil)
+	assert.NoError(t, err)
+
+	nodes := []*apiv1.Node{n1, n2}
+	nodeInfos, _ := nodeinfosprovider.NewDefaultTemplateNodeInfoProvider(nil, false).Process(&context, nodes, []*appsv1.DaemonSet{}, taints.TaintConfig{}, time.Now())
+	processors := NewTestProcessors(&context)
+	clusterState := clusterstate.NewClusterStateRegistry(provider, clusterstate.ClusterStateRegistryConfig{}, context.LogRecorder, NewBackoff(), nodegroupconfig.NewDefaultNodeGroupConfigProcessor(config.NodeGroupAutoscalingOptions{MaxNodeProvisionTime: 15 * time.Minute}))
+	clusterState.UpdateNodes(nodes, nodeInfos, time.Now())
+
+	suOrchestrator := New()
+	suOrchestrator.Initialize(&context, processors, clusterState, newEstimatorBuilder(), taints.TaintConfig{})
+	scaleUpStatus, err := suOrchestrator.ScaleUpToNodeGroupMinSize(nodes, nodeInfos)
+	assert.NoError(t, err)
+	assert.True(t, scaleUpStatus.WasSuccessful())
+	assert.Equal(t, 1, len(scaleUpStatus.ScaleUpInfos))
+	assert.Equal(t, 3, scaleUpStatus.ScaleUpInfos[0].NewSize)
+	assert.Equal(t, "ng1", scaleUpStatus.ScaleUpInfos[0].Group.Id())
+}
+
 func TestCheckDeltaWithinLimits(t *testing.T) {
 	type testcase struct {
 		limits            resource.Limits
@@ -1713,6 +1941,62 @@ func TestCheckDeltaWithinLimits(t *testing.T) {
 	}
 }
 
+func TestSchedulingReasons(t *testing.T) {
+	testCases := []struct {
+		name        string
+		err         *predicatechecker.PredicateError
+		wantVolumes bool
+		wantReasons []string
+	}{
+		{
+			name:        "volume node affinity conflict",
+			err:         predicatechecker.NewPredicateError(predicatechecker.NotSchedulablePredicateError, "VolumeBinding", "", []string{"node(s) had volume node affinity conflict"}, nil),
+			wantVolumes: true,
+			wantReasons: []string{"can't satisfy volumes of the pod: node(s) had volume node affinity conflict"},
+		},
+		{
+			name:        "volume node affinity conflict with other reasons",
+			err:         predicatechecker.NewPredicateError(predicatechecker.NotSchedulablePredicateError, "VolumeBinding", "", []string{"node(s) did not have enough free storage", "node(s) had volume node affinity conflict"}, nil),
+			wantVolumes: true,
+			wantReasons: []string{"can't satisfy volumes of the pod: node(s) had volume node affinity conflict"},
+		},
+		{
+			name:        "volume zone conflict",
+			err:         predicatechecker.NewPredicateError(predicatechecker.NotSchedulablePredicateError, "VolumeZone", "", []string{"node(s) had no available volume zone"}, nil),
+			wantVolumes: true,
+			wantReasons: []string{"can't satisfy volumes of the pod: node(s) had no available volume zone"},
+		},
+		{
+			name:        "not enough storage capacity",
+			err:         predicatechecker.NewPredicateError(predicatechecker.NotSchedulablePredicateError, "VolumeBinding", "", []string{"node(s) did not have enough free storage"}, nil),
+			wantReasons: []string{"node(s) did not have enough free storage"},
+		},
+		{
+			name:        "attachable volume limit exceeded",
+			err:         predicatechecker.NewPredicateError(predicatechecker.NotSchedulablePredicateError, "NodeVolumeLimits", "", []string{"node(s) exceed max volume count"}, nil),
+			wantReasons: []string{"node(s) exceed max volume count"},
+		},
+		{
+			name:        "internal volume binding error",
+			err:         predicatechecker.NewPredicateError(predicatechecker.InternalPredicateError, "VolumeBinding", "", []string{"failed to list PersistentVolumes"}, nil),
+			wantReasons: []string{"failed to list PersistentVolumes"},
+		},
+		{
+			name:        "other predicate",
+			err:         predicatechecker.NewPredicateError(predicatechecker.NotSchedulablePredicateError, "NodeResourcesFit", "", []string{"Insufficient cpu"}, nil),
+			wantReasons: []string{"Insufficient cpu"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reasons := schedulingReasons(tc.err)
+			_, isVolumes := reasons.(*UnsatisfiableVolumes)
+			assert.Equal(t, tc.wantVolumes, isVolumes)
+			assert.Equal(t, tc.wantReasons, reasons.Reasons())
+		})
+	}
+}
+
 func TestAuthErrorHandling(t *testing.T) {
 	metrics.RegisterAll(false)
 	config := &ScaleUpTestConfig{
@@ -16,6 +16,13 @@ limitations under the License.
 
 package orchestrator
 
+import (
+	"fmt"
+	"strings"
+
+	apiv1 "k8s.io/api/core/v1"
+)
+
 // RejectedReasons contains information why given node group was rejected as a scale-up option.
 type RejectedReasons struct {
 	messages []string
@@ -35,3 +42,38 @@ var (
 	// AllOrNothingReason means the node group was rejected because not all pods would fit it when using all-or-nothing strategy.
 	AllOrNothingReason = NewRejectedReasons("not all pods would fit and scale-up is using all-or-nothing strategy")
 )
+
+// TenantQuotaExceeded contains information why pods were rejected from scale-up by the quota of their tenant.
+type TenantQuotaExceeded struct {
+	messages  []string
+	tenant    string
+	resources []apiv1.ResourceName
+}
+
+// NewTenantQuotaExceeded creates new TenantQuotaExceeded object.
+func NewTenantQuotaExceeded(tenant string, resources []apiv1.ResourceName) *TenantQuotaExceeded {
+	names := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		names = append(names, string(resource))
+	}
+	return &TenantQuotaExceeded{
+		messages:  []string{fmt.Sprintf("%s quota of tenant %q exceeded", strings.Join(names, ", "), tenant)},
+		tenant:    tenant,
+		resources: resources,
+	}
+}
+
+// Reasons returns a slice of reasons why the node group was not considered for scale up.
+func (sr *TenantQuotaExceeded) Reasons() []string {
+	return sr.messages
+}
+
+// Tenant returns the tenant whose quota was exceeded.
+func (sr *TenantQuotaExceeded) Tenant() string {
+	return sr.tenant
+}
+
+// Resources returns a slice of resources whose quota was exceeded.
+func (sr *TenantQuotaExceeded) Resources() []apiv1.ResourceName {
+	return sr.resources
+}
@@ -68,3 +68,30 @@ func NewMaxResourceLimitReached(resources []string) *MaxResourceLimitReached {
 		resources: resources,
 	}
 }
+
+// UnsatisfiableVolumes contains information why given node group was skipped for a pod:
+// its nodes can never satisfy volumes of the pod because of node affinity of the pod's
+// PersistentVolumes or zones of its volumes.
+type UnsatisfiableVolumes struct {
+	messages      []string
+	predicateName string
+}
+
+// Reasons returns a slice of reasons why the node group was not considered for scale up.
+func (sr *UnsatisfiableVolumes) Reasons() []string {
+	return sr.messages
+}
+
+// PredicateName returns the name of the volume predicate which failed.
+func (sr *UnsatisfiableVolumes) PredicateName() string {
+	return sr.predicateName
+}
+
+// NewUnsatisfiableVolumes returns a reason describing why volumes of a pod can't be satisfied
+// by nodes of a node group, based on reasons of the failed volume predicate.
+func NewUnsatisfiableVolumes(predicateName string, reasons []string) *UnsatisfiableVolumes {
+	return &UnsatisfiableVolumes{
+		messages:      []string{fmt.Sprintf("can't satisfy volumes of the pod: %s", strings.Join(reasons, ", "))},
+		predicateName: predicateName,
+	}
+}
@@ -50,3 +50,14 @@ func TestMaxResourceLimitReached(t *testing.T) {
 		})
 	}
 }
+
+func TestUnsatisfiableVolumes(t *testing.T) {
+	reason := NewUnsatisfiableVolumes("VolumeBinding", []string{"node(s) had volume node affinity conflict", "node(s) did not have enough free storage"})
+	wantReasons := []string{"can't satisfy volumes of the pod: node(s) had volume node affinity conflict, node(s) did not have enough free storage"}
+	if !reflect.DeepEqual(reason.Reasons(), wantReasons) {
+		t.Errorf("UnsatisfiableVolumes.Reasons() = %v, want %v", reason.Reasons(), wantReasons)
+	}
+	if reason.PredicateName() != "VolumeBinding" {
+		t.Errorf("UnsatisfiableVolumes.PredicateName() = %v, want VolumeBinding", reason.PredicateName())
+	}
+}
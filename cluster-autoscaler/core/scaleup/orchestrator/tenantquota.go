@@ -0,0 +1,161 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orchestrator
+
+import (
+	"sort"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"k8s.io/autoscaler/cluster-autoscaler/core/scaleup/equivalence"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/klog/v2"
+	resourcehelper "k8s.io/kubernetes/pkg/api/v1/resource"
+)
+
+// applyTenantQuotas returns the unschedulable pods which scale-up can make room
+// for within the quotas of their tenants, and the pods rejected by the quotas.
+// The rejected pods remain unschedulable and the rejection is reported in their
+// NotTriggerScaleUp event.
+func (o *ScaleUpOrchestrator) applyTenantQuotas(unschedulablePods []*apiv1.Pod) ([]*apiv1.Pod, map[string]*tenantQuotaRejection, error) {
+	quotas := o.autoscalingContext.TenantQuotas
+	if quotas == nil {
+		return unschedulablePods, nil, nil
+	}
+	nodeInfos, err := o.autoscalingContext.ClusterSnapshot.NodeInfos().List()
+	if err != nil {
+		return nil, nil, err
+	}
+	var scheduledPods []*apiv1.Pod
+	for _, nodeInfo := range nodeInfos {
+		for _, podInfo := range nodeInfo.Pods {
+			scheduledPods = append(scheduledPods, podInfo.Pod)
+		}
+	}
+	allowedPods, rejections := filterByTenantQuotas(quotas, scheduledPods, unschedulablePods)
+	return allowedPods, rejections, nil
+}
+
+// filterByTenantQuotas splits pending pods into the ones which fit in the
+// quotas of their tenants, on top of the requests of scheduled pods, and the
+// ones which don't, grouped by the reason. Pods with higher priority are
+// admitted first.
+func filterByTenantQuotas(quotas *config.TenantQuotas, scheduledPods, pendingPods []*apiv1.Pod) ([]*apiv1.Pod, map[string]*tenantQuotaRejection) {
+	usage := map[string]apiv1.ResourceList{}
+	for _, pod := range scheduledPods {
+		tenant := quotas.Tenant(pod)
+		if _, limited := quotas.Quota(tenant); limited {
+			usage[tenant] = addRequests(usage[tenant], pod)
+		}
+	}
+
+	sorted := make([]*apiv1.Pod, len(pendingPods))
+	copy(sorted, pendingPods)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return corev1helpers.PodPriority(sorted[i]) > corev1helpers.PodPriority(sorted[j])
+	})
+
+	admitted := make(map[*apiv1.Pod]bool, len(pendingPods))
+	rejections := map[string]*tenantQuotaRejection{}
+	for _, pod := range sorted {
+		tenant := quotas.Tenant(pod)
+		quota, limited := quotas.Quota(tenant)
+		if !limited {
+			admitted[pod] = true
+			continue
+		}
+		newUsage := addRequests(usage[tenant], pod)
+		if exceeded := exceededResources(quota, newUsage); len(exceeded) > 0 {
+			reason := NewTenantQuotaExceeded(tenant, exceeded)
+			key := reason.Reasons()[0]
+			if rejections[key] == nil {
+				rejections[key] = &tenantQuotaRejection{reason: reason}
+			}
+			rejections[key].pods = append(rejections[key].pods, pod)
+			continue
+		}
+		usage[tenant] = newUsage
+		admitted[pod] = true
+	}
+
+	// Keep the original order of admitted pods.
+	var allowed []*apiv1.Pod
+	for _, pod := range pendingPods {
+		if admitted[pod] {
+			allowed = append(allowed, pod)
+		}
+	}
+	return allowed, rejections
+}
+
+type tenantQuotaRejection struct {
+	reason *TenantQuotaExceeded
+	pods   []*apiv1.Pod
+}
+
+// addRequests returns the sum of the usage and the requests of the pod. Each
+// pod also uses one "pods" resource.
+func addRequests(usage apiv1.ResourceList, pod *apiv1.Pod) apiv1.ResourceList {
+	sum := usage.DeepCopy()
+	if sum == nil {
+		sum = apiv1.ResourceList{}
+	}
+	for name, value := range resourcehelper.PodRequests(pod, resourcehelper.PodResourcesOptions{}) {
+		total := sum[name]
+		total.Add(value)
+		sum[name] = total
+	}
+	pods := sum[apiv1.ResourcePods]
+	pods.Add(*resource.NewQuantity(1, resource.DecimalSI))
+	sum[apiv1.ResourcePods] = pods
+	return sum
+}
+
+func exceededResources(quota, usage apiv1.ResourceList) []apiv1.ResourceName {
+	var exceeded []apiv1.ResourceName
+	for name, limit := range quota {
+		if value, found := usage[name]; found && value.Cmp(limit) > 0 {
+			exceeded = append(exceeded, name)
+		}
+	}
+	sort.Slice(exceeded, func(i, j int) bool { return exceeded[i] < exceeded[j] })
+	return exceeded
+}
+
+// tenantQuotaExceededGroups builds equivalence groups of the pods rejected by
+// tenant quotas, rejected by all node groups with the reason.
+func tenantQuotaExceededGroups(rejections map[string]*tenantQuotaRejection, nodeGroups []cloudprovider.NodeGroup) []*equivalence.PodGroup {
+	keys := make([]string, 0, len(rejections))
+	for key := range rejections {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var groups []*equivalence.PodGroup
+	for _, key := range keys {
+		rejection := rejections[key]
+		klog.V(1).Infof("%d pods not considered for scale-up: %s", len(rejection.pods), rejection.reason.Reasons()[0])
+		for _, eg := range equivalence.BuildPodGroups(rejection.pods) {
+			for _, nodeGroup := range nodeGroups {
+				eg.SchedulingErrors[nodeGroup.Id()] = rejection.reason
+			}
+			groups = append(groups, eg)
+		}
+	}
+	return groups
+}
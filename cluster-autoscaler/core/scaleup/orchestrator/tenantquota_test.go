@@ -0,0 +1,142 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orchestrator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	k8sresource "k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+)
+
+func buildTenantPod(name, namespace, team string, cpu int64, priority int32) *apiv1.Pod {
+	pod := BuildTestPod(name, cpu, 0)
+	pod.Namespace = namespace
+	if team != "" {
+		pod.Labels = map[string]string{"team": team}
+	}
+	pod.Spec.Priority = &priority
+	return pod
+}
+
+func podNames(pods []*apiv1.Pod) []string {
+	var names []string
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+	}
+	return names
+}
+
+func TestFilterByTenantQuotas(t *testing.T) {
+	cpuQuota := func(cpu string) apiv1.ResourceList {
+		return apiv1.ResourceList{apiv1.ResourceCPU: k8sresource.MustParse(cpu)}
+	}
+	testCases := []struct {
+		name          string
+		quotas        *config.TenantQuotas
+		scheduledPods []*apiv1.Pod
+		pendingPods   []*apiv1.Pod
+		wantAllowed   []string
+		wantRejected  map[string][]string
+	}{
+		{
+			name:   "usage of scheduled pods counts",
+			quotas: &config.TenantQuotas{Quotas: map[string]apiv1.ResourceList{"a": cpuQuota("3")}},
+			scheduledPods: []*apiv1.Pod{
+				buildTenantPod("s1", "a", "", 1000, 0),
+				buildTenantPod("s2", "b", "", 5000, 0),
+			},
+			pendingPods: []*apiv1.Pod{
+				buildTenantPod("p1", "a", "", 1000, 0),
+				buildTenantPod("p2", "a", "", 1500, 0),
+				buildTenantPod("p3", "a", "", 1000, 0),
+				buildTenantPod("p4", "b", "", 5000, 0),
+			},
+			wantAllowed:  []string{"p1", "p3", "p4"},
+			wantRejected: map[string][]string{`cpu quota of tenant "a" exceeded`: {"p2"}},
+		},
+		{
+			name:   "higher priority admitted first",
+			quotas: &config.TenantQuotas{Quotas: map[string]apiv1.ResourceList{"a": cpuQuota("2")}},
+			pendingPods: []*apiv1.Pod{
+				buildTenantPod("p1", "a", "", 2000, 0),
+				buildTenantPod("p2", "a", "", 2000, 100),
+			},
+			wantAllowed:  []string{"p2"},
+			wantRejected: map[string][]string{`cpu quota of tenant "a" exceeded`: {"p1"}},
+		},
+		{
+			name: "tenant label and default quota",
+			quotas: &config.TenantQuotas{
+				TenantLabel:  "team",
+				Quotas:       map[string]apiv1.ResourceList{"a": cpuQuota("10")},
+				DefaultQuota: apiv1.ResourceList{apiv1.ResourcePods: k8sresource.MustParse("1")},
+			},
+			pendingPods: []*apiv1.Pod{
+				buildTenantPod("p1", "x", "a", 5000, 0),
+				buildTenantPod("p2", "y", "a", 5000, 0),
+				buildTenantPod("p3", "x", "b", 100, 0),
+				buildTenantPod("p4", "x", "b", 100, 0),
+				buildTenantPod("p5", "x", "", 100, 0),
+			},
+			wantAllowed: []string{"p1", "p2", "p3", "p5"},
+			wantRejected: map[string][]string{
+				`pods quota of tenant "b" exceeded`: {"p4"},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			allowed, rejections := filterByTenantQuotas(tc.quotas, tc.scheduledPods, tc.pendingPods)
+			assert.Equal(t, tc.wantAllowed, podNames(allowed))
+			rejected := map[string][]string{}
+			for key, rejection := range rejections {
+				assert.Equal(t, key, rejection.reason.Reasons()[0])
+				rejected[key] = podNames(rejection.pods)
+			}
+			assert.Equal(t, tc.wantRejected, rejected)
+		})
+	}
+}
+
+func TestTenantQuotaExceededGroups(t *testing.T) {
+	quota := apiv1.ResourceList{apiv1.ResourceCPU: k8sresource.MustParse("1")}
+	quotas := &config.TenantQuotas{Quotas: map[string]apiv1.ResourceList{"a": quota, "b": quota, "c": quota}}
+	pendingPods := []*apiv1.Pod{
+		buildTenantPod("p1", "c", "", 2000, 0),
+		buildTenantPod("p2", "a", "", 2000, 0),
+		buildTenantPod("p3", "b", "", 2000, 0),
+	}
+	_, rejections := filterByTenantQuotas(quotas, nil, pendingPods)
+	ng := testprovider.NewTestNodeGroup("ng1", 10, 0, 1, true, false, "", nil, nil)
+
+	groups := tenantQuotaExceededGroups(rejections, []cloudprovider.NodeGroup{ng})
+	var reasons []string
+	for _, group := range groups {
+		reasons = append(reasons, group.SchedulingErrors["ng1"].Reasons()...)
+	}
+	assert.Equal(t, []string{
+		`cpu quota of tenant "a" exceeded`,
+		`cpu quota of tenant "b" exceeded`,
+		`cpu quota of tenant "c" exceeded`,
+	}, reasons)
+}
@@ -36,11 +36,17 @@ import (
 
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
 	"k8s.io/autoscaler/cluster-autoscaler/clusterstate"
+	"k8s.io/autoscaler/cluster-autoscaler/clusterstate/statestore"
 	"k8s.io/autoscaler/cluster-autoscaler/clusterstate/utils"
 	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"k8s.io/autoscaler/cluster-autoscaler/config/reload"
 	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/core/auditlog"
+	"k8s.io/autoscaler/cluster-autoscaler/core/dryrun"
 	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown"
 	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown/actuation"
+	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown/budgets"
+	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown/consolidation"
 	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown/deletiontracker"
 	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown/legacy"
 	core_utils "k8s.io/autoscaler/cluster-autoscaler/core/utils"
@@ -49,10 +55,13 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/metrics"
 	"k8s.io/autoscaler/cluster-autoscaler/observers/loopstart"
 	ca_processors "k8s.io/autoscaler/cluster-autoscaler/processors"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/maintenance"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroupconfig"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/status"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/drainability/rules"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/dynamicresources"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/options"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/predicatechecker"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/backoff"
@@ -89,12 +98,19 @@ type StaticAutoscaler struct {
 	lastScaleDownFailTime   time.Time
 	scaleDownPlanner        scaledown.Planner
 	scaleDownActuator       scaledown.Actuator
+	budgetProcessor         *budgets.ScaleDownBudgetProcessor
+	consolidator            *consolidation.Consolidator
+	rebalancer              *consolidation.Rebalancer
 	scaleUpOrchestrator     scaleup.Orchestrator
 	processors              *ca_processors.AutoscalingProcessors
 	loopStartNotifier       *loopstart.ObserversList
 	processorCallbacks      *staticAutoscalerProcessorCallbacks
 	initialized             bool
 	taintConfig             taints.TaintConfig
+	statePersister          *clusterstate.StatePersister
+	optionsReloader         *reload.Reloader
+	auditLogger             *auditlog.Logger
+	dynamicResources        *dynamicresources.Provider
 }
 
 type staticAutoscalerProcessorCallbacks struct {
@@ -147,11 +163,17 @@ func NewStaticAutoscaler(
 	remainingPdbTracker pdb.RemainingPdbTracker,
 	scaleUpOrchestrator scaleup.Orchestrator,
 	deleteOptions options.NodeDeleteOptions,
-	drainabilityRules rules.Rules) *StaticAutoscaler {
+	drainabilityRules rules.Rules,
+	stateStore statestore.Store,
+	optionsReloader *reload.Reloader,
+	auditLogger *auditlog.Logger,
+	dynamicResourcesProvider *dynamicresources.Provider,
+	fallbackPricingModel cloudprovider.PricingModel) *StaticAutoscaler {
 
 	clusterStateConfig := clusterstate.ClusterStateRegistryConfig{
 		MaxTotalUnreadyPercentage: opts.MaxTotalUnreadyPercentage,
 		OkTotalUnreadyCount:       opts.OkTotalUnreadyCount,
+		InterruptionTaints:        opts.InterruptionTaints,
 	}
 	clusterStateRegistry := clusterstate.NewClusterStateRegistry(cloudProvider, clusterStateConfig, autoscalingKubeClients.LogRecorder, backoff, processors.NodeGroupConfigProcessor)
 	processorCallbacks := newStaticAutoscalerProcessorCallbacks()
@@ -167,6 +189,10 @@ func NewStaticAutoscaler(
 		remainingPdbTracker,
 		clusterStateRegistry)
 
+	if opts.DryRun {
+		autoscalingContext.DryRunRecorder = dryrun.NewRecorder(autoscalingKubeClients.LogRecorder)
+	}
+
 	taintConfig := taints.NewTaintConfig(opts)
 	processors.ScaleDownCandidatesNotifier.Register(clusterStateRegistry)
 	processors.ScaleStateNotifier.Register(clusterStateRegistry)
@@ -191,11 +217,27 @@ func NewStaticAutoscaler(
 	}
 	processorCallbacks.scaleDownPlanner = scaleDownPlanner
 
+	var consolidator *consolidation.Consolidator
+	if opts.ConsolidationEnabled {
+		consolidator = consolidation.NewConsolidator(autoscalingContext, estimatorBuilder, deleteOptions, drainabilityRules, processors.ScaleStateNotifier, clusterStateRegistry, processors.NodeGroupConfigProcessor, processors.MaintenanceWindows, fallbackPricingModel)
+	}
+	var rebalancer *consolidation.Rebalancer
+	if opts.RebalanceEnabled {
+		// Like the priority expander, the lister never stops.
+		configMapLister := kube_util.NewConfigMapListerForNamespace(autoscalingKubeClients.ClientSet, make(chan struct{}), opts.ConfigNamespace)
+		rebalancer = consolidation.NewRebalancer(autoscalingContext, estimatorBuilder, deleteOptions, drainabilityRules, configMapLister.ConfigMaps(opts.ConfigNamespace), processors.ScaleStateNotifier, clusterStateRegistry, processors.NodeGroupConfigProcessor, actuator.BudgetProcessor(), processors.MaintenanceWindows)
+	}
+
 	if scaleUpOrchestrator == nil {
 		scaleUpOrchestrator = orchestrator.New()
 	}
 	scaleUpOrchestrator.Initialize(autoscalingContext, processors, clusterStateRegistry, estimatorBuilder, taintConfig)
 
+	var statePersister *clusterstate.StatePersister
+	if stateStore != nil {
+		statePersister = clusterstate.NewStatePersister(clusterStateRegistry, stateStore)
+	}
+
 	// Set the initial scale times to be less than the start time so as to
 	// not start in cooldown mode.
 	initialScaleTime := time.Now().Add(-time.Hour)
@@ -206,13 +248,48 @@ func NewStaticAutoscaler(
 		lastScaleDownFailTime:   initialScaleTime,
 		scaleDownPlanner:        scaleDownPlanner,
 		scaleDownActuator:       scaleDownActuator,
+		budgetProcessor:         actuator.BudgetProcessor(),
+		consolidator:            consolidator,
+		rebalancer:              rebalancer,
 		scaleUpOrchestrator:     scaleUpOrchestrator,
 		processors:              processors,
 		loopStartNotifier:       loopStartNotifier,
 		processorCallbacks:      processorCallbacks,
 		clusterStateRegistry:    clusterStateRegistry,
 		taintConfig:             taintConfig,
+		statePersister:          statePersister,
+		optionsReloader:         optionsReloader,
+		auditLogger:             auditLogger,
+		dynamicResources:        dynamicResourcesProvider,
+	}
+}
+
+// reloadOptions applies changes of the dynamic options before the loop starts.
+// Invalid options are rejected as a whole and the current ones are kept.
+func (a *StaticAutoscaler) reloadOptions() {
+	if a.optionsReloader == nil {
+		return
+	}
+	update, err := a.optionsReloader.Reload(a.AutoscalingOptions)
+	if err != nil {
+		klog.Errorf("Failed to reload dynamic options, keeping the current ones: %v", err)
+		a.AutoscalingContext.LogRecorder.Eventf(apiv1.EventTypeWarning, "DynamicOptionsRejected", "Failed to reload dynamic options: %v", err)
+		metrics.RegisterOptionsReload(metrics.OptionsReloadRejected)
+		return
+	}
+	if update == nil {
+		return
+	}
+	reload.Apply(&a.AutoscalingContext.AutoscalingOptions, update.Options)
+	if update.ExpanderStrategy != nil {
+		a.AutoscalingContext.ExpanderStrategy = update.ExpanderStrategy
 	}
+	for _, change := range update.Changes {
+		klog.Infof("Dynamic option %s changed from %s to %s", change.Option, change.OldValue, change.NewValue)
+		a.AutoscalingContext.LogRecorder.Eventf(apiv1.EventTypeNormal, "DynamicOptionChanged", "Option %s changed from %s to %s", change.Option, change.OldValue, change.NewValue)
+		metrics.RegisterOptionChange(change.Option)
+	}
+	metrics.RegisterOptionsReload(metrics.OptionsReloadApplied)
 }
 
 // LastScaleUpTime returns last scale up time
@@ -237,6 +314,11 @@ func (a *StaticAutoscaler) cleanUpIfRequired() {
 	if a.initialized {
 		return
 	}
+	// Taints left in dry-run mode belong to the Cluster Autoscaler which actually scales the cluster.
+	if a.DryRunRecorder != nil {
+		a.initialized = true
+		return
+	}
 
 	// CA can die at any time. Removing taints that might have been left from the previous run.
 	if allNodes, err := a.AllNodeLister().List(); err != nil {
@@ -274,6 +356,17 @@ func (a *StaticAutoscaler) initializeClusterSnapshot(nodes []*apiv1.Node, schedu
 			}
 		}
 	}
+	if a.dynamicResources != nil {
+		// Set after the scheduled pods are added, so that their ResourceClaims
+		// aren't allocated again: the snapshot keeps their allocations and
+		// reservations as they are in the cluster.
+		dynamicResources, err := a.dynamicResources.Snapshot()
+		if err != nil {
+			klog.Errorf("Failed to snapshot dynamic resources: %v", err)
+			return caerrors.ToAutoscalerError(caerrors.ApiCallError, err)
+		}
+		a.ClusterSnapshot.SetDynamicResources(dynamicResources)
+	}
 	return nil
 }
 
@@ -294,6 +387,7 @@ func (a *StaticAutoscaler) initializeRemainingPdbTracker() caerrors.AutoscalerEr
 
 // RunOnce iterates over node groups and scales them up/down if necessary
 func (a *StaticAutoscaler) RunOnce(currentTime time.Time) caerrors.AutoscalerError {
+	a.reloadOptions()
 	a.cleanUpIfRequired()
 	a.processorCallbacks.reset()
 	a.clusterStateRegistry.PeriodicCleanup()
@@ -314,6 +408,10 @@ func (a *StaticAutoscaler) RunOnce(currentTime time.Time) caerrors.AutoscalerErr
 		return typedErr
 	}
 
+	if a.budgetProcessor != nil {
+		a.budgetProcessor.UpdateClusterState(allNodes, currentTime)
+	}
+
 	if abortLoop, err := a.processors.ActionableClusterProcessor.ShouldAbort(
 		a.AutoscalingContext, allNodes, readyNodes, currentTime); abortLoop {
 		return err
@@ -353,6 +451,22 @@ func (a *StaticAutoscaler) RunOnce(currentTime time.Time) caerrors.AutoscalerErr
 	}
 	a.loopStartNotifier.Refresh()
 
+	if a.statePersister != nil {
+		// Restoring is retried every loop until it succeeds, saving is a no-op before that.
+		if err := a.statePersister.Restore(); err != nil {
+			klog.Errorf("Failed to restore persisted cluster state: %v", err)
+		}
+		// In dry-run mode the state is only read, it's persisted by the Cluster Autoscaler
+		// which actually scales the cluster.
+		if a.DryRunRecorder == nil {
+			defer func() {
+				if err := a.statePersister.Save(); err != nil {
+					klog.Errorf("Failed to persist cluster state: %v", err)
+				}
+			}()
+		}
+	}
+
 	// Update node groups min/max and maximum number of nodes being set for all node groups after cloud provider refresh
 	maxNodesCount := 0
 	for _, nodeGroup := range a.AutoscalingContext.CloudProvider.NodeGroups() {
@@ -397,6 +511,15 @@ func (a *StaticAutoscaler) RunOnce(currentTime time.Time) caerrors.AutoscalerErr
 		// Update status information when the loop is done (regardless of reason)
 		if autoscalingContext.WriteStatusConfigMap {
 			status := a.clusterStateRegistry.GetStatus(currentTime)
+			if a.DryRunRecorder != nil {
+				status.DryRunActions = a.DryRunRecorder.Actions()
+			}
+			if a.processors != nil && a.processors.MaintenanceWindows != nil {
+				status.MaintenanceWindows = a.processors.MaintenanceWindows.Status()
+			}
+			if a.DisruptionBudgets != nil && a.budgetProcessor != nil {
+				status.DisruptionBudgets = a.budgetProcessor.DisruptionBudgetsStatus(a.scaleDownActuator.CheckStatus())
+			}
 			utils.WriteStatusConfigMap(autoscalingContext.ClientSet, autoscalingContext.ConfigNamespace,
 				*status, a.AutoscalingContext.LogRecorder, a.AutoscalingContext.StatusConfigMapName, currentTime)
 		}
@@ -417,6 +540,10 @@ func (a *StaticAutoscaler) RunOnce(currentTime time.Time) caerrors.AutoscalerErr
 			a.processors.ScaleDownStatusProcessor.Process(a.AutoscalingContext, scaleDownStatus)
 		}
 
+		if a.auditLogger != nil {
+			a.auditLogger.Log(auditlog.NewRecord(currentTime, scaleUpStatus, scaleDownStatus, a.scaleDownPlanner.UnneededNodes(), a.CloudProvider))
+		}
+
 		if a.processors != nil && a.processors.AutoscalingStatusProcessor != nil {
 			err := a.processors.AutoscalingStatusProcessor.Process(a.AutoscalingContext, a.clusterStateRegistry, currentTime)
 			if err != nil {
@@ -453,7 +580,7 @@ func (a *StaticAutoscaler) RunOnce(currentTime time.Time) caerrors.AutoscalerErr
 	// Check if there has been a constant difference between the number of nodes in k8s and
 	// the number of nodes on the cloud provider side.
 	// TODO: andrewskim - add protection for ready AWS nodes.
-	fixedSomething, err := fixNodeGroupSize(autoscalingContext, a.clusterStateRegistry, currentTime)
+	fixedSomething, err := fixNodeGroupSize(autoscalingContext, a.clusterStateRegistry, a.processors.MaintenanceWindows, currentTime)
 	if err != nil {
 		klog.Errorf("Failed to fix node group sizes: %v", err)
 		return caerrors.ToAutoscalerError(caerrors.CloudProviderError, err)
@@ -605,6 +732,13 @@ func (a *StaticAutoscaler) RunOnce(currentTime time.Time) caerrors.AutoscalerErr
 			}
 		}
 
+		if a.consolidator != nil {
+			scaleDownCandidates = a.consolidator.FilterOutInProgress(scaleDownCandidates)
+		}
+		if a.rebalancer != nil {
+			scaleDownCandidates = a.rebalancer.FilterOutInProgress(scaleDownCandidates)
+		}
+
 		typedErr := a.scaleDownPlanner.UpdateClusterState(podDestinations, scaleDownCandidates, scaleDownActuationStatus, currentTime)
 		// Update clusterStateRegistry and metrics regardless of whether ScaleDown was successful or not.
 		unneededNodes := a.scaleDownPlanner.UnneededNodes()
@@ -628,7 +762,7 @@ func (a *StaticAutoscaler) RunOnce(currentTime time.Time) caerrors.AutoscalerErr
 		// in progress.
 		_, drained := scaleDownActuationStatus.DeletionsInProgress()
 		var removedNodeGroups []cloudprovider.NodeGroup
-		if len(drained) == 0 {
+		if len(drained) == 0 && a.DryRunRecorder == nil {
 			var err error
 			removedNodeGroups, err = a.processors.NodeGroupManager.RemoveUnneededNodeGroups(autoscalingContext)
 			if err != nil {
@@ -653,6 +787,15 @@ func (a *StaticAutoscaler) RunOnce(currentTime time.Time) caerrors.AutoscalerErr
 
 			scaleDownStatus.RemovedNodeGroups = removedNodeGroups
 
+			if typedErr == nil &&
+				(scaleDownStatus.Result == scaledownstatus.ScaleDownNoNodeDeleted ||
+					scaleDownStatus.Result == scaledownstatus.ScaleDownNoUnneeded) {
+				if replacedNodes := a.replaceNodes(scaleDownCandidates, nodeInfosForGroups, currentTime); len(replacedNodes) > 0 {
+					scaleDownStatus.Result = scaledownstatus.ScaleDownNodeDeleteStarted
+					scaleDownStatus.ScaledDownNodes = append(scaleDownStatus.ScaledDownNodes, replacedNodes...)
+				}
+			}
+
 			if scaleDownStatus.Result == scaledownstatus.ScaleDownNodeDeleteStarted {
 				a.lastScaleDownDeleteTime = currentTime
 				a.clusterStateRegistry.Recalculate()
@@ -692,6 +835,28 @@ func (a *StaticAutoscaler) RunOnce(currentTime time.Time) caerrors.AutoscalerErr
 	return nil
 }
 
+// replaceNodes runs rebalancing and consolidation, only one of which replaces
+// nodes at a time, and returns the nodes whose deletion started.
+func (a *StaticAutoscaler) replaceNodes(candidates []*apiv1.Node, nodeInfos map[string]*schedulerframework.NodeInfo, currentTime time.Time) []*scaledownstatus.ScaleDownNode {
+	if a.rebalancer != nil && (a.consolidator == nil || a.consolidator.InProgress() == nil) {
+		nodes, err := a.rebalancer.RunOnce(candidates, nodeInfos, a.scaleDownActuator, currentTime)
+		if err != nil {
+			klog.Errorf("Failed to rebalance nodes: %v", err)
+		}
+		if len(nodes) > 0 || a.rebalancer.InProgress() != nil {
+			return nodes
+		}
+	}
+	if a.consolidator != nil && (a.rebalancer == nil || a.rebalancer.InProgress() == nil) {
+		nodes, err := a.consolidator.RunOnce(candidates, nodeInfos, a.scaleDownActuator, currentTime)
+		if err != nil {
+			klog.Errorf("Failed to consolidate nodes: %v", err)
+		}
+		return nodes
+	}
+	return nil
+}
+
 func (a *StaticAutoscaler) isScaleDownInCooldown(currentTime time.Time, scaleDownCandidates []*apiv1.Node) bool {
 	scaleDownInCooldown := a.processorCallbacks.disableScaleDownForLoop || len(scaleDownCandidates) == 0
 
@@ -705,15 +870,19 @@ func (a *StaticAutoscaler) isScaleDownInCooldown(currentTime time.Time, scaleDow
 }
 
 // Sets the target size of node groups to the current number of nodes in them
-// if the difference was constant for a prolonged time. Returns true if managed
-// to fix something.
-func fixNodeGroupSize(context *context.AutoscalingContext, clusterStateRegistry *clusterstate.ClusterStateRegistry, currentTime time.Time) (bool, error) {
+// if the difference was constant for a prolonged time. Node groups frozen by a
+// maintenance window are left alone. Returns true if managed to fix something.
+func fixNodeGroupSize(context *context.AutoscalingContext, clusterStateRegistry *clusterstate.ClusterStateRegistry, maintenanceWindows *maintenance.Tracker, currentTime time.Time) (bool, error) {
 	fixed := false
 	for _, nodeGroup := range context.CloudProvider.NodeGroups() {
 		incorrectSize := clusterStateRegistry.GetIncorrectNodeGroupSize(nodeGroup.Id())
 		if incorrectSize == nil {
 			continue
 		}
+		if maintenanceWindows.Frozen(nodeGroup.Id()) {
+			klog.V(1).Infof("Not fixing the size of %s, it is frozen by a maintenance window", nodeGroup.Id())
+			continue
+		}
 		maxNodeProvisionTime, err := clusterStateRegistry.MaxNodeProvisionTime(nodeGroup)
 		if err != nil {
 			return false, fmt.Errorf("failed to retrieve maxNodeProvisionTime for nodeGroup %s", nodeGroup.Id())
@@ -725,6 +894,11 @@ func fixNodeGroupSize(context *context.AutoscalingContext, clusterStateRegistry
 					incorrectSize.ExpectedSize,
 					incorrectSize.CurrentSize,
 					delta)
+				if context.DryRunRecorder != nil {
+					// Nothing changes in dry-run mode, so there is no need to skip the iteration.
+					context.DryRunRecorder.RecordDecreaseTargetSize(nodeGroup.Id(), delta, dryrun.IncorrectSizeReason)
+					continue
+				}
 				if err := nodeGroup.DecreaseTargetSize(delta); err != nil {
 					return fixed, fmt.Errorf("failed to decrease %s: %v", nodeGroup.Id(), err)
 				}
@@ -766,6 +940,10 @@ func (a *StaticAutoscaler) removeOldUnregisteredNodes(allUnregisteredNodes []clu
 	removedAny := false
 	for nodeGroupId, unregisteredNodesToDelete := range nodesToDeleteByNodeGroupId {
 		nodeGroup := nodeGroups[nodeGroupId]
+		if a.processors.MaintenanceWindows.Frozen(nodeGroupId) {
+			klog.V(1).Infof("Not removing %v unregistered nodes from node group %v, it is frozen by a maintenance window", len(unregisteredNodesToDelete), nodeGroupId)
+			continue
+		}
 
 		klog.V(0).Infof("Removing %v unregistered nodes for node group %v", len(unregisteredNodesToDelete), nodeGroupId)
 		size, err := nodeGroup.TargetSize()
@@ -784,12 +962,17 @@ func (a *StaticAutoscaler) removeOldUnregisteredNodes(allUnregisteredNodes []clu
 		}
 		nodesToDelete := toNodes(unregisteredNodesToDelete)
 
-		nodesToDelete, err = overrideNodesToDeleteForZeroOrMax(a.NodeGroupDefaults, nodeGroup, nodesToDelete)
+		nodesToDelete, err = overrideNodesToDeleteForZeroOrMax(a.processors.NodeGroupConfigProcessor, nodeGroup, nodesToDelete)
 		if err != nil {
 			klog.Warningf("Failed to remove unregistered nodes from node group %s: %v", nodeGroupId, err)
 			continue
 		}
 
+		if context.DryRunRecorder != nil {
+			context.DryRunRecorder.RecordDeleteNodes(nodeGroupId, nodesToDelete, nil, dryrun.UnregisteredReason)
+			continue
+		}
+
 		err = nodeGroup.DeleteNodes(nodesToDelete)
 		csr.InvalidateNodeInstancesCacheEntry(nodeGroup)
 		if err != nil {
@@ -827,13 +1010,21 @@ func (a *StaticAutoscaler) deleteCreatedNodesWithErrors() {
 	deletedAny := false
 
 	for nodeGroupId, nodesToDelete := range nodesToDeleteByNodeGroupId {
+		if a.processors.MaintenanceWindows.Frozen(nodeGroupId) {
+			klog.V(1).Infof("Not deleting %v from %v node group because of create errors, it is frozen by a maintenance window", len(nodesToDelete), nodeGroupId)
+			continue
+		}
 		var err error
 		klog.V(1).Infof("Deleting %v from %v node group because of create errors", len(nodesToDelete), nodeGroupId)
 
 		nodeGroup := nodeGroups[nodeGroupId]
 		if nodeGroup == nil {
 			err = fmt.Errorf("node group %s not found", nodeGroupId)
-		} else if nodesToDelete, err = overrideNodesToDeleteForZeroOrMax(a.NodeGroupDefaults, nodeGroup, nodesToDelete); err == nil {
+		} else if nodesToDelete, err = overrideNodesToDeleteForZeroOrMax(a.processors.NodeGroupConfigProcessor, nodeGroup, nodesToDelete); err == nil {
+			if a.DryRunRecorder != nil {
+				a.DryRunRecorder.RecordDeleteNodes(nodeGroupId, nodesToDelete, nil, dryrun.CreateErrorReason)
+				continue
+			}
 			err = nodeGroup.DeleteNodes(nodesToDelete)
 		}
 
@@ -854,14 +1045,14 @@ func (a *StaticAutoscaler) deleteCreatedNodesWithErrors() {
 // overrideNodesToDeleteForZeroOrMax returns a list of nodes to delete, taking into account that
 // node deletion for a "ZeroOrMaxNodeScaling" node group is atomic and should delete all nodes.
 // For a non-"ZeroOrMaxNodeScaling" node group it returns the unchanged list of nodes to delete.
-func overrideNodesToDeleteForZeroOrMax(defaults config.NodeGroupAutoscalingOptions, nodeGroup cloudprovider.NodeGroup, nodesToDelete []*apiv1.Node) ([]*apiv1.Node, error) {
-	opts, err := nodeGroup.GetOptions(defaults)
-	if err != nil && err != cloudprovider.ErrNotImplemented {
+func overrideNodesToDeleteForZeroOrMax(configGetter nodegroupconfig.NodeGroupConfigProcessor, nodeGroup cloudprovider.NodeGroup, nodesToDelete []*apiv1.Node) ([]*apiv1.Node, error) {
+	zeroOrMaxNodeScaling, err := configGetter.GetZeroOrMaxNodeScaling(nodeGroup)
+	if err != nil {
 		return []*apiv1.Node{}, fmt.Errorf("Failed to get node group options for %s: %s", nodeGroup.Id(), err)
 	}
 	// If a scale-up of "ZeroOrMaxNodeScaling" node group failed, the cleanup
 	// should stick to the all-or-nothing principle. Deleting all nodes.
-	if opts != nil && opts.ZeroOrMaxNodeScaling {
+	if zeroOrMaxNodeScaling {
 		instances, err := nodeGroup.Nodes()
 		if err != nil {
 			return []*apiv1.Node{}, fmt.Errorf("Failed to fill in nodes to delete from group %s based on ZeroOrMaxNodeScaling option: %s", nodeGroup.Id(), err)
@@ -925,6 +1116,9 @@ func (a *StaticAutoscaler) filterOutYoungPods(allUnschedulablePods []*apiv1.Pod,
 func (a *StaticAutoscaler) ExitCleanUp() {
 	a.processors.CleanUp()
 	a.DebuggingSnapshotter.Cleanup()
+	if a.auditLogger != nil {
+		a.auditLogger.Stop()
+	}
 
 	if !a.AutoscalingContext.WriteStatusConfigMap {
 		return
@@ -1007,16 +1201,18 @@ func getUpcomingNodeInfos(upcomingCounts map[string]int, nodeInfos map[string]*s
 			continue
 		}
 
-		if nodeTemplate.Node().Annotations == nil {
-			nodeTemplate.Node().Annotations = make(map[string]string)
-		}
-		nodeTemplate.Node().Annotations[NodeUpcomingAnnotation] = "true"
-
 		for i := 0; i < numberOfNodes; i++ {
 			// Ensure new nodes have different names because nodeName
 			// will be used as a map key. Also deep copy pods (daemonsets &
 			// any pods added by cloud provider on template).
-			upcomingNodes = append(upcomingNodes, scheduler_utils.DeepCopyTemplateNode(nodeTemplate, fmt.Sprintf("upcoming-%d", i)))
+			upcomingNode := scheduler_utils.DeepCopyTemplateNode(nodeTemplate, fmt.Sprintf("upcoming-%d", i))
+			// The annotation is set on the copies only, so that the templates,
+			// also captured by the debugging snapshot, aren't marked as upcoming.
+			if upcomingNode.Node().Annotations == nil {
+				upcomingNode.Node().Annotations = make(map[string]string)
+			}
+			upcomingNode.Node().Annotations[NodeUpcomingAnnotation] = "true"
+			upcomingNodes = append(upcomingNodes, upcomingNode)
 		}
 	}
 	return upcomingNodes
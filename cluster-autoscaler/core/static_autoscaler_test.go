@@ -2185,12 +2185,12 @@ func TestRemoveFixNodeTargetSize(t *testing.T) {
 	assert.NoError(t, err)
 
 	// Nothing should be fixed. The incorrect size state is not old enough.
-	removed, err := fixNodeGroupSize(context, clusterState, now.Add(-50*time.Minute))
+	removed, err := fixNodeGroupSize(context, clusterState, nil, now.Add(-50*time.Minute))
 	assert.NoError(t, err)
 	assert.False(t, removed)
 
 	// Node group should be decreased.
-	removed, err = fixNodeGroupSize(context, clusterState, now)
+	removed, err = fixNodeGroupSize(context, clusterState, nil, now)
 	assert.NoError(t, err)
 	assert.True(t, removed)
 	change := core_utils.GetStringFromChan(sizeChanges)
@@ -2363,6 +2363,19 @@ func TestSubtractNodes(t *testing.T) {
 	}
 }
 
+func TestGetUpcomingNodeInfos(t *testing.T) {
+	template := schedulerframework.NewNodeInfo()
+	template.SetNode(BuildTestNode("template", 1000, 1000))
+	nodeInfos := map[string]*schedulerframework.NodeInfo{"ng1": template}
+
+	upcoming := getUpcomingNodeInfos(map[string]int{"ng1": 2, "ng2": 1}, nodeInfos)
+	assert.Len(t, upcoming, 2)
+	for _, nodeInfo := range upcoming {
+		assert.Equal(t, "true", nodeInfo.Node().Annotations[NodeUpcomingAnnotation])
+	}
+	assert.NotContains(t, template.Node().Annotations, NodeUpcomingAnnotation)
+}
+
 func TestFilterOutYoungPods(t *testing.T) {
 	now := time.Now()
 	klog.InitFlags(nil)
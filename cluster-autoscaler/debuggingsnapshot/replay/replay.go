@@ -0,0 +1,371 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/pricing"
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/clusterstate"
+	"k8s.io/autoscaler/cluster-autoscaler/clusterstate/utils"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/core/podlistprocessor"
+	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown/actuation"
+	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown/deletiontracker"
+	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown/pdb"
+	"k8s.io/autoscaler/cluster-autoscaler/core/scaleup/orchestrator"
+	"k8s.io/autoscaler/cluster-autoscaler/debuggingsnapshot"
+	"k8s.io/autoscaler/cluster-autoscaler/estimator"
+	"k8s.io/autoscaler/cluster-autoscaler/expander/factory"
+	ca_processors "k8s.io/autoscaler/cluster-autoscaler/processors"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/drainability/rules"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/options"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/predicatechecker"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/scheduling"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/backoff"
+	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/taints"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	kube_record "k8s.io/client-go/tools/record"
+	schedulerframework "k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// upcomingNodeAnnotation is core.NodeUpcomingAnnotation, which StaticAutoscaler
+// sets on the placeholders it adds to the cluster snapshot for nodes that are
+// still being provisioned. The debugging snapshot captures them together with
+// the registered nodes. It's copied to keep cloud providers out of the replay.
+const upcomingNodeAnnotation = "cluster-autoscaler.k8s.io/upcoming-node"
+
+// DefaultNodeGroupMaxSize is the max size assumed for node groups recreated
+// from a debugging snapshot, as the snapshot doesn't capture node group limits.
+const DefaultNodeGroupMaxSize = 1000
+
+// Options configure how a debugging snapshot is replayed.
+type Options struct {
+	config.AutoscalingOptions
+	// NodeGroupMaxSize is the max size of every node group recreated from the snapshot.
+	NodeGroupMaxSize int
+	// ExtraPendingPods are pods added to the pending pods recorded in the snapshot,
+	// allowing to ask "what if these pods were pending as well".
+	ExtraPendingPods []*apiv1.Pod
+	// PriorityConfigMap, if set, is served to the priority expander.
+	PriorityConfigMap *apiv1.ConfigMap
+}
+
+// Replayer recreates the cluster captured in a debugging snapshot on top of an
+// in-memory cloud provider and runs autoscaling logic against it, without
+// talking to a real cluster or cloud.
+type Replayer struct {
+	context      *context.AutoscalingContext
+	clusterState *clusterstate.ClusterStateRegistry
+	orchestrator *orchestrator.ScaleUpOrchestrator
+	provider     *testprovider.TestCloudProvider
+	processors   *ca_processors.AutoscalingProcessors
+	options      Options
+
+	deleteOptions     options.NodeDeleteOptions
+	drainabilityRules rules.Rules
+
+	// nodes are the registered nodes captured in the snapshot.
+	nodes []*apiv1.Node
+	// upcomingNodes are the placeholders for nodes that were still being provisioned.
+	upcomingNodes []*apiv1.Node
+	podsByNode    map[string][]*apiv1.Pod
+	templates     map[string]*schedulerframework.NodeInfo
+	pendingPods   []*apiv1.Pod
+	nodeGroups    map[string]string
+	now           time.Time
+}
+
+// LoadSnapshot reads a debugging snapshot, as returned by the /snapshotz endpoint, from a file.
+func LoadSnapshot(path string) (*debuggingsnapshot.DebuggingSnapshotImpl, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read debugging snapshot %s: %v", path, err)
+	}
+	snapshot := &debuggingsnapshot.DebuggingSnapshotImpl{}
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode debugging snapshot %s: %v", path, err)
+	}
+	if snapshot.Error != "" {
+		return nil, fmt.Errorf("debugging snapshot %s was captured with an error: %s", path, snapshot.Error)
+	}
+	return snapshot, nil
+}
+
+// NewReplayer builds a Replayer for the given snapshot. Every template node in the
+// snapshot becomes a node group and every captured node is assigned to the node
+// group whose template it matches.
+func NewReplayer(snapshot *debuggingsnapshot.DebuggingSnapshotImpl, opts Options) (*Replayer, error) {
+	if len(snapshot.TemplateNodes) == 0 {
+		return nil, fmt.Errorf("debugging snapshot doesn't contain any template nodes")
+	}
+	if opts.NodeGroupMaxSize <= 0 {
+		opts.NodeGroupMaxSize = DefaultNodeGroupMaxSize
+	}
+
+	r := &Replayer{
+		options:    opts,
+		podsByNode: make(map[string][]*apiv1.Pod),
+		templates:  make(map[string]*schedulerframework.NodeInfo),
+		nodeGroups: make(map[string]string),
+		now:        snapshot.StartTimestamp,
+	}
+	if r.now.IsZero() {
+		r.now = time.Now()
+	}
+
+	for id, template := range snapshot.TemplateNodes {
+		if template == nil || template.Node == nil {
+			return nil, fmt.Errorf("template node for node group %s is empty", id)
+		}
+		nodeInfo := schedulerframework.NewNodeInfo(template.Pods...)
+		nodeInfo.SetNode(template.Node)
+		r.templates[id] = nodeInfo
+	}
+
+	for _, clusterNode := range snapshot.NodeList {
+		if clusterNode == nil || clusterNode.Node == nil {
+			continue
+		}
+		node := clusterNode.Node
+		if _, found := node.Annotations[upcomingNodeAnnotation]; found {
+			r.upcomingNodes = append(r.upcomingNodes, node)
+		} else {
+			r.nodes = append(r.nodes, node)
+		}
+		r.podsByNode[node.Name] = clusterNode.Pods
+		if id := matchingNodeGroup(node, r.templates); id != "" {
+			r.nodeGroups[node.Name] = id
+		}
+	}
+
+	r.pendingPods = append(r.pendingPods, snapshot.UnscheduledPodsCanBeScheduled...)
+	r.pendingPods = append(r.pendingPods, opts.ExtraPendingPods...)
+
+	if err := r.buildAutoscaler(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Replayer) buildAutoscaler() error {
+	opts := r.options.AutoscalingOptions
+
+	r.provider = testprovider.NewTestCloudProvider(
+		func(string, int) error { return nil },
+		func(string, string) error { return nil })
+	r.provider.SetResourceLimiter(context.NewResourceLimiterFromAutoscalingOptions(opts))
+	for _, id := range sortedKeys(r.templates) {
+		r.provider.AddNodeGroup(id, 0, r.options.NodeGroupMaxSize, 0)
+	}
+	for _, node := range append(append([]*apiv1.Node{}, r.nodes...), r.upcomingNodes...) {
+		id, found := r.nodeGroups[node.Name]
+		if !found {
+			continue
+		}
+		r.provider.AddNode(id, node)
+		ng := r.provider.GetNodeGroup(id).(*testprovider.TestNodeGroup)
+		size, _ := ng.TargetSize()
+		ng.SetTargetSize(size + 1)
+	}
+
+	var objects []runtime.Object
+	if r.options.PriorityConfigMap != nil {
+		objects = append(objects, r.options.PriorityConfigMap)
+	}
+	kubeClient := fake.NewSimpleClientset(objects...)
+	informerFactory := informers.NewSharedInformerFactory(kubeClient, 0)
+	// Events are dropped, the replay only reports decisions.
+	recorder := &kube_record.FakeRecorder{}
+	logRecorder, err := utils.NewStatusMapRecorder(kubeClient, opts.ConfigNamespace, recorder, false, opts.StatusConfigMapName)
+	if err != nil {
+		return err
+	}
+	kubeClients := &context.AutoscalingKubeClients{
+		ListerRegistry: kube_util.NewListerRegistryWithDefaultListers(informerFactory),
+		ClientSet:      kubeClient,
+		Recorder:       recorder,
+		LogRecorder:    logRecorder,
+	}
+	predicateChecker, err := predicatechecker.NewSchedulerBasedPredicateChecker(informerFactory, opts.SchedulerConfig)
+	if err != nil {
+		return err
+	}
+
+	// The test provider doesn't know any prices, the price expander can only
+	// be used with a price table.
+	var expanderProvider cloudprovider.CloudProvider = r.provider
+	if opts.PricingConfigFile != "" {
+		priceTable, err := pricing.LoadPriceTable(opts.PricingConfigFile)
+		if err != nil {
+			return err
+		}
+		expanderProvider = pricing.WithFallbackPricing(r.provider, priceTable)
+	}
+	nodeGroupBackoff := backoff.NewIdBasedExponentialBackoff(opts.InitialNodeGroupBackoffDuration, opts.MaxNodeGroupBackoffDuration, opts.NodeGroupBackoffResetTimeout)
+	expanderFactory := factory.NewFactory()
+	expanderFactory.RegisterDefaultExpanders(expanderProvider, kubeClients, kubeClient, opts.ConfigNamespace, opts.GRPCExpanderCert, opts.GRPCExpanderURL, nodeGroupBackoff, opts.ExpanderWeights)
+	expanderStrategy, err := expanderFactory.Build(strings.Split(opts.ExpanderNames, ","))
+	if err != nil {
+		return err
+	}
+	thresholds := []estimator.Threshold{
+		estimator.NewStaticThreshold(opts.MaxNodesPerScaleUp, opts.MaxNodeGroupBinpackingDuration),
+		estimator.NewSngCapacityThreshold(),
+		estimator.NewClusterCapacityThreshold(),
+	}
+	estimatorBuilder, err := estimator.NewEstimatorBuilder(
+		opts.EstimatorName,
+		estimator.NewThresholdBasedEstimationLimiter(thresholds),
+		estimator.NewDecreasingPodOrderer(),
+		/* EstimationAnalyserFunc */ nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	r.processors = ca_processors.DefaultProcessors(opts)
+	r.processors.PodListProcessor = podlistprocessor.NewDefaultPodListProcessor(predicateChecker, scheduling.ScheduleAnywhere)
+	clusterStateConfig := clusterstate.ClusterStateRegistryConfig{
+		MaxTotalUnreadyPercentage: opts.MaxTotalUnreadyPercentage,
+		OkTotalUnreadyCount:       opts.OkTotalUnreadyCount,
+		InterruptionTaints:        opts.InterruptionTaints,
+	}
+	r.clusterState = clusterstate.NewClusterStateRegistry(r.provider, clusterStateConfig, logRecorder, nodeGroupBackoff, r.processors.NodeGroupConfigProcessor)
+	r.context = context.NewAutoscalingContext(
+		opts,
+		predicateChecker,
+		clustersnapshot.NewBasicClusterSnapshot(),
+		kubeClients,
+		r.provider,
+		expanderStrategy,
+		&processorCallbacks{},
+		debuggingsnapshot.NewDebuggingSnapshotter(false),
+		pdb.NewBasicRemainingPdbTracker(),
+		r.clusterState)
+
+	r.deleteOptions = options.NewNodeDeleteOptions(opts)
+	r.drainabilityRules = rules.Default(r.deleteOptions)
+	ndt := deletiontracker.NewNodeDeletionTracker(0 * time.Second)
+	r.context.ScaleDownActuator = actuation.NewActuator(r.context, r.processors.ScaleStateNotifier, ndt, r.deleteOptions, r.drainabilityRules, r.processors.NodeGroupConfigProcessor)
+
+	r.orchestrator = orchestrator.New()
+	r.orchestrator.Initialize(r.context, r.processors, r.clusterState, estimatorBuilder, taints.NewTaintConfig(opts))
+
+	stop := make(chan struct{})
+	informerFactory.Start(stop)
+	informerFactory.WaitForCacheSync(stop)
+	return nil
+}
+
+// initializeClusterState loads the captured nodes and pods into the cluster
+// snapshot and the cluster state registry, the same way a single iteration of
+// the main loop would.
+func (r *Replayer) initializeClusterState() error {
+	snapshot := r.context.ClusterSnapshot
+	snapshot.Clear()
+	for _, node := range append(append([]*apiv1.Node{}, r.nodes...), r.upcomingNodes...) {
+		if err := snapshot.AddNodeWithPods(node, r.podsByNode[node.Name]); err != nil {
+			return fmt.Errorf("failed to add node %s to cluster snapshot: %v", node.Name, err)
+		}
+	}
+	if err := r.clusterState.UpdateNodes(r.nodes, r.templates, r.now); err != nil {
+		return fmt.Errorf("failed to update cluster state: %v", err)
+	}
+	return nil
+}
+
+func (r *Replayer) readyNodes() []*apiv1.Node {
+	var ready []*apiv1.Node
+	for _, node := range r.nodes {
+		if kube_util.IsNodeReadyAndSchedulable(node) {
+			ready = append(ready, node)
+		}
+	}
+	return ready
+}
+
+// matchingNodeGroup returns the id of the node group whose template node matches
+// the given node best, or an empty string if there's no such node group. A
+// template matches a node if all of the template labels, apart from the
+// hostname, are present on the node with the same values.
+func matchingNodeGroup(node *apiv1.Node, templates map[string]*schedulerframework.NodeInfo) string {
+	bestId, bestScore := "", -1
+	for _, id := range sortedKeys(templates) {
+		templateLabels := templates[id].Node().Labels
+		score := 0
+		matches := true
+		for key, value := range templateLabels {
+			if key == apiv1.LabelHostname {
+				continue
+			}
+			if node.Labels[key] != value {
+				matches = false
+				break
+			}
+			score++
+		}
+		if matches && score > bestScore {
+			bestId, bestScore = id, score
+		}
+	}
+	return bestId
+}
+
+// processorCallbacks is a no-op implementation of callbacks.ProcessorCallbacks,
+// storing extra values for the duration of the replay.
+type processorCallbacks struct {
+	extraValues map[string]interface{}
+}
+
+func (c *processorCallbacks) DisableScaleDownForLoop() {}
+
+func (c *processorCallbacks) ResetUnneededNodes() {}
+
+func (c *processorCallbacks) SetExtraValue(key string, value interface{}) {
+	if c.extraValues == nil {
+		c.extraValues = make(map[string]interface{})
+	}
+	c.extraValues[key] = value
+}
+
+func (c *processorCallbacks) GetExtraValue(key string) (value interface{}, found bool) {
+	value, found = c.extraValues[key]
+	return
+}
+
+func sortedKeys(templates map[string]*schedulerframework.NodeInfo) []string {
+	keys := make([]string, 0, len(templates))
+	for key := range templates {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
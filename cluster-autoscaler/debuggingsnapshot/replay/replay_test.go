@@ -0,0 +1,267 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"k8s.io/autoscaler/cluster-autoscaler/debuggingsnapshot"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/status"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/drain"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+	schedulerframework "k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+func testOptions() Options {
+	return Options{
+		AutoscalingOptions: config.AutoscalingOptions{
+			ExpanderNames:  "least-waste",
+			EstimatorName:  "binpacking",
+			MaxCoresTotal:  config.DefaultMaxClusterCores,
+			MaxMemoryTotal: config.DefaultMaxClusterMemory,
+			NodeGroupDefaults: config.NodeGroupAutoscalingOptions{
+				ScaleDownUtilizationThreshold: 0.5,
+				ScaleDownUnneededTime:         10 * time.Minute,
+				MaxNodeProvisionTime:          15 * time.Minute,
+			},
+			MaxTotalUnreadyPercentage:     45,
+			OkTotalUnreadyCount:           3,
+			UnremovableNodeRecheckTimeout: 5 * time.Minute,
+			MaxScaleDownParallelism:       10,
+			MaxDrainParallelism:           1,
+			ScaleDownSimulationTimeout:    30 * time.Second,
+		},
+	}
+}
+
+func labeledNode(name string, cpu, mem int64, group string) *apiv1.Node {
+	node := BuildTestNode(name, cpu, mem)
+	node.Labels = map[string]string{
+		"group":             group,
+		apiv1.LabelHostname: name,
+	}
+	SetNodeReadyState(node, true, time.Now().Add(-time.Hour))
+	return node
+}
+
+func buildSnapshot(nodes []*apiv1.Node, scheduled map[string][]*apiv1.Pod, templates []*apiv1.Node, templateIds []string, pending []*apiv1.Pod) *debuggingsnapshot.DebuggingSnapshotImpl {
+	snapshot := &debuggingsnapshot.DebuggingSnapshotImpl{}
+	var nodeInfos []*schedulerframework.NodeInfo
+	for _, node := range nodes {
+		nodeInfo := schedulerframework.NewNodeInfo(scheduled[node.Name]...)
+		nodeInfo.SetNode(node)
+		nodeInfos = append(nodeInfos, nodeInfo)
+	}
+	snapshot.SetClusterNodes(nodeInfos)
+	templateInfos := make(map[string]*schedulerframework.NodeInfo)
+	for i, template := range templates {
+		nodeInfo := schedulerframework.NewNodeInfo()
+		nodeInfo.SetNode(template)
+		templateInfos[templateIds[i]] = nodeInfo
+	}
+	snapshot.SetTemplateNodes(templateInfos)
+	snapshot.SetUnscheduledPodsCanBeScheduled(pending)
+	return snapshot
+}
+
+func TestLoadSnapshot(t *testing.T) {
+	node := labeledNode("n1", 1000, 1000, "ng1")
+	snapshot := buildSnapshot([]*apiv1.Node{node}, nil, []*apiv1.Node{labeledNode("t1", 1000, 1000, "ng1")}, []string{"ng1"}, nil)
+	data, isError := snapshot.GetOutputBytes()
+	assert.False(t, isError)
+
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	assert.NoError(t, os.WriteFile(path, data, 0644))
+	loaded, err := LoadSnapshot(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(loaded.NodeList))
+	assert.Equal(t, "n1", loaded.NodeList[0].Node.Name)
+	assert.Contains(t, loaded.TemplateNodes, "ng1")
+
+	errorSnapshot := &debuggingsnapshot.DebuggingSnapshotImpl{Error: "Unable to collect any data"}
+	data, err = json.Marshal(errorSnapshot)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(path, data, 0644))
+	_, err = LoadSnapshot(path)
+	assert.Error(t, err)
+}
+
+func TestMatchingNodeGroup(t *testing.T) {
+	templates := map[string]*schedulerframework.NodeInfo{}
+	for _, id := range []string{"ng1", "ng2"} {
+		nodeInfo := schedulerframework.NewNodeInfo()
+		nodeInfo.SetNode(labeledNode("template-"+id, 1000, 1000, id))
+		templates[id] = nodeInfo
+	}
+	assert.Equal(t, "ng1", matchingNodeGroup(labeledNode("n1", 1000, 1000, "ng1"), templates))
+	assert.Equal(t, "ng2", matchingNodeGroup(labeledNode("n2", 1000, 1000, "ng2"), templates))
+	assert.Equal(t, "", matchingNodeGroup(labeledNode("n3", 1000, 1000, "ng3"), templates))
+}
+
+func TestReplayScaleUp(t *testing.T) {
+	n1 := labeledNode("n1", 2000, 2000, "ng1")
+	busy := BuildScheduledTestPod("busy", 2000, 1000, "n1")
+	pending := []*apiv1.Pod{
+		BuildTestPod("p1", 1000, 500, MarkUnschedulable()),
+		BuildTestPod("p2", 1000, 500, MarkUnschedulable()),
+		BuildTestPod("p3", 1000, 500, MarkUnschedulable()),
+		BuildTestPod("too-big", 10000, 500, MarkUnschedulable()),
+	}
+	snapshot := buildSnapshot(
+		[]*apiv1.Node{n1},
+		map[string][]*apiv1.Pod{"n1": {busy}},
+		[]*apiv1.Node{labeledNode("t1", 2000, 2000, "ng1"), labeledNode("t2", 1000, 8000, "ng2")},
+		[]string{"ng1", "ng2"},
+		pending)
+
+	replayer, err := NewReplayer(snapshot, testOptions())
+	assert.NoError(t, err)
+	result, err := replayer.ScaleUp()
+	assert.NoError(t, err)
+	assert.Equal(t, status.ScaleUpSuccessful, result.Status)
+	assert.Equal(t, 4, result.PendingPods)
+	assert.Equal(t, 4, result.PodsToHelp)
+	assert.Equal(t, []NodeGroupScaleUp{{NodeGroup: "ng1", CurrentSize: 1, NewSize: 3}}, result.ScaleUps)
+	assert.Equal(t, 3, len(result.PodsTriggeredScaleUp))
+	assert.Equal(t, 1, len(result.PodsRemainUnschedulable))
+	assert.Equal(t, "too-big", result.PodsRemainUnschedulable[0].Pod.Name)
+	assert.Contains(t, result.PodsRemainUnschedulable[0].RejectedNodeGroups, "ng1")
+
+	var out bytes.Buffer
+	result.Print(&out)
+	assert.Contains(t, out.String(), "ng1: 1 -> 3 (+2)")
+}
+
+func TestReplayScaleUpNotNeeded(t *testing.T) {
+	n1 := labeledNode("n1", 2000, 2000, "ng1")
+	snapshot := buildSnapshot(
+		[]*apiv1.Node{n1},
+		nil,
+		[]*apiv1.Node{labeledNode("t1", 2000, 2000, "ng1")},
+		[]string{"ng1"},
+		[]*apiv1.Pod{BuildTestPod("p1", 1000, 500, MarkUnschedulable())})
+
+	replayer, err := NewReplayer(snapshot, testOptions())
+	assert.NoError(t, err)
+	result, err := replayer.ScaleUp()
+	assert.NoError(t, err)
+	assert.Equal(t, status.ScaleUpNotNeeded, result.Status)
+	assert.Equal(t, 0, result.PodsToHelp)
+	assert.Empty(t, result.ScaleUps)
+}
+
+func TestReplayUpcomingNodes(t *testing.T) {
+	n1 := labeledNode("n1", 2000, 2000, "ng1")
+	upcoming := labeledNode("t1-upcoming-0", 2000, 2000, "ng1")
+	upcoming.Annotations = map[string]string{upcomingNodeAnnotation: "true"}
+	snapshot := buildSnapshot(
+		[]*apiv1.Node{n1, upcoming},
+		map[string][]*apiv1.Pod{"n1": {BuildScheduledTestPod("busy", 2000, 1000, "n1")}},
+		[]*apiv1.Node{labeledNode("t1", 2000, 2000, "ng1")},
+		[]string{"ng1"},
+		[]*apiv1.Pod{BuildTestPod("p1", 1000, 500, MarkUnschedulable())})
+
+	replayer, err := NewReplayer(snapshot, testOptions())
+	assert.NoError(t, err)
+	assert.Equal(t, []*apiv1.Node{n1}, replayer.nodes)
+	assert.Equal(t, []*apiv1.Node{upcoming}, replayer.upcomingNodes)
+	result, err := replayer.ScaleUp()
+	assert.NoError(t, err)
+	assert.Equal(t, status.ScaleUpNotNeeded, result.Status)
+	assert.Empty(t, result.ScaleUps)
+}
+
+func TestReplayScaleDown(t *testing.T) {
+	n1 := labeledNode("n1", 2000, 2000, "ng1")
+	n2 := labeledNode("n2", 2000, 2000, "ng1")
+	n3 := labeledNode("n3", 2000, 2000, "ng1")
+	unmanaged := labeledNode("unmanaged", 2000, 2000, "other")
+	snapshot := buildSnapshot(
+		[]*apiv1.Node{n1, n2, n3, unmanaged},
+		map[string][]*apiv1.Pod{
+			"n2": {BuildScheduledTestPod("unreplicated", 100, 100, "n2")},
+			"n3": {BuildScheduledTestPod("busy", 1800, 1000, "n3")},
+		},
+		[]*apiv1.Node{labeledNode("t1", 2000, 2000, "ng1")},
+		[]string{"ng1"},
+		nil)
+
+	replayer, err := NewReplayer(snapshot, testOptions())
+	assert.NoError(t, err)
+	result, err := replayer.ScaleDown()
+	assert.NoError(t, err)
+	assert.Equal(t, 10*time.Minute, result.UnneededTime)
+	assert.Equal(t, 4, len(result.Nodes))
+
+	byName := map[string]NodeScaleDown{}
+	for _, node := range result.Nodes {
+		byName[node.Node] = node
+	}
+	assert.True(t, byName["n1"].Unneeded)
+	assert.True(t, byName["n1"].Removed)
+	assert.False(t, byName["n1"].NeedsDrain)
+	assert.Equal(t, simulator.NoReason, byName["n1"].Reason)
+
+	assert.False(t, byName["n2"].Removed)
+	assert.Equal(t, simulator.BlockedByPod, byName["n2"].Reason)
+	if assert.NotNil(t, byName["n2"].BlockingPod) {
+		assert.Equal(t, "unreplicated", byName["n2"].BlockingPod.Pod.Name)
+		assert.Equal(t, drain.NotReplicated, byName["n2"].BlockingPod.Reason)
+	}
+
+	assert.False(t, byName["n3"].Unneeded)
+	assert.Equal(t, simulator.NotUnderutilized, byName["n3"].Reason)
+
+	assert.Equal(t, "", byName["unmanaged"].NodeGroup)
+	assert.Equal(t, simulator.NotAutoscaled, byName["unmanaged"].Reason)
+
+	var out bytes.Buffer
+	result.Print(&out)
+	assert.Contains(t, out.String(), "n1 (ng1): utilization 0.00, removed (empty)")
+	assert.Contains(t, out.String(), "reason: BlockedByPod, blocked by pod default/unreplicated: NotReplicated")
+}
+
+func TestReplayScaleUpWithPriceTable(t *testing.T) {
+	n1 := labeledNode("n1", 2000, 2000, "ng1")
+	busy := BuildScheduledTestPod("busy", 2000, 1000, "n1")
+	snapshot := buildSnapshot(
+		[]*apiv1.Node{n1},
+		map[string][]*apiv1.Pod{"n1": {busy}},
+		[]*apiv1.Node{labeledNode("t1", 2000, 2000, "ng1"), labeledNode("t2", 2000, 2000, "ng2")},
+		[]string{"ng1", "ng2"},
+		[]*apiv1.Pod{BuildTestPod("p1", 1000, 500, MarkUnschedulable())})
+
+	path := filepath.Join(t.TempDir(), "prices.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte("cpu: 0.1\nmemory: 0.1\nnodeGroupLabel: group\nnodeGroups:\n  ng1: 10\n  ng2: 0.1\n"), 0644))
+	opts := testOptions()
+	opts.ExpanderNames = "price"
+	opts.PricingConfigFile = path
+	replayer, err := NewReplayer(snapshot, opts)
+	assert.NoError(t, err)
+	result, err := replayer.ScaleUp()
+	assert.NoError(t, err)
+	assert.Equal(t, []NodeGroupScaleUp{{NodeGroup: "ng2", CurrentSize: 0, NewSize: 1}}, result.ScaleUps)
+}
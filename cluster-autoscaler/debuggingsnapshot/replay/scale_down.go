@@ -0,0 +1,230 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replay
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown/planner"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/drain"
+)
+
+// NodeScaleDown describes the scale-down verdict for a single captured node.
+type NodeScaleDown struct {
+	Node      string
+	NodeGroup string
+	// Utilization is the utilization computed by the eligibility checker, it's
+	// only set for nodes which got that far.
+	Utilization *float64
+	// Unneeded is true if the node was found unneeded in the replayed loop.
+	Unneeded bool
+	// Removed is true if the node would be removed once it stayed unneeded for
+	// long enough, assuming the cluster doesn't change in the meantime.
+	Removed bool
+	// NeedsDrain is true if the removed node has pods which need to be rescheduled.
+	NeedsDrain bool
+	// Reason is the reason why the node can't be removed, NoReason if it can.
+	Reason simulator.UnremovableReason
+	// BlockingPod is the pod preventing the removal, if Reason is BlockedByPod.
+	BlockingPod *drain.BlockingPod
+}
+
+// ScaleDownResult is the outcome of a replayed scale-down.
+type ScaleDownResult struct {
+	// UnneededTime is how long the nodes were assumed to stay unneeded
+	// before checking whether they would be removed.
+	UnneededTime time.Duration
+	Nodes        []NodeScaleDown
+}
+
+// ScaleDown runs the scale-down planner against the captured cluster and
+// reports, for every node, whether it would be unneeded, whether it would
+// eventually be removed and, if not, why. The planner is run twice: once at
+// the snapshot time to find unneeded nodes, and once after the unneeded time
+// passed to find the nodes that would actually be deleted. No real cloud
+// provider is called.
+func (r *Replayer) ScaleDown() (*ScaleDownResult, error) {
+	if err := r.initializeClusterState(); err != nil {
+		return nil, err
+	}
+	// Pending pods that fit on existing nodes are placed in the cluster snapshot,
+	// the same way the main loop does before looking for unneeded nodes.
+	if _, err := r.processors.PodListProcessor.Process(r.context, r.pendingPods); err != nil {
+		return nil, fmt.Errorf("failed to process pending pods: %v", err)
+	}
+
+	scaleDownCandidates, err := r.processors.ScaleDownNodeProcessor.GetScaleDownCandidates(r.context, r.nodes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scale-down candidates: %v", err)
+	}
+	podDestinations, err := r.processors.ScaleDownNodeProcessor.GetPodDestinationCandidates(r.context, r.nodes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod destination candidates: %v", err)
+	}
+
+	p := planner.New(r.context, r.processors, r.deleteOptions, r.drainabilityRules)
+	actuationStatus := r.context.ScaleDownActuator.CheckStatus()
+	if typedErr := p.UpdateClusterState(podDestinations, scaleDownCandidates, actuationStatus, r.now); typedErr != nil {
+		return nil, fmt.Errorf("failed to find unneeded nodes: %v", typedErr)
+	}
+	unneeded := nodeNameSet(p.UnneededNodes())
+	unremovable := unremovableByName(p.UnremovableNodes())
+	utilization := p.NodeUtilizationMap()
+
+	result := &ScaleDownResult{UnneededTime: r.unneededTime()}
+	removed := map[string]bool{}
+	needsDrain := map[string]bool{}
+	if len(unneeded) > 0 {
+		// Nodes are removed once they were unneeded for strictly longer than the unneeded time.
+		later := r.now.Add(result.UnneededTime + time.Second)
+		if typedErr := p.UpdateClusterState(podDestinations, scaleDownCandidates, actuationStatus, later); typedErr != nil {
+			return nil, fmt.Errorf("failed to find unneeded nodes: %v", typedErr)
+		}
+		empty, drained := p.NodesToDelete(later)
+		for _, node := range empty {
+			removed[node.Name] = true
+		}
+		for _, node := range drained {
+			removed[node.Name] = true
+			needsDrain[node.Name] = true
+		}
+		// Nodes unneeded in the first pass keep the reason found at removal time,
+		// the ones which weren't unneeded keep their original reason.
+		for name, u := range unremovableByName(p.UnremovableNodes()) {
+			if unneeded[name] {
+				unremovable[name] = u
+			}
+		}
+	}
+
+	candidates := nodeNameSet(scaleDownCandidates)
+	for _, node := range r.nodes {
+		verdict := NodeScaleDown{
+			Node:       node.Name,
+			NodeGroup:  r.nodeGroups[node.Name],
+			Unneeded:   unneeded[node.Name],
+			Removed:    removed[node.Name],
+			NeedsDrain: needsDrain[node.Name],
+		}
+		if info, found := utilization[node.Name]; found {
+			verdict.Utilization = &info.Utilization
+		}
+		if u, found := unremovable[node.Name]; found && !verdict.Removed {
+			verdict.Reason = u.Reason
+			verdict.BlockingPod = u.BlockingPod
+		} else if !candidates[node.Name] {
+			// Nodes filtered out by the scale-down node processor never reach the planner.
+			verdict.Reason = simulator.NotUnneededOtherReason
+			if verdict.NodeGroup == "" {
+				verdict.Reason = simulator.NotAutoscaled
+			}
+		}
+		result.Nodes = append(result.Nodes, verdict)
+	}
+	sort.Slice(result.Nodes, func(i, j int) bool { return result.Nodes[i].Node < result.Nodes[j].Node })
+	return result, nil
+}
+
+// unneededTime returns the longest time a node has to be unneeded or unready
+// before it's removed.
+func (r *Replayer) unneededTime() time.Duration {
+	defaults := r.options.NodeGroupDefaults
+	if defaults.ScaleDownUnreadyTime > defaults.ScaleDownUnneededTime {
+		return defaults.ScaleDownUnreadyTime
+	}
+	return defaults.ScaleDownUnneededTime
+}
+
+// Print writes a human readable summary of the result.
+func (s *ScaleDownResult) Print(w io.Writer) {
+	fmt.Fprintf(w, "Nodes: %d, assumed unneeded time: %v\n", len(s.Nodes), s.UnneededTime)
+	for _, node := range s.Nodes {
+		nodeGroup := node.NodeGroup
+		if nodeGroup == "" {
+			nodeGroup = "<none>"
+		}
+		utilization := "-"
+		if node.Utilization != nil {
+			utilization = fmt.Sprintf("%.2f", *node.Utilization)
+		}
+		verdict := "kept"
+		switch {
+		case node.Removed && node.NeedsDrain:
+			verdict = "removed after drain"
+		case node.Removed:
+			verdict = "removed (empty)"
+		case node.Unneeded:
+			verdict = "unneeded but kept"
+		}
+		fmt.Fprintf(w, "  %s (%s): utilization %s, %s", node.Node, nodeGroup, utilization, verdict)
+		if node.Reason != simulator.NoReason {
+			fmt.Fprintf(w, ", reason: %s", unremovableReasonName(node.Reason))
+		}
+		if node.BlockingPod != nil && node.BlockingPod.Pod != nil {
+			fmt.Fprintf(w, ", blocked by pod %s/%s: %v", node.BlockingPod.Pod.Namespace, node.BlockingPod.Pod.Name, node.BlockingPod.Reason)
+		}
+		fmt.Fprintf(w, "\n")
+	}
+}
+
+func nodeNameSet(nodes []*apiv1.Node) map[string]bool {
+	names := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		names[node.Name] = true
+	}
+	return names
+}
+
+func unremovableByName(nodes []*simulator.UnremovableNode) map[string]*simulator.UnremovableNode {
+	byName := make(map[string]*simulator.UnremovableNode, len(nodes))
+	for _, node := range nodes {
+		byName[node.Node.Name] = node
+	}
+	return byName
+}
+
+// unremovableReasonName returns the name of the reason, which is printed by
+// the replay instead of its numeric value.
+func unremovableReasonName(reason simulator.UnremovableReason) string {
+	if name, found := unremovableReasonNames[reason]; found {
+		return name
+	}
+	return fmt.Sprintf("unrecognized reason: %d", int(reason))
+}
+
+var unremovableReasonNames = map[simulator.UnremovableReason]string{
+	simulator.NoReason:                     "NoReason",
+	simulator.ScaleDownDisabledAnnotation:  "ScaleDownDisabledAnnotation",
+	simulator.ScaleDownUnreadyDisabled:     "ScaleDownUnreadyDisabled",
+	simulator.NotAutoscaled:                "NotAutoscaled",
+	simulator.NotUnneededLongEnough:        "NotUnneededLongEnough",
+	simulator.NotUnreadyLongEnough:         "NotUnreadyLongEnough",
+	simulator.NodeGroupMinSizeReached:      "NodeGroupMinSizeReached",
+	simulator.MinimalResourceLimitExceeded: "MinimalResourceLimitExceeded",
+	simulator.CurrentlyBeingDeleted:        "CurrentlyBeingDeleted",
+	simulator.NotUnderutilized:             "NotUnderutilized",
+	simulator.NotUnneededOtherReason:       "NotUnneededOtherReason",
+	simulator.RecentlyUnremovable:          "RecentlyUnremovable",
+	simulator.NoPlaceToMovePods:            "NoPlaceToMovePods",
+	simulator.BlockedByPod:                 "BlockedByPod",
+	simulator.UnexpectedError:              "UnexpectedError",
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replay
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/status"
+)
+
+// NodeGroupScaleUp describes a single node group resize decided by the replayed scale-up.
+type NodeGroupScaleUp struct {
+	NodeGroup   string
+	CurrentSize int
+	NewSize     int
+}
+
+// PodNoScaleUp describes a pending pod that wouldn't trigger a scale-up,
+// along with the reasons reported for every node group.
+type PodNoScaleUp struct {
+	Pod                *apiv1.Pod
+	RejectedNodeGroups map[string][]string
+	SkippedNodeGroups  map[string][]string
+}
+
+// ScaleUpResult is the outcome of a replayed scale-up.
+type ScaleUpResult struct {
+	Status                  status.ScaleUpResult
+	Error                   string
+	PendingPods             int
+	PodsToHelp              int
+	ScaleUps                []NodeGroupScaleUp
+	PodsTriggeredScaleUp    []*apiv1.Pod
+	PodsRemainUnschedulable []PodNoScaleUp
+}
+
+// ScaleUp runs the pod list processors, the scale-up orchestrator and the configured
+// expanders once against the captured cluster and reports which node groups would
+// be resized and by how much. No real cloud provider is called.
+func (r *Replayer) ScaleUp() (*ScaleUpResult, error) {
+	if err := r.initializeClusterState(); err != nil {
+		return nil, err
+	}
+
+	result := &ScaleUpResult{PendingPods: len(r.pendingPods)}
+	podsToHelp, err := r.processors.PodListProcessor.Process(r.context, r.pendingPods)
+	if err != nil {
+		return nil, fmt.Errorf("failed to process pending pods: %v", err)
+	}
+	result.PodsToHelp = len(podsToHelp)
+	if len(podsToHelp) == 0 {
+		result.Status = status.ScaleUpNotNeeded
+		return result, nil
+	}
+
+	scaleUpStatus, typedErr := r.orchestrator.ScaleUp(podsToHelp, r.readyNodes(), nil, r.templates, false)
+	if typedErr != nil {
+		result.Error = typedErr.Error()
+	}
+	if scaleUpStatus == nil {
+		result.Status = status.ScaleUpError
+		return result, nil
+	}
+	result.Status = scaleUpStatus.Result
+	for _, info := range scaleUpStatus.ScaleUpInfos {
+		result.ScaleUps = append(result.ScaleUps, NodeGroupScaleUp{
+			NodeGroup:   info.Group.Id(),
+			CurrentSize: info.CurrentSize,
+			NewSize:     info.NewSize,
+		})
+	}
+	result.PodsTriggeredScaleUp = scaleUpStatus.PodsTriggeredScaleUp
+	for _, noScaleUp := range scaleUpStatus.PodsRemainUnschedulable {
+		result.PodsRemainUnschedulable = append(result.PodsRemainUnschedulable, PodNoScaleUp{
+			Pod:                noScaleUp.Pod,
+			RejectedNodeGroups: reasonsByNodeGroup(noScaleUp.RejectedNodeGroups),
+			SkippedNodeGroups:  reasonsByNodeGroup(noScaleUp.SkippedNodeGroups),
+		})
+	}
+	return result, nil
+}
+
+// Print writes a human readable summary of the result.
+func (s *ScaleUpResult) Print(w io.Writer) {
+	fmt.Fprintf(w, "Pending pods: %d, pods needing new capacity: %d\n", s.PendingPods, s.PodsToHelp)
+	fmt.Fprintf(w, "Scale-up result: %s\n", scaleUpResultName(s.Status))
+	if s.Error != "" {
+		fmt.Fprintf(w, "Scale-up error: %s\n", s.Error)
+	}
+	for _, scaleUp := range s.ScaleUps {
+		fmt.Fprintf(w, "  %s: %d -> %d (+%d)\n", scaleUp.NodeGroup, scaleUp.CurrentSize, scaleUp.NewSize, scaleUp.NewSize-scaleUp.CurrentSize)
+	}
+	if len(s.PodsTriggeredScaleUp) > 0 {
+		fmt.Fprintf(w, "Pods triggering scale-up:\n")
+		for _, pod := range s.PodsTriggeredScaleUp {
+			fmt.Fprintf(w, "  %s/%s\n", pod.Namespace, pod.Name)
+		}
+	}
+	if len(s.PodsRemainUnschedulable) > 0 {
+		fmt.Fprintf(w, "Pods that wouldn't trigger scale-up:\n")
+		for _, noScaleUp := range s.PodsRemainUnschedulable {
+			fmt.Fprintf(w, "  %s/%s\n", noScaleUp.Pod.Namespace, noScaleUp.Pod.Name)
+			printReasons(w, "rejected", noScaleUp.RejectedNodeGroups)
+			printReasons(w, "skipped", noScaleUp.SkippedNodeGroups)
+		}
+	}
+}
+
+func printReasons(w io.Writer, kind string, reasons map[string][]string) {
+	nodeGroups := make([]string, 0, len(reasons))
+	for nodeGroup := range reasons {
+		nodeGroups = append(nodeGroups, nodeGroup)
+	}
+	sort.Strings(nodeGroups)
+	for _, nodeGroup := range nodeGroups {
+		fmt.Fprintf(w, "    %s by %s: %s\n", kind, nodeGroup, strings.Join(reasons[nodeGroup], "; "))
+	}
+}
+
+func reasonsByNodeGroup(reasons map[string]status.Reasons) map[string][]string {
+	if len(reasons) == 0 {
+		return nil
+	}
+	result := make(map[string][]string, len(reasons))
+	for nodeGroup, r := range reasons {
+		if r == nil {
+			continue
+		}
+		result[nodeGroup] = r.Reasons()
+	}
+	return result
+}
+
+func scaleUpResultName(result status.ScaleUpResult) string {
+	switch result {
+	case status.ScaleUpSuccessful:
+		return "ScaleUpSuccessful"
+	case status.ScaleUpError:
+		return "ScaleUpError"
+	case status.ScaleUpNoOptionsAvailable:
+		return "ScaleUpNoOptionsAvailable"
+	case status.ScaleUpNotNeeded:
+		return "ScaleUpNotNeeded"
+	case status.ScaleUpNotTried:
+		return "ScaleUpNotTried"
+	case status.ScaleUpInCooldown:
+		return "ScaleUpInCooldown"
+	}
+	return fmt.Sprintf("ScaleUpResult(%d)", result)
+}
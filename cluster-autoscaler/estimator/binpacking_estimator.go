@@ -215,6 +215,7 @@ func (e *BinpackingNodeEstimator) addNewNodeToSnapshot(
 	for _, podInfo := range newNodeInfo.Pods {
 		pods = append(pods, podInfo.Pod)
 	}
+	e.clusterSnapshot.DynamicResources().CopyResourceSlices(template.Node().Name, newNodeInfo.Node().Name)
 	if err := e.clusterSnapshot.AddNodeWithPods(newNodeInfo.Node(), pods); err != nil {
 		return err
 	}
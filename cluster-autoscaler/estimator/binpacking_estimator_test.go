@@ -17,13 +17,17 @@ limitations under the License.
 package estimator
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
 	apiv1 "k8s.io/api/core/v1"
+	resourceapi "k8s.io/api/resource/v1alpha2"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/dynamicresources"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/predicatechecker"
 	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/units"
@@ -232,6 +236,75 @@ func TestBinpackingEstimate(t *testing.T) {
 	}
 }
 
+func TestBinpackingEstimateDynamicResources(t *testing.T) {
+	structured := true
+	parameters := &resourceapi.ResourceClaimParameters{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "one-gpu"},
+		DriverRequests: []resourceapi.DriverRequests{{
+			DriverName: "gpu.example.com",
+			Requests: []resourceapi.ResourceRequest{{
+				ResourceRequestModel: resourceapi.ResourceRequestModel{
+					NamedResources: &resourceapi.NamedResourcesRequest{Selector: "true"},
+				},
+			}},
+		}},
+	}
+	templateSlice := &resourceapi.ResourceSlice{
+		ObjectMeta: metav1.ObjectMeta{Name: "template-gpus"},
+		NodeName:   "template",
+		DriverName: "gpu.example.com",
+		ResourceModel: resourceapi.ResourceModel{
+			NamedResources: &resourceapi.NamedResourcesResources{
+				Instances: []resourceapi.NamedResourcesInstance{{Name: "gpu-0"}, {Name: "gpu-1"}},
+			},
+		},
+	}
+	class := &resourceapi.ResourceClass{
+		ObjectMeta:           metav1.ObjectMeta{Name: "gpu"},
+		DriverName:           "gpu.example.com",
+		StructuredParameters: &structured,
+	}
+	var claims []*resourceapi.ResourceClaim
+	var pods []*apiv1.Pod
+	for i := 0; i < 5; i++ {
+		claimName := fmt.Sprintf("gpu-%d", i)
+		claims = append(claims, &resourceapi.ResourceClaim{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: claimName, UID: types.UID(claimName)},
+			Spec: resourceapi.ResourceClaimSpec{
+				ResourceClassName: class.Name,
+				ParametersRef: &resourceapi.ResourceClaimParametersReference{
+					APIGroup: resourceapi.GroupName,
+					Kind:     "ResourceClaimParameters",
+					Name:     parameters.Name,
+				},
+			},
+		})
+		pods = append(pods, BuildTestPod(fmt.Sprintf("estimatee-%d", i), 10, 10, func(pod *apiv1.Pod) {
+			pod.Spec.ResourceClaims = []apiv1.PodResourceClaim{{Name: "gpu", ResourceClaimName: &claimName}}
+		}))
+	}
+
+	clusterSnapshot := clustersnapshot.NewBasicClusterSnapshot()
+	clusterSnapshot.SetDynamicResources(dynamicresources.NewSnapshotFromObjects(
+		[]*resourceapi.ResourceSlice{templateSlice}, claims, []*resourceapi.ResourceClass{class}, nil,
+		[]*resourceapi.ResourceClaimParameters{parameters}))
+	predicateChecker, err := predicatechecker.NewTestPredicateChecker()
+	assert.NoError(t, err)
+	limiter := NewThresholdBasedEstimationLimiter([]Threshold{NewStaticThreshold(0, time.Duration(0))})
+	estimator := NewBinpackingNodeEstimator(predicateChecker, clusterSnapshot, limiter, NewDecreasingPodOrderer(), nil /* EstimationContext */, nil /* EstimationAnalyserFunc */)
+	nodeInfo := schedulerframework.NewNodeInfo()
+	nodeInfo.SetNode(makeNode(1000, 1000, 10, "template", "zone-mars"))
+
+	// Nodes have plenty of CPU and memory, but only two GPUs each.
+	estimatedNodes, estimatedPods := estimator.Estimate([]PodEquivalenceGroup{{Pods: pods}}, nodeInfo, nil)
+	assert.Equal(t, 3, estimatedNodes)
+	assert.Equal(t, 5, len(estimatedPods))
+	// Claims allocated during estimation are reverted.
+	for _, claim := range claims {
+		assert.Nil(t, clusterSnapshot.DynamicResources().Claim("default", claim.Name).Status.Allocation)
+	}
+}
+
 func BenchmarkBinpackingEstimate(b *testing.B) {
 	millicores := int64(1000)
 	memory := int64(5000)
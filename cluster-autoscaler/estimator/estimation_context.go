@@ -18,6 +18,7 @@ package estimator
 
 import (
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	schedulerframework "k8s.io/kubernetes/pkg/scheduler/framework"
 )
 
 // EstimationContext stores static and runtime state of autoscaling, used by Estimator
@@ -25,21 +26,26 @@ type EstimationContext interface {
 	SimilarNodeGroups() []cloudprovider.NodeGroup
 	ClusterMaxNodeLimit() int
 	CurrentNodeCount() int
+	NodeTemplate(nodeGroupId string) *schedulerframework.NodeInfo
 }
 
 type estimationContext struct {
 	similarNodeGroups   []cloudprovider.NodeGroup
 	currentNodeCount    int
 	clusterMaxNodeLimit int
+	nodeTemplates       map[string]*schedulerframework.NodeInfo
 }
 
 // NewEstimationContext creates a patch for estimation context with runtime properties.
 // This patch is used to update existing context.
-func NewEstimationContext(clusterMaxNodeLimit int, similarNodeGroups []cloudprovider.NodeGroup, currentNodeCount int) EstimationContext {
+// Node templates are keyed by node group id, they can be nil if the estimator
+// doesn't need templates of similar node groups.
+func NewEstimationContext(clusterMaxNodeLimit int, similarNodeGroups []cloudprovider.NodeGroup, currentNodeCount int, nodeTemplates map[string]*schedulerframework.NodeInfo) EstimationContext {
 	return &estimationContext{
 		similarNodeGroups:   similarNodeGroups,
 		currentNodeCount:    currentNodeCount,
 		clusterMaxNodeLimit: clusterMaxNodeLimit,
+		nodeTemplates:       nodeTemplates,
 	}
 }
 
@@ -57,3 +63,8 @@ func (c *estimationContext) ClusterMaxNodeLimit() int {
 func (c *estimationContext) CurrentNodeCount() int {
 	return c.currentNodeCount
 }
+
+// NodeTemplate returns the template node of the node group, nil if unknown
+func (c *estimationContext) NodeTemplate(nodeGroupId string) *schedulerframework.NodeInfo {
+	return c.nodeTemplates[nodeGroupId]
+}
@@ -30,10 +30,13 @@ import (
 const (
 	// BinpackingEstimatorName is the name of binpacking estimator.
 	BinpackingEstimatorName = "binpacking"
+	// TopologyAwareEstimatorName is the name of the estimator binpacking pods
+	// on template nodes of the node group and its similar node groups.
+	TopologyAwareEstimatorName = "topology-aware"
 )
 
 // AvailableEstimators is a list of available estimators.
-var AvailableEstimators = []string{BinpackingEstimatorName}
+var AvailableEstimators = []string{BinpackingEstimatorName, TopologyAwareEstimatorName}
 
 // PodEquivalenceGroup represents a group of pods, which have the same scheduling
 // requirements and are managed by the same controller.
@@ -57,6 +60,15 @@ type Estimator interface {
 	Estimate([]PodEquivalenceGroup, *schedulerframework.NodeInfo, cloudprovider.NodeGroup) (int, []*apiv1.Pod)
 }
 
+// NodeGroupSplittingEstimator is an Estimator which splits the estimated nodes
+// between the node group and its similar node groups.
+type NodeGroupSplittingEstimator interface {
+	Estimator
+	// NodeGroupCounts returns the number of nodes of each node group, keyed by
+	// node group id, needed by the last estimation.
+	NodeGroupCounts() map[string]int
+}
+
 // EstimatorBuilder creates a new estimator object.
 type EstimatorBuilder func(predicatechecker.PredicateChecker, clustersnapshot.ClusterSnapshot, EstimationContext) Estimator
 
@@ -71,12 +83,28 @@ func NewEstimatorBuilder(name string, limiter EstimationLimiter, orderer Estimat
 			predicateChecker predicatechecker.PredicateChecker,
 			clusterSnapshot clustersnapshot.ClusterSnapshot,
 			context EstimationContext) Estimator {
-			return NewBinpackingNodeEstimator(predicateChecker, clusterSnapshot, limiter, orderer, context, estimationAnalyserFunc)
+			return NewBinpackingNodeEstimator(predicateChecker, clusterSnapshot, cloneLimiter(limiter), orderer, context, estimationAnalyserFunc)
+		}, nil
+	case TopologyAwareEstimatorName:
+		return func(
+			predicateChecker predicatechecker.PredicateChecker,
+			clusterSnapshot clustersnapshot.ClusterSnapshot,
+			context EstimationContext) Estimator {
+			return NewTopologyAwareNodeEstimator(predicateChecker, clusterSnapshot, cloneLimiter(limiter), orderer, context, estimationAnalyserFunc)
 		}, nil
 	}
 	return nil, fmt.Errorf("unknown estimator: %s", name)
 }
 
+// cloneLimiter returns a clone of the limiter if it's clonable, the limiter
+// itself otherwise.
+func cloneLimiter(limiter EstimationLimiter) EstimationLimiter {
+	if clonable, ok := limiter.(ClonableEstimationLimiter); ok {
+		return clonable.Clone()
+	}
+	return limiter
+}
+
 // EstimationLimiter controls how many nodes can be added by Estimator.
 // A limiter can be used to prevent costly estimation if an actual ability to
 // scale-up is limited by external factors.
@@ -93,6 +121,15 @@ type EstimationLimiter interface {
 	PermissionToAddNode() bool
 }
 
+// ClonableEstimationLimiter is an EstimationLimiter which can create new
+// limiters with the same configuration. Estimators built by the EstimatorBuilder
+// get their own clones, so that they can run concurrently.
+type ClonableEstimationLimiter interface {
+	EstimationLimiter
+	// Clone returns a new limiter with the same configuration.
+	Clone() EstimationLimiter
+}
+
 // EstimationPodOrderer is an interface used to determine the order of the pods
 // used while binpacking during scale up estimation
 type EstimationPodOrderer interface {
@@ -54,6 +54,11 @@ func getMinLimit[V int | time.Duration](baseLimit V, targetLimit V) V {
 
 func (*thresholdBasedEstimationLimiter) EndEstimation() {}
 
+// Clone returns a new limiter with the same thresholds.
+func (tbel *thresholdBasedEstimationLimiter) Clone() EstimationLimiter {
+	return &thresholdBasedEstimationLimiter{thresholds: tbel.thresholds}
+}
+
 func (tbel *thresholdBasedEstimationLimiter) PermissionToAddNode() bool {
 	if tbel.maxNodes < 0 || (tbel.maxNodes > 0 && tbel.nodes >= tbel.maxNodes) {
 		klog.V(4).Infof("Capping binpacking after exceeding threshold of %d nodes", tbel.maxNodes)
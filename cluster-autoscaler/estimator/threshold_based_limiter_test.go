@@ -221,3 +221,16 @@ func TestMinLimit(t *testing.T) {
 		})
 	}
 }
+
+func TestThresholdBasedLimiterClone(t *testing.T) {
+	limiter := NewThresholdBasedEstimationLimiter([]Threshold{NewStaticThreshold(1, 0)})
+	clone := limiter.(ClonableEstimationLimiter).Clone()
+	limiter.StartEstimation(nil, nil, nil)
+	clone.StartEstimation(nil, nil, nil)
+
+	// Clones count the nodes of their estimations separately.
+	assert.True(t, limiter.PermissionToAddNode())
+	assert.True(t, clone.PermissionToAddNode())
+	assert.False(t, limiter.PermissionToAddNode())
+	assert.False(t, clone.PermissionToAddNode())
+}
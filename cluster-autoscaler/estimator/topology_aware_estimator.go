@@ -0,0 +1,239 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package estimator
+
+import (
+	"fmt"
+	"sort"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/predicatechecker"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/scheduler"
+	klog "k8s.io/klog/v2"
+	schedulerframework "k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// TopologyAwareNodeEstimator estimates the number of needed nodes like the
+// BinpackingNodeEstimator, but adds template nodes of the node group and its
+// similar node groups. Similar node groups usually differ in their zones, so
+// pods with topology spread constraints or anti-affinity get nodes in the
+// topology domains they can be scheduled in. The estimated nodes are split
+// between the node groups.
+type TopologyAwareNodeEstimator struct {
+	predicateChecker       predicatechecker.PredicateChecker
+	clusterSnapshot        clustersnapshot.ClusterSnapshot
+	limiter                EstimationLimiter
+	podOrderer             EstimationPodOrderer
+	context                EstimationContext
+	estimationAnalyserFunc EstimationAnalyserFunc // optional
+	nodeGroupCounts        map[string]int
+}
+
+// topologyDomain is a node group new nodes can be added to.
+type topologyDomain struct {
+	nodeGroup cloudprovider.NodeGroup
+	template  *schedulerframework.NodeInfo
+	// capacity is the number of nodes which can be added to the node group,
+	// negative if unlimited.
+	capacity int
+	newNodes int
+}
+
+// topologyEstimationState contains helper variables of a single estimation.
+type topologyEstimationState struct {
+	scheduledPods    []*apiv1.Pod
+	newNodeNameIndex int
+	newNodeDomains   map[string]*topologyDomain
+	newNodesWithPods map[string]bool
+}
+
+// NewTopologyAwareNodeEstimator builds a new TopologyAwareNodeEstimator.
+func NewTopologyAwareNodeEstimator(
+	predicateChecker predicatechecker.PredicateChecker,
+	clusterSnapshot clustersnapshot.ClusterSnapshot,
+	limiter EstimationLimiter,
+	podOrderer EstimationPodOrderer,
+	context EstimationContext,
+	estimationAnalyserFunc EstimationAnalyserFunc,
+) *TopologyAwareNodeEstimator {
+	return &TopologyAwareNodeEstimator{
+		predicateChecker:       predicateChecker,
+		clusterSnapshot:        clusterSnapshot,
+		limiter:                limiter,
+		podOrderer:             podOrderer,
+		context:                context,
+		estimationAnalyserFunc: estimationAnalyserFunc,
+	}
+}
+
+// Estimate schedules the pods on the nodes added during estimation first. If
+// a pod doesn't fit any of them, a template node of the node group with the
+// fewest nodes the pod can be scheduled on is added, so that similar node
+// groups are kept balanced unless the pods require otherwise.
+// Returns the number of nodes needed by all node groups together.
+func (e *TopologyAwareNodeEstimator) Estimate(
+	podsEquivalenceGroups []PodEquivalenceGroup,
+	nodeTemplate *schedulerframework.NodeInfo,
+	nodeGroup cloudprovider.NodeGroup,
+) (int, []*apiv1.Pod) {
+	e.nodeGroupCounts = map[string]int{}
+
+	e.limiter.StartEstimation(podsEquivalenceGroups, nodeGroup, e.context)
+	defer e.limiter.EndEstimation()
+
+	podsEquivalenceGroups = e.podOrderer.Order(podsEquivalenceGroups, nodeTemplate, nodeGroup)
+
+	e.clusterSnapshot.Fork()
+	defer func() {
+		e.clusterSnapshot.Revert()
+	}()
+
+	domains := e.topologyDomains(nodeTemplate, nodeGroup)
+	estimationState := &topologyEstimationState{
+		scheduledPods:    []*apiv1.Pod{},
+		newNodeDomains:   map[string]*topologyDomain{},
+		newNodesWithPods: map[string]bool{},
+	}
+	for _, podsEquivalenceGroup := range podsEquivalenceGroups {
+		for _, pod := range podsEquivalenceGroup.Pods {
+			nodeName, err := e.predicateChecker.FitsAnyNodeMatching(e.clusterSnapshot, pod, func(nodeInfo *schedulerframework.NodeInfo) bool {
+				return estimationState.newNodeDomains[nodeInfo.Node().Name] != nil
+			})
+			if err != nil {
+				var found bool
+				nodeName, found, err = e.addNewNodeForPod(estimationState, domains, pod)
+				if err != nil {
+					klog.Errorf(err.Error())
+					return 0, nil
+				}
+				if !found {
+					// Pods of the group have the same requirements, none of
+					// the remaining ones would fit either.
+					break
+				}
+			}
+			if err := e.clusterSnapshot.AddPod(pod, nodeName); err != nil {
+				klog.Errorf("Error adding pod %v.%v to node %v in ClusterSnapshot; %v", pod.Namespace, pod.Name, nodeName, err)
+				return 0, nil
+			}
+			estimationState.newNodesWithPods[nodeName] = true
+			estimationState.scheduledPods = append(estimationState.scheduledPods, pod)
+		}
+	}
+
+	newNodes := 0
+	for _, domain := range domains {
+		if domain.newNodes > 0 && domain.nodeGroup != nil {
+			e.nodeGroupCounts[domain.nodeGroup.Id()] = domain.newNodes
+		}
+		newNodes += domain.newNodes
+	}
+	if e.estimationAnalyserFunc != nil {
+		e.estimationAnalyserFunc(e.clusterSnapshot, nodeGroup, estimationState.newNodesWithPods)
+	}
+	return newNodes, estimationState.scheduledPods
+}
+
+// NodeGroupCounts returns the number of nodes of each node group needed by the
+// last estimation, keyed by node group id.
+func (e *TopologyAwareNodeEstimator) NodeGroupCounts() map[string]int {
+	return e.nodeGroupCounts
+}
+
+// topologyDomains returns the domain of the node group followed by the
+// domains of its similar node groups with known templates.
+func (e *TopologyAwareNodeEstimator) topologyDomains(nodeTemplate *schedulerframework.NodeInfo, nodeGroup cloudprovider.NodeGroup) []*topologyDomain {
+	domains := []*topologyDomain{newTopologyDomain(nodeGroup, nodeTemplate)}
+	if e.context == nil {
+		return domains
+	}
+	for _, similarNodeGroup := range e.context.SimilarNodeGroups() {
+		template := e.context.NodeTemplate(similarNodeGroup.Id())
+		if template == nil {
+			klog.V(4).Infof("No template node for similar node group %s, skipping it in estimation", similarNodeGroup.Id())
+			continue
+		}
+		domains = append(domains, newTopologyDomain(similarNodeGroup, template))
+	}
+	return domains
+}
+
+func newTopologyDomain(nodeGroup cloudprovider.NodeGroup, template *schedulerframework.NodeInfo) *topologyDomain {
+	domain := &topologyDomain{nodeGroup: nodeGroup, template: template, capacity: -1}
+	if nodeGroup == nil {
+		return domain
+	}
+	if targetSize, err := nodeGroup.TargetSize(); err == nil {
+		domain.capacity = nodeGroup.MaxSize() - targetSize
+	}
+	return domain
+}
+
+// addNewNodeForPod adds a template node of the first domain, ordered by the
+// number of nodes added to them, which has capacity left and the pod can be
+// scheduled on. Returns the name of the node and whether it was added.
+func (e *TopologyAwareNodeEstimator) addNewNodeForPod(
+	estimationState *topologyEstimationState,
+	domains []*topologyDomain,
+	pod *apiv1.Pod,
+) (string, bool, error) {
+	candidates := make([]*topologyDomain, 0, len(domains))
+	for _, domain := range domains {
+		if domain.capacity < 0 || domain.newNodes < domain.capacity {
+			candidates = append(candidates, domain)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].newNodes < candidates[j].newNodes
+	})
+
+	for _, domain := range candidates {
+		newNodeInfo := scheduler.DeepCopyTemplateNode(domain.template, fmt.Sprintf("e-%d", estimationState.newNodeNameIndex))
+		nodeName := newNodeInfo.Node().Name
+		var pods []*apiv1.Pod
+		for _, podInfo := range newNodeInfo.Pods {
+			pods = append(pods, podInfo.Pod)
+		}
+		e.clusterSnapshot.DynamicResources().CopyResourceSlices(domain.template.Node().Name, nodeName)
+		if err := e.clusterSnapshot.AddNodeWithPods(newNodeInfo.Node(), pods); err != nil {
+			return "", false, fmt.Errorf("Error while adding new node for template to ClusterSnapshot; %w", err)
+		}
+		estimationState.newNodeNameIndex++
+
+		// Nodes of a domain the pod doesn't fit in are removed right away,
+		// so that they don't affect topology spreading of later pods.
+		// The limiter assumes one node gets added for each permission, so
+		// it's only asked for nodes which are kept.
+		fits := e.predicateChecker.CheckPredicates(e.clusterSnapshot, pod, nodeName) == nil
+		if !fits || !e.limiter.PermissionToAddNode() {
+			if err := e.clusterSnapshot.RemoveNode(nodeName); err != nil {
+				return "", false, fmt.Errorf("Error while removing new node from ClusterSnapshot; %w", err)
+			}
+			if fits {
+				// Stop adding nodes if the limit was reached.
+				return "", false, nil
+			}
+			continue
+		}
+		domain.newNodes++
+		estimationState.newNodeDomains[nodeName] = domain
+		return nodeName, true, nil
+	}
+	return "", false, nil
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package estimator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/predicatechecker"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+	schedulerframework "k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+func TestTopologyAwareEstimate(t *testing.T) {
+	estimatee := func(opts ...func(*apiv1.Pod)) *apiv1.Pod {
+		opts = append([]func(*apiv1.Pod){
+			WithNamespace("universe"),
+			WithLabels(map[string]string{"app": "estimatee"}),
+		}, opts...)
+		return BuildTestPod("estimatee", 600, 100, opts...)
+	}
+	testCases := []struct {
+		name                 string
+		podsEquivalenceGroup []PodEquivalenceGroup
+		withoutSimilar       bool
+		jupiterMaxSize       int
+		maxNodes             int
+		expectNodeCount      int
+		expectPodCount       int
+		expectNodeGroupCount map[string]int
+	}{
+		{
+			name:                 "zonal topology spreading adds nodes in both zones",
+			podsEquivalenceGroup: []PodEquivalenceGroup{makePodEquivalenceGroup(estimatee(WithMaxSkew(1, "topology.kubernetes.io/zone")), 8)},
+			expectNodeCount:      8,
+			expectPodCount:       8,
+			expectNodeGroupCount: map[string]int{"ng-mars": 4, "ng-jupiter": 4},
+		},
+		{
+			name:                 "zonal topology spreading without similar node groups",
+			podsEquivalenceGroup: []PodEquivalenceGroup{makePodEquivalenceGroup(estimatee(WithMaxSkew(1, "topology.kubernetes.io/zone")), 8)},
+			withoutSimilar:       true,
+			expectNodeCount:      1,
+			expectPodCount:       1,
+			expectNodeGroupCount: map[string]int{"ng-mars": 1},
+		},
+		{
+			name:                 "pods without constraints are balanced",
+			podsEquivalenceGroup: []PodEquivalenceGroup{makePodEquivalenceGroup(estimatee(), 5)},
+			expectNodeCount:      5,
+			expectPodCount:       5,
+			expectNodeGroupCount: map[string]int{"ng-mars": 3, "ng-jupiter": 2},
+		},
+		{
+			name:                 "max size of similar node group limits zonal topology spreading",
+			podsEquivalenceGroup: []PodEquivalenceGroup{makePodEquivalenceGroup(estimatee(WithMaxSkew(1, "topology.kubernetes.io/zone")), 8)},
+			jupiterMaxSize:       2,
+			expectNodeCount:      3,
+			expectPodCount:       3,
+			expectNodeGroupCount: map[string]int{"ng-mars": 2, "ng-jupiter": 1},
+		},
+		{
+			name:                 "limiter caps the node count",
+			podsEquivalenceGroup: []PodEquivalenceGroup{makePodEquivalenceGroup(estimatee(), 5)},
+			maxNodes:             3,
+			expectNodeCount:      3,
+			expectPodCount:       3,
+			expectNodeGroupCount: map[string]int{"ng-mars": 2, "ng-jupiter": 1},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			jupiterMaxSize := tc.jupiterMaxSize
+			if jupiterMaxSize == 0 {
+				jupiterMaxSize = 20
+			}
+			provider := testprovider.NewTestCloudProvider(nil, nil)
+			provider.AddNodeGroup("ng-mars", 0, 20, 1)
+			provider.AddNodeGroup("ng-jupiter", 0, jupiterMaxSize, 1)
+			marsTemplate := schedulerframework.NewNodeInfo()
+			marsTemplate.SetNode(makeNode(1000, 5000, 10, "template-mars", "zone-mars"))
+			jupiterTemplate := schedulerframework.NewNodeInfo()
+			jupiterTemplate.SetNode(makeNode(1000, 5000, 10, "template-jupiter", "zone-jupiter"))
+			templates := map[string]*schedulerframework.NodeInfo{"ng-mars": marsTemplate, "ng-jupiter": jupiterTemplate}
+			var similarNodeGroups []cloudprovider.NodeGroup
+			if !tc.withoutSimilar {
+				similarNodeGroups = []cloudprovider.NodeGroup{provider.GetNodeGroup("ng-jupiter")}
+			}
+
+			clusterSnapshot := clustersnapshot.NewBasicClusterSnapshot()
+			clusterSnapshot.AddNode(makeNode(100, 100, 10, "oldnode", "zone-jupiter"))
+			predicateChecker, err := predicatechecker.NewTestPredicateChecker()
+			assert.NoError(t, err)
+			limiter := NewThresholdBasedEstimationLimiter([]Threshold{NewStaticThreshold(tc.maxNodes, time.Duration(0))})
+			context := NewEstimationContext(0, similarNodeGroups, 2, templates)
+			estimator := NewTopologyAwareNodeEstimator(predicateChecker, clusterSnapshot, limiter, NewDecreasingPodOrderer(), context, nil /* EstimationAnalyserFunc */)
+
+			estimatedNodes, estimatedPods := estimator.Estimate(tc.podsEquivalenceGroup, marsTemplate, provider.GetNodeGroup("ng-mars"))
+			assert.Equal(t, tc.expectNodeCount, estimatedNodes)
+			assert.Equal(t, tc.expectPodCount, len(estimatedPods))
+			assert.Equal(t, tc.expectNodeGroupCount, estimator.NodeGroupCounts())
+
+			// Nodes added during estimation are reverted.
+			nodeInfos, err := clusterSnapshot.NodeInfos().List()
+			assert.NoError(t, err)
+			assert.Len(t, nodeInfos, 1)
+		})
+	}
+}
@@ -24,7 +24,7 @@ import (
 
 var (
 	// AvailableExpanders is a list of available expander options
-	AvailableExpanders = []string{RandomExpanderName, MostPodsExpanderName, LeastWasteExpanderName, PriceBasedExpanderName, PriorityBasedExpanderName, GRPCExpanderName}
+	AvailableExpanders = []string{RandomExpanderName, MostPodsExpanderName, LeastWasteExpanderName, PriceBasedExpanderName, PriorityBasedExpanderName, GRPCExpanderName, GRPCV2ExpanderName, LeastInterruptedExpanderName, WeightedExpanderName}
 	// RandomExpanderName selects a node group at random
 	RandomExpanderName = "random"
 	// MostPodsExpanderName selects a node group that fits the most pods
@@ -40,6 +40,13 @@ var (
 	PriorityBasedExpanderName = "priority"
 	// GRPCExpanderName uses the gRPC client expander to call to an external gRPC server to select a node group for scale up
 	GRPCExpanderName = "grpc"
+	// GRPCV2ExpanderName uses the gRPC client expander to get scores of all options from an external gRPC server, and
+	// selects the node groups with the highest score
+	GRPCV2ExpanderName = "grpc-v2"
+	// LeastInterruptedExpanderName selects node groups with the least recent interruptions, e.g. reclaimed spot instances
+	LeastInterruptedExpanderName = "least-interrupted"
+	// WeightedExpanderName selects node groups with the highest weighted average of normalized scores of other expanders
+	WeightedExpanderName = "weighted"
 )
 
 // Option describes an option to expand the cluster.
@@ -49,6 +56,9 @@ type Option struct {
 	NodeCount         int
 	Debug             string
 	Pods              []*apiv1.Pod
+	// NodeGroupCounts splits NodeCount between NodeGroup and SimilarNodeGroups,
+	// keyed by node group id. Nil if the estimator doesn't split node counts.
+	NodeGroupCounts map[string]int
 }
 
 // Strategy describes an interface for selecting the best option when scaling up
@@ -60,3 +70,44 @@ type Strategy interface {
 type Filter interface {
 	BestOptions(options []Option, nodeInfo map[string]*schedulerframework.NodeInfo) []Option
 }
+
+// Scorer is an optional interface of a Filter reporting the values it compares
+// options by, keyed by node group id.
+type Scorer interface {
+	Scores(options []Option, nodeInfo map[string]*schedulerframework.NodeInfo) map[string]float64
+}
+
+// Explainer is an optional interface of a Scorer explaining the scores it
+// reported, keyed by node group id.
+type Explainer interface {
+	Explanations(options []Option, nodeInfo map[string]*schedulerframework.NodeInfo) map[string]string
+}
+
+// FilterResult describes how a single filter narrowed down the options.
+type FilterResult struct {
+	// Name is the name of the filter.
+	Name string
+	// Scores are the values the filter compared options by, keyed by node
+	// group id. Empty if the filter doesn't implement Scorer.
+	Scores map[string]float64
+	// Explanations are human readable reasons of the scores, keyed by node
+	// group id. Empty if the filter doesn't implement Explainer.
+	Explanations map[string]string
+	// Options are ids of node groups of the options kept by the filter.
+	Options []string
+}
+
+// ExplainableStrategy is an optional interface of a Strategy which can
+// explain how it selected the best option.
+type ExplainableStrategy interface {
+	ExplainBestOption(options []Option, nodeInfo map[string]*schedulerframework.NodeInfo) (*Option, []FilterResult)
+}
+
+// ExplainBestOption selects the best option with the strategy, along with
+// results of the strategy filters if the strategy is explainable.
+func ExplainBestOption(strategy Strategy, options []Option, nodeInfo map[string]*schedulerframework.NodeInfo) (*Option, []FilterResult) {
+	if explainable, ok := strategy.(ExplainableStrategy); ok {
+		return explainable.ExplainBestOption(options, nodeInfo)
+	}
+	return strategy.BestOption(options, nodeInfo), nil
+}
@@ -23,24 +23,58 @@ import (
 )
 
 type chainStrategy struct {
+	names    []string
 	filters  []expander.Filter
 	fallback expander.Strategy
 }
 
-func newChainStrategy(filters []expander.Filter, fallback expander.Strategy) expander.Strategy {
+func newChainStrategy(names []string, filters []expander.Filter, fallback expander.Strategy) *chainStrategy {
 	return &chainStrategy{
+		names:    names,
 		filters:  filters,
 		fallback: fallback,
 	}
 }
 
 func (c *chainStrategy) BestOption(options []expander.Option, nodeInfo map[string]*schedulerframework.NodeInfo) *expander.Option {
+	bestOption, _ := c.bestOption(options, nodeInfo, false)
+	return bestOption
+}
+
+// ExplainBestOption selects the best option, returning results of the filters
+// which were run in the order they were run.
+func (c *chainStrategy) ExplainBestOption(options []expander.Option, nodeInfo map[string]*schedulerframework.NodeInfo) (*expander.Option, []expander.FilterResult) {
+	return c.bestOption(options, nodeInfo, true)
+}
+
+func (c *chainStrategy) bestOption(options []expander.Option, nodeInfo map[string]*schedulerframework.NodeInfo, explain bool) (*expander.Option, []expander.FilterResult) {
+	var results []expander.FilterResult
 	filteredOptions := options
-	for _, filter := range c.filters {
+	for i, filter := range c.filters {
+		var result expander.FilterResult
+		if explain {
+			if i < len(c.names) {
+				result.Name = c.names[i]
+			}
+			if scorer, ok := filter.(expander.Scorer); ok {
+				result.Scores = scorer.Scores(filteredOptions, nodeInfo)
+			}
+			if explainer, ok := filter.(expander.Explainer); ok {
+				result.Explanations = explainer.Explanations(filteredOptions, nodeInfo)
+			}
+		}
 		filteredOptions = filter.BestOptions(filteredOptions, nodeInfo)
+		if explain {
+			for _, option := range filteredOptions {
+				if option.NodeGroup != nil {
+					result.Options = append(result.Options, option.NodeGroup.Id())
+				}
+			}
+			results = append(results, result)
+		}
 		if len(filteredOptions) == 1 {
-			return &filteredOptions[0]
+			return &filteredOptions[0], results
 		}
 	}
-	return c.fallback.BestOption(filteredOptions, nodeInfo)
+	return c.fallback.BestOption(filteredOptions, nodeInfo), results
 }
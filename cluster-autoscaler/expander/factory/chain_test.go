@@ -17,6 +17,7 @@ limitations under the License.
 package factory
 
 import (
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
 	"k8s.io/autoscaler/cluster-autoscaler/expander"
 	"strings"
 	"testing"
@@ -119,7 +120,7 @@ func TestChainStrategy_BestOption(t *testing.T) {
 		},
 	} {
 		t.Run(name, func(t *testing.T) {
-			subject := newChainStrategy(tc.filters, tc.fallback)
+			subject := newChainStrategy(nil, tc.filters, tc.fallback)
 			actual := subject.BestOption(tc.options, nil)
 			assert.Equal(t, tc.expected, actual)
 		})
@@ -131,3 +132,59 @@ func newOption(debug string) *expander.Option {
 		Debug: debug,
 	}
 }
+
+type countTestFilter struct {
+	minNodes int
+}
+
+func (f *countTestFilter) BestOptions(expansionOptions []expander.Option, nodeInfo map[string]*schedulerframework.NodeInfo) []expander.Option {
+	var ret []expander.Option
+	for _, option := range expansionOptions {
+		if option.NodeCount >= f.minNodes {
+			ret = append(ret, option)
+		}
+	}
+	return ret
+}
+
+func (f *countTestFilter) Scores(expansionOptions []expander.Option, nodeInfo map[string]*schedulerframework.NodeInfo) map[string]float64 {
+	scores := map[string]float64{}
+	for _, option := range expansionOptions {
+		scores[option.NodeGroup.Id()] = float64(option.NodeCount)
+	}
+	return scores
+}
+
+func (f *countTestFilter) Explanations(expansionOptions []expander.Option, nodeInfo map[string]*schedulerframework.NodeInfo) map[string]string {
+	explanations := map[string]string{}
+	for _, option := range expansionOptions {
+		if option.NodeCount < f.minNodes {
+			explanations[option.NodeGroup.Id()] = "too few nodes"
+		}
+	}
+	return explanations
+}
+
+func TestChainStrategy_ExplainBestOption(t *testing.T) {
+	ng1 := testprovider.NewTestNodeGroup("ng1", 10, 0, 0, true, false, "", nil, nil)
+	ng2 := testprovider.NewTestNodeGroup("ng2", 10, 0, 0, true, false, "", nil, nil)
+	ng3 := testprovider.NewTestNodeGroup("ng3", 10, 0, 0, true, false, "", nil, nil)
+	options := []expander.Option{
+		{NodeGroup: ng1, NodeCount: 1, Debug: "a"},
+		{NodeGroup: ng2, NodeCount: 2, Debug: "b"},
+		{NodeGroup: ng3, NodeCount: 3, Debug: "ab"},
+	}
+
+	subject := newChainStrategy(
+		[]string{"count", "substring-a", "substring-b"},
+		[]expander.Filter{&countTestFilter{minNodes: 2}, newSubstringTestFilterStrategy("a"), newSubstringTestFilterStrategy("b")},
+		newSubstringTestFilterStrategy("x"),
+	)
+	best, results := expander.ExplainBestOption(subject, options, nil)
+	assert.Equal(t, &options[2], best)
+	assert.Equal(t, []expander.FilterResult{
+		{Name: "count", Scores: map[string]float64{"ng1": 1, "ng2": 2, "ng3": 3}, Explanations: map[string]string{"ng1": "too few nodes"}, Options: []string{"ng2", "ng3"}},
+		{Name: "substring-a", Options: []string{"ng3"}},
+	}, results)
+	assert.Equal(t, best, subject.BestOption(options, nil))
+}
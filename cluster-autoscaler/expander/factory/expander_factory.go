@@ -21,12 +21,15 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/context"
 	"k8s.io/autoscaler/cluster-autoscaler/expander"
 	"k8s.io/autoscaler/cluster-autoscaler/expander/grpcplugin"
+	"k8s.io/autoscaler/cluster-autoscaler/expander/leastinterrupted"
 	"k8s.io/autoscaler/cluster-autoscaler/expander/leastnodes"
 	"k8s.io/autoscaler/cluster-autoscaler/expander/mostpods"
 	"k8s.io/autoscaler/cluster-autoscaler/expander/price"
 	"k8s.io/autoscaler/cluster-autoscaler/expander/priority"
 	"k8s.io/autoscaler/cluster-autoscaler/expander/random"
 	"k8s.io/autoscaler/cluster-autoscaler/expander/waste"
+	"k8s.io/autoscaler/cluster-autoscaler/expander/weighted"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/backoff"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/errors"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
 
@@ -75,15 +78,16 @@ func (f *Factory) Build(names []string) (expander.Strategy, errors.AutoscalerErr
 			strategySeen = true
 		}
 	}
-	return newChainStrategy(filters, random.NewStrategy()), nil
+	return newChainStrategy(names, filters, random.NewStrategy()), nil
 }
 
 // RegisterDefaultExpanders is a convenience function, registering all known expanders in the Factory.
-func (f *Factory) RegisterDefaultExpanders(cloudProvider cloudprovider.CloudProvider, autoscalingKubeClients *context.AutoscalingKubeClients, kubeClient kube_client.Interface, configNamespace string, GRPCExpanderCert string, GRPCExpanderURL string) {
+func (f *Factory) RegisterDefaultExpanders(cloudProvider cloudprovider.CloudProvider, autoscalingKubeClients *context.AutoscalingKubeClients, kubeClient kube_client.Interface, configNamespace string, GRPCExpanderCert string, GRPCExpanderURL string, nodeGroupBackoff backoff.Backoff, expanderWeights map[string]float64) {
 	f.RegisterFilter(expander.RandomExpanderName, random.NewFilter)
 	f.RegisterFilter(expander.MostPodsExpanderName, mostpods.NewFilter)
 	f.RegisterFilter(expander.LeastWasteExpanderName, waste.NewFilter)
 	f.RegisterFilter(expander.LeastNodesExpanderName, leastnodes.NewFilter)
+	f.RegisterFilter(expander.LeastInterruptedExpanderName, func() expander.Filter { return leastinterrupted.NewFilter(nodeGroupBackoff) })
 	f.RegisterFilter(expander.PriceBasedExpanderName, func() expander.Filter {
 		if _, err := cloudProvider.Pricing(); err != nil {
 			klog.Fatalf("Couldn't access cloud provider pricing for %s expander: %v", expander.PriceBasedExpanderName, err)
@@ -98,4 +102,22 @@ func (f *Factory) RegisterDefaultExpanders(cloudProvider cloudprovider.CloudProv
 		return priority.NewFilter(lister.ConfigMaps(configNamespace), autoscalingKubeClients.Recorder)
 	})
 	f.RegisterFilter(expander.GRPCExpanderName, func() expander.Filter { return grpcplugin.NewFilter(GRPCExpanderCert, GRPCExpanderURL) })
+	f.RegisterFilter(expander.GRPCV2ExpanderName, func() expander.Filter {
+		return grpcplugin.NewScoringFilter(GRPCExpanderCert, GRPCExpanderURL, cloudProvider, nodeGroupBackoff)
+	})
+	f.RegisterFilter(expander.WeightedExpanderName, func() expander.Filter {
+		scorers := make(map[string]expander.Scorer)
+		for name := range expanderWeights {
+			if create, found := f.createFunc[name]; found {
+				if scorer, ok := create().(expander.Scorer); ok {
+					scorers[name] = scorer
+				}
+			}
+		}
+		var recorder weighted.EventRecorder
+		if autoscalingKubeClients != nil && autoscalingKubeClients.LogRecorder != nil {
+			recorder = autoscalingKubeClients.LogRecorder
+		}
+		return weighted.NewFilter(expanderWeights, scorers, recorder)
+	})
 }
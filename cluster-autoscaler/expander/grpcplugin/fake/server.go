@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fake provides a reference implementation of the ExpanderV2 gRPC
+// service, served in memory for tests.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"k8s.io/autoscaler/cluster-autoscaler/expander/grpcplugin/protos"
+)
+
+// ScoreFunc scores the options of a request.
+type ScoreFunc func(req *protos.ScoreOptionsRequest) ([]*protos.OptionScore, error)
+
+// Server is an ExpanderV2 server scoring options with a ScoreFunc. It records
+// the requests it received.
+type Server struct {
+	protos.UnimplementedExpanderV2Server
+
+	scoreFunc ScoreFunc
+	mutex     sync.Mutex
+	requests  []*protos.ScoreOptionsRequest
+	server    *grpc.Server
+	conn      *grpc.ClientConn
+}
+
+// NewServer returns a server scoring options with the given function.
+func NewServer(scoreFunc ScoreFunc) *Server {
+	return &Server{scoreFunc: scoreFunc}
+}
+
+// ScoreOptions implements the ExpanderV2 service.
+func (s *Server) ScoreOptions(_ context.Context, req *protos.ScoreOptionsRequest) (*protos.ScoreOptionsResponse, error) {
+	s.mutex.Lock()
+	s.requests = append(s.requests, req)
+	s.mutex.Unlock()
+	scores, err := s.scoreFunc(req)
+	if err != nil {
+		return nil, err
+	}
+	return &protos.ScoreOptionsResponse{Scores: scores}, nil
+}
+
+// Requests returns the requests received so far.
+func (s *Server) Requests() []*protos.ScoreOptionsRequest {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]*protos.ScoreOptionsRequest{}, s.requests...)
+}
+
+// Start serves the server in memory, and returns a client connected to it.
+func (s *Server) Start() (protos.ExpanderV2Client, error) {
+	listener := bufconn.Listen(1 << 20)
+	s.server = grpc.NewServer()
+	protos.RegisterExpanderV2Server(s.server, s)
+	go s.server.Serve(listener)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		s.server.Stop()
+		return nil, err
+	}
+	s.conn = conn
+	return protos.NewExpanderV2Client(conn), nil
+}
+
+// Stop closes the client connection and stops the server.
+func (s *Server) Stop() {
+	if s.conn != nil {
+		s.conn.Close()
+	}
+	if s.server != nil {
+		s.server.Stop()
+	}
+}
+
+// CheapestScore is a reference ScoreFunc preferring the options with the
+// lowest hourly cost of the new nodes. Options of node groups which are backed
+// off or without pricing are not scored.
+func CheapestScore(req *protos.ScoreOptionsRequest) ([]*protos.OptionScore, error) {
+	var scores []*protos.OptionScore
+	for _, option := range req.Options {
+		nodeGroup, found := req.NodeGroups[option.NodeGroupId]
+		if !found || nodeGroup.BackedOff || !nodeGroup.PricingAvailable {
+			continue
+		}
+		cost := nodeGroup.NodePrice * float64(option.NodeCount)
+		scores = append(scores, &protos.OptionScore{
+			NodeGroupId: option.NodeGroupId,
+			Score:       -cost,
+			Explanation: fmt.Sprintf("%d nodes cost %.3f per hour", option.NodeCount, cost),
+		})
+	}
+	return scores, nil
+}
@@ -50,6 +50,14 @@ func NewFilter(expanderCert string, expanderUrl string) expander.Filter {
 }
 
 func createGRPCClient(expanderCert string, expanderUrl string) protos.ExpanderClient {
+	conn := dialGRPCServer(expanderCert, expanderUrl)
+	if conn == nil {
+		return nil
+	}
+	return protos.NewExpanderClient(conn)
+}
+
+func dialGRPCServer(expanderCert string, expanderUrl string) *grpc.ClientConn {
 	if expanderCert == "" {
 		log.Fatalf("GRPC Expander Cert not specified, insecure connections not allowed")
 		return nil
@@ -69,7 +77,7 @@ func createGRPCClient(expanderCert string, expanderUrl string) protos.ExpanderCl
 		log.Fatalf("Fail to dial server: %v", err)
 		return nil
 	}
-	return protos.NewExpanderClient(conn)
+	return conn
 }
 
 func (g *grpcclientstrategy) BestOptions(expansionOptions []expander.Option, nodeInfo map[string]*schedulerframework.NodeInfo) []expander.Option {
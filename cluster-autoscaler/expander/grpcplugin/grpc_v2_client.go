@@ -0,0 +1,280 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcplugin
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/core/scaleup/equivalence"
+	"k8s.io/autoscaler/cluster-autoscaler/expander"
+	"k8s.io/autoscaler/cluster-autoscaler/expander/grpcplugin/protos"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/backoff"
+	"k8s.io/klog/v2"
+	schedulerframework "k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// BackoffStatusProvider provides the backoff status of node groups.
+type BackoffStatusProvider interface {
+	BackoffStatus(nodeGroup cloudprovider.NodeGroup, nodeInfo *schedulerframework.NodeInfo, currentTime time.Time) backoff.Status
+}
+
+type grpcscoringstrategy struct {
+	grpcClient    protos.ExpanderV2Client
+	cloudProvider cloudprovider.CloudProvider
+	backoff       BackoffStatusProvider
+	now           func() time.Time
+
+	// lastKey and lastScores hold the scores of the options scored last, so
+	// that explaining a decision doesn't call the server again.
+	lastKey    string
+	lastScores map[string]*protos.OptionScore
+}
+
+// NewScoringFilter returns an expansion filter that calls out to a gRPC server
+// implementing the ExpanderV2 service for scores of all options, and keeps the
+// options with the highest score. Pricing of the cloud provider and backoff
+// of node groups are passed to the server, both are optional.
+func NewScoringFilter(expanderCert string, expanderUrl string, cloudProvider cloudprovider.CloudProvider, backoff BackoffStatusProvider) expander.Filter {
+	var client protos.ExpanderV2Client
+	if conn := dialGRPCServer(expanderCert, expanderUrl); conn != nil {
+		client = protos.NewExpanderV2Client(conn)
+	}
+	return newScoringFilter(client, cloudProvider, backoff)
+}
+
+func newScoringFilter(client protos.ExpanderV2Client, cloudProvider cloudprovider.CloudProvider, backoff BackoffStatusProvider) *grpcscoringstrategy {
+	return &grpcscoringstrategy{
+		grpcClient:    client,
+		cloudProvider: cloudProvider,
+		backoff:       backoff,
+		now:           time.Now,
+	}
+}
+
+// BestOptions selects the options with the highest score. All options are
+// kept if the server fails or doesn't score any of them.
+func (g *grpcscoringstrategy) BestOptions(expansionOptions []expander.Option, nodeInfo map[string]*schedulerframework.NodeInfo) []expander.Option {
+	scores := g.scoreOptions(expansionOptions, nodeInfo)
+	// Scores reported for an explanation are used only once, the next
+	// BestOptions call may see a different cluster state.
+	g.lastKey, g.lastScores = "", nil
+	if len(scores) == 0 {
+		return expansionOptions
+	}
+
+	bestScore := math.Inf(-1)
+	var bestOptions []expander.Option
+	for _, option := range expansionOptions {
+		score, found := scores[option.NodeGroup.Id()]
+		if !found {
+			continue
+		}
+		if score.Score == bestScore {
+			bestOptions = append(bestOptions, option)
+			continue
+		}
+		if score.Score > bestScore {
+			bestScore = score.Score
+			bestOptions = []expander.Option{option}
+		}
+	}
+	return bestOptions
+}
+
+// Scores returns the scores returned by the server. The server is always
+// called, as the weighted expander uses the scores without calling
+// BestOptions, and they are kept for the following explanation and decision.
+func (g *grpcscoringstrategy) Scores(expansionOptions []expander.Option, nodeInfo map[string]*schedulerframework.NodeInfo) map[string]float64 {
+	scores := g.callScoreOptions(expansionOptions, nodeInfo)
+	g.lastKey, g.lastScores = optionsKey(expansionOptions), scores
+	if len(scores) == 0 {
+		return nil
+	}
+	result := make(map[string]float64, len(scores))
+	for id, score := range scores {
+		result[id] = score.Score
+	}
+	return result
+}
+
+// Explanations returns the explanations of the scores returned by the server.
+func (g *grpcscoringstrategy) Explanations(expansionOptions []expander.Option, nodeInfo map[string]*schedulerframework.NodeInfo) map[string]string {
+	var result map[string]string
+	for id, score := range g.cachedScores(expansionOptions, nodeInfo) {
+		if score.Explanation == "" {
+			continue
+		}
+		if result == nil {
+			result = make(map[string]string)
+		}
+		result[id] = score.Explanation
+	}
+	return result
+}
+
+func (g *grpcscoringstrategy) cachedScores(expansionOptions []expander.Option, nodeInfo map[string]*schedulerframework.NodeInfo) map[string]*protos.OptionScore {
+	key := optionsKey(expansionOptions)
+	if g.lastScores != nil && g.lastKey == key {
+		return g.lastScores
+	}
+	g.lastKey, g.lastScores = key, g.callScoreOptions(expansionOptions, nodeInfo)
+	return g.lastScores
+}
+
+func (g *grpcscoringstrategy) scoreOptions(expansionOptions []expander.Option, nodeInfo map[string]*schedulerframework.NodeInfo) map[string]*protos.OptionScore {
+	if g.lastScores != nil && g.lastKey == optionsKey(expansionOptions) {
+		return g.lastScores
+	}
+	return g.callScoreOptions(expansionOptions, nodeInfo)
+}
+
+// callScoreOptions returns valid scores returned by the server, keyed by node
+// group id, or nil if the call failed.
+func (g *grpcscoringstrategy) callScoreOptions(expansionOptions []expander.Option, nodeInfo map[string]*schedulerframework.NodeInfo) map[string]*protos.OptionScore {
+	if g.grpcClient == nil {
+		klog.Errorf("Incorrect gRPC client config, filtering no options")
+		return nil
+	}
+
+	request := g.buildScoreOptionsRequest(expansionOptions, nodeInfo)
+	klog.V(2).Infof("gRPC call to score %v options", len(request.Options))
+	ctx, cancel := context.WithTimeout(context.Background(), gRPCTimeout)
+	defer cancel()
+	response, err := g.grpcClient.ScoreOptions(ctx, request)
+	if err != nil {
+		if status.Code(err) == codes.Unimplemented {
+			klog.V(4).Infof("gRPC expander server doesn't implement ScoreOptions, no options filtered")
+		} else {
+			klog.Errorf("gRPC call to ScoreOptions failed, no options filtered: %v", err)
+		}
+		return nil
+	}
+
+	scores := make(map[string]*protos.OptionScore)
+	for _, score := range response.GetScores() {
+		if score == nil {
+			klog.Error("gRPC server returned nil OptionScore")
+			continue
+		}
+		if _, found := request.NodeGroups[score.NodeGroupId]; !found {
+			klog.Errorf("gRPC server returned score of invalid nodeGroup ID: %s", score.NodeGroupId)
+			continue
+		}
+		if math.IsNaN(score.Score) {
+			klog.Errorf("gRPC server returned NaN score for nodeGroup ID: %s", score.NodeGroupId)
+			continue
+		}
+		scores[score.NodeGroupId] = score
+	}
+	return scores
+}
+
+func (g *grpcscoringstrategy) buildScoreOptionsRequest(expansionOptions []expander.Option, nodeInfo map[string]*schedulerframework.NodeInfo) *protos.ScoreOptionsRequest {
+	now := g.now()
+	request := &protos.ScoreOptionsRequest{NodeGroups: make(map[string]*protos.NodeGroupInfo)}
+	var pods []*apiv1.Pod
+	seenPods := make(map[string]bool)
+	for _, option := range expansionOptions {
+		id := option.NodeGroup.Id()
+		grpcOption := &protos.ScoreOption{NodeGroupId: id, NodeCount: int32(option.NodeCount), Debug: option.Debug}
+		for _, pod := range option.Pods {
+			key := podKey(pod)
+			grpcOption.Pods = append(grpcOption.Pods, key)
+			if !seenPods[key] {
+				seenPods[key] = true
+				pods = append(pods, pod)
+			}
+		}
+		request.Options = append(request.Options, grpcOption)
+		request.NodeGroups[id] = g.nodeGroupInfo(option.NodeGroup, nodeInfo[id], now)
+	}
+	request.PodEquivalenceGroups = podEquivalenceGroups(pods)
+	return request
+}
+
+func (g *grpcscoringstrategy) nodeGroupInfo(nodeGroup cloudprovider.NodeGroup, nodeInfo *schedulerframework.NodeInfo, now time.Time) *protos.NodeGroupInfo {
+	info := &protos.NodeGroupInfo{
+		Id:      nodeGroup.Id(),
+		MinSize: int32(nodeGroup.MinSize()),
+		MaxSize: int32(nodeGroup.MaxSize()),
+	}
+	if targetSize, err := nodeGroup.TargetSize(); err == nil {
+		info.TargetSize = int32(targetSize)
+	}
+	if nodeInfo != nil {
+		info.TemplateNode = nodeInfo.Node()
+	}
+	if g.cloudProvider != nil && info.TemplateNode != nil {
+		if pricing, err := g.cloudProvider.Pricing(); err == nil {
+			if price, err := pricing.NodePrice(info.TemplateNode, now, now.Add(time.Hour)); err == nil {
+				info.PricingAvailable = true
+				info.NodePrice = price
+			} else {
+				klog.V(4).Infof("Failed to get price of node group %s: %v", nodeGroup.Id(), err)
+			}
+		}
+	}
+	if g.backoff != nil {
+		backoffStatus := g.backoff.BackoffStatus(nodeGroup, nodeInfo, now)
+		info.BackedOff = backoffStatus.IsBackedOff
+		info.BackoffErrorCode = backoffStatus.ErrorInfo.ErrorCode
+		info.BackoffErrorMessage = backoffStatus.ErrorInfo.ErrorMessage
+	}
+	return info
+}
+
+// podEquivalenceGroups groups the pods by scheduling properties, in the order
+// of the first pod of each group.
+func podEquivalenceGroups(pods []*apiv1.Pod) []*protos.PodEquivalenceGroup {
+	var groups []*protos.PodEquivalenceGroup
+	for _, podGroup := range equivalence.BuildPodGroups(pods) {
+		group := &protos.PodEquivalenceGroup{Pod: podGroup.Pods[0]}
+		for _, pod := range podGroup.Pods {
+			group.Pods = append(group.Pods, podKey(pod))
+		}
+		groups = append(groups, group)
+	}
+	order := make(map[string]int, len(pods))
+	for i, pod := range pods {
+		order[podKey(pod)] = i
+	}
+	sort.Slice(groups, func(i, j int) bool { return order[groups[i].Pods[0]] < order[groups[j].Pods[0]] })
+	return groups
+}
+
+// optionsKey identifies a list of options for caching the scores.
+func optionsKey(expansionOptions []expander.Option) string {
+	var key strings.Builder
+	for _, option := range expansionOptions {
+		fmt.Fprintf(&key, "%s:%d;", option.NodeGroup.Id(), option.NodeCount)
+	}
+	return key.String()
+}
+
+func podKey(pod *apiv1.Pod) string {
+	return pod.Namespace + "/" + pod.Name
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcplugin
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/expander"
+	"k8s.io/autoscaler/cluster-autoscaler/expander/grpcplugin/fake"
+	"k8s.io/autoscaler/cluster-autoscaler/expander/grpcplugin/protos"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/backoff"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+	schedulerframework "k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakePricingModel struct {
+	prices map[string]float64
+}
+
+func (m *fakePricingModel) NodePrice(node *apiv1.Node, startTime time.Time, endTime time.Time) (float64, error) {
+	price, found := m.prices[node.Name]
+	if !found {
+		return 0, fmt.Errorf("unknown node %s", node.Name)
+	}
+	return price * endTime.Sub(startTime).Hours(), nil
+}
+
+func (m *fakePricingModel) PodPrice(*apiv1.Pod, time.Time, time.Time) (float64, error) {
+	return 0, nil
+}
+
+type fakeBackoff struct {
+	backedOff map[string]cloudprovider.InstanceErrorInfo
+}
+
+func (b *fakeBackoff) BackoffStatus(nodeGroup cloudprovider.NodeGroup, _ *schedulerframework.NodeInfo, _ time.Time) backoff.Status {
+	errorInfo, found := b.backedOff[nodeGroup.Id()]
+	return backoff.Status{IsBackedOff: found, ErrorInfo: errorInfo}
+}
+
+func newScoringTestEnv(t *testing.T, scoreFunc fake.ScoreFunc) (*grpcscoringstrategy, *fake.Server, []expander.Option, map[string]*schedulerframework.NodeInfo) {
+	provider := testprovider.NewTestCloudProvider(nil, nil)
+	provider.SetPricingModel(&fakePricingModel{prices: map[string]float64{"template-ng1": 1, "template-ng2": 0.5, "template-ng3": 0.1}})
+	nodeInfos := map[string]*schedulerframework.NodeInfo{}
+	var options []expander.Option
+	for _, id := range []string{"ng1", "ng2", "ng3", "ng4"} {
+		provider.AddNodeGroup(id, 1, 10, 2)
+		nodeInfo := schedulerframework.NewNodeInfo()
+		nodeInfo.SetNode(BuildTestNode("template-"+id, 1000, 1000))
+		nodeInfos[id] = nodeInfo
+		options = append(options, expander.Option{NodeGroup: provider.GetNodeGroup(id), NodeCount: 2, Debug: id})
+	}
+	ownerRefs := GenerateOwnerReferences("rs", "ReplicaSet", "apps/v1", "rs-uid")
+	p1 := BuildTestPod("p1", 100, 0)
+	p1.OwnerReferences = ownerRefs
+	p2 := BuildTestPod("p2", 100, 0)
+	p2.OwnerReferences = ownerRefs
+	p3 := BuildTestPod("p3", 500, 0)
+	options[0].Pods = []*apiv1.Pod{p1, p2, p3}
+	options[1].Pods = []*apiv1.Pod{p1, p2}
+
+	server := fake.NewServer(scoreFunc)
+	client, err := server.Start()
+	assert.NoError(t, err)
+	t.Cleanup(server.Stop)
+	backoff := &fakeBackoff{backedOff: map[string]cloudprovider.InstanceErrorInfo{
+		"ng3": {ErrorCode: "QuotaExceeded", ErrorMessage: "out of quota"},
+	}}
+	return newScoringFilter(client, provider, backoff), server, options, nodeInfos
+}
+
+func TestScoringFilterRequest(t *testing.T) {
+	filter, server, options, nodeInfos := newScoringTestEnv(t, fake.CheapestScore)
+	bestOptions := filter.BestOptions(options, nodeInfos)
+	assert.Equal(t, []expander.Option{options[1]}, bestOptions)
+
+	requests := server.Requests()
+	assert.Len(t, requests, 1)
+	req := requests[0]
+	assert.Len(t, req.Options, 4)
+	assert.Equal(t, "ng1", req.Options[0].NodeGroupId)
+	assert.Equal(t, int32(2), req.Options[0].NodeCount)
+	assert.Equal(t, []string{"default/p1", "default/p2", "default/p3"}, req.Options[0].Pods)
+
+	assert.Len(t, req.NodeGroups, 4)
+	ng1 := req.NodeGroups["ng1"]
+	assert.Equal(t, "template-ng1", ng1.TemplateNode.Name)
+	assert.Equal(t, int32(1), ng1.MinSize)
+	assert.Equal(t, int32(10), ng1.MaxSize)
+	assert.Equal(t, int32(2), ng1.TargetSize)
+	assert.True(t, ng1.PricingAvailable)
+	assert.Equal(t, 1.0, ng1.NodePrice)
+	assert.False(t, ng1.BackedOff)
+	assert.True(t, req.NodeGroups["ng3"].BackedOff)
+	assert.Equal(t, "QuotaExceeded", req.NodeGroups["ng3"].BackoffErrorCode)
+	assert.Equal(t, "out of quota", req.NodeGroups["ng3"].BackoffErrorMessage)
+	assert.False(t, req.NodeGroups["ng4"].PricingAvailable)
+
+	assert.Len(t, req.PodEquivalenceGroups, 2)
+	assert.Equal(t, "p1", req.PodEquivalenceGroups[0].Pod.Name)
+	assert.Equal(t, []string{"default/p1", "default/p2"}, req.PodEquivalenceGroups[0].Pods)
+	assert.Equal(t, []string{"default/p3"}, req.PodEquivalenceGroups[1].Pods)
+}
+
+func TestScoringFilterBestOptions(t *testing.T) {
+	testCases := []struct {
+		name     string
+		scores   []*protos.OptionScore
+		err      error
+		expected []int
+	}{
+		{
+			name: "highest score",
+			scores: []*protos.OptionScore{
+				{NodeGroupId: "ng1", Score: 1},
+				{NodeGroupId: "ng2", Score: 3},
+				{NodeGroupId: "ng3", Score: 2},
+			},
+			expected: []int{1},
+		},
+		{
+			name: "ties kept",
+			scores: []*protos.OptionScore{
+				{NodeGroupId: "ng1", Score: 3},
+				{NodeGroupId: "ng2", Score: 1},
+				{NodeGroupId: "ng4", Score: 3},
+			},
+			expected: []int{0, 3},
+		},
+		{
+			name: "invalid scores ignored",
+			scores: []*protos.OptionScore{
+				{NodeGroupId: "unknown", Score: 10},
+				{NodeGroupId: "ng1", Score: math.NaN()},
+				{NodeGroupId: "ng2", Score: -1},
+			},
+			expected: []int{1},
+		},
+		{
+			name:     "no scores",
+			expected: []int{0, 1, 2, 3},
+		},
+		{
+			name:     "error",
+			err:      fmt.Errorf("boom"),
+			expected: []int{0, 1, 2, 3},
+		},
+		{
+			name:     "not implemented",
+			err:      status.Error(codes.Unimplemented, "not implemented"),
+			expected: []int{0, 1, 2, 3},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filter, _, options, nodeInfos := newScoringTestEnv(t, func(*protos.ScoreOptionsRequest) ([]*protos.OptionScore, error) {
+				return tc.scores, tc.err
+			})
+			var expected []expander.Option
+			for _, i := range tc.expected {
+				expected = append(expected, options[i])
+			}
+			assert.Equal(t, expected, filter.BestOptions(options, nodeInfos))
+		})
+	}
+}
+
+func TestScoringFilterExplanations(t *testing.T) {
+	filter, server, options, nodeInfos := newScoringTestEnv(t, fake.CheapestScore)
+	assert.Equal(t, map[string]float64{"ng1": -2, "ng2": -1}, filter.Scores(options, nodeInfos))
+	assert.Equal(t, map[string]string{
+		"ng1": "2 nodes cost 2.000 per hour",
+		"ng2": "2 nodes cost 1.000 per hour",
+	}, filter.Explanations(options, nodeInfos))
+	assert.Equal(t, []expander.Option{options[1]}, filter.BestOptions(options, nodeInfos))
+	assert.Len(t, server.Requests(), 1)
+
+	// Scores aren't reused by the next decision.
+	assert.Equal(t, []expander.Option{options[1]}, filter.BestOptions(options, nodeInfos))
+	assert.Len(t, server.Requests(), 2)
+
+	// Nor by the next scoring, e.g. by the weighted expander.
+	assert.Equal(t, map[string]float64{"ng1": -2, "ng2": -1}, filter.Scores(options, nodeInfos))
+	assert.Equal(t, map[string]float64{"ng1": -2, "ng2": -1}, filter.Scores(options, nodeInfos))
+	assert.Len(t, server.Requests(), 4)
+}
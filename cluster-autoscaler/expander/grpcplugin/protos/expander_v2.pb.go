@@ -0,0 +1,692 @@
+//
+//Copyright 2024 The Kubernetes Authors.
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: expander/grpcplugin/protos/expander_v2.proto
+
+package protos
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	v1 "k8s.io/api/core/v1"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ScoreOptionsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Options []*ScoreOption `protobuf:"bytes,1,rep,name=options,proto3" json:"options,omitempty"`
+	// key is node group id from options
+	NodeGroups map[string]*NodeGroupInfo `protobuf:"bytes,2,rep,name=nodeGroups,proto3" json:"nodeGroups,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// pods helped by the options, grouped by scheduling properties
+	PodEquivalenceGroups []*PodEquivalenceGroup `protobuf:"bytes,3,rep,name=podEquivalenceGroups,proto3" json:"podEquivalenceGroups,omitempty"`
+}
+
+func (x *ScoreOptionsRequest) Reset() {
+	*x = ScoreOptionsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_expander_grpcplugin_protos_expander_v2_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ScoreOptionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScoreOptionsRequest) ProtoMessage() {}
+
+func (x *ScoreOptionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_expander_grpcplugin_protos_expander_v2_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScoreOptionsRequest.ProtoReflect.Descriptor instead.
+func (*ScoreOptionsRequest) Descriptor() ([]byte, []int) {
+	return file_expander_grpcplugin_protos_expander_v2_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ScoreOptionsRequest) GetOptions() []*ScoreOption {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *ScoreOptionsRequest) GetNodeGroups() map[string]*NodeGroupInfo {
+	if x != nil {
+		return x.NodeGroups
+	}
+	return nil
+}
+
+func (x *ScoreOptionsRequest) GetPodEquivalenceGroups() []*PodEquivalenceGroup {
+	if x != nil {
+		return x.PodEquivalenceGroups
+	}
+	return nil
+}
+
+type ScoreOptionsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Scores []*OptionScore `protobuf:"bytes,1,rep,name=scores,proto3" json:"scores,omitempty"`
+}
+
+func (x *ScoreOptionsResponse) Reset() {
+	*x = ScoreOptionsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_expander_grpcplugin_protos_expander_v2_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ScoreOptionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScoreOptionsResponse) ProtoMessage() {}
+
+func (x *ScoreOptionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_expander_grpcplugin_protos_expander_v2_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScoreOptionsResponse.ProtoReflect.Descriptor instead.
+func (*ScoreOptionsResponse) Descriptor() ([]byte, []int) {
+	return file_expander_grpcplugin_protos_expander_v2_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ScoreOptionsResponse) GetScores() []*OptionScore {
+	if x != nil {
+		return x.Scores
+	}
+	return nil
+}
+
+type ScoreOption struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NodeGroupId string `protobuf:"bytes,1,opt,name=nodeGroupId,proto3" json:"nodeGroupId,omitempty"`
+	NodeCount   int32  `protobuf:"varint,2,opt,name=nodeCount,proto3" json:"nodeCount,omitempty"`
+	Debug       string `protobuf:"bytes,3,opt,name=debug,proto3" json:"debug,omitempty"`
+	// namespace/name of the pods which would be scheduled on the new nodes
+	Pods []string `protobuf:"bytes,4,rep,name=pods,proto3" json:"pods,omitempty"`
+}
+
+func (x *ScoreOption) Reset() {
+	*x = ScoreOption{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_expander_grpcplugin_protos_expander_v2_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ScoreOption) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScoreOption) ProtoMessage() {}
+
+func (x *ScoreOption) ProtoReflect() protoreflect.Message {
+	mi := &file_expander_grpcplugin_protos_expander_v2_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScoreOption.ProtoReflect.Descriptor instead.
+func (*ScoreOption) Descriptor() ([]byte, []int) {
+	return file_expander_grpcplugin_protos_expander_v2_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ScoreOption) GetNodeGroupId() string {
+	if x != nil {
+		return x.NodeGroupId
+	}
+	return ""
+}
+
+func (x *ScoreOption) GetNodeCount() int32 {
+	if x != nil {
+		return x.NodeCount
+	}
+	return 0
+}
+
+func (x *ScoreOption) GetDebug() string {
+	if x != nil {
+		return x.Debug
+	}
+	return ""
+}
+
+func (x *ScoreOption) GetPods() []string {
+	if x != nil {
+		return x.Pods
+	}
+	return nil
+}
+
+type NodeGroupInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id           string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	TemplateNode *v1.Node `protobuf:"bytes,2,opt,name=templateNode,proto3" json:"templateNode,omitempty"`
+	MinSize      int32    `protobuf:"varint,3,opt,name=minSize,proto3" json:"minSize,omitempty"`
+	MaxSize      int32    `protobuf:"varint,4,opt,name=maxSize,proto3" json:"maxSize,omitempty"`
+	TargetSize   int32    `protobuf:"varint,5,opt,name=targetSize,proto3" json:"targetSize,omitempty"`
+	// whether the cloud provider implements pricing
+	PricingAvailable bool `protobuf:"varint,6,opt,name=pricingAvailable,proto3" json:"pricingAvailable,omitempty"`
+	// price of running a template node for an hour
+	NodePrice           float64 `protobuf:"fixed64,7,opt,name=nodePrice,proto3" json:"nodePrice,omitempty"`
+	BackedOff           bool    `protobuf:"varint,8,opt,name=backedOff,proto3" json:"backedOff,omitempty"`
+	BackoffErrorCode    string  `protobuf:"bytes,9,opt,name=backoffErrorCode,proto3" json:"backoffErrorCode,omitempty"`
+	BackoffErrorMessage string  `protobuf:"bytes,10,opt,name=backoffErrorMessage,proto3" json:"backoffErrorMessage,omitempty"`
+}
+
+func (x *NodeGroupInfo) Reset() {
+	*x = NodeGroupInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_expander_grpcplugin_protos_expander_v2_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NodeGroupInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeGroupInfo) ProtoMessage() {}
+
+func (x *NodeGroupInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_expander_grpcplugin_protos_expander_v2_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeGroupInfo.ProtoReflect.Descriptor instead.
+func (*NodeGroupInfo) Descriptor() ([]byte, []int) {
+	return file_expander_grpcplugin_protos_expander_v2_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *NodeGroupInfo) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *NodeGroupInfo) GetTemplateNode() *v1.Node {
+	if x != nil {
+		return x.TemplateNode
+	}
+	return nil
+}
+
+func (x *NodeGroupInfo) GetMinSize() int32 {
+	if x != nil {
+		return x.MinSize
+	}
+	return 0
+}
+
+func (x *NodeGroupInfo) GetMaxSize() int32 {
+	if x != nil {
+		return x.MaxSize
+	}
+	return 0
+}
+
+func (x *NodeGroupInfo) GetTargetSize() int32 {
+	if x != nil {
+		return x.TargetSize
+	}
+	return 0
+}
+
+func (x *NodeGroupInfo) GetPricingAvailable() bool {
+	if x != nil {
+		return x.PricingAvailable
+	}
+	return false
+}
+
+func (x *NodeGroupInfo) GetNodePrice() float64 {
+	if x != nil {
+		return x.NodePrice
+	}
+	return 0
+}
+
+func (x *NodeGroupInfo) GetBackedOff() bool {
+	if x != nil {
+		return x.BackedOff
+	}
+	return false
+}
+
+func (x *NodeGroupInfo) GetBackoffErrorCode() string {
+	if x != nil {
+		return x.BackoffErrorCode
+	}
+	return ""
+}
+
+func (x *NodeGroupInfo) GetBackoffErrorMessage() string {
+	if x != nil {
+		return x.BackoffErrorMessage
+	}
+	return ""
+}
+
+type PodEquivalenceGroup struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// a pod representing all pods of the group
+	Pod *v1.Pod `protobuf:"bytes,1,opt,name=pod,proto3" json:"pod,omitempty"`
+	// namespace/name of the pods of the group
+	Pods []string `protobuf:"bytes,2,rep,name=pods,proto3" json:"pods,omitempty"`
+}
+
+func (x *PodEquivalenceGroup) Reset() {
+	*x = PodEquivalenceGroup{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_expander_grpcplugin_protos_expander_v2_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PodEquivalenceGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PodEquivalenceGroup) ProtoMessage() {}
+
+func (x *PodEquivalenceGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_expander_grpcplugin_protos_expander_v2_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PodEquivalenceGroup.ProtoReflect.Descriptor instead.
+func (*PodEquivalenceGroup) Descriptor() ([]byte, []int) {
+	return file_expander_grpcplugin_protos_expander_v2_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PodEquivalenceGroup) GetPod() *v1.Pod {
+	if x != nil {
+		return x.Pod
+	}
+	return nil
+}
+
+func (x *PodEquivalenceGroup) GetPods() []string {
+	if x != nil {
+		return x.Pods
+	}
+	return nil
+}
+
+type OptionScore struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NodeGroupId string  `protobuf:"bytes,1,opt,name=nodeGroupId,proto3" json:"nodeGroupId,omitempty"`
+	Score       float64 `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	// human readable reason of the score
+	Explanation string `protobuf:"bytes,3,opt,name=explanation,proto3" json:"explanation,omitempty"`
+}
+
+func (x *OptionScore) Reset() {
+	*x = OptionScore{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_expander_grpcplugin_protos_expander_v2_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OptionScore) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OptionScore) ProtoMessage() {}
+
+func (x *OptionScore) ProtoReflect() protoreflect.Message {
+	mi := &file_expander_grpcplugin_protos_expander_v2_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OptionScore.ProtoReflect.Descriptor instead.
+func (*OptionScore) Descriptor() ([]byte, []int) {
+	return file_expander_grpcplugin_protos_expander_v2_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *OptionScore) GetNodeGroupId() string {
+	if x != nil {
+		return x.NodeGroupId
+	}
+	return ""
+}
+
+func (x *OptionScore) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *OptionScore) GetExplanation() string {
+	if x != nil {
+		return x.Explanation
+	}
+	return ""
+}
+
+var File_expander_grpcplugin_protos_expander_v2_proto protoreflect.FileDescriptor
+
+var file_expander_grpcplugin_protos_expander_v2_proto_rawDesc = []byte{
+	0x0a, 0x2c, 0x65, 0x78, 0x70, 0x61, 0x6e, 0x64, 0x65, 0x72, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x70,
+	0x6c, 0x75, 0x67, 0x69, 0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2f, 0x65, 0x78, 0x70,
+	0x61, 0x6e, 0x64, 0x65, 0x72, 0x5f, 0x76, 0x32, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0a,
+	0x67, 0x72, 0x70, 0x63, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x1a, 0x22, 0x6b, 0x38, 0x73, 0x2e,
+	0x69, 0x6f, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x76, 0x31, 0x2f, 0x67,
+	0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xc8,
+	0x02, 0x0a, 0x13, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x31, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x70, 0x6c,
+	0x75, 0x67, 0x69, 0x6e, 0x2e, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x4f, 0x0a, 0x0a, 0x6e, 0x6f, 0x64,
+	0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2f, 0x2e,
+	0x67, 0x72, 0x70, 0x63, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x2e, 0x53, 0x63, 0x6f, 0x72, 0x65,
+	0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x4e,
+	0x6f, 0x64, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0a,
+	0x6e, 0x6f, 0x64, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x12, 0x53, 0x0a, 0x14, 0x70, 0x6f,
+	0x64, 0x45, 0x71, 0x75, 0x69, 0x76, 0x61, 0x6c, 0x65, 0x6e, 0x63, 0x65, 0x47, 0x72, 0x6f, 0x75,
+	0x70, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x70,
+	0x6c, 0x75, 0x67, 0x69, 0x6e, 0x2e, 0x50, 0x6f, 0x64, 0x45, 0x71, 0x75, 0x69, 0x76, 0x61, 0x6c,
+	0x65, 0x6e, 0x63, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x14, 0x70, 0x6f, 0x64, 0x45, 0x71,
+	0x75, 0x69, 0x76, 0x61, 0x6c, 0x65, 0x6e, 0x63, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x1a,
+	0x58, 0x0a, 0x0f, 0x4e, 0x6f, 0x64, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x6b, 0x65, 0x79, 0x12, 0x2f, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e,
+	0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x47, 0x0a, 0x14, 0x53, 0x63, 0x6f,
+	0x72, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x2f, 0x0a, 0x06, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x17, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x2e, 0x4f,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x52, 0x06, 0x73, 0x63, 0x6f, 0x72,
+	0x65, 0x73, 0x22, 0x77, 0x0a, 0x0b, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x6e, 0x6f, 0x64, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6e, 0x6f, 0x64, 0x65, 0x47, 0x72, 0x6f, 0x75,
+	0x70, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x6f, 0x64, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x6e, 0x6f, 0x64, 0x65, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x12, 0x14, 0x0a, 0x05, 0x64, 0x65, 0x62, 0x75, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x64, 0x65, 0x62, 0x75, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x64, 0x73, 0x18,
+	0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x70, 0x6f, 0x64, 0x73, 0x22, 0xf7, 0x02, 0x0a, 0x0d,
+	0x4e, 0x6f, 0x64, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x0e, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x3c, 0x0a,
+	0x0c, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6b, 0x38, 0x73, 0x2e, 0x69, 0x6f, 0x2e, 0x61, 0x70, 0x69,
+	0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x0c, 0x74,
+	0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d,
+	0x69, 0x6e, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x6d, 0x69,
+	0x6e, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x61, 0x78, 0x53, 0x69, 0x7a, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x6d, 0x61, 0x78, 0x53, 0x69, 0x7a, 0x65, 0x12,
+	0x1e, 0x0a, 0x0a, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0a, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x53, 0x69, 0x7a, 0x65, 0x12,
+	0x2a, 0x0a, 0x10, 0x70, 0x72, 0x69, 0x63, 0x69, 0x6e, 0x67, 0x41, 0x76, 0x61, 0x69, 0x6c, 0x61,
+	0x62, 0x6c, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x70, 0x72, 0x69, 0x63, 0x69,
+	0x6e, 0x67, 0x41, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6e,
+	0x6f, 0x64, 0x65, 0x50, 0x72, 0x69, 0x63, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09,
+	0x6e, 0x6f, 0x64, 0x65, 0x50, 0x72, 0x69, 0x63, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x62, 0x61, 0x63,
+	0x6b, 0x65, 0x64, 0x4f, 0x66, 0x66, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x62, 0x61,
+	0x63, 0x6b, 0x65, 0x64, 0x4f, 0x66, 0x66, 0x12, 0x2a, 0x0a, 0x10, 0x62, 0x61, 0x63, 0x6b, 0x6f,
+	0x66, 0x66, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x10, 0x62, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x43,
+	0x6f, 0x64, 0x65, 0x12, 0x30, 0x0a, 0x13, 0x62, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66, 0x45, 0x72,
+	0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x13, 0x62, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x54, 0x0a, 0x13, 0x50, 0x6f, 0x64, 0x45, 0x71, 0x75, 0x69,
+	0x76, 0x61, 0x6c, 0x65, 0x6e, 0x63, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x29, 0x0a, 0x03,
+	0x70, 0x6f, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6b, 0x38, 0x73, 0x2e,
+	0x69, 0x6f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50,
+	0x6f, 0x64, 0x52, 0x03, 0x70, 0x6f, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x64, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x70, 0x6f, 0x64, 0x73, 0x22, 0x67, 0x0a, 0x0b, 0x4f,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x6e, 0x6f,
+	0x64, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x6e, 0x6f, 0x64, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05,
+	0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x73, 0x63, 0x6f,
+	0x72, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x65, 0x78, 0x70, 0x6c, 0x61, 0x6e, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x65, 0x78, 0x70, 0x6c, 0x61, 0x6e, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x32, 0x61, 0x0a, 0x0a, 0x45, 0x78, 0x70, 0x61, 0x6e, 0x64, 0x65, 0x72,
+	0x56, 0x32, 0x12, 0x53, 0x0a, 0x0c, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x12, 0x1f, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x2e,
+	0x53, 0x63, 0x6f, 0x72, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e,
+	0x2e, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x2f, 0x5a, 0x2d, 0x63, 0x6c, 0x75, 0x73, 0x74,
+	0x65, 0x72, 0x2d, 0x61, 0x75, 0x74, 0x6f, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x72, 0x2f, 0x65, 0x78,
+	0x70, 0x61, 0x6e, 0x64, 0x65, 0x72, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x70, 0x6c, 0x75, 0x67, 0x69,
+	0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_expander_grpcplugin_protos_expander_v2_proto_rawDescOnce sync.Once
+	file_expander_grpcplugin_protos_expander_v2_proto_rawDescData = file_expander_grpcplugin_protos_expander_v2_proto_rawDesc
+)
+
+func file_expander_grpcplugin_protos_expander_v2_proto_rawDescGZIP() []byte {
+	file_expander_grpcplugin_protos_expander_v2_proto_rawDescOnce.Do(func() {
+		file_expander_grpcplugin_protos_expander_v2_proto_rawDescData = protoimpl.X.CompressGZIP(file_expander_grpcplugin_protos_expander_v2_proto_rawDescData)
+	})
+	return file_expander_grpcplugin_protos_expander_v2_proto_rawDescData
+}
+
+var file_expander_grpcplugin_protos_expander_v2_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_expander_grpcplugin_protos_expander_v2_proto_goTypes = []any{
+	(*ScoreOptionsRequest)(nil),  // 0: grpcplugin.ScoreOptionsRequest
+	(*ScoreOptionsResponse)(nil), // 1: grpcplugin.ScoreOptionsResponse
+	(*ScoreOption)(nil),          // 2: grpcplugin.ScoreOption
+	(*NodeGroupInfo)(nil),        // 3: grpcplugin.NodeGroupInfo
+	(*PodEquivalenceGroup)(nil),  // 4: grpcplugin.PodEquivalenceGroup
+	(*OptionScore)(nil),          // 5: grpcplugin.OptionScore
+	nil,                          // 6: grpcplugin.ScoreOptionsRequest.NodeGroupsEntry
+	(*v1.Node)(nil),              // 7: k8s.io.api.core.v1.Node
+	(*v1.Pod)(nil),               // 8: k8s.io.api.core.v1.Pod
+}
+var file_expander_grpcplugin_protos_expander_v2_proto_depIdxs = []int32{
+	2, // 0: grpcplugin.ScoreOptionsRequest.options:type_name -> grpcplugin.ScoreOption
+	6, // 1: grpcplugin.ScoreOptionsRequest.nodeGroups:type_name -> grpcplugin.ScoreOptionsRequest.NodeGroupsEntry
+	4, // 2: grpcplugin.ScoreOptionsRequest.podEquivalenceGroups:type_name -> grpcplugin.PodEquivalenceGroup
+	5, // 3: grpcplugin.ScoreOptionsResponse.scores:type_name -> grpcplugin.OptionScore
+	7, // 4: grpcplugin.NodeGroupInfo.templateNode:type_name -> k8s.io.api.core.v1.Node
+	8, // 5: grpcplugin.PodEquivalenceGroup.pod:type_name -> k8s.io.api.core.v1.Pod
+	3, // 6: grpcplugin.ScoreOptionsRequest.NodeGroupsEntry.value:type_name -> grpcplugin.NodeGroupInfo
+	0, // 7: grpcplugin.ExpanderV2.ScoreOptions:input_type -> grpcplugin.ScoreOptionsRequest
+	1, // 8: grpcplugin.ExpanderV2.ScoreOptions:output_type -> grpcplugin.ScoreOptionsResponse
+	8, // [8:9] is the sub-list for method output_type
+	7, // [7:8] is the sub-list for method input_type
+	7, // [7:7] is the sub-list for extension type_name
+	7, // [7:7] is the sub-list for extension extendee
+	0, // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_expander_grpcplugin_protos_expander_v2_proto_init() }
+func file_expander_grpcplugin_protos_expander_v2_proto_init() {
+	if File_expander_grpcplugin_protos_expander_v2_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_expander_grpcplugin_protos_expander_v2_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*ScoreOptionsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_expander_grpcplugin_protos_expander_v2_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*ScoreOptionsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_expander_grpcplugin_protos_expander_v2_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*ScoreOption); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_expander_grpcplugin_protos_expander_v2_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*NodeGroupInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_expander_grpcplugin_protos_expander_v2_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*PodEquivalenceGroup); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_expander_grpcplugin_protos_expander_v2_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*OptionScore); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_expander_grpcplugin_protos_expander_v2_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_expander_grpcplugin_protos_expander_v2_proto_goTypes,
+		DependencyIndexes: file_expander_grpcplugin_protos_expander_v2_proto_depIdxs,
+		MessageInfos:      file_expander_grpcplugin_protos_expander_v2_proto_msgTypes,
+	}.Build()
+	File_expander_grpcplugin_protos_expander_v2_proto = out.File
+	file_expander_grpcplugin_protos_expander_v2_proto_rawDesc = nil
+	file_expander_grpcplugin_protos_expander_v2_proto_goTypes = nil
+	file_expander_grpcplugin_protos_expander_v2_proto_depIdxs = nil
+}
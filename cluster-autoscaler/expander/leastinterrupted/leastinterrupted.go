@@ -0,0 +1,61 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package leastinterrupted
+
+import (
+	"math"
+	"time"
+
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/expander"
+	schedulerframework "k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// InterruptionCounter provides the number of recent interruptions of a node group.
+type InterruptionCounter interface {
+	InterruptionCount(nodeGroup cloudprovider.NodeGroup, nodeInfo *schedulerframework.NodeInfo, currentTime time.Time) int
+}
+
+type leastinterrupted struct {
+	counter InterruptionCounter
+}
+
+// NewFilter returns a scale up filter that picks the node groups with the least recent
+// interruptions, e.g. reclaimed spot instances or out of capacity errors.
+func NewFilter(counter InterruptionCounter) expander.Filter {
+	return &leastinterrupted{counter: counter}
+}
+
+// BestOptions selects the expansion options whose node groups were interrupted the least recently.
+func (l *leastinterrupted) BestOptions(expansionOptions []expander.Option, nodeInfo map[string]*schedulerframework.NodeInfo) []expander.Option {
+	now := time.Now()
+	leastInterruptions := math.MaxInt
+	var leastOptions []expander.Option
+
+	for _, option := range expansionOptions {
+		interruptions := l.counter.InterruptionCount(option.NodeGroup, nodeInfo[option.NodeGroup.Id()], now)
+		if interruptions == leastInterruptions {
+			leastOptions = append(leastOptions, option)
+			continue
+		}
+		if interruptions < leastInterruptions {
+			leastInterruptions = interruptions
+			leastOptions = []expander.Option{option}
+		}
+	}
+	return leastOptions
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package leastinterrupted
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/expander"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/backoff"
+)
+
+func TestLeastInterrupted(t *testing.T) {
+	provider := testprovider.NewTestCloudProvider(nil, nil)
+	for _, id := range []string{"spot-a", "spot-b", "on-demand"} {
+		provider.AddNodeGroup(id, 0, 10, 0)
+	}
+	spotA := provider.GetNodeGroup("spot-a")
+	spotB := provider.GetNodeGroup("spot-b")
+	onDemand := provider.GetNodeGroup("on-demand")
+
+	optionSpotA := expander.Option{NodeGroup: spotA, NodeCount: 1, Debug: "spot-a"}
+	optionSpotB := expander.Option{NodeGroup: spotB, NodeCount: 1, Debug: "spot-b"}
+	optionOnDemand := expander.Option{NodeGroup: onDemand, NodeCount: 1, Debug: "on-demand"}
+
+	nodeGroupBackoff := backoff.NewIdBasedExponentialBackoff(time.Minute, time.Hour, time.Hour)
+	filter := NewFilter(nodeGroupBackoff)
+	allOptions := []expander.Option{optionSpotA, optionSpotB, optionOnDemand}
+
+	assert.Nil(t, filter.BestOptions(nil, nil))
+	assert.Equal(t, allOptions, filter.BestOptions(allOptions, nil))
+
+	now := time.Now()
+	interruption := cloudprovider.InstanceErrorInfo{ErrorClass: cloudprovider.OutOfResourcesErrorClass, ErrorCode: "interrupted"}
+	nodeGroupBackoff.RegisterInterruption(spotA, nil, interruption, now)
+	nodeGroupBackoff.RegisterInterruption(spotA, nil, interruption, now)
+	nodeGroupBackoff.RegisterInterruption(spotB, nil, interruption, now)
+	assert.Equal(t, []expander.Option{optionOnDemand}, filter.BestOptions(allOptions, nil))
+	assert.Equal(t, []expander.Option{optionSpotB}, filter.BestOptions([]expander.Option{optionSpotA, optionSpotB}, nil))
+}
@@ -59,3 +59,12 @@ func (m *leastnodes) BestOptions(expansionOptions []expander.Option, nodeInfo ma
 
 	return leastOptions
 }
+
+// Scores returns the number of nodes each option uses.
+func (m *leastnodes) Scores(expansionOptions []expander.Option, nodeInfo map[string]*schedulerframework.NodeInfo) map[string]float64 {
+	scores := make(map[string]float64, len(expansionOptions))
+	for _, option := range expansionOptions {
+		scores[option.NodeGroup.Id()] = float64(option.NodeCount)
+	}
+	return scores
+}
@@ -52,3 +52,12 @@ func (m *mostpods) BestOptions(expansionOptions []expander.Option, nodeInfo map[
 
 	return maxOptions
 }
+
+// Scores returns the number of pods each option schedules.
+func (m *mostpods) Scores(expansionOptions []expander.Option, nodeInfo map[string]*schedulerframework.NodeInfo) map[string]float64 {
+	scores := make(map[string]float64, len(expansionOptions))
+	for _, option := range expansionOptions {
+		scores[option.NodeGroup.Id()] = float64(len(option.Pods))
+	}
+	return scores
+}
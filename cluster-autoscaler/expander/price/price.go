@@ -90,6 +90,44 @@ func NewFilter(cloudProvider cloudprovider.CloudProvider,
 func (p *priceBased) BestOptions(expansionOptions []expander.Option, nodeInfos map[string]*schedulerframework.NodeInfo) []expander.Option {
 	var bestOptions []expander.Option
 	bestOptionScore := 0.0
+
+	for _, scored := range p.scoreOptions(expansionOptions, nodeInfos) {
+		maybeBestOption := expander.Option{
+			NodeGroup: scored.option.NodeGroup,
+			NodeCount: scored.option.NodeCount,
+			Debug:     fmt.Sprintf("%s | price-expander: %s", scored.option.Debug, scored.debug),
+			Pods:      scored.option.Pods,
+		}
+		if len(bestOptions) == 0 || bestOptionScore == scored.score {
+			bestOptions = append(bestOptions, maybeBestOption)
+			bestOptionScore = scored.score
+		} else if bestOptionScore > scored.score {
+			bestOptions = []expander.Option{maybeBestOption}
+			bestOptionScore = scored.score
+		}
+	}
+	return bestOptions
+}
+
+// Scores returns the price score of each option, lower is better. Options
+// which couldn't be priced have no score.
+func (p *priceBased) Scores(expansionOptions []expander.Option, nodeInfos map[string]*schedulerframework.NodeInfo) map[string]float64 {
+	scores := make(map[string]float64, len(expansionOptions))
+	for _, scored := range p.scoreOptions(expansionOptions, nodeInfos) {
+		scores[scored.option.NodeGroup.Id()] = scored.score
+	}
+	return scores
+}
+
+type scoredOption struct {
+	option expander.Option
+	score  float64
+	debug  string
+}
+
+// scoreOptions computes the score of options which can be priced.
+func (p *priceBased) scoreOptions(expansionOptions []expander.Option, nodeInfos map[string]*schedulerframework.NodeInfo) []scoredOption {
+	var scoredOptions []scoredOption
 	now := time.Now()
 	then := now.Add(time.Hour)
 
@@ -169,21 +207,9 @@ nextoption:
 
 		klog.V(5).Infof("Price expander for %s: %s", option.NodeGroup.Id(), debug)
 
-		maybeBestOption := expander.Option{
-			NodeGroup: option.NodeGroup,
-			NodeCount: option.NodeCount,
-			Debug:     fmt.Sprintf("%s | price-expander: %s", option.Debug, debug),
-			Pods:      option.Pods,
-		}
-		if len(bestOptions) == 0 || bestOptionScore == optionScore {
-			bestOptions = append(bestOptions, maybeBestOption)
-			bestOptionScore = optionScore
-		} else if bestOptionScore > optionScore {
-			bestOptions = []expander.Option{maybeBestOption}
-			bestOptionScore = optionScore
-		}
+		scoredOptions = append(scoredOptions, scoredOption{option: option, score: optionScore, debug: debug})
 	}
-	return bestOptions
+	return scoredOptions
 }
 
 // buildPod creates a pod with specified resources.
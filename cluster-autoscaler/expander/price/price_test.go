@@ -137,6 +137,15 @@ func TestPriceExpander(t *testing.T) {
 		},
 		SimpleNodeUnfitness,
 	).BestOptions(options, nodeInfosForGroups)), []string{"ng1"})
+	scores := NewFilter(
+		provider,
+		&testPreferredNodeProvider{
+			preferred: buildNode(2000, units.GiB),
+		},
+		SimpleNodeUnfitness,
+	).(expander.Scorer).Scores(options, nodeInfosForGroups)
+	assert.Len(t, scores, 2)
+	assert.Less(t, scores["ng1"], scores["ng2"])
 
 	// First node group is cheaper, however, the second one is preferred.
 	pricingModel = &testPricingModel{
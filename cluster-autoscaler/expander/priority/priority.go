@@ -89,6 +89,19 @@ func (p *priority) logConfigWarning(cm *apiv1.ConfigMap, reason, msg string) {
 }
 
 func (p *priority) parsePrioritiesYAMLString(prioritiesYAML string) (priorities, error) {
+	newPriorities, err := parsePriorities(prioritiesYAML)
+	if err != nil {
+		return nil, err
+	}
+
+	p.okConfigUpdates++
+	msg := "Successfully loaded priority configuration from configmap."
+	klog.V(4).Info(msg)
+
+	return newPriorities, nil
+}
+
+func parsePriorities(prioritiesYAML string) (priorities, error) {
 	if prioritiesYAML == "" {
 		return nil, fmt.Errorf("priority configuration in %s configmap is empty; please provide valid configuration",
 			PriorityConfigMapName)
@@ -108,11 +121,6 @@ func (p *priority) parsePrioritiesYAMLString(prioritiesYAML string) (priorities,
 			newPriorities[prio] = append(newPriorities[prio], regexp)
 		}
 	}
-
-	p.okConfigUpdates++
-	msg := "Successfully loaded priority configuration from configmap."
-	klog.V(4).Info(msg)
-
 	return newPriorities, nil
 }
 
@@ -132,7 +140,7 @@ func (p *priority) BestOptions(expansionOptions []expander.Option, nodeInfo map[
 		id := option.NodeGroup.Id()
 		found := false
 		for prio, nameRegexpList := range priorities {
-			if !p.groupIDMatchesList(id, nameRegexpList) {
+			if !groupIDMatchesList(id, nameRegexpList) {
 				continue
 			}
 			found = true
@@ -165,7 +173,54 @@ func (p *priority) BestOptions(expansionOptions []expander.Option, nodeInfo map[
 	return best
 }
 
-func (p *priority) groupIDMatchesList(id string, nameRegexpList []*regexp.Regexp) bool {
+// Scores returns the highest priority assigned to the node group of each
+// option. Options of node groups without a priority have no score. Unlike
+// BestOptions, it doesn't report configuration problems.
+func (p *priority) Scores(expansionOptions []expander.Option, nodeInfo map[string]*schedulerframework.NodeInfo) map[string]float64 {
+	ids := make([]string, 0, len(expansionOptions))
+	for _, option := range expansionOptions {
+		ids = append(ids, option.NodeGroup.Id())
+	}
+	groupPriorities, err := NodeGroupPriorities(p.configMapLister, ids)
+	if err != nil {
+		return nil
+	}
+
+	scores := make(map[string]float64, len(groupPriorities))
+	for id, prio := range groupPriorities {
+		scores[id] = float64(prio)
+	}
+	return scores
+}
+
+// NodeGroupPriorities returns the highest priority assigned to each of the node
+// groups by the priority expander configuration. Node groups without a priority
+// are omitted.
+func NodeGroupPriorities(configMapLister v1lister.ConfigMapNamespaceLister, nodeGroupIds []string) (map[string]int, error) {
+	cm, err := configMapLister.Get(PriorityConfigMapName)
+	if err != nil {
+		return nil, fmt.Errorf("Priority expander config map %s not found: %v", PriorityConfigMapName, err)
+	}
+	priorities, err := parsePriorities(cm.Data[ConfigMapKey])
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]int, len(nodeGroupIds))
+	for _, id := range nodeGroupIds {
+		for prio, nameRegexpList := range priorities {
+			if current, found := result[id]; found && current >= prio {
+				continue
+			}
+			if groupIDMatchesList(id, nameRegexpList) {
+				result[id] = prio
+			}
+		}
+	}
+	return result, nil
+}
+
+func groupIDMatchesList(id string, nameRegexpList []*regexp.Regexp) bool {
 	for _, re := range nameRegexpList {
 		if re.FindStringIndex(id) != nil {
 			return true
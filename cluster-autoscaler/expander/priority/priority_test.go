@@ -167,3 +167,28 @@ func TestPriorityExpanderCorrecltySkipsBadChangeConfig(t *testing.T) {
 	assert.EqualValues(t, configWarnConfigMapEmpty, event)
 	assert.Equal(t, ret, []expander.Option{eoT2Large, eoT3Large, eoM44XLarge})
 }
+
+func TestPriorityExpanderScores(t *testing.T) {
+	s, r, _ := getFilterInstance(t, wildcardMatchConfig)
+	scores := s.(expander.Scorer).Scores([]expander.Option{eoT2Large, eoT2Micro}, nil)
+	assert.Equal(t, map[string]float64{eoT2Large.NodeGroup.Id(): 10, eoT2Micro.NodeGroup.Id(): 5}, scores)
+
+	s, r, _ = getFilterInstance(t, oneEntryConfig)
+	scores = s.(expander.Scorer).Scores([]expander.Option{eoT2Large, eoT3Large}, nil)
+	assert.Equal(t, map[string]float64{eoT2Large.NodeGroup.Id(): 10}, scores)
+	assert.Empty(t, r.Events)
+}
+
+func TestNodeGroupPriorities(t *testing.T) {
+	_, _, cm := getFilterInstance(t, config)
+	lister, err := kubernetes.NewTestConfigMapLister([]*apiv1.ConfigMap{cm})
+	assert.NoError(t, err)
+	priorities, err := NodeGroupPriorities(lister.ConfigMaps(testNamespace), []string{"my-asg.t2.micro", "my-asg.m4.4xlarge", "my-asg.c5.large"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"my-asg.t2.micro": 5, "my-asg.m4.4xlarge": 50}, priorities)
+
+	lister, err = kubernetes.NewTestConfigMapLister(nil)
+	assert.NoError(t, err)
+	_, err = NodeGroupPriorities(lister.ConfigMaps(testNamespace), []string{"my-asg.t2.micro"})
+	assert.Error(t, err)
+}
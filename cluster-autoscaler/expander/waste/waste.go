@@ -38,18 +38,13 @@ func (l *leastwaste) BestOptions(expansionOptions []expander.Option, nodeInfo ma
 	var leastWastedOptions []expander.Option
 
 	for _, option := range expansionOptions {
-		requestedCPU, requestedMemory := resourcesForPods(option.Pods)
 		node, found := nodeInfo[option.NodeGroup.Id()]
 		if !found {
 			klog.Errorf("No node info for: %s", option.NodeGroup.Id())
 			continue
 		}
 
-		nodeCPU, nodeMemory := resourcesForNode(node.Node())
-		availCPU := nodeCPU.MilliValue() * int64(option.NodeCount)
-		availMemory := nodeMemory.Value() * int64(option.NodeCount)
-		wastedCPU := float64(availCPU-requestedCPU.MilliValue()) / float64(availCPU)
-		wastedMemory := float64(availMemory-requestedMemory.Value()) / float64(availMemory)
+		wastedCPU, wastedMemory := wastedResources(option, node)
 		wastedScore := wastedCPU + wastedMemory
 
 		klog.V(1).Infof("Expanding Node Group %s would waste %0.2f%% CPU, %0.2f%% Memory, %0.2f%% Blended\n", option.NodeGroup.Id(), wastedCPU*100.0, wastedMemory*100.0, wastedScore*50.0)
@@ -71,6 +66,30 @@ func (l *leastwaste) BestOptions(expansionOptions []expander.Option, nodeInfo ma
 	return leastWastedOptions
 }
 
+// Scores returns the blended fraction of CPU and Memory each option would waste.
+func (l *leastwaste) Scores(expansionOptions []expander.Option, nodeInfo map[string]*schedulerframework.NodeInfo) map[string]float64 {
+	scores := make(map[string]float64, len(expansionOptions))
+	for _, option := range expansionOptions {
+		node, found := nodeInfo[option.NodeGroup.Id()]
+		if !found {
+			continue
+		}
+		wastedCPU, wastedMemory := wastedResources(option, node)
+		scores[option.NodeGroup.Id()] = (wastedCPU + wastedMemory) / 2
+	}
+	return scores
+}
+
+func wastedResources(option expander.Option, node *schedulerframework.NodeInfo) (wastedCPU float64, wastedMemory float64) {
+	requestedCPU, requestedMemory := resourcesForPods(option.Pods)
+	nodeCPU, nodeMemory := resourcesForNode(node.Node())
+	availCPU := nodeCPU.MilliValue() * int64(option.NodeCount)
+	availMemory := nodeMemory.Value() * int64(option.NodeCount)
+	wastedCPU = float64(availCPU-requestedCPU.MilliValue()) / float64(availCPU)
+	wastedMemory = float64(availMemory-requestedMemory.Value()) / float64(availMemory)
+	return wastedCPU, wastedMemory
+}
+
 func resourcesForPods(pods []*apiv1.Pod) (cpu resource.Quantity, memory resource.Quantity) {
 	for _, pod := range pods {
 		for _, container := range pod.Spec.Containers {
@@ -125,3 +125,21 @@ func TestLeastWaste(t *testing.T) {
 	ret = e.BestOptions([]expander.Option{balancedOption, highmemOption, lowcpuOption}, nodeMap)
 	assert.Equal(t, ret, []expander.Option{lowcpuOption})
 }
+
+func TestLeastWasteScores(t *testing.T) {
+	e := NewFilter().(expander.Scorer)
+	nodeMap := map[string]*schedulerframework.NodeInfo{
+		"balanced": makeNodeInfo(16000, 16*1024, 100),
+		"highmem":  makeNodeInfo(16000, 32*1024, 100),
+	}
+	pod := BuildTestPod("p", 1000, 1024)
+	options := []expander.Option{
+		{NodeGroup: &FakeNodeGroup{"balanced"}, NodeCount: 1, Pods: []*apiv1.Pod{pod}},
+		{NodeGroup: &FakeNodeGroup{"highmem"}, NodeCount: 1, Pods: []*apiv1.Pod{pod}},
+		{NodeGroup: &FakeNodeGroup{"unknown"}, NodeCount: 1, Pods: []*apiv1.Pod{pod}},
+	}
+	assert.Equal(t, map[string]float64{
+		"balanced": (15.0/16 + 15.0/16) / 2,
+		"highmem":  (15.0/16 + 31.0/32) / 2,
+	}, e.Scores(options, nodeMap))
+}
@@ -0,0 +1,278 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package weighted
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/expander"
+	klog "k8s.io/klog/v2"
+	schedulerframework "k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+const (
+	// ScoresEventReason is the reason of events reporting the scores of the best options.
+	ScoresEventReason = "WeightedExpanderScores"
+	// maxEventOptions is the maximum number of options reported in an event.
+	maxEventOptions = 5
+)
+
+// lowerIsBetter maps names of the expanders which can be combined to whether
+// lower scores of the expander are better.
+var lowerIsBetter = map[string]bool{
+	expander.LeastWasteExpanderName:    true,
+	expander.PriceBasedExpanderName:    true,
+	expander.LeastNodesExpanderName:    true,
+	expander.MostPodsExpanderName:      false,
+	expander.PriorityBasedExpanderName: false,
+	expander.GRPCV2ExpanderName:        false,
+}
+
+// EventRecorder records events on a central object, e.g. the status config map.
+type EventRecorder interface {
+	Eventf(eventtype, reason, message string, args ...interface{})
+}
+
+type criterion struct {
+	name          string
+	scorer        expander.Scorer
+	weight        float64
+	lowerIsBetter bool
+}
+
+type weighted struct {
+	criteria []criterion
+	recorder EventRecorder
+}
+
+// NewFilter returns a filter that normalizes the scores of other expanders to
+// [0, 1], 1 being the best, and picks the options with the highest weighted
+// average of them. Scorers are keyed by expander name, weights of expanders
+// without a scorer are ignored. Recorder is optional.
+func NewFilter(weights map[string]float64, scorers map[string]expander.Scorer, recorder EventRecorder) expander.Filter {
+	w := &weighted{recorder: recorder}
+	for _, name := range sortedNames(weights) {
+		scorer, found := scorers[name]
+		if !found {
+			klog.Errorf("Weighted expander: no scores available for %s, ignoring its weight", name)
+			continue
+		}
+		w.criteria = append(w.criteria, criterion{name: name, scorer: scorer, weight: weights[name], lowerIsBetter: lowerIsBetter[name]})
+	}
+	return w
+}
+
+// ParseWeights parses weights of expanders in the name=weight,name=weight
+// format. Only expanders which report scores can be weighted.
+func ParseWeights(value string) (map[string]float64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	weights := make(map[string]float64)
+	total := 0.0
+	for _, entry := range strings.Split(value, ",") {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid expander weight %q, expected name=weight", entry)
+		}
+		name := strings.TrimSpace(parts[0])
+		if _, found := lowerIsBetter[name]; !found {
+			return nil, fmt.Errorf("expander %s can't be weighted, supported expanders: %s", name, strings.Join(SupportedExpanders(), ", "))
+		}
+		if _, found := weights[name]; found {
+			return nil, fmt.Errorf("expander %s weighted more than once", name)
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		if err != nil || weight < 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+			return nil, fmt.Errorf("invalid weight %q of expander %s, expected a non-negative number", parts[1], name)
+		}
+		weights[name] = weight
+		total += weight
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("at least one expander weight must be positive")
+	}
+	return weights, nil
+}
+
+// SupportedExpanders returns the names of the expanders which can be weighted.
+func SupportedExpanders() []string {
+	names := make([]string, 0, len(lowerIsBetter))
+	for name := range lowerIsBetter {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BestOptions selects the options with the highest weighted score. The score
+// breakdown is appended to their debug information.
+func (w *weighted) BestOptions(expansionOptions []expander.Option, nodeInfo map[string]*schedulerframework.NodeInfo) []expander.Option {
+	breakdowns := w.score(expansionOptions, nodeInfo)
+	if breakdowns == nil {
+		return expansionOptions
+	}
+
+	bestScore := math.Inf(-1)
+	var bestOptions []expander.Option
+	for _, option := range expansionOptions {
+		breakdown := breakdowns[option.NodeGroup.Id()]
+		option.Debug = fmt.Sprintf("%s | weighted-expander: %s", option.Debug, breakdown)
+		if breakdown.score == bestScore {
+			bestOptions = append(bestOptions, option)
+			continue
+		}
+		if breakdown.score > bestScore {
+			bestScore = breakdown.score
+			bestOptions = []expander.Option{option}
+		}
+	}
+	w.recordScores(expansionOptions, breakdowns)
+	return bestOptions
+}
+
+// Scores returns the weighted score of each option, higher is better.
+func (w *weighted) Scores(expansionOptions []expander.Option, nodeInfo map[string]*schedulerframework.NodeInfo) map[string]float64 {
+	breakdowns := w.score(expansionOptions, nodeInfo)
+	if breakdowns == nil {
+		return nil
+	}
+	scores := make(map[string]float64, len(breakdowns))
+	for id, breakdown := range breakdowns {
+		scores[id] = breakdown.score
+	}
+	return scores
+}
+
+// Explanations returns the normalized score and weight of each expander for
+// each option.
+func (w *weighted) Explanations(expansionOptions []expander.Option, nodeInfo map[string]*schedulerframework.NodeInfo) map[string]string {
+	breakdowns := w.score(expansionOptions, nodeInfo)
+	if breakdowns == nil {
+		return nil
+	}
+	explanations := make(map[string]string, len(breakdowns))
+	for id, breakdown := range breakdowns {
+		explanations[id] = breakdown.String()
+	}
+	return explanations
+}
+
+type part struct {
+	name       string
+	weight     float64
+	normalized float64
+	scored     bool
+}
+
+type breakdown struct {
+	score float64
+	parts []part
+}
+
+func (b breakdown) String() string {
+	parts := make([]string, 0, len(b.parts))
+	for _, p := range b.parts {
+		if !p.scored {
+			parts = append(parts, fmt.Sprintf("%s=n/a*%g", p.name, p.weight))
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s=%.3f*%g", p.name, p.normalized, p.weight))
+	}
+	return fmt.Sprintf("score=%.3f (%s)", b.score, strings.Join(parts, " "))
+}
+
+// score computes the weighted score of each option, keyed by node group id.
+// Returns nil if there are no criteria to score the options with.
+func (w *weighted) score(expansionOptions []expander.Option, nodeInfo map[string]*schedulerframework.NodeInfo) map[string]breakdown {
+	totalWeight := 0.0
+	for _, c := range w.criteria {
+		totalWeight += c.weight
+	}
+	if totalWeight == 0 || len(expansionOptions) == 0 {
+		return nil
+	}
+
+	breakdowns := make(map[string]breakdown, len(expansionOptions))
+	for _, c := range w.criteria {
+		normalized := normalize(c.scorer.Scores(expansionOptions, nodeInfo), c.lowerIsBetter)
+		for _, option := range expansionOptions {
+			id := option.NodeGroup.Id()
+			b := breakdowns[id]
+			value, scored := normalized[id]
+			b.parts = append(b.parts, part{name: c.name, weight: c.weight, normalized: value, scored: scored})
+			b.score += c.weight * value / totalWeight
+			breakdowns[id] = b
+		}
+	}
+	return breakdowns
+}
+
+// normalize linearly maps the scores to [0, 1], 1 being the best score. All
+// scores are 1 if they are equal.
+func normalize(scores map[string]float64, lowerIsBetter bool) map[string]float64 {
+	minScore, maxScore := math.Inf(1), math.Inf(-1)
+	for _, score := range scores {
+		minScore = math.Min(minScore, score)
+		maxScore = math.Max(maxScore, score)
+	}
+	normalized := make(map[string]float64, len(scores))
+	for id, score := range scores {
+		switch {
+		case maxScore == minScore:
+			normalized[id] = 1
+		case lowerIsBetter:
+			normalized[id] = (maxScore - score) / (maxScore - minScore)
+		default:
+			normalized[id] = (score - minScore) / (maxScore - minScore)
+		}
+	}
+	return normalized
+}
+
+func (w *weighted) recordScores(expansionOptions []expander.Option, breakdowns map[string]breakdown) {
+	if w.recorder == nil {
+		return
+	}
+	ids := make([]string, 0, len(expansionOptions))
+	for _, option := range expansionOptions {
+		ids = append(ids, option.NodeGroup.Id())
+	}
+	sort.SliceStable(ids, func(i, j int) bool { return breakdowns[ids[i]].score > breakdowns[ids[j]].score })
+	if len(ids) > maxEventOptions {
+		ids = ids[:maxEventOptions]
+	}
+	messages := make([]string, 0, len(ids))
+	for _, id := range ids {
+		messages = append(messages, fmt.Sprintf("%s: %s", id, breakdowns[id]))
+	}
+	w.recorder.Eventf(apiv1.EventTypeNormal, ScoresEventReason, "Weighted expander scores: %s", strings.Join(messages, "; "))
+}
+
+func sortedNames(weights map[string]float64) []string {
+	names := make([]string, 0, len(weights))
+	for name := range weights {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
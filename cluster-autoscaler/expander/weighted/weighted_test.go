@@ -0,0 +1,169 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package weighted
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/expander"
+	schedulerframework "k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+type fakeScorer struct {
+	scores map[string]float64
+}
+
+func (s *fakeScorer) Scores([]expander.Option, map[string]*schedulerframework.NodeInfo) map[string]float64 {
+	return s.scores
+}
+
+type fakeRecorder struct {
+	events []string
+}
+
+func (r *fakeRecorder) Eventf(eventtype, reason, message string, args ...interface{}) {
+	r.events = append(r.events, fmt.Sprintf("%s %s %s", eventtype, reason, fmt.Sprintf(message, args...)))
+}
+
+func newOption(id string) expander.Option {
+	return expander.Option{NodeGroup: test.NewTestNodeGroup(id, 10, 1, 1, true, false, "", nil, nil), NodeCount: 1, Debug: id}
+}
+
+func TestBestOptions(t *testing.T) {
+	options := []expander.Option{newOption("ng1"), newOption("ng2"), newOption("ng3")}
+	// ng1 wastes the least, ng3 is the cheapest, ng2 is in between for both.
+	scorers := map[string]expander.Scorer{
+		expander.LeastWasteExpanderName: &fakeScorer{scores: map[string]float64{"ng1": 0.1, "ng2": 0.2, "ng3": 0.5}},
+		expander.PriceBasedExpanderName: &fakeScorer{scores: map[string]float64{"ng1": 10, "ng2": 4, "ng3": 2}},
+		expander.MostPodsExpanderName:   &fakeScorer{scores: map[string]float64{"ng1": 3, "ng2": 3}},
+		// The plugin prefers ng3, then ng1; higher scores are better.
+		expander.GRPCV2ExpanderName: &fakeScorer{scores: map[string]float64{"ng1": 5, "ng2": 0, "ng3": 9}},
+	}
+
+	testCases := []struct {
+		name     string
+		weights  map[string]float64
+		expected string
+	}{
+		{
+			name:     "waste only",
+			weights:  map[string]float64{expander.LeastWasteExpanderName: 1},
+			expected: "ng1",
+		},
+		{
+			name:     "price only",
+			weights:  map[string]float64{expander.PriceBasedExpanderName: 1},
+			expected: "ng3",
+		},
+		{
+			name:     "a little waste traded for a lot of cost",
+			weights:  map[string]float64{expander.LeastWasteExpanderName: 1, expander.PriceBasedExpanderName: 1},
+			expected: "ng2",
+		},
+		{
+			name:     "options without score are the worst",
+			weights:  map[string]float64{expander.PriceBasedExpanderName: 1, expander.MostPodsExpanderName: 2},
+			expected: "ng2",
+		},
+		{
+			name:     "plugin only",
+			weights:  map[string]float64{expander.GRPCV2ExpanderName: 1},
+			expected: "ng3",
+		},
+		{
+			name:     "plugin scores weighted against waste",
+			weights:  map[string]float64{expander.GRPCV2ExpanderName: 1, expander.LeastWasteExpanderName: 1},
+			expected: "ng1",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			best := NewFilter(tc.weights, scorers, nil).BestOptions(options, nil)
+			assert.Len(t, best, 1)
+			assert.Equal(t, tc.expected, best[0].NodeGroup.Id())
+		})
+	}
+}
+
+func TestBestOptionsBreakdown(t *testing.T) {
+	options := []expander.Option{newOption("ng1"), newOption("ng2")}
+	scorers := map[string]expander.Scorer{
+		expander.LeastWasteExpanderName: &fakeScorer{scores: map[string]float64{"ng1": 0.1, "ng2": 0.3}},
+		expander.MostPodsExpanderName:   &fakeScorer{scores: map[string]float64{"ng2": 5}},
+	}
+	recorder := &fakeRecorder{}
+	filter := NewFilter(map[string]float64{expander.LeastWasteExpanderName: 3, expander.MostPodsExpanderName: 1}, scorers, recorder)
+
+	best := filter.BestOptions(options, nil)
+	assert.Len(t, best, 1)
+	assert.Equal(t, "ng1 | weighted-expander: score=0.750 (least-waste=1.000*3 most-pods=n/a*1)", best[0].Debug)
+	assert.Equal(t, []string{"Normal WeightedExpanderScores Weighted expander scores: " +
+		"ng1: score=0.750 (least-waste=1.000*3 most-pods=n/a*1); ng2: score=0.250 (least-waste=0.000*3 most-pods=1.000*1)"}, recorder.events)
+
+	scorer := filter.(expander.Scorer)
+	assert.Equal(t, map[string]float64{"ng1": 0.75, "ng2": 0.25}, scorer.Scores(options, nil))
+	explainer := filter.(expander.Explainer)
+	assert.Equal(t, "score=0.250 (least-waste=0.000*3 most-pods=1.000*1)", explainer.Explanations(options, nil)["ng2"])
+}
+
+func TestBestOptionsTies(t *testing.T) {
+	options := []expander.Option{newOption("ng1"), newOption("ng2"), newOption("ng3")}
+	scorers := map[string]expander.Scorer{
+		expander.LeastNodesExpanderName: &fakeScorer{scores: map[string]float64{"ng1": 2, "ng2": 2, "ng3": 3}},
+	}
+	best := NewFilter(map[string]float64{expander.LeastNodesExpanderName: 1}, scorers, nil).BestOptions(options, nil)
+	assert.Len(t, best, 2)
+	assert.Equal(t, "ng1", best[0].NodeGroup.Id())
+	assert.Equal(t, "ng2", best[1].NodeGroup.Id())
+
+	// Without scorers, options aren't filtered.
+	assert.Equal(t, options, NewFilter(map[string]float64{expander.LeastNodesExpanderName: 1}, nil, nil).BestOptions(options, nil))
+}
+
+func TestParseWeights(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected map[string]float64
+		wantErr  bool
+	}{
+		{value: "", expected: nil},
+		{value: "least-waste=1, price=2.5", expected: map[string]float64{"least-waste": 1, "price": 2.5}},
+		{value: "priority=0,most-pods=1", expected: map[string]float64{"priority": 0, "most-pods": 1}},
+		{value: "grpc-v2=2,least-waste=1", expected: map[string]float64{"grpc-v2": 2, "least-waste": 1}},
+		{value: "least-waste", wantErr: true},
+		{value: "random=1", wantErr: true},
+		{value: "price=1,price=2", wantErr: true},
+		{value: "price=-1", wantErr: true},
+		{value: "price=NaN", wantErr: true},
+		{value: "price=abc", wantErr: true},
+		{value: "price=0", wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			weights, err := ParseWeights(tc.value)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, weights)
+		})
+	}
+}
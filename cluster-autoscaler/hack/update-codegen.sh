@@ -16,7 +16,8 @@
 
 ###
 # This script is to be used when updating the generated clients of 
-# the Provisioning Request CRD.
+# the Provisioning Request CRD and the deepcopy functions of the
+# NodeGroupAutoscalingConfig CRD.
 ###
 
 set -o errexit
@@ -36,6 +37,12 @@ bash "${CODEGEN_PKG}"/generate-groups.sh "applyconfiguration,client,deepcopy,inf
   autoscaling.x-k8s.io:v1beta1 \
   --go-header-file "${SCRIPT_ROOT}"/../hack/boilerplate/boilerplate.generatego.txt
 
+source "${CODEGEN_PKG}"/kube_codegen.sh
+
+kube::codegen::gen_helpers \
+  --boilerplate "${SCRIPT_ROOT}"/../hack/boilerplate/boilerplate.generatego.txt \
+  nodegroupautoscalingconfig
+
 chmod -x "${CODEGEN_PKG}"/generate-groups.sh
 chmod -x "${CODEGEN_PKG}"/generate-internal-groups.sh
 popd
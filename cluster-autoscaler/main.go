@@ -23,6 +23,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
 	"strconv"
 	"strings"
 	"syscall"
@@ -54,11 +55,16 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/core/podlistprocessor"
 	"k8s.io/autoscaler/cluster-autoscaler/estimator"
 	"k8s.io/autoscaler/cluster-autoscaler/expander"
+	"k8s.io/autoscaler/cluster-autoscaler/expander/weighted"
 	"k8s.io/autoscaler/cluster-autoscaler/metrics"
 	"k8s.io/autoscaler/cluster-autoscaler/observers/loopstart"
 	ca_processors "k8s.io/autoscaler/cluster-autoscaler/processors"
+	processors_grpcplugin "k8s.io/autoscaler/cluster-autoscaler/processors/grpcplugin"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroupconfig"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroupconfig/crdclient"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroupset"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/nodeinfosprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/nodes"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/provreq"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/scaledowncandidates"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/scaledowncandidates/emptycandidates"
@@ -160,6 +166,7 @@ var (
 	coresTotal                  = flag.String("cores-total", minMaxFlagString(0, config.DefaultMaxClusterCores), "Minimum and maximum number of cores in cluster, in the format <min>:<max>. Cluster autoscaler will not scale the cluster beyond these numbers.")
 	memoryTotal                 = flag.String("memory-total", minMaxFlagString(0, config.DefaultMaxClusterMemory), "Minimum and maximum number of gigabytes of memory in cluster, in the format <min>:<max>. Cluster autoscaler will not scale the cluster beyond these numbers.")
 	gpuTotal                    = multiStringFlag("gpu-total", "Minimum and maximum number of different GPUs in cluster, in the format <gpu_type>:<min>:<max>. Cluster autoscaler will not scale the cluster beyond these numbers. Can be passed multiple times. CURRENTLY THIS FLAG ONLY WORKS ON GKE.")
+	tenantQuotasFile            = flag.String("tenant-quotas-file", "", "Path to a file (YAML or JSON) with per-tenant quotas limiting the total requests of pods of a tenant (a namespace, or a value of a pod label) which scale-up can make room for. Empty string for no quotas.")
 	cloudProviderFlag           = flag.String("cloud-provider", cloudBuilder.DefaultCloudProvider,
 		"Cloud provider type. Available values: ["+strings.Join(cloudBuilder.AvailableCloudProviders, ",")+"]")
 	maxBulkSoftTaintCount      = flag.Int("max-bulk-soft-taint-count", 10, "Maximum number of nodes that can be tainted/untainted PreferNoSchedule at the same time. Set to 0 to turn off such tainting.")
@@ -173,6 +180,7 @@ var (
 	parallelScaleUp           = flag.Bool("parallel-scale-up", false, "Whether to allow parallel node groups scale up. Experimental: may not work on some cloud providers, enable at your own risk.")
 	maxNodeProvisionTime      = flag.Duration("max-node-provision-time", 15*time.Minute, "The default maximum time CA waits for node to be provisioned - the value can be overridden per node group")
 	maxPodEvictionTime        = flag.Duration("max-pod-eviction-time", 2*time.Minute, "Maximum time CA tries to evict a pod before giving up")
+	preEvictionHookTimeout    = flag.Duration("pre-eviction-hook-timeout", 2*time.Minute, "Maximum time CA waits for pods with a pre-eviction hook to acknowledge it before evicting them. 0 turns pre-eviction hooks off.")
 	nodeGroupsFlag            = multiStringFlag(
 		"nodes",
 		"sets min,max size and other configuration data for a node group in a format accepted by cloud provider. Can be used multiple times. Format: <min>:<max>:<other...>")
@@ -190,9 +198,17 @@ var (
 
 	expanderFlag = flag.String("expander", expander.RandomExpanderName, "Type of node group expander to be used in scale up. Available values: ["+strings.Join(expander.AvailableExpanders, ",")+"]. Specifying multiple values separated by commas will call the expanders in succession until there is only one option remaining. Ties still existing after this process are broken randomly.")
 
+	expanderWeightsFlag = flag.String("expander-weights", "", "Weights of the expanders combined by the weighted expander, in the name=weight,name=weight format, e.g. least-waste=1,price=2. Expanders which can be weighted: ["+strings.Join(weighted.SupportedExpanders(), ",")+"].")
+
 	grpcExpanderCert = flag.String("grpc-expander-cert", "", "Path to cert used by gRPC server over TLS")
 	grpcExpanderURL  = flag.String("grpc-expander-url", "", "URL to reach gRPC expander server.")
 
+	grpcProcessorsCert = flag.String("grpc-processors-cert", "", "Path to cert used by gRPC processors server over TLS")
+	grpcProcessorsURL  = flag.String("grpc-processors-url", "", "URL to reach gRPC processors server. If set, the server can filter unschedulable pods and scale down candidates, and adjust node group balancing.")
+
+	pricingConfigFile    = flag.String("pricing-config-file", "", "Path to a price table (YAML or JSON) used by the price expander if the cloud provider doesn't implement pricing.")
+	pricingConfigMapName = flag.String("pricing-config-map", "", "Name of a ConfigMap in the config namespace holding a price table (under the \"prices\" key) used by the price expander if the cloud provider doesn't implement pricing. Ignored if --pricing-config-file is set.")
+
 	ignoreDaemonSetsUtilization = flag.Bool("ignore-daemonsets-utilization", false,
 		"Should CA ignore DaemonSet pods when calculating resource utilization for scaling down")
 	ignoreMirrorPodsUtilization = flag.Bool("ignore-mirror-pods-utilization", false,
@@ -202,6 +218,7 @@ var (
 	statusConfigMapName              = flag.String("status-config-map-name", "cluster-autoscaler-status", "Status configmap name")
 	maxInactivityTimeFlag            = flag.Duration("max-inactivity", 10*time.Minute, "Maximum time from last recorded autoscaler activity before automatic restart")
 	maxBinpackingTimeFlag            = flag.Duration("max-binpacking-time", 5*time.Minute, "Maximum time spend on binpacking for a single scale-up. If binpacking is limited by this, scale-up will continue with the already calculated scale-up options.")
+	parallelEstimationWorkers        = flag.Int("parallel-estimation-workers", 1, "Maximum number of node groups binpacked concurrently during a single scale-up, each on its own copy-on-write clone of the cluster snapshot. 1 binpacks node groups one by one.")
 	maxFailingTimeFlag               = flag.Duration("max-failing-time", 15*time.Minute, "Maximum time from last recorded successful autoscaler run before automatic restart")
 	balanceSimilarNodeGroupsFlag     = flag.Bool("balance-similar-node-groups", false, "Detect similar node groups and balance the number of nodes between them")
 	nodeAutoprovisioningEnabled      = flag.Bool("node-autoprovisioning-enabled", false, "Should CA autoprovision node groups when needed.This flag is deprecated and will be removed in future releases.")
@@ -217,6 +234,7 @@ var (
 	statusTaintsFlag          = multiStringFlag("status-taint", "Specifies a taint to ignore in node templates when considering to scale a node group but nodes will not be treated as unready")
 	balancingIgnoreLabelsFlag = multiStringFlag("balancing-ignore-label", "Specifies a label to ignore in addition to the basic and cloud-provider set of labels when comparing if two node groups are similar")
 	balancingLabelsFlag       = multiStringFlag("balancing-label", "Specifies a label to use for comparing if two node groups are similar, rather than the built in heuristics. Setting this flag disables all other comparison logic, and cannot be combined with --balancing-ignore-label.")
+	preEvictionHookHostsFlag  = multiStringFlag("pre-eviction-hook-allowed-host", "Specifies a host which HTTP pre-eviction hooks with absolute URLs can point to. Hooks relative to the pod IP are always allowed. Can be passed multiple times.")
 	awsUseStaticInstanceList  = flag.Bool("aws-use-static-instance-list", false, "Should CA fetch instance types in runtime or use a static list. AWS only")
 
 	// GCE specific flags
@@ -240,8 +258,33 @@ var (
 		"maxNodeGroupBackoffDuration is the maximum backoff duration for a NodeGroup after new nodes failed to start.")
 	nodeGroupBackoffResetTimeout = flag.Duration("node-group-backoff-reset-timeout", 3*time.Hour,
 		"nodeGroupBackoffResetTimeout is the time after last failed scale-up when the backoff duration is reset.")
+	interruptionTaints = pflag.StringSlice("interruption-taints", config.DefaultInterruptionTaints,
+		"Taints marking nodes about to be interrupted, e.g. reclaimed spot instances. Nodes removed while having one of them count as interruptions of their node group.")
+	interruptionWindow = flag.Duration("interruption-window", time.Hour,
+		"How long interruptions of a node group are remembered by the backoff and the least-interrupted expander.")
+	interruptionBackoffThreshold = flag.Int("interruption-backoff-threshold", 0,
+		"Number of interruptions within --interruption-window after which a node group is backed off. 0 disables backing off because of interruptions.")
+	dynamicOptionsFile = flag.String("dynamic-options-file", "",
+		"Path of a YAML file with options which are reloaded without restarting, e.g. one mounted from a ConfigMap. Keys are names of the flags which can be changed: max-nodes-total, scale-down-enabled, scale-down-delay-after-add, scale-down-delay-after-delete, scale-down-delay-after-failure, scale-down-non-empty-candidates-count, scale-down-candidates-pool-ratio, scale-down-candidates-pool-min-count, max-scale-down-parallelism, max-drain-parallelism, new-pod-scale-up-delay and expander. Options which are not set keep the values of their flags.")
+	dynamicOptionsConfigMap = flag.String("dynamic-options-config-map", "",
+		"Name of a ConfigMap in the config namespace with options which are reloaded without restarting, under the 'options' key, in the format of --dynamic-options-file. Ignored if --dynamic-options-file is set.")
+	maintenanceWindowsFile = flag.String("maintenance-windows-file", "",
+		"Path of a YAML file with maintenance windows during which scale-down, or all actuation, is suspended for the whole cluster or selected node groups. It is reloaded every loop.")
+	maintenanceWindowsConfigMap = flag.String("maintenance-windows-config-map", "",
+		"Name of a ConfigMap in the config namespace with maintenance windows under the 'windows' key, in the format of --maintenance-windows-file. Ignored if --maintenance-windows-file is set.")
+	stateStoreType = flag.String("state-store", "",
+		"Where node group backoffs and failed scale-up history are persisted, so that they survive restarts and leader failovers. One of: configmap, lease. Nothing is persisted if empty.")
+	stateStoreName = flag.String("state-store-name", "cluster-autoscaler-state",
+		"Name of the ConfigMap or Lease in the config namespace keeping the persisted state. It must differ from the leader election lease.")
+	dryRun = flag.Bool("dry-run", false,
+		"Plan scale-ups and scale-downs as usual, but only record the planned actions in events, metrics and the status ConfigMap instead of resizing node groups, tainting nodes or evicting pods. Useful for running a new version or configuration next to the active Cluster Autoscaler.")
+	auditLogFile = flag.String("audit-log-file", "",
+		"Path of a file to which a JSON line is appended every loop, describing candidate node groups, estimator results, expander scores, the chosen option and per-node scale-down verdicts. Disabled if empty.")
+	auditLogGRPCURL                         = flag.String("audit-log-grpc-url", "", "URL of a gRPC server receiving the audit log records. Disabled if empty.")
+	auditLogGRPCCert                        = flag.String("audit-log-grpc-cert", "", "Path to the certificate used to verify the audit log gRPC server.")
 	maxScaleDownParallelismFlag             = flag.Int("max-scale-down-parallelism", 10, "Maximum number of nodes (both empty and needing drain) that can be deleted in parallel.")
 	maxDrainParallelismFlag                 = flag.Int("max-drain-parallelism", 1, "Maximum number of nodes needing drain, that can be drained and deleted in parallel.")
+	disruptionBudgetsFile                   = flag.String("disruption-budgets-file", "", "Path to a file (YAML or JSON) with disruption budgets limiting the number or percentage of nodes of a node group, or of nodes matching a label selector, deleted at once. Empty string for no budgets.")
 	recordDuplicatedEvents                  = flag.Bool("record-duplicated-events", false, "enable duplication of similar events within a 5 minute window.")
 	maxNodesPerScaleUp                      = flag.Int("max-nodes-per-scaleup", 1000, "Max nodes added in a single scale-up. This is intended strictly for optimizing CA algorithm latency and not a tool to rate-limit scale-up throughput.")
 	maxNodeGroupBinpackingDuration          = flag.Duration("max-nodegroup-binpacking-duration", 10*time.Second, "Maximum time that will be spent in binpacking simulation for each NodeGroup.")
@@ -251,6 +294,13 @@ var (
 	minReplicaCount                         = flag.Int("min-replica-count", 0, "Minimum number or replicas that a replica set or replication controller should have to allow their pods deletion in scale down")
 	nodeDeleteDelayAfterTaint               = flag.Duration("node-delete-delay-after-taint", 5*time.Second, "How long to wait before deleting a node after tainting it")
 	scaleDownSimulationTimeout              = flag.Duration("scale-down-simulation-timeout", 30*time.Second, "How long should we run scale down simulation.")
+	consolidationEnabled                    = flag.Bool("consolidation-enabled", false, "Should CA replace under-utilized nodes with fewer or cheaper nodes of another node group. Requires pricing, see --pricing-config-file.")
+	consolidationUtilizationThreshold       = flag.Float64("consolidation-utilization-threshold", config.DefaultConsolidationUtilizationThreshold, "Node utilization level, defined as sum of requested resources divided by capacity, below which a node can be replaced by consolidation")
+	consolidationMinSavings                 = flag.Float64("consolidation-min-savings", config.DefaultConsolidationMinSavings, "Minimum fraction of the hourly price of the replaced nodes that a consolidation has to save")
+	consolidationMaxNodes                   = flag.Int("consolidation-max-nodes", config.DefaultConsolidationMaxNodes, "Maximum number of nodes replaced by a single consolidation")
+	rebalanceEnabled                        = flag.Bool("rebalance-enabled", false, "Should CA move nodes of fallback node groups back to the node groups preferred by the priority expander once they can be scaled up again. Requires the priority expander ConfigMap.")
+	rebalanceInterval                       = flag.Duration("rebalance-interval", config.DefaultRebalanceInterval, "How often CA looks for nodes to move to preferred node groups")
+	rebalanceMaxNodes                       = flag.Int("rebalance-max-nodes", config.DefaultRebalanceMaxNodes, "Maximum number of nodes moved to a preferred node group at once")
 	parallelDrain                           = flag.Bool("parallel-drain", true, "Whether to allow parallel drain of nodes. This flag is deprecated and will be removed in future releases.")
 	maxCapacityMemoryDifferenceRatio        = flag.Float64("memory-difference-ratio", config.DefaultMaxCapacityMemoryDifferenceRatio, "Maximum difference in memory capacity between two similar node groups to be considered for balancing. Value is a ratio of the smaller node group's memory capacity.")
 	maxFreeDifferenceRatio                  = flag.Float64("max-free-difference-ratio", config.DefaultMaxFreeDifferenceRatio, "Maximum difference in free resources between two similar node groups to be considered for balancing. Value is a ratio of the smaller node group's free resource.")
@@ -263,8 +313,10 @@ var (
 			"--max-graceful-termination-sec flag should not be set when this flag is set. Not setting this flag will use unordered evictor by default."+
 			"Priority evictor reuses the concepts of drain logic in kubelet(https://github.com/kubernetes/enhancements/tree/master/keps/sig-node/2712-pod-priority-based-graceful-node-shutdown#migration-from-the-node-graceful-shutdown-feature)."+
 			"Eg. flag usage:  '10000:20,1000:100,0:60'")
-	provisioningRequestsEnabled = flag.Bool("enable-provisioning-requests", false, "Whether the clusterautoscaler will be handling the ProvisioningRequest CRs.")
-	frequentLoopsEnabled        = flag.Bool("frequent-loops-enabled", false, "Whether clusterautoscaler triggers new iterations more frequently when it's needed")
+	provisioningRequestsEnabled        = flag.Bool("enable-provisioning-requests", false, "Whether the clusterautoscaler will be handling the ProvisioningRequest CRs.")
+	nodeGroupAutoscalingConfigsEnabled = flag.Bool("enable-node-group-autoscaling-configs", false, "Whether the clusterautoscaler will take per node group options from NodeGroupAutoscalingConfig CRs. The options they set take precedence over the ones set by the cloud provider.")
+	dynamicResourceAllocationEnabled   = flag.Bool("enable-dynamic-resource-allocation", false, "Whether the clusterautoscaler will simulate allocation of ResourceClaims of pods using Dynamic Resource Allocation with structured parameters. Requires the resource.k8s.io/v1alpha2 API.")
+	frequentLoopsEnabled               = flag.Bool("frequent-loops-enabled", false, "Whether clusterautoscaler triggers new iterations more frequently when it's needed")
 )
 
 func isFlagPassed(name string) bool {
@@ -294,6 +346,25 @@ func createAutoscalingOptions() config.AutoscalingOptions {
 	if err != nil {
 		klog.Fatalf("Failed to parse flags: %v", err)
 	}
+	expanderWeights, err := weighted.ParseWeights(*expanderWeightsFlag)
+	if err != nil {
+		klog.Fatalf("Failed to parse flags: %v", err)
+	}
+	if expanderWeights == nil && slices.Contains(strings.Split(*expanderFlag, ","), expander.WeightedExpanderName) {
+		klog.Fatalf("Invalid configuration, --expander-weights must be set to use the %s expander", expander.WeightedExpanderName)
+	}
+	var tenantQuotas *config.TenantQuotas
+	if *tenantQuotasFile != "" {
+		if tenantQuotas, err = config.LoadTenantQuotas(*tenantQuotasFile); err != nil {
+			klog.Fatalf("Failed to parse flags: %v", err)
+		}
+	}
+	var disruptionBudgets *config.DisruptionBudgets
+	if *disruptionBudgetsFile != "" {
+		if disruptionBudgets, err = config.LoadDisruptionBudgets(*disruptionBudgetsFile); err != nil {
+			klog.Fatalf("Failed to parse flags: %v", err)
+		}
+	}
 	if *maxDrainParallelismFlag > 1 && !*parallelDrain {
 		klog.Fatalf("Invalid configuration, could not use --max-drain-parallelism > 1 if --parallel-drain is false")
 	}
@@ -355,20 +426,28 @@ func createAutoscalingOptions() config.AutoscalingOptions {
 		ParallelScaleUp:                  *parallelScaleUp,
 		EstimatorName:                    *estimatorFlag,
 		ExpanderNames:                    *expanderFlag,
+		ExpanderWeights:                  expanderWeights,
 		GRPCExpanderCert:                 *grpcExpanderCert,
 		GRPCExpanderURL:                  *grpcExpanderURL,
+		GRPCProcessorsCert:               *grpcProcessorsCert,
+		GRPCProcessorsURL:                *grpcProcessorsURL,
+		PricingConfigFile:                *pricingConfigFile,
+		PricingConfigMapName:             *pricingConfigMapName,
 		IgnoreMirrorPodsUtilization:      *ignoreMirrorPodsUtilization,
 		MaxBulkSoftTaintCount:            *maxBulkSoftTaintCount,
 		MaxBulkSoftTaintTime:             *maxBulkSoftTaintTime,
 		MaxEmptyBulkDelete:               *maxEmptyBulkDeleteFlag,
 		MaxGracefulTerminationSec:        *maxGracefulTerminationFlag,
 		MaxPodEvictionTime:               *maxPodEvictionTime,
+		PreEvictionHookTimeout:           *preEvictionHookTimeout,
+		PreEvictionHookAllowedHosts:      *preEvictionHookHostsFlag,
 		MaxNodesTotal:                    *maxNodesTotal,
 		MaxCoresTotal:                    maxCoresTotal,
 		MinCoresTotal:                    minCoresTotal,
 		MaxMemoryTotal:                   maxMemoryTotal,
 		MinMemoryTotal:                   minMemoryTotal,
 		GpuTotal:                         parsedGpuTotal,
+		TenantQuotas:                     tenantQuotas,
 		NodeGroups:                       *nodeGroupsFlag,
 		EnforceNodeGroupMinSize:          *enforceNodeGroupMinSize,
 		ScaleDownDelayAfterAdd:           *scaleDownDelayAfterAdd,
@@ -418,18 +497,40 @@ func createAutoscalingOptions() config.AutoscalingOptions {
 		InitialNodeGroupBackoffDuration:    *initialNodeGroupBackoffDuration,
 		MaxNodeGroupBackoffDuration:        *maxNodeGroupBackoffDuration,
 		NodeGroupBackoffResetTimeout:       *nodeGroupBackoffResetTimeout,
+		InterruptionTaints:                 *interruptionTaints,
+		InterruptionWindow:                 *interruptionWindow,
+		InterruptionBackoffThreshold:       *interruptionBackoffThreshold,
+		DynamicOptionsFile:                 *dynamicOptionsFile,
+		DynamicOptionsConfigMapName:        *dynamicOptionsConfigMap,
+		MaintenanceWindowsFile:             *maintenanceWindowsFile,
+		MaintenanceWindowsConfigMapName:    *maintenanceWindowsConfigMap,
+		StateStoreType:                     *stateStoreType,
+		StateStoreName:                     *stateStoreName,
+		DryRun:                             *dryRun,
+		AuditLogFile:                       *auditLogFile,
+		AuditLogGRPCURL:                    *auditLogGRPCURL,
+		AuditLogGRPCCert:                   *auditLogGRPCCert,
 		MaxScaleDownParallelism:            *maxScaleDownParallelismFlag,
 		MaxDrainParallelism:                *maxDrainParallelismFlag,
+		DisruptionBudgets:                  disruptionBudgets,
 		RecordDuplicatedEvents:             *recordDuplicatedEvents,
 		MaxNodesPerScaleUp:                 *maxNodesPerScaleUp,
 		MaxNodeGroupBinpackingDuration:     *maxNodeGroupBinpackingDuration,
 		MaxBinpackingTime:                  *maxBinpackingTimeFlag,
+		ParallelEstimationWorkers:          *parallelEstimationWorkers,
 		NodeDeletionBatcherInterval:        *nodeDeletionBatcherInterval,
 		SkipNodesWithSystemPods:            *skipNodesWithSystemPods,
 		SkipNodesWithLocalStorage:          *skipNodesWithLocalStorage,
 		MinReplicaCount:                    *minReplicaCount,
 		NodeDeleteDelayAfterTaint:          *nodeDeleteDelayAfterTaint,
 		ScaleDownSimulationTimeout:         *scaleDownSimulationTimeout,
+		ConsolidationEnabled:               *consolidationEnabled,
+		ConsolidationUtilizationThreshold:  *consolidationUtilizationThreshold,
+		ConsolidationMinSavings:            *consolidationMinSavings,
+		ConsolidationMaxNodes:              *consolidationMaxNodes,
+		RebalanceEnabled:                   *rebalanceEnabled,
+		RebalanceInterval:                  *rebalanceInterval,
+		RebalanceMaxNodes:                  *rebalanceMaxNodes,
 		ParallelDrain:                      *parallelDrain,
 		SkipNodesWithCustomControllerPods:  *skipNodesWithCustomControllerPods,
 		NodeGroupSetRatios: config.NodeGroupDifferenceRatios{
@@ -440,6 +541,8 @@ func createAutoscalingOptions() config.AutoscalingOptions {
 		DynamicNodeDeleteDelayAfterTaintEnabled: *dynamicNodeDeleteDelayAfterTaintEnabled,
 		BypassedSchedulers:                      scheduler_util.GetBypassedSchedulersMap(*bypassedSchedulers),
 		ProvisioningRequestEnabled:              *provisioningRequestsEnabled,
+		NodeGroupAutoscalingConfigsEnabled:      *nodeGroupAutoscalingConfigsEnabled,
+		DynamicResourceAllocationEnabled:        *dynamicResourceAllocationEnabled,
 	}
 }
 
@@ -482,9 +585,15 @@ func buildAutoscaler(debuggingSnapshotter debuggingsnapshot.DebuggingSnapshotter
 	deleteOptions := options.NewNodeDeleteOptions(autoscalingOptions)
 	drainabilityRules := rules.Default(deleteOptions)
 
+	var clusterSnapshot clustersnapshot.ClusterSnapshot = clustersnapshot.NewDeltaClusterSnapshot()
+	if autoscalingOptions.ParallelEstimationWorkers > 1 {
+		// Parallel estimation workers clone persistent snapshots instead of copying them.
+		clusterSnapshot = clustersnapshot.NewPersistentClusterSnapshot()
+	}
+
 	opts := core.AutoscalerOptions{
 		AutoscalingOptions:   autoscalingOptions,
-		ClusterSnapshot:      clustersnapshot.NewDeltaClusterSnapshot(),
+		ClusterSnapshot:      clusterSnapshot,
 		KubeClient:           kubeClient,
 		InformerFactory:      informerFactory,
 		DebuggingSnapshotter: debuggingSnapshotter,
@@ -495,14 +604,16 @@ func buildAutoscaler(debuggingSnapshotter debuggingsnapshot.DebuggingSnapshotter
 	}
 
 	opts.Processors = ca_processors.DefaultProcessors(autoscalingOptions)
-	opts.Processors.TemplateNodeInfoProvider = nodeinfosprovider.NewDefaultTemplateNodeInfoProvider(nodeInfoCacheExpireTime, *forceDaemonSets)
+	csiNodeLister := informerFactory.Storage().V1().CSINodes().Lister()
+	opts.Processors.TemplateNodeInfoProvider = nodeinfosprovider.NewDefaultTemplateNodeInfoProvider(nodeInfoCacheExpireTime, *forceDaemonSets, nodeinfosprovider.WithCSINodeLister(csiNodeLister))
 	podListProcessor := podlistprocessor.NewDefaultPodListProcessor(opts.PredicateChecker, scheduling.ScheduleAnywhere)
+	var loopStartObservers []loopstart.Observer
 
 	if autoscalingOptions.ProvisioningRequestEnabled {
 		podListProcessor.AddProcessor(provreq.NewProvisioningRequestPodsFilter(provreq.NewDefautlEventManager()))
 
 		restConfig := kube_util.GetKubeConfig(autoscalingOptions.KubeClientOpts)
-		client, err := provreqclient.NewProvisioningRequestClient(restConfig)
+		client, err := provreqclient.NewProvisioningRequestClient(restConfig, autoscalingOptions.DryRun)
 		if err != nil {
 			return nil, err
 		}
@@ -517,8 +628,8 @@ func buildAutoscaler(debuggingSnapshotter debuggingsnapshot.DebuggingSnapshotter
 		if err != nil {
 			return nil, err
 		}
-		opts.LoopStartNotifier = loopstart.NewObserversList([]loopstart.Observer{provreqProcesor})
-		injector, err := provreq.NewProvisioningRequestPodsInjector(restConfig)
+		loopStartObservers = append(loopStartObservers, provreqProcesor)
+		injector, err := provreq.NewProvisioningRequestPodsInjector(restConfig, autoscalingOptions.DryRun)
 		if err != nil {
 			return nil, err
 		}
@@ -526,6 +637,27 @@ func buildAutoscaler(debuggingSnapshotter debuggingsnapshot.DebuggingSnapshotter
 		podListProcessor.AddProcessor(provreqProcesor)
 	}
 	opts.Processors.PodListProcessor = podListProcessor
+	if autoscalingOptions.NodeGroupAutoscalingConfigsEnabled {
+		restConfig := kube_util.GetKubeConfig(autoscalingOptions.KubeClientOpts)
+		client, err := crdclient.NewNodeGroupAutoscalingConfigClient(restConfig)
+		if err != nil {
+			return nil, err
+		}
+		nodeLister := kube_util.NewAllNodeLister(informerFactory.Core().V1().Nodes().Lister())
+		nodeGroupConfigProcessor := nodegroupconfig.NewCRDNodeGroupConfigProcessor(client, nodeLister, opts.Processors.NodeGroupConfigProcessor, autoscalingOptions.DryRun)
+		opts.Processors.NodeGroupConfigProcessor = nodeGroupConfigProcessor
+		// The default scale-down set processor reads ZeroOrMaxNodeScaling from the replaced processor.
+		opts.Processors.ScaleDownSetProcessor = nodes.NewCompositeScaleDownSetProcessor(
+			[]nodes.ScaleDownSetProcessor{
+				nodes.NewMaxNodesProcessor(),
+				nodes.NewAtomicResizeFilteringProcessor(nodeGroupConfigProcessor),
+			},
+		)
+		loopStartObservers = append(loopStartObservers, nodeGroupConfigProcessor)
+	}
+	if len(loopStartObservers) > 0 {
+		opts.LoopStartNotifier = loopstart.NewObserversList(loopStartObservers)
+	}
 	scaleDownCandidatesComparers := []scaledowncandidates.CandidatesComparer{}
 	if autoscalingOptions.ParallelDrain {
 		sdCandidatesSorting := previouscandidates.NewPreviousCandidates()
@@ -556,10 +688,10 @@ func buildAutoscaler(debuggingSnapshotter debuggingsnapshot.DebuggingSnapshotter
 			nodeInfoComparatorBuilder = nodegroupset.CreateAzureNodeInfoComparator
 		} else if autoscalingOptions.CloudProviderName == cloudprovider.AwsProviderName {
 			nodeInfoComparatorBuilder = nodegroupset.CreateAwsNodeInfoComparator
-			opts.Processors.TemplateNodeInfoProvider = nodeinfosprovider.NewAsgTagResourceNodeInfoProvider(nodeInfoCacheExpireTime, *forceDaemonSets)
+			opts.Processors.TemplateNodeInfoProvider = nodeinfosprovider.NewAsgTagResourceNodeInfoProvider(nodeInfoCacheExpireTime, *forceDaemonSets, nodeinfosprovider.WithCSINodeLister(csiNodeLister))
 		} else if autoscalingOptions.CloudProviderName == cloudprovider.GceProviderName {
 			nodeInfoComparatorBuilder = nodegroupset.CreateGceNodeInfoComparator
-			opts.Processors.TemplateNodeInfoProvider = nodeinfosprovider.NewAnnotationNodeInfoProvider(nodeInfoCacheExpireTime, *forceDaemonSets)
+			opts.Processors.TemplateNodeInfoProvider = nodeinfosprovider.NewAnnotationNodeInfoProvider(nodeInfoCacheExpireTime, *forceDaemonSets, nodeinfosprovider.WithCSINodeLister(csiNodeLister))
 		}
 		nodeInfoComparator = nodeInfoComparatorBuilder(autoscalingOptions.BalancingExtraIgnoredLabels, autoscalingOptions.NodeGroupSetRatios)
 	}
@@ -568,6 +700,19 @@ func buildAutoscaler(debuggingSnapshotter debuggingsnapshot.DebuggingSnapshotter
 		Comparator: nodeInfoComparator,
 	}
 
+	if autoscalingOptions.GRPCProcessorsURL != "" {
+		client, err := processors_grpcplugin.NewProcessorsClient(autoscalingOptions.GRPCProcessorsCert, autoscalingOptions.GRPCProcessorsURL)
+		if err != nil {
+			return nil, err
+		}
+		podListProcessor.AddProcessor(processors_grpcplugin.NewPodListProcessor(client))
+		opts.Processors.ScaleDownSetProcessor = nodes.NewCompositeScaleDownSetProcessor([]nodes.ScaleDownSetProcessor{
+			processors_grpcplugin.NewScaleDownSetProcessor(client),
+			opts.Processors.ScaleDownSetProcessor,
+		})
+		opts.Processors.NodeGroupSetProcessor = processors_grpcplugin.NewNodeGroupSetProcessor(client, opts.Processors.NodeGroupSetProcessor)
+	}
+
 	// These metrics should be published only once.
 	metrics.UpdateNapEnabled(autoscalingOptions.NodeAutoprovisioningEnabled)
 	metrics.UpdateCPULimitsCores(autoscalingOptions.MinCoresTotal, autoscalingOptions.MaxCoresTotal)
@@ -627,6 +772,14 @@ func run(healthCheck *metrics.HealthCheck, debuggingSnapshotter debuggingsnapsho
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == simulateCommand {
+		klog.InitFlags(nil)
+		if err := runSimulate(os.Args[2:]); err != nil {
+			klog.Fatalf("Failed to simulate: %v", err)
+		}
+		return
+	}
+
 	klog.InitFlags(nil)
 
 	leaderElection := defaultLeaderElectionConfiguration()
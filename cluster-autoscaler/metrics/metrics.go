@@ -47,6 +47,9 @@ type NodeGroupType string
 // PodEvictionResult describes result of the pod eviction attempt
 type PodEvictionResult string
 
+// OptionsReloadResult describes result of reloading dynamic options
+type OptionsReloadResult string
+
 const (
 	caNamespace           = "cluster_autoscaler"
 	readyLabel            = "ready"
@@ -94,6 +97,10 @@ const (
 	PodEvictionSucceed PodEvictionResult = "succeeded"
 	// PodEvictionFailed means creation of the pod eviction object failed
 	PodEvictionFailed PodEvictionResult = "failed"
+	// OptionsReloadApplied means changed dynamic options were applied
+	OptionsReloadApplied OptionsReloadResult = "applied"
+	// OptionsReloadRejected means dynamic options couldn't be read or were invalid
+	OptionsReloadRejected OptionsReloadResult = "rejected"
 )
 
 // Names of Cluster Autoscaler operations
@@ -420,6 +427,47 @@ var (
 		[]string{"type"},
 	)
 
+	optionsReloadsCount = k8smetrics.NewCounterVec(
+		&k8smetrics.CounterOpts{
+			Namespace: caNamespace,
+			Name:      "options_reloads_total",
+			Help:      "Number of times changed dynamic options were reloaded, by result.",
+		}, []string{"result"},
+	)
+
+	optionChangesCount = k8smetrics.NewCounterVec(
+		&k8smetrics.CounterOpts{
+			Namespace: caNamespace,
+			Name:      "option_changes_total",
+			Help:      "Number of applied changes of dynamic options, by option.",
+		}, []string{"option"},
+	)
+
+	dryRunActionsCount = k8smetrics.NewCounterVec(
+		&k8smetrics.CounterOpts{
+			Namespace: caNamespace,
+			Name:      "dry_run_actions_total",
+			Help:      "Number of actions recorded instead of executed in dry-run mode, by action.",
+		}, []string{"action"},
+	)
+
+	maintenanceWindowsActive = k8smetrics.NewGaugeVec(
+		&k8smetrics.GaugeOpts{
+			Namespace: caNamespace,
+			Name:      "maintenance_windows_active",
+			Help:      "Number of active maintenance windows, by the kind of actuation they suspend.",
+		}, []string{"freeze"},
+	)
+
+	nodeGroupEstimationDuration = k8smetrics.NewHistogram(
+		&k8smetrics.HistogramOpts{
+			Namespace: caNamespace,
+			Name:      "node_group_estimation_duration_seconds",
+			Help:      "Time taken by scale-up estimation of a single node group.",
+			Buckets:   k8smetrics.ExponentialBuckets(0.001, 2, 16), // 0.001, 0.002, ..., 16.384, 32.768
+		},
+	)
+
 	inconsistentInstancesMigsCount = k8smetrics.NewGauge(
 		&k8smetrics.GaugeOpts{
 			Namespace: caNamespace,
@@ -463,6 +511,11 @@ func RegisterAll(emitPerNodeGroupMetrics bool) {
 	legacyregistry.MustRegister(pendingNodeDeletions)
 	legacyregistry.MustRegister(nodeTaintsCount)
 	legacyregistry.MustRegister(inconsistentInstancesMigsCount)
+	legacyregistry.MustRegister(optionsReloadsCount)
+	legacyregistry.MustRegister(optionChangesCount)
+	legacyregistry.MustRegister(dryRunActionsCount)
+	legacyregistry.MustRegister(maintenanceWindowsActive)
+	legacyregistry.MustRegister(nodeGroupEstimationDuration)
 
 	if emitPerNodeGroupMetrics {
 		legacyregistry.MustRegister(nodesGroupMinNodes)
@@ -733,3 +786,30 @@ func ObserveNodeTaintsCount(taintType string, count float64) {
 func UpdateInconsistentInstancesMigsCount(migCount int) {
 	inconsistentInstancesMigsCount.Set(float64(migCount))
 }
+
+// RegisterOptionsReload records a reload of changed dynamic options.
+func RegisterOptionsReload(result OptionsReloadResult) {
+	optionsReloadsCount.WithLabelValues(string(result)).Inc()
+}
+
+// RegisterOptionChange records an applied change of a dynamic option.
+func RegisterOptionChange(option string) {
+	optionChangesCount.WithLabelValues(option).Inc()
+}
+
+// RegisterDryRunAction records an action which wasn't executed because of dry-run mode.
+func RegisterDryRunAction(action string) {
+	dryRunActionsCount.WithLabelValues(action).Inc()
+}
+
+// UpdateMaintenanceWindowsActive records the number of active maintenance
+// windows suspending the given kind of actuation.
+func UpdateMaintenanceWindowsActive(freeze string, count int) {
+	maintenanceWindowsActive.WithLabelValues(freeze).Set(float64(count))
+}
+
+// UpdateNodeGroupEstimationDuration records the time taken by scale-up
+// estimation of a single node group.
+func UpdateNodeGroupEstimationDuration(duration time.Duration) {
+	nodeGroupEstimationDuration.Observe(duration.Seconds())
+}
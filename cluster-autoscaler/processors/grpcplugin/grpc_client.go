@@ -0,0 +1,66 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcplugin
+
+import (
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/grpcplugin/protos"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	gRPCTimeout        = 5 * time.Second
+	gRPCMaxRecvMsgSize = 128 << 20
+)
+
+// NewProcessorsClient returns a client of the gRPC processors server at url,
+// connecting over TLS with the given cert.
+func NewProcessorsClient(cert string, url string) (protos.ProcessorsClient, error) {
+	if cert == "" {
+		return nil, fmt.Errorf("gRPC processors cert not specified, insecure connections not allowed")
+	}
+	creds, err := credentials.NewClientTLSFromFile(cert, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create TLS credentials: %v", err)
+	}
+	dialOpts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(gRPCMaxRecvMsgSize)),
+	}
+	klog.V(2).Infof("Dialing: %s with dialopt: %v", url, dialOpts)
+	conn, err := grpc.Dial(url, dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial gRPC processors server: %v", err)
+	}
+	return protos.NewProcessorsClient(conn), nil
+}
+
+// logCallError logs a failed call. Methods the server doesn't implement are
+// expected and only logged at a high verbosity.
+func logCallError(method string, err error) {
+	if status.Code(err) == codes.Unimplemented {
+		klog.V(4).Infof("gRPC processors server doesn't implement %s, skipping", method)
+		return
+	}
+	klog.Errorf("gRPC call to %s failed, skipping: %v", method, err)
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcplugin
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/grpcplugin/protos"
+)
+
+// fakeProcessorsServer implements the methods for which a handler is set.
+type fakeProcessorsServer struct {
+	protos.UnimplementedProcessorsServer
+	filterUnschedulablePods func(*protos.FilterUnschedulablePodsRequest) (*protos.FilterUnschedulablePodsResponse, error)
+	filterNodesToRemove     func(*protos.FilterNodesToRemoveRequest) (*protos.FilterNodesToRemoveResponse, error)
+	filterSimilarNodeGroups func(*protos.FilterSimilarNodeGroupsRequest) (*protos.FilterSimilarNodeGroupsResponse, error)
+	balanceScaleUp          func(*protos.BalanceScaleUpRequest) (*protos.BalanceScaleUpResponse, error)
+}
+
+func (s *fakeProcessorsServer) FilterUnschedulablePods(ctx context.Context, req *protos.FilterUnschedulablePodsRequest) (*protos.FilterUnschedulablePodsResponse, error) {
+	if s.filterUnschedulablePods == nil {
+		return s.UnimplementedProcessorsServer.FilterUnschedulablePods(ctx, req)
+	}
+	return s.filterUnschedulablePods(req)
+}
+
+func (s *fakeProcessorsServer) FilterNodesToRemove(ctx context.Context, req *protos.FilterNodesToRemoveRequest) (*protos.FilterNodesToRemoveResponse, error) {
+	if s.filterNodesToRemove == nil {
+		return s.UnimplementedProcessorsServer.FilterNodesToRemove(ctx, req)
+	}
+	return s.filterNodesToRemove(req)
+}
+
+func (s *fakeProcessorsServer) FilterSimilarNodeGroups(ctx context.Context, req *protos.FilterSimilarNodeGroupsRequest) (*protos.FilterSimilarNodeGroupsResponse, error) {
+	if s.filterSimilarNodeGroups == nil {
+		return s.UnimplementedProcessorsServer.FilterSimilarNodeGroups(ctx, req)
+	}
+	return s.filterSimilarNodeGroups(req)
+}
+
+func (s *fakeProcessorsServer) BalanceScaleUp(ctx context.Context, req *protos.BalanceScaleUpRequest) (*protos.BalanceScaleUpResponse, error) {
+	if s.balanceScaleUp == nil {
+		return s.UnimplementedProcessorsServer.BalanceScaleUp(ctx, req)
+	}
+	return s.balanceScaleUp(req)
+}
+
+// newTestClient serves the fake server in memory and returns a client
+// connected to it.
+func newTestClient(t *testing.T, fakeServer *fakeProcessorsServer) protos.ProcessorsClient {
+	server := grpc.NewServer()
+	protos.RegisterProcessorsServer(server, fakeServer)
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return protos.NewProcessorsClient(conn)
+}
+
+func TestNewProcessorsClientRequiresCert(t *testing.T) {
+	_, err := NewProcessorsClient("", "localhost:1234")
+	assert.Error(t, err)
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcplugin
+
+import (
+	"context"
+	"fmt"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	acontext "k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/grpcplugin/protos"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroupset"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/errors"
+	klog "k8s.io/klog/v2"
+	schedulerframework "k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+type nodeGroupSetProcessor struct {
+	client   protos.ProcessorsClient
+	delegate nodegroupset.NodeGroupSetProcessor
+}
+
+// NewNodeGroupSetProcessor returns a processor letting the gRPC server adjust
+// the decisions of the delegate processor.
+func NewNodeGroupSetProcessor(client protos.ProcessorsClient, delegate nodegroupset.NodeGroupSetProcessor) nodegroupset.NodeGroupSetProcessor {
+	return &nodeGroupSetProcessor{client: client, delegate: delegate}
+}
+
+// FindSimilarNodeGroups returns the node groups found similar by the delegate
+// processor which are kept by the gRPC server. The gRPC server can't add node
+// groups.
+func (p *nodeGroupSetProcessor) FindSimilarNodeGroups(ctx *acontext.AutoscalingContext, nodeGroup cloudprovider.NodeGroup,
+	nodeInfosForGroups map[string]*schedulerframework.NodeInfo) ([]cloudprovider.NodeGroup, errors.AutoscalerError) {
+	similarNodeGroups, aErr := p.delegate.FindSimilarNodeGroups(ctx, nodeGroup, nodeInfosForGroups)
+	if aErr != nil || len(similarNodeGroups) == 0 {
+		return similarNodeGroups, aErr
+	}
+
+	req := &protos.FilterSimilarNodeGroupsRequest{
+		NodeGroupId:   nodeGroup.Id(),
+		TemplateNodes: map[string]*apiv1.Node{},
+	}
+	similarById := make(map[string]cloudprovider.NodeGroup, len(similarNodeGroups))
+	for _, ng := range append([]cloudprovider.NodeGroup{nodeGroup}, similarNodeGroups...) {
+		if ng != nodeGroup {
+			req.SimilarNodeGroupIds = append(req.SimilarNodeGroupIds, ng.Id())
+			similarById[ng.Id()] = ng
+		}
+		if nodeInfo, found := nodeInfosForGroups[ng.Id()]; found {
+			req.TemplateNodes[ng.Id()] = nodeInfo.Node()
+		}
+	}
+
+	callCtx, cancel := context.WithTimeout(context.Background(), gRPCTimeout)
+	defer cancel()
+	resp, err := p.client.FilterSimilarNodeGroups(callCtx, req)
+	if err != nil {
+		logCallError("FilterSimilarNodeGroups", err)
+		return similarNodeGroups, nil
+	}
+
+	result := []cloudprovider.NodeGroup{}
+	for _, id := range resp.SimilarNodeGroupIds {
+		ng, found := similarById[id]
+		if !found {
+			klog.Errorf("gRPC processors server returned node group %s which is not similar to %s", id, nodeGroup.Id())
+			continue
+		}
+		delete(similarById, id)
+		result = append(result, ng)
+	}
+	return result, nil
+}
+
+// BalanceScaleUpBetweenGroups returns the scale ups returned by the gRPC
+// server, or the ones computed by the delegate processor if the call fails or
+// the returned scale ups are invalid.
+func (p *nodeGroupSetProcessor) BalanceScaleUpBetweenGroups(ctx *acontext.AutoscalingContext, groups []cloudprovider.NodeGroup, newNodes int) ([]nodegroupset.ScaleUpInfo, errors.AutoscalerError) {
+	scaleUpInfos, aErr := p.delegate.BalanceScaleUpBetweenGroups(ctx, groups, newNodes)
+	if aErr != nil {
+		return scaleUpInfos, aErr
+	}
+	return p.balanceScaleUp(groups, newNodes, scaleUpInfos), nil
+}
+
+// ProcessEstimatedScaleUps returns the scale ups returned by the gRPC server
+// for the scale ups split between the groups by the estimator, or the
+// estimated ones if the call fails or the returned scale ups are invalid.
+func (p *nodeGroupSetProcessor) ProcessEstimatedScaleUps(ctx *acontext.AutoscalingContext, groups []cloudprovider.NodeGroup, scaleUpInfos []nodegroupset.ScaleUpInfo) ([]nodegroupset.ScaleUpInfo, errors.AutoscalerError) {
+	if delegate, ok := p.delegate.(nodegroupset.EstimatedScaleUpProcessor); ok {
+		var aErr errors.AutoscalerError
+		if scaleUpInfos, aErr = delegate.ProcessEstimatedScaleUps(ctx, groups, scaleUpInfos); aErr != nil {
+			return scaleUpInfos, aErr
+		}
+	}
+	newNodes := 0
+	for _, info := range scaleUpInfos {
+		newNodes += info.NewSize - info.CurrentSize
+	}
+	return p.balanceScaleUp(groups, newNodes, scaleUpInfos), nil
+}
+
+// balanceScaleUp lets the gRPC server adjust the scale ups of newNodes nodes.
+func (p *nodeGroupSetProcessor) balanceScaleUp(groups []cloudprovider.NodeGroup, newNodes int, scaleUpInfos []nodegroupset.ScaleUpInfo) []nodegroupset.ScaleUpInfo {
+	req := &protos.BalanceScaleUpRequest{NewNodes: int32(newNodes)}
+	for _, info := range scaleUpInfos {
+		req.ScaleUpInfos = append(req.ScaleUpInfos, &protos.ScaleUpInfo{
+			NodeGroupId: info.Group.Id(),
+			CurrentSize: int32(info.CurrentSize),
+			NewSize:     int32(info.NewSize),
+			MaxSize:     int32(info.MaxSize),
+		})
+	}
+
+	callCtx, cancel := context.WithTimeout(context.Background(), gRPCTimeout)
+	defer cancel()
+	resp, err := p.client.BalanceScaleUp(callCtx, req)
+	if err != nil {
+		logCallError("BalanceScaleUp", err)
+		return scaleUpInfos
+	}
+
+	result, err := scaleUpInfosFromGRPC(resp.ScaleUpInfos, groups, newNodes)
+	if err != nil {
+		klog.Errorf("gRPC processors server returned invalid scale ups, using %v instead: %v", scaleUpInfos, err)
+		return scaleUpInfos
+	}
+	return result
+}
+
+// scaleUpInfosFromGRPC validates the scale ups returned by the gRPC server.
+// Each scale up must increase the size of one of the groups without exceeding
+// its max size, and newNodes can't be exceeded in total.
+func scaleUpInfosFromGRPC(grpcInfos []*protos.ScaleUpInfo, groups []cloudprovider.NodeGroup, newNodes int) ([]nodegroupset.ScaleUpInfo, error) {
+	groupsById := make(map[string]cloudprovider.NodeGroup, len(groups))
+	for _, group := range groups {
+		groupsById[group.Id()] = group
+	}
+
+	var result []nodegroupset.ScaleUpInfo
+	totalDelta := 0
+	for _, grpcInfo := range grpcInfos {
+		if grpcInfo == nil {
+			continue
+		}
+		group, found := groupsById[grpcInfo.NodeGroupId]
+		if !found {
+			return nil, fmt.Errorf("unknown or duplicate node group %s", grpcInfo.NodeGroupId)
+		}
+		delete(groupsById, grpcInfo.NodeGroupId)
+		currentSize, err := group.TargetSize()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get target size of node group %s: %v", group.Id(), err)
+		}
+		newSize := int(grpcInfo.NewSize)
+		if newSize == currentSize {
+			continue
+		}
+		if newSize < currentSize || newSize > group.MaxSize() {
+			return nil, fmt.Errorf("new size %d of node group %s is out of range [%d, %d]", newSize, group.Id(), currentSize, group.MaxSize())
+		}
+		totalDelta += newSize - currentSize
+		result = append(result, nodegroupset.ScaleUpInfo{
+			Group:       group,
+			CurrentSize: currentSize,
+			NewSize:     newSize,
+			MaxSize:     group.MaxSize(),
+		})
+	}
+	if totalDelta == 0 {
+		return nil, fmt.Errorf("no node is added")
+	}
+	if totalDelta > newNodes {
+		return nil, fmt.Errorf("%d nodes are added, at most %d are allowed", totalDelta, newNodes)
+	}
+	return result, nil
+}
+
+// CleanUp cleans up the processor's internal structures.
+func (p *nodeGroupSetProcessor) CleanUp() {
+	p.delegate.CleanUp()
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcplugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/grpcplugin/protos"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroupset"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/errors"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+	schedulerframework "k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+type fakeNodeGroupSetProcessor struct {
+	similar      []cloudprovider.NodeGroup
+	scaleUpInfos []nodegroupset.ScaleUpInfo
+}
+
+func (p *fakeNodeGroupSetProcessor) FindSimilarNodeGroups(*context.AutoscalingContext, cloudprovider.NodeGroup, map[string]*schedulerframework.NodeInfo) ([]cloudprovider.NodeGroup, errors.AutoscalerError) {
+	return p.similar, nil
+}
+
+func (p *fakeNodeGroupSetProcessor) BalanceScaleUpBetweenGroups(*context.AutoscalingContext, []cloudprovider.NodeGroup, int) ([]nodegroupset.ScaleUpInfo, errors.AutoscalerError) {
+	return p.scaleUpInfos, nil
+}
+
+func (p *fakeNodeGroupSetProcessor) CleanUp() {}
+
+func newTestNodeGroups() (*testprovider.TestCloudProvider, []cloudprovider.NodeGroup) {
+	provider := testprovider.NewTestCloudProvider(nil, nil)
+	provider.AddNodeGroup("ng1", 0, 10, 1)
+	provider.AddNodeGroup("ng2", 0, 10, 2)
+	provider.AddNodeGroup("ng3", 0, 3, 3)
+	return provider, []cloudprovider.NodeGroup{provider.GetNodeGroup("ng1"), provider.GetNodeGroup("ng2"), provider.GetNodeGroup("ng3")}
+}
+
+func TestFindSimilarNodeGroups(t *testing.T) {
+	provider, groups := newTestNodeGroups()
+	nodeInfos := map[string]*schedulerframework.NodeInfo{}
+	for _, group := range groups {
+		nodeInfo := schedulerframework.NewNodeInfo()
+		nodeInfo.SetNode(BuildTestNode("template-"+group.Id(), 1000, 1000))
+		nodeInfos[group.Id()] = nodeInfo
+	}
+	delegate := &fakeNodeGroupSetProcessor{similar: []cloudprovider.NodeGroup{groups[1], groups[2]}}
+
+	var received *protos.FilterSimilarNodeGroupsRequest
+	server := &fakeProcessorsServer{filterSimilarNodeGroups: func(req *protos.FilterSimilarNodeGroupsRequest) (*protos.FilterSimilarNodeGroupsResponse, error) {
+		received = req
+		return &protos.FilterSimilarNodeGroupsResponse{SimilarNodeGroupIds: []string{"ng3", "ng1", "unknown"}}, nil
+	}}
+	processor := NewNodeGroupSetProcessor(newTestClient(t, server), delegate)
+	similar, err := processor.FindSimilarNodeGroups(&context.AutoscalingContext{CloudProvider: provider}, groups[0], nodeInfos)
+	assert.NoError(t, err)
+	assert.Equal(t, []cloudprovider.NodeGroup{groups[2]}, similar)
+
+	assert.Equal(t, "ng1", received.NodeGroupId)
+	assert.Equal(t, []string{"ng2", "ng3"}, received.SimilarNodeGroupIds)
+	assert.Len(t, received.TemplateNodes, 3)
+	assert.Equal(t, "template-ng2", received.TemplateNodes["ng2"].Name)
+
+	// not implemented
+	processor = NewNodeGroupSetProcessor(newTestClient(t, &fakeProcessorsServer{}), delegate)
+	similar, err = processor.FindSimilarNodeGroups(&context.AutoscalingContext{CloudProvider: provider}, groups[0], nodeInfos)
+	assert.NoError(t, err)
+	assert.Equal(t, delegate.similar, similar)
+}
+
+func TestBalanceScaleUpBetweenGroups(t *testing.T) {
+	provider, groups := newTestNodeGroups()
+	delegate := &fakeNodeGroupSetProcessor{scaleUpInfos: []nodegroupset.ScaleUpInfo{
+		{Group: groups[0], CurrentSize: 1, NewSize: 3, MaxSize: 10},
+		{Group: groups[1], CurrentSize: 2, NewSize: 4, MaxSize: 10},
+	}}
+
+	testCases := []struct {
+		name     string
+		response []*protos.ScaleUpInfo
+		expected []nodegroupset.ScaleUpInfo
+	}{
+		{
+			name: "scale ups moved between groups",
+			response: []*protos.ScaleUpInfo{
+				{NodeGroupId: "ng1", NewSize: 1},
+				{NodeGroupId: "ng2", NewSize: 5},
+			},
+			expected: []nodegroupset.ScaleUpInfo{{Group: groups[1], CurrentSize: 2, NewSize: 5, MaxSize: 10}},
+		},
+		{
+			name: "too many nodes",
+			response: []*protos.ScaleUpInfo{
+				{NodeGroupId: "ng1", NewSize: 4},
+				{NodeGroupId: "ng2", NewSize: 4},
+			},
+			expected: delegate.scaleUpInfos,
+		},
+		{
+			name:     "above max size",
+			response: []*protos.ScaleUpInfo{{NodeGroupId: "ng3", NewSize: 4}},
+			expected: delegate.scaleUpInfos,
+		},
+		{
+			name:     "below current size",
+			response: []*protos.ScaleUpInfo{{NodeGroupId: "ng2", NewSize: 1}},
+			expected: delegate.scaleUpInfos,
+		},
+		{
+			name:     "unknown group",
+			response: []*protos.ScaleUpInfo{{NodeGroupId: "unknown", NewSize: 1}},
+			expected: delegate.scaleUpInfos,
+		},
+		{
+			name:     "no node added",
+			response: []*protos.ScaleUpInfo{},
+			expected: delegate.scaleUpInfos,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var received *protos.BalanceScaleUpRequest
+			server := &fakeProcessorsServer{balanceScaleUp: func(req *protos.BalanceScaleUpRequest) (*protos.BalanceScaleUpResponse, error) {
+				received = req
+				return &protos.BalanceScaleUpResponse{ScaleUpInfos: tc.response}, nil
+			}}
+			processor := NewNodeGroupSetProcessor(newTestClient(t, server), delegate)
+			scaleUpInfos, err := processor.BalanceScaleUpBetweenGroups(&context.AutoscalingContext{CloudProvider: provider}, groups, 4)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, scaleUpInfos)
+			assert.Equal(t, int32(4), received.NewNodes)
+			assert.Equal(t, []*protos.ScaleUpInfo{
+				{NodeGroupId: "ng1", CurrentSize: 1, NewSize: 3, MaxSize: 10},
+				{NodeGroupId: "ng2", CurrentSize: 2, NewSize: 4, MaxSize: 10},
+			}, received.ScaleUpInfos)
+		})
+	}
+}
+
+func TestProcessEstimatedScaleUps(t *testing.T) {
+	provider, groups := newTestNodeGroups()
+	estimated := []nodegroupset.ScaleUpInfo{
+		{Group: groups[0], CurrentSize: 1, NewSize: 2, MaxSize: 10},
+		{Group: groups[1], CurrentSize: 2, NewSize: 4, MaxSize: 10},
+	}
+
+	var received *protos.BalanceScaleUpRequest
+	server := &fakeProcessorsServer{balanceScaleUp: func(req *protos.BalanceScaleUpRequest) (*protos.BalanceScaleUpResponse, error) {
+		received = req
+		return &protos.BalanceScaleUpResponse{ScaleUpInfos: []*protos.ScaleUpInfo{{NodeGroupId: "ng1", NewSize: 4}}}, nil
+	}}
+	processor := NewNodeGroupSetProcessor(newTestClient(t, server), &fakeNodeGroupSetProcessor{}).(nodegroupset.EstimatedScaleUpProcessor)
+	scaleUpInfos, err := processor.ProcessEstimatedScaleUps(&context.AutoscalingContext{CloudProvider: provider}, groups, estimated)
+	assert.NoError(t, err)
+	assert.Equal(t, []nodegroupset.ScaleUpInfo{{Group: groups[0], CurrentSize: 1, NewSize: 4, MaxSize: 10}}, scaleUpInfos)
+	assert.Equal(t, int32(3), received.NewNodes)
+	assert.Equal(t, []*protos.ScaleUpInfo{
+		{NodeGroupId: "ng1", CurrentSize: 1, NewSize: 2, MaxSize: 10},
+		{NodeGroupId: "ng2", CurrentSize: 2, NewSize: 4, MaxSize: 10},
+	}, received.ScaleUpInfos)
+
+	// not implemented
+	processor = NewNodeGroupSetProcessor(newTestClient(t, &fakeProcessorsServer{}), &fakeNodeGroupSetProcessor{}).(nodegroupset.EstimatedScaleUpProcessor)
+	scaleUpInfos, err = processor.ProcessEstimatedScaleUps(&context.AutoscalingContext{CloudProvider: provider}, groups, estimated)
+	assert.NoError(t, err)
+	assert.Equal(t, estimated, scaleUpInfos)
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcplugin
+
+import (
+	"context"
+
+	apiv1 "k8s.io/api/core/v1"
+	acontext "k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/grpcplugin/protos"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/pods"
+	klog "k8s.io/klog/v2"
+)
+
+type podListProcessor struct {
+	client protos.ProcessorsClient
+}
+
+// NewPodListProcessor returns a processor letting the gRPC server drop
+// unschedulable pods, so that they don't trigger scale up.
+func NewPodListProcessor(client protos.ProcessorsClient) pods.PodListProcessor {
+	return &podListProcessor{client: client}
+}
+
+// Process keeps the unschedulable pods returned by the gRPC server. All pods
+// are kept if the call fails.
+func (p *podListProcessor) Process(ctx *acontext.AutoscalingContext, unschedulablePods []*apiv1.Pod) ([]*apiv1.Pod, error) {
+	if len(unschedulablePods) == 0 {
+		return unschedulablePods, nil
+	}
+	callCtx, cancel := context.WithTimeout(context.Background(), gRPCTimeout)
+	defer cancel()
+	resp, err := p.client.FilterUnschedulablePods(callCtx, &protos.FilterUnschedulablePodsRequest{Pods: unschedulablePods})
+	if err != nil {
+		logCallError("FilterUnschedulablePods", err)
+		return unschedulablePods, nil
+	}
+
+	keep := make(map[string]bool, len(resp.Pods))
+	for _, pod := range resp.Pods {
+		keep[pod] = true
+	}
+	var result []*apiv1.Pod
+	for _, pod := range unschedulablePods {
+		if keep[podKey(pod)] {
+			result = append(result, pod)
+		} else {
+			klog.V(4).Infof("Pod %s dropped by gRPC processors server", podKey(pod))
+		}
+	}
+	return result, nil
+}
+
+// CleanUp cleans up the processor's internal structures.
+func (p *podListProcessor) CleanUp() {
+}
+
+func podKey(pod *apiv1.Pod) string {
+	return pod.Namespace + "/" + pod.Name
+}
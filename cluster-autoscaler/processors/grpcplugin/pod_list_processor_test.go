@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcplugin
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/grpcplugin/protos"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+)
+
+func TestPodListProcessor(t *testing.T) {
+	p1 := BuildTestPod("p1", 100, 0)
+	p1.Namespace = "team-a"
+	p2 := BuildTestPod("p2", 100, 0)
+	p2.Namespace = "team-b"
+	p3 := BuildTestPod("p3", 100, 0)
+	p3.Namespace = "team-a"
+	pods := []*apiv1.Pod{p1, p2, p3}
+
+	testCases := []struct {
+		name         string
+		server       *fakeProcessorsServer
+		expectedPods []*apiv1.Pod
+	}{
+		{
+			name: "pods dropped by the server",
+			server: &fakeProcessorsServer{filterUnschedulablePods: func(req *protos.FilterUnschedulablePodsRequest) (*protos.FilterUnschedulablePodsResponse, error) {
+				var keep []string
+				for _, pod := range req.Pods {
+					if pod.Namespace == "team-a" {
+						keep = append(keep, podKey(pod))
+					}
+				}
+				return &protos.FilterUnschedulablePodsResponse{Pods: append(keep, "unknown/pod")}, nil
+			}},
+			expectedPods: []*apiv1.Pod{p1, p3},
+		},
+		{
+			name:         "not implemented",
+			server:       &fakeProcessorsServer{},
+			expectedPods: pods,
+		},
+		{
+			name: "error",
+			server: &fakeProcessorsServer{filterUnschedulablePods: func(*protos.FilterUnschedulablePodsRequest) (*protos.FilterUnschedulablePodsResponse, error) {
+				return nil, fmt.Errorf("boom")
+			}},
+			expectedPods: pods,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			processor := NewPodListProcessor(newTestClient(t, tc.server))
+			result, err := processor.Process(&context.AutoscalingContext{}, pods)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedPods, result)
+		})
+	}
+}
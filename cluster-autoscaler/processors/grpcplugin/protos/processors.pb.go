@@ -0,0 +1,913 @@
+//
+//Copyright 2024 The Kubernetes Authors.
+//
+//Licensed under the Apache License, Version 2.0 (the "License");
+//you may not use this file except in compliance with the License.
+//You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+//Unless required by applicable law or agreed to in writing, software
+//distributed under the License is distributed on an "AS IS" BASIS,
+//WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//See the License for the specific language governing permissions and
+//limitations under the License.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: processors/grpcplugin/protos/processors.proto
+
+package protos
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	v1 "k8s.io/api/core/v1"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type FilterUnschedulablePodsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pods []*v1.Pod `protobuf:"bytes,1,rep,name=pods,proto3" json:"pods,omitempty"`
+}
+
+func (x *FilterUnschedulablePodsRequest) Reset() {
+	*x = FilterUnschedulablePodsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_processors_grpcplugin_protos_processors_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FilterUnschedulablePodsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FilterUnschedulablePodsRequest) ProtoMessage() {}
+
+func (x *FilterUnschedulablePodsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_processors_grpcplugin_protos_processors_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FilterUnschedulablePodsRequest.ProtoReflect.Descriptor instead.
+func (*FilterUnschedulablePodsRequest) Descriptor() ([]byte, []int) {
+	return file_processors_grpcplugin_protos_processors_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *FilterUnschedulablePodsRequest) GetPods() []*v1.Pod {
+	if x != nil {
+		return x.Pods
+	}
+	return nil
+}
+
+type FilterUnschedulablePodsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// namespace/name of the pods to keep, other pods are dropped.
+	Pods []string `protobuf:"bytes,1,rep,name=pods,proto3" json:"pods,omitempty"`
+}
+
+func (x *FilterUnschedulablePodsResponse) Reset() {
+	*x = FilterUnschedulablePodsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_processors_grpcplugin_protos_processors_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FilterUnschedulablePodsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FilterUnschedulablePodsResponse) ProtoMessage() {}
+
+func (x *FilterUnschedulablePodsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_processors_grpcplugin_protos_processors_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FilterUnschedulablePodsResponse.ProtoReflect.Descriptor instead.
+func (*FilterUnschedulablePodsResponse) Descriptor() ([]byte, []int) {
+	return file_processors_grpcplugin_protos_processors_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *FilterUnschedulablePodsResponse) GetPods() []string {
+	if x != nil {
+		return x.Pods
+	}
+	return nil
+}
+
+type NodeToRemove struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Node             *v1.Node  `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	NodeGroupId      string    `protobuf:"bytes,2,opt,name=nodeGroupId,proto3" json:"nodeGroupId,omitempty"`
+	PodsToReschedule []*v1.Pod `protobuf:"bytes,3,rep,name=podsToReschedule,proto3" json:"podsToReschedule,omitempty"`
+}
+
+func (x *NodeToRemove) Reset() {
+	*x = NodeToRemove{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_processors_grpcplugin_protos_processors_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NodeToRemove) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeToRemove) ProtoMessage() {}
+
+func (x *NodeToRemove) ProtoReflect() protoreflect.Message {
+	mi := &file_processors_grpcplugin_protos_processors_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeToRemove.ProtoReflect.Descriptor instead.
+func (*NodeToRemove) Descriptor() ([]byte, []int) {
+	return file_processors_grpcplugin_protos_processors_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *NodeToRemove) GetNode() *v1.Node {
+	if x != nil {
+		return x.Node
+	}
+	return nil
+}
+
+func (x *NodeToRemove) GetNodeGroupId() string {
+	if x != nil {
+		return x.NodeGroupId
+	}
+	return ""
+}
+
+func (x *NodeToRemove) GetPodsToReschedule() []*v1.Pod {
+	if x != nil {
+		return x.PodsToReschedule
+	}
+	return nil
+}
+
+type FilterNodesToRemoveRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Candidates []*NodeToRemove `protobuf:"bytes,1,rep,name=candidates,proto3" json:"candidates,omitempty"`
+	// maximum number of nodes which can be removed.
+	MaxCount int32 `protobuf:"varint,2,opt,name=maxCount,proto3" json:"maxCount,omitempty"`
+}
+
+func (x *FilterNodesToRemoveRequest) Reset() {
+	*x = FilterNodesToRemoveRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_processors_grpcplugin_protos_processors_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FilterNodesToRemoveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FilterNodesToRemoveRequest) ProtoMessage() {}
+
+func (x *FilterNodesToRemoveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_processors_grpcplugin_protos_processors_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FilterNodesToRemoveRequest.ProtoReflect.Descriptor instead.
+func (*FilterNodesToRemoveRequest) Descriptor() ([]byte, []int) {
+	return file_processors_grpcplugin_protos_processors_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *FilterNodesToRemoveRequest) GetCandidates() []*NodeToRemove {
+	if x != nil {
+		return x.Candidates
+	}
+	return nil
+}
+
+func (x *FilterNodesToRemoveRequest) GetMaxCount() int32 {
+	if x != nil {
+		return x.MaxCount
+	}
+	return 0
+}
+
+type FilterNodesToRemoveResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// names of the nodes to remove, in the order of removal.
+	NodeNames []string `protobuf:"bytes,1,rep,name=nodeNames,proto3" json:"nodeNames,omitempty"`
+}
+
+func (x *FilterNodesToRemoveResponse) Reset() {
+	*x = FilterNodesToRemoveResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_processors_grpcplugin_protos_processors_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FilterNodesToRemoveResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FilterNodesToRemoveResponse) ProtoMessage() {}
+
+func (x *FilterNodesToRemoveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_processors_grpcplugin_protos_processors_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FilterNodesToRemoveResponse.ProtoReflect.Descriptor instead.
+func (*FilterNodesToRemoveResponse) Descriptor() ([]byte, []int) {
+	return file_processors_grpcplugin_protos_processors_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *FilterNodesToRemoveResponse) GetNodeNames() []string {
+	if x != nil {
+		return x.NodeNames
+	}
+	return nil
+}
+
+type FilterSimilarNodeGroupsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NodeGroupId string `protobuf:"bytes,1,opt,name=nodeGroupId,proto3" json:"nodeGroupId,omitempty"`
+	// node groups found similar by Cluster Autoscaler.
+	SimilarNodeGroupIds []string `protobuf:"bytes,2,rep,name=similarNodeGroupIds,proto3" json:"similarNodeGroupIds,omitempty"`
+	// key is node group id.
+	TemplateNodes map[string]*v1.Node `protobuf:"bytes,3,rep,name=templateNodes,proto3" json:"templateNodes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *FilterSimilarNodeGroupsRequest) Reset() {
+	*x = FilterSimilarNodeGroupsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_processors_grpcplugin_protos_processors_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FilterSimilarNodeGroupsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FilterSimilarNodeGroupsRequest) ProtoMessage() {}
+
+func (x *FilterSimilarNodeGroupsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_processors_grpcplugin_protos_processors_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FilterSimilarNodeGroupsRequest.ProtoReflect.Descriptor instead.
+func (*FilterSimilarNodeGroupsRequest) Descriptor() ([]byte, []int) {
+	return file_processors_grpcplugin_protos_processors_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *FilterSimilarNodeGroupsRequest) GetNodeGroupId() string {
+	if x != nil {
+		return x.NodeGroupId
+	}
+	return ""
+}
+
+func (x *FilterSimilarNodeGroupsRequest) GetSimilarNodeGroupIds() []string {
+	if x != nil {
+		return x.SimilarNodeGroupIds
+	}
+	return nil
+}
+
+func (x *FilterSimilarNodeGroupsRequest) GetTemplateNodes() map[string]*v1.Node {
+	if x != nil {
+		return x.TemplateNodes
+	}
+	return nil
+}
+
+type FilterSimilarNodeGroupsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SimilarNodeGroupIds []string `protobuf:"bytes,1,rep,name=similarNodeGroupIds,proto3" json:"similarNodeGroupIds,omitempty"`
+}
+
+func (x *FilterSimilarNodeGroupsResponse) Reset() {
+	*x = FilterSimilarNodeGroupsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_processors_grpcplugin_protos_processors_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FilterSimilarNodeGroupsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FilterSimilarNodeGroupsResponse) ProtoMessage() {}
+
+func (x *FilterSimilarNodeGroupsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_processors_grpcplugin_protos_processors_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FilterSimilarNodeGroupsResponse.ProtoReflect.Descriptor instead.
+func (*FilterSimilarNodeGroupsResponse) Descriptor() ([]byte, []int) {
+	return file_processors_grpcplugin_protos_processors_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *FilterSimilarNodeGroupsResponse) GetSimilarNodeGroupIds() []string {
+	if x != nil {
+		return x.SimilarNodeGroupIds
+	}
+	return nil
+}
+
+type ScaleUpInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NodeGroupId string `protobuf:"bytes,1,opt,name=nodeGroupId,proto3" json:"nodeGroupId,omitempty"`
+	CurrentSize int32  `protobuf:"varint,2,opt,name=currentSize,proto3" json:"currentSize,omitempty"`
+	NewSize     int32  `protobuf:"varint,3,opt,name=newSize,proto3" json:"newSize,omitempty"`
+	MaxSize     int32  `protobuf:"varint,4,opt,name=maxSize,proto3" json:"maxSize,omitempty"`
+}
+
+func (x *ScaleUpInfo) Reset() {
+	*x = ScaleUpInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_processors_grpcplugin_protos_processors_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ScaleUpInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScaleUpInfo) ProtoMessage() {}
+
+func (x *ScaleUpInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_processors_grpcplugin_protos_processors_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScaleUpInfo.ProtoReflect.Descriptor instead.
+func (*ScaleUpInfo) Descriptor() ([]byte, []int) {
+	return file_processors_grpcplugin_protos_processors_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ScaleUpInfo) GetNodeGroupId() string {
+	if x != nil {
+		return x.NodeGroupId
+	}
+	return ""
+}
+
+func (x *ScaleUpInfo) GetCurrentSize() int32 {
+	if x != nil {
+		return x.CurrentSize
+	}
+	return 0
+}
+
+func (x *ScaleUpInfo) GetNewSize() int32 {
+	if x != nil {
+		return x.NewSize
+	}
+	return 0
+}
+
+func (x *ScaleUpInfo) GetMaxSize() int32 {
+	if x != nil {
+		return x.MaxSize
+	}
+	return 0
+}
+
+type BalanceScaleUpRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// scale ups computed by Cluster Autoscaler.
+	ScaleUpInfos []*ScaleUpInfo `protobuf:"bytes,1,rep,name=scaleUpInfos,proto3" json:"scaleUpInfos,omitempty"`
+	// number of nodes to add in total.
+	NewNodes int32 `protobuf:"varint,2,opt,name=newNodes,proto3" json:"newNodes,omitempty"`
+}
+
+func (x *BalanceScaleUpRequest) Reset() {
+	*x = BalanceScaleUpRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_processors_grpcplugin_protos_processors_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BalanceScaleUpRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BalanceScaleUpRequest) ProtoMessage() {}
+
+func (x *BalanceScaleUpRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_processors_grpcplugin_protos_processors_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BalanceScaleUpRequest.ProtoReflect.Descriptor instead.
+func (*BalanceScaleUpRequest) Descriptor() ([]byte, []int) {
+	return file_processors_grpcplugin_protos_processors_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *BalanceScaleUpRequest) GetScaleUpInfos() []*ScaleUpInfo {
+	if x != nil {
+		return x.ScaleUpInfos
+	}
+	return nil
+}
+
+func (x *BalanceScaleUpRequest) GetNewNodes() int32 {
+	if x != nil {
+		return x.NewNodes
+	}
+	return 0
+}
+
+type BalanceScaleUpResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ScaleUpInfos []*ScaleUpInfo `protobuf:"bytes,1,rep,name=scaleUpInfos,proto3" json:"scaleUpInfos,omitempty"`
+}
+
+func (x *BalanceScaleUpResponse) Reset() {
+	*x = BalanceScaleUpResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_processors_grpcplugin_protos_processors_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BalanceScaleUpResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BalanceScaleUpResponse) ProtoMessage() {}
+
+func (x *BalanceScaleUpResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_processors_grpcplugin_protos_processors_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BalanceScaleUpResponse.ProtoReflect.Descriptor instead.
+func (*BalanceScaleUpResponse) Descriptor() ([]byte, []int) {
+	return file_processors_grpcplugin_protos_processors_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *BalanceScaleUpResponse) GetScaleUpInfos() []*ScaleUpInfo {
+	if x != nil {
+		return x.ScaleUpInfos
+	}
+	return nil
+}
+
+var File_processors_grpcplugin_protos_processors_proto protoreflect.FileDescriptor
+
+var file_processors_grpcplugin_protos_processors_proto_rawDesc = []byte{
+	0x0a, 0x2d, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x73, 0x2f, 0x67, 0x72, 0x70,
+	0x63, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2f, 0x70,
+	0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x15, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x73, 0x2e, 0x67, 0x72, 0x70, 0x63,
+	0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x1a, 0x22, 0x6b, 0x38, 0x73, 0x2e, 0x69, 0x6f, 0x2f, 0x61,
+	0x70, 0x69, 0x2f, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x76, 0x31, 0x2f, 0x67, 0x65, 0x6e, 0x65, 0x72,
+	0x61, 0x74, 0x65, 0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x4d, 0x0a, 0x1e, 0x46, 0x69,
+	0x6c, 0x74, 0x65, 0x72, 0x55, 0x6e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x61, 0x62, 0x6c,
+	0x65, 0x50, 0x6f, 0x64, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2b, 0x0a, 0x04,
+	0x70, 0x6f, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6b, 0x38, 0x73,
+	0x2e, 0x69, 0x6f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e,
+	0x50, 0x6f, 0x64, 0x52, 0x04, 0x70, 0x6f, 0x64, 0x73, 0x22, 0x35, 0x0a, 0x1f, 0x46, 0x69, 0x6c,
+	0x74, 0x65, 0x72, 0x55, 0x6e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x61, 0x62, 0x6c, 0x65,
+	0x50, 0x6f, 0x64, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x70, 0x6f, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x70, 0x6f, 0x64, 0x73,
+	0x22, 0xa3, 0x01, 0x0a, 0x0c, 0x4e, 0x6f, 0x64, 0x65, 0x54, 0x6f, 0x52, 0x65, 0x6d, 0x6f, 0x76,
+	0x65, 0x12, 0x2c, 0x0a, 0x04, 0x6e, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x18, 0x2e, 0x6b, 0x38, 0x73, 0x2e, 0x69, 0x6f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x63, 0x6f, 0x72,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x6e, 0x6f, 0x64, 0x65, 0x12,
+	0x20, 0x0a, 0x0b, 0x6e, 0x6f, 0x64, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6e, 0x6f, 0x64, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49,
+	0x64, 0x12, 0x43, 0x0a, 0x10, 0x70, 0x6f, 0x64, 0x73, 0x54, 0x6f, 0x52, 0x65, 0x73, 0x63, 0x68,
+	0x65, 0x64, 0x75, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6b, 0x38,
+	0x73, 0x2e, 0x69, 0x6f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x50, 0x6f, 0x64, 0x52, 0x10, 0x70, 0x6f, 0x64, 0x73, 0x54, 0x6f, 0x52, 0x65, 0x73, 0x63,
+	0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x22, 0x7d, 0x0a, 0x1a, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72,
+	0x4e, 0x6f, 0x64, 0x65, 0x73, 0x54, 0x6f, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x43, 0x0a, 0x0a, 0x63, 0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74,
+	0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x70, 0x72, 0x6f, 0x63, 0x65,
+	0x73, 0x73, 0x6f, 0x72, 0x73, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e,
+	0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x54, 0x6f, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x52, 0x0a, 0x63,
+	0x61, 0x6e, 0x64, 0x69, 0x64, 0x61, 0x74, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x6d, 0x61, 0x78,
+	0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x6d, 0x61, 0x78,
+	0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x3b, 0x0a, 0x1b, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x4e,
+	0x6f, 0x64, 0x65, 0x73, 0x54, 0x6f, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x6f, 0x64, 0x65, 0x4e, 0x61, 0x6d, 0x65,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x6f, 0x64, 0x65, 0x4e, 0x61, 0x6d,
+	0x65, 0x73, 0x22, 0xc0, 0x02, 0x0a, 0x1e, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x53, 0x69, 0x6d,
+	0x69, 0x6c, 0x61, 0x72, 0x4e, 0x6f, 0x64, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x6e, 0x6f, 0x64, 0x65, 0x47, 0x72, 0x6f,
+	0x75, 0x70, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6e, 0x6f, 0x64, 0x65,
+	0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x30, 0x0a, 0x13, 0x73, 0x69, 0x6d, 0x69, 0x6c,
+	0x61, 0x72, 0x4e, 0x6f, 0x64, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x13, 0x73, 0x69, 0x6d, 0x69, 0x6c, 0x61, 0x72, 0x4e, 0x6f, 0x64,
+	0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x73, 0x12, 0x6e, 0x0a, 0x0d, 0x74, 0x65, 0x6d,
+	0x70, 0x6c, 0x61, 0x74, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x48, 0x2e, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x73, 0x2e, 0x67, 0x72,
+	0x70, 0x63, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x2e, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x53,
+	0x69, 0x6d, 0x69, 0x6c, 0x61, 0x72, 0x4e, 0x6f, 0x64, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65,
+	0x4e, 0x6f, 0x64, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0d, 0x74, 0x65, 0x6d, 0x70,
+	0x6c, 0x61, 0x74, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x1a, 0x5a, 0x0a, 0x12, 0x54, 0x65, 0x6d,
+	0x70, 0x6c, 0x61, 0x74, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x2e, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x18, 0x2e, 0x6b, 0x38, 0x73, 0x2e, 0x69, 0x6f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x63, 0x6f,
+	0x72, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x53, 0x0a, 0x1f, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x53,
+	0x69, 0x6d, 0x69, 0x6c, 0x61, 0x72, 0x4e, 0x6f, 0x64, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x30, 0x0a, 0x13, 0x73, 0x69, 0x6d, 0x69,
+	0x6c, 0x61, 0x72, 0x4e, 0x6f, 0x64, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x13, 0x73, 0x69, 0x6d, 0x69, 0x6c, 0x61, 0x72, 0x4e, 0x6f,
+	0x64, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x73, 0x22, 0x85, 0x01, 0x0a, 0x0b, 0x53,
+	0x63, 0x61, 0x6c, 0x65, 0x55, 0x70, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x20, 0x0a, 0x0b, 0x6e, 0x6f,
+	0x64, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x6e, 0x6f, 0x64, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x20, 0x0a, 0x0b,
+	0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x0b, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x6e, 0x65, 0x77, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x07, 0x6e, 0x65, 0x77, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x61, 0x78, 0x53,
+	0x69, 0x7a, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x6d, 0x61, 0x78, 0x53, 0x69,
+	0x7a, 0x65, 0x22, 0x7b, 0x0a, 0x15, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x53, 0x63, 0x61,
+	0x6c, 0x65, 0x55, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x46, 0x0a, 0x0c, 0x73,
+	0x63, 0x61, 0x6c, 0x65, 0x55, 0x70, 0x49, 0x6e, 0x66, 0x6f, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x22, 0x2e, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x73, 0x2e, 0x67,
+	0x72, 0x70, 0x63, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x2e, 0x53, 0x63, 0x61, 0x6c, 0x65, 0x55,
+	0x70, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0c, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x55, 0x70, 0x49, 0x6e,
+	0x66, 0x6f, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x6e, 0x65, 0x77, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x6e, 0x65, 0x77, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x22,
+	0x60, 0x0a, 0x16, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x53, 0x63, 0x61, 0x6c, 0x65, 0x55,
+	0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x46, 0x0a, 0x0c, 0x73, 0x63, 0x61,
+	0x6c, 0x65, 0x55, 0x70, 0x49, 0x6e, 0x66, 0x6f, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x22, 0x2e, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x73, 0x2e, 0x67, 0x72, 0x70,
+	0x63, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x2e, 0x53, 0x63, 0x61, 0x6c, 0x65, 0x55, 0x70, 0x49,
+	0x6e, 0x66, 0x6f, 0x52, 0x0c, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x55, 0x70, 0x49, 0x6e, 0x66, 0x6f,
+	0x73, 0x32, 0x97, 0x04, 0x0a, 0x0a, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x73,
+	0x12, 0x8a, 0x01, 0x0a, 0x17, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x55, 0x6e, 0x73, 0x63, 0x68,
+	0x65, 0x64, 0x75, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x50, 0x6f, 0x64, 0x73, 0x12, 0x35, 0x2e, 0x70,
+	0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x73, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x70, 0x6c,
+	0x75, 0x67, 0x69, 0x6e, 0x2e, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x55, 0x6e, 0x73, 0x63, 0x68,
+	0x65, 0x64, 0x75, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x50, 0x6f, 0x64, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x36, 0x2e, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x73,
+	0x2e, 0x67, 0x72, 0x70, 0x63, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x2e, 0x46, 0x69, 0x6c, 0x74,
+	0x65, 0x72, 0x55, 0x6e, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x50,
+	0x6f, 0x64, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x7e, 0x0a,
+	0x13, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x54, 0x6f, 0x52, 0x65,
+	0x6d, 0x6f, 0x76, 0x65, 0x12, 0x31, 0x2e, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72,
+	0x73, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x2e, 0x46, 0x69, 0x6c,
+	0x74, 0x65, 0x72, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x54, 0x6f, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x32, 0x2e, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73,
+	0x73, 0x6f, 0x72, 0x73, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x2e,
+	0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x54, 0x6f, 0x52, 0x65, 0x6d,
+	0x6f, 0x76, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x8a, 0x01,
+	0x0a, 0x17, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x53, 0x69, 0x6d, 0x69, 0x6c, 0x61, 0x72, 0x4e,
+	0x6f, 0x64, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x12, 0x35, 0x2e, 0x70, 0x72, 0x6f, 0x63,
+	0x65, 0x73, 0x73, 0x6f, 0x72, 0x73, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x70, 0x6c, 0x75, 0x67, 0x69,
+	0x6e, 0x2e, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x53, 0x69, 0x6d, 0x69, 0x6c, 0x61, 0x72, 0x4e,
+	0x6f, 0x64, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x36, 0x2e, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x73, 0x2e, 0x67, 0x72,
+	0x70, 0x63, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x2e, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x53,
+	0x69, 0x6d, 0x69, 0x6c, 0x61, 0x72, 0x4e, 0x6f, 0x64, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x6f, 0x0a, 0x0e, 0x42, 0x61,
+	0x6c, 0x61, 0x6e, 0x63, 0x65, 0x53, 0x63, 0x61, 0x6c, 0x65, 0x55, 0x70, 0x12, 0x2c, 0x2e, 0x70,
+	0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x73, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x70, 0x6c,
+	0x75, 0x67, 0x69, 0x6e, 0x2e, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x53, 0x63, 0x61, 0x6c,
+	0x65, 0x55, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2d, 0x2e, 0x70, 0x72, 0x6f,
+	0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x73, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x70, 0x6c, 0x75, 0x67,
+	0x69, 0x6e, 0x2e, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x53, 0x63, 0x61, 0x6c, 0x65, 0x55,
+	0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x31, 0x5a, 0x2f, 0x63,
+	0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x2d, 0x61, 0x75, 0x74, 0x6f, 0x73, 0x63, 0x61, 0x6c, 0x65,
+	0x72, 0x2f, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x73, 0x2f, 0x67, 0x72, 0x70,
+	0x63, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_processors_grpcplugin_protos_processors_proto_rawDescOnce sync.Once
+	file_processors_grpcplugin_protos_processors_proto_rawDescData = file_processors_grpcplugin_protos_processors_proto_rawDesc
+)
+
+func file_processors_grpcplugin_protos_processors_proto_rawDescGZIP() []byte {
+	file_processors_grpcplugin_protos_processors_proto_rawDescOnce.Do(func() {
+		file_processors_grpcplugin_protos_processors_proto_rawDescData = protoimpl.X.CompressGZIP(file_processors_grpcplugin_protos_processors_proto_rawDescData)
+	})
+	return file_processors_grpcplugin_protos_processors_proto_rawDescData
+}
+
+var file_processors_grpcplugin_protos_processors_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_processors_grpcplugin_protos_processors_proto_goTypes = []any{
+	(*FilterUnschedulablePodsRequest)(nil),  // 0: processors.grpcplugin.FilterUnschedulablePodsRequest
+	(*FilterUnschedulablePodsResponse)(nil), // 1: processors.grpcplugin.FilterUnschedulablePodsResponse
+	(*NodeToRemove)(nil),                    // 2: processors.grpcplugin.NodeToRemove
+	(*FilterNodesToRemoveRequest)(nil),      // 3: processors.grpcplugin.FilterNodesToRemoveRequest
+	(*FilterNodesToRemoveResponse)(nil),     // 4: processors.grpcplugin.FilterNodesToRemoveResponse
+	(*FilterSimilarNodeGroupsRequest)(nil),  // 5: processors.grpcplugin.FilterSimilarNodeGroupsRequest
+	(*FilterSimilarNodeGroupsResponse)(nil), // 6: processors.grpcplugin.FilterSimilarNodeGroupsResponse
+	(*ScaleUpInfo)(nil),                     // 7: processors.grpcplugin.ScaleUpInfo
+	(*BalanceScaleUpRequest)(nil),           // 8: processors.grpcplugin.BalanceScaleUpRequest
+	(*BalanceScaleUpResponse)(nil),          // 9: processors.grpcplugin.BalanceScaleUpResponse
+	nil,                                     // 10: processors.grpcplugin.FilterSimilarNodeGroupsRequest.TemplateNodesEntry
+	(*v1.Pod)(nil),                          // 11: k8s.io.api.core.v1.Pod
+	(*v1.Node)(nil),                         // 12: k8s.io.api.core.v1.Node
+}
+var file_processors_grpcplugin_protos_processors_proto_depIdxs = []int32{
+	11, // 0: processors.grpcplugin.FilterUnschedulablePodsRequest.pods:type_name -> k8s.io.api.core.v1.Pod
+	12, // 1: processors.grpcplugin.NodeToRemove.node:type_name -> k8s.io.api.core.v1.Node
+	11, // 2: processors.grpcplugin.NodeToRemove.podsToReschedule:type_name -> k8s.io.api.core.v1.Pod
+	2,  // 3: processors.grpcplugin.FilterNodesToRemoveRequest.candidates:type_name -> processors.grpcplugin.NodeToRemove
+	10, // 4: processors.grpcplugin.FilterSimilarNodeGroupsRequest.templateNodes:type_name -> processors.grpcplugin.FilterSimilarNodeGroupsRequest.TemplateNodesEntry
+	7,  // 5: processors.grpcplugin.BalanceScaleUpRequest.scaleUpInfos:type_name -> processors.grpcplugin.ScaleUpInfo
+	7,  // 6: processors.grpcplugin.BalanceScaleUpResponse.scaleUpInfos:type_name -> processors.grpcplugin.ScaleUpInfo
+	12, // 7: processors.grpcplugin.FilterSimilarNodeGroupsRequest.TemplateNodesEntry.value:type_name -> k8s.io.api.core.v1.Node
+	0,  // 8: processors.grpcplugin.Processors.FilterUnschedulablePods:input_type -> processors.grpcplugin.FilterUnschedulablePodsRequest
+	3,  // 9: processors.grpcplugin.Processors.FilterNodesToRemove:input_type -> processors.grpcplugin.FilterNodesToRemoveRequest
+	5,  // 10: processors.grpcplugin.Processors.FilterSimilarNodeGroups:input_type -> processors.grpcplugin.FilterSimilarNodeGroupsRequest
+	8,  // 11: processors.grpcplugin.Processors.BalanceScaleUp:input_type -> processors.grpcplugin.BalanceScaleUpRequest
+	1,  // 12: processors.grpcplugin.Processors.FilterUnschedulablePods:output_type -> processors.grpcplugin.FilterUnschedulablePodsResponse
+	4,  // 13: processors.grpcplugin.Processors.FilterNodesToRemove:output_type -> processors.grpcplugin.FilterNodesToRemoveResponse
+	6,  // 14: processors.grpcplugin.Processors.FilterSimilarNodeGroups:output_type -> processors.grpcplugin.FilterSimilarNodeGroupsResponse
+	9,  // 15: processors.grpcplugin.Processors.BalanceScaleUp:output_type -> processors.grpcplugin.BalanceScaleUpResponse
+	12, // [12:16] is the sub-list for method output_type
+	8,  // [8:12] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_processors_grpcplugin_protos_processors_proto_init() }
+func file_processors_grpcplugin_protos_processors_proto_init() {
+	if File_processors_grpcplugin_protos_processors_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_processors_grpcplugin_protos_processors_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*FilterUnschedulablePodsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_processors_grpcplugin_protos_processors_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*FilterUnschedulablePodsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_processors_grpcplugin_protos_processors_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*NodeToRemove); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_processors_grpcplugin_protos_processors_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*FilterNodesToRemoveRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_processors_grpcplugin_protos_processors_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*FilterNodesToRemoveResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_processors_grpcplugin_protos_processors_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*FilterSimilarNodeGroupsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_processors_grpcplugin_protos_processors_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*FilterSimilarNodeGroupsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_processors_grpcplugin_protos_processors_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*ScaleUpInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_processors_grpcplugin_protos_processors_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*BalanceScaleUpRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_processors_grpcplugin_protos_processors_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*BalanceScaleUpResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_processors_grpcplugin_protos_processors_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_processors_grpcplugin_protos_processors_proto_goTypes,
+		DependencyIndexes: file_processors_grpcplugin_protos_processors_proto_depIdxs,
+		MessageInfos:      file_processors_grpcplugin_protos_processors_proto_msgTypes,
+	}.Build()
+	File_processors_grpcplugin_protos_processors_proto = out.File
+	file_processors_grpcplugin_protos_processors_proto_rawDesc = nil
+	file_processors_grpcplugin_protos_processors_proto_goTypes = nil
+	file_processors_grpcplugin_protos_processors_proto_depIdxs = nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcplugin
+
+import (
+	"context"
+
+	acontext "k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/grpcplugin/protos"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/nodes"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator"
+	klog "k8s.io/klog/v2"
+)
+
+type scaleDownSetProcessor struct {
+	client protos.ProcessorsClient
+}
+
+// NewScaleDownSetProcessor returns a processor letting the gRPC server select
+// and order the nodes to scale down.
+func NewScaleDownSetProcessor(client protos.ProcessorsClient) nodes.ScaleDownSetProcessor {
+	return &scaleDownSetProcessor{client: client}
+}
+
+// GetNodesToRemove returns the candidates selected by the gRPC server, in the
+// order it returned them. All candidates are returned if the call fails.
+func (p *scaleDownSetProcessor) GetNodesToRemove(ctx *acontext.AutoscalingContext, candidates []simulator.NodeToBeRemoved, maxCount int) []simulator.NodeToBeRemoved {
+	if len(candidates) == 0 {
+		return candidates
+	}
+	req := &protos.FilterNodesToRemoveRequest{MaxCount: int32(maxCount)}
+	candidatesByName := make(map[string]simulator.NodeToBeRemoved, len(candidates))
+	for _, candidate := range candidates {
+		candidatesByName[candidate.Node.Name] = candidate
+		nodeGroupId := ""
+		if nodeGroup, err := ctx.CloudProvider.NodeGroupForNode(candidate.Node); err == nil && nodeGroup != nil {
+			nodeGroupId = nodeGroup.Id()
+		}
+		req.Candidates = append(req.Candidates, &protos.NodeToRemove{
+			Node:             candidate.Node,
+			NodeGroupId:      nodeGroupId,
+			PodsToReschedule: candidate.PodsToReschedule,
+		})
+	}
+
+	callCtx, cancel := context.WithTimeout(context.Background(), gRPCTimeout)
+	defer cancel()
+	resp, err := p.client.FilterNodesToRemove(callCtx, req)
+	if err != nil {
+		logCallError("FilterNodesToRemove", err)
+		return candidates
+	}
+
+	var result []simulator.NodeToBeRemoved
+	for _, name := range resp.NodeNames {
+		candidate, found := candidatesByName[name]
+		if !found {
+			klog.Errorf("gRPC processors server returned node %s which is not a scale down candidate", name)
+			continue
+		}
+		delete(candidatesByName, name)
+		result = append(result, candidate)
+	}
+	if len(result) > maxCount {
+		result = result[:maxCount]
+	}
+	return result
+}
+
+// CleanUp cleans up the processor's internal structures.
+func (p *scaleDownSetProcessor) CleanUp() {
+}
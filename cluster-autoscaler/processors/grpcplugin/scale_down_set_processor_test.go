@@ -0,0 +1,70 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcplugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/grpcplugin/protos"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+)
+
+func TestScaleDownSetProcessor(t *testing.T) {
+	provider := testprovider.NewTestCloudProvider(nil, nil)
+	provider.AddNodeGroup("ng1", 0, 10, 3)
+	n1 := BuildTestNode("n1", 1000, 1000)
+	n2 := BuildTestNode("n2", 1000, 1000)
+	n3 := BuildTestNode("n3", 1000, 1000)
+	provider.AddNode("ng1", n1)
+	provider.AddNode("ng1", n2)
+	provider.AddNode("ng1", n3)
+	p1 := BuildTestPod("p1", 100, 0)
+	candidates := []simulator.NodeToBeRemoved{
+		{Node: n1, PodsToReschedule: []*apiv1.Pod{p1}},
+		{Node: n2},
+		{Node: n3},
+	}
+
+	var received *protos.FilterNodesToRemoveRequest
+	server := &fakeProcessorsServer{filterNodesToRemove: func(req *protos.FilterNodesToRemoveRequest) (*protos.FilterNodesToRemoveResponse, error) {
+		received = req
+		return &protos.FilterNodesToRemoveResponse{NodeNames: []string{"n3", "unknown", "n3", "n1", "n2"}}, nil
+	}}
+	processor := NewScaleDownSetProcessor(newTestClient(t, server))
+	result := processor.GetNodesToRemove(&context.AutoscalingContext{CloudProvider: provider}, candidates, 2)
+	assert.Equal(t, []simulator.NodeToBeRemoved{candidates[2], candidates[0]}, result)
+
+	assert.Equal(t, int32(2), received.MaxCount)
+	assert.Len(t, received.Candidates, 3)
+	assert.Equal(t, "n1", received.Candidates[0].Node.Name)
+	assert.Equal(t, "ng1", received.Candidates[0].NodeGroupId)
+	assert.Len(t, received.Candidates[0].PodsToReschedule, 1)
+	assert.Equal(t, "p1", received.Candidates[0].PodsToReschedule[0].Name)
+}
+
+func TestScaleDownSetProcessorNotImplemented(t *testing.T) {
+	provider := testprovider.NewTestCloudProvider(nil, nil)
+	candidates := []simulator.NodeToBeRemoved{{Node: BuildTestNode("n1", 1000, 1000)}}
+	processor := NewScaleDownSetProcessor(newTestClient(t, &fakeProcessorsServer{}))
+	result := processor.GetNodesToRemove(&context.AutoscalingContext{CloudProvider: provider}, candidates, 1)
+	assert.Equal(t, candidates, result)
+}
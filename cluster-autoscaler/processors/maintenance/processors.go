@@ -0,0 +1,133 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenance
+
+import (
+	"reflect"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/clusterstate/utils"
+	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/metrics"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/actionablecluster"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroups"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/nodes"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/errors"
+	"k8s.io/klog/v2"
+	schedulerframework "k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// ActionableClusterProcessor refreshes the maintenance windows every loop. It
+// aborts the loop while all actuation is frozen in the whole cluster, and
+// disables scale-down for the loop while scale-down is frozen in the whole
+// cluster. Otherwise it defers to the wrapped processor.
+type ActionableClusterProcessor struct {
+	actionablecluster.ActionableClusterProcessor
+	tracker *Tracker
+}
+
+// NewActionableClusterProcessor returns a new ActionableClusterProcessor
+// wrapping the given one.
+func NewActionableClusterProcessor(processor actionablecluster.ActionableClusterProcessor, tracker *Tracker) *ActionableClusterProcessor {
+	return &ActionableClusterProcessor{ActionableClusterProcessor: processor, tracker: tracker}
+}
+
+// ShouldAbort returns true if the wrapped processor aborts the loop or all
+// actuation is frozen.
+func (p *ActionableClusterProcessor) ShouldAbort(context *context.AutoscalingContext, allNodes []*apiv1.Node, readyNodes []*apiv1.Node, currentTime time.Time) (bool, errors.AutoscalerError) {
+	p.tracker.Refresh(context, currentTime)
+	if abort, err := p.ActionableClusterProcessor.ShouldAbort(context, allNodes, readyNodes, currentTime); abort {
+		return abort, err
+	}
+	if p.tracker.ClusterFrozen() {
+		klog.V(1).Info("Skipping the loop, all actuation is frozen by a maintenance window")
+		metrics.UpdateScaleDownInCooldown(true)
+		if context.WriteStatusConfigMap && context.ClusterStateRegistry != nil {
+			status := context.ClusterStateRegistry.GetStatus(currentTime)
+			status.MaintenanceWindows = p.tracker.Status()
+			utils.WriteStatusConfigMap(context.ClientSet, context.ConfigNamespace, *status, context.LogRecorder, context.StatusConfigMapName, currentTime)
+		}
+		return true, nil
+	}
+	if p.tracker.ClusterScaleDownFrozen() {
+		klog.V(1).Info("Scale-down is frozen by a maintenance window")
+		context.ProcessorCallbacks.DisableScaleDownForLoop()
+	}
+	return false, nil
+}
+
+// ScaleDownNodeProcessor filters out scale-down candidates of node groups
+// whose scale-down is frozen, so that neither the scale-down planner nor
+// node replacement removes them.
+type ScaleDownNodeProcessor struct {
+	nodes.ScaleDownNodeProcessor
+	tracker *Tracker
+}
+
+// NewScaleDownNodeProcessor returns a new ScaleDownNodeProcessor wrapping the
+// given one.
+func NewScaleDownNodeProcessor(processor nodes.ScaleDownNodeProcessor, tracker *Tracker) *ScaleDownNodeProcessor {
+	return &ScaleDownNodeProcessor{ScaleDownNodeProcessor: processor, tracker: tracker}
+}
+
+// GetScaleDownCandidates returns the candidates of the wrapped processor
+// which don't belong to frozen node groups.
+func (p *ScaleDownNodeProcessor) GetScaleDownCandidates(context *context.AutoscalingContext, allNodes []*apiv1.Node) ([]*apiv1.Node, errors.AutoscalerError) {
+	candidates, err := p.ScaleDownNodeProcessor.GetScaleDownCandidates(context, allNodes)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*apiv1.Node, 0, len(candidates))
+	for _, node := range candidates {
+		nodeGroup, err := context.CloudProvider.NodeGroupForNode(node)
+		if err == nil && nodeGroup != nil && !reflect.ValueOf(nodeGroup).IsNil() && p.tracker.ScaleDownFrozen(nodeGroup.Id()) {
+			klog.V(4).Infof("Skipping %s - scale-down of node group %s is frozen by a maintenance window", node.Name, nodeGroup.Id())
+			continue
+		}
+		result = append(result, node)
+	}
+	return result, nil
+}
+
+// NodeGroupListProcessor filters out node groups whose actuation is frozen
+// from scale-up.
+type NodeGroupListProcessor struct {
+	nodegroups.NodeGroupListProcessor
+	tracker *Tracker
+}
+
+// NewNodeGroupListProcessor returns a new NodeGroupListProcessor wrapping the
+// given one.
+func NewNodeGroupListProcessor(processor nodegroups.NodeGroupListProcessor, tracker *Tracker) *NodeGroupListProcessor {
+	return &NodeGroupListProcessor{NodeGroupListProcessor: processor, tracker: tracker}
+}
+
+// Process passes the node groups which aren't frozen to the wrapped processor.
+func (p *NodeGroupListProcessor) Process(context *context.AutoscalingContext, nodeGroups []cloudprovider.NodeGroup, nodeInfos map[string]*schedulerframework.NodeInfo,
+	unschedulablePods []*apiv1.Pod) ([]cloudprovider.NodeGroup, map[string]*schedulerframework.NodeInfo, error) {
+	result := make([]cloudprovider.NodeGroup, 0, len(nodeGroups))
+	for _, nodeGroup := range nodeGroups {
+		if p.tracker.ScaleUpFrozen(nodeGroup.Id()) {
+			klog.V(4).Infof("Skipping node group %s - scale-up is frozen by a maintenance window", nodeGroup.Id())
+			continue
+		}
+		result = append(result, nodeGroup)
+	}
+	return p.NodeGroupListProcessor.Process(context, result, nodeInfos, unschedulablePods)
+}
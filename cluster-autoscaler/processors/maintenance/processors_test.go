@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/actionablecluster"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/callbacks"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroups"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/nodes"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+)
+
+func TestShouldAbort(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		now                  time.Time
+		nodes                []*apiv1.Node
+		wantAbort            bool
+		wantScaleDownDisable bool
+	}{
+		{
+			name: "no active window",
+			now:  time.Date(2024, 6, 3, 5, 0, 0, 0, time.UTC),
+		},
+		{
+			name:                 "cluster-wide scale-down freeze",
+			now:                  time.Date(2024, 6, 3, 1, 30, 0, 0, time.UTC),
+			wantScaleDownDisable: true,
+		},
+		{
+			name: "node group freeze",
+			now:  time.Date(2024, 6, 3, 12, 30, 0, 0, time.UTC),
+		},
+		{
+			name:      "cluster-wide freeze of all actuation",
+			now:       time.Date(2024, 12, 24, 5, 0, 0, 0, time.UTC),
+			wantAbort: true,
+		},
+		{
+			name:      "wrapped processor aborts",
+			now:       time.Date(2024, 6, 3, 5, 0, 0, 0, time.UTC),
+			nodes:     []*apiv1.Node{},
+			wantAbort: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			context, _ := newTestContext(t)
+			allNodes := tc.nodes
+			if allNodes == nil {
+				allNodes = []*apiv1.Node{BuildTestNode("n1", 1000, 1000)}
+			}
+			processor := NewActionableClusterProcessor(actionablecluster.NewDefaultActionableClusterProcessor(), NewTracker(&fakeSource{data: []byte(testWindows)}))
+			abort, err := processor.ShouldAbort(context, allNodes, allNodes, tc.now)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantAbort, abort)
+			assert.Equal(t, tc.wantScaleDownDisable, context.ProcessorCallbacks.(*callbacks.TestProcessorCallbacks).ScaleDownDisabledForLoop)
+		})
+	}
+}
+
+func TestFrozenNodeGroups(t *testing.T) {
+	context, _ := newTestContext(t)
+	provider := testprovider.NewTestCloudProvider(nil, nil)
+	provider.AddNodeGroup("default", 0, 10, 1)
+	provider.AddNodeGroup("batch-pool", 0, 10, 1)
+	n1 := BuildTestNode("n1", 1000, 1000)
+	n2 := BuildTestNode("n2", 1000, 1000)
+	provider.AddNode("default", n1)
+	provider.AddNode("batch-pool", n2)
+	context.CloudProvider = provider
+
+	tracker := NewTracker(&fakeSource{data: []byte(testWindows)})
+	tracker.Refresh(context, time.Date(2024, 6, 3, 12, 30, 0, 0, time.UTC))
+
+	scaleDownProcessor := NewScaleDownNodeProcessor(nodes.NewPreFilteringScaleDownNodeProcessor(), tracker)
+	candidates, err := scaleDownProcessor.GetScaleDownCandidates(context, []*apiv1.Node{n1, n2})
+	assert.NoError(t, err)
+	assert.Equal(t, []*apiv1.Node{n1}, candidates)
+	destinations, err := scaleDownProcessor.GetPodDestinationCandidates(context, []*apiv1.Node{n1, n2})
+	assert.NoError(t, err)
+	assert.Equal(t, []*apiv1.Node{n1, n2}, destinations)
+
+	listProcessor := NewNodeGroupListProcessor(nodegroups.NewDefaultNodeGroupListProcessor(), tracker)
+	nodeGroups, _, listErr := listProcessor.Process(context, provider.NodeGroups(), nil, nil)
+	assert.NoError(t, listErr)
+	var ids []string
+	for _, nodeGroup := range nodeGroups {
+		ids = append(ids, nodeGroup.Id())
+	}
+	assert.Equal(t, []string{"default"}, ids)
+
+	// Scale-up isn't frozen by scale-down windows.
+	tracker.Refresh(context, time.Date(2024, 6, 3, 1, 30, 0, 0, time.UTC))
+	nodeGroups, _, listErr = listProcessor.Process(context, provider.NodeGroups(), nil, nil)
+	assert.NoError(t, listErr)
+	assert.Len(t, nodeGroups, 2)
+}
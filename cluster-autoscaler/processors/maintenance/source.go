@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenance
+
+import (
+	"fmt"
+	"os"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
+	kube_client "k8s.io/client-go/kubernetes"
+	v1lister "k8s.io/client-go/listers/core/v1"
+)
+
+// WindowsConfigMapKey is the ConfigMap key holding the maintenance windows.
+const WindowsConfigMapKey = "windows"
+
+// Source provides serialized maintenance windows.
+type Source interface {
+	// Load returns the current maintenance windows, or nil if there are none.
+	Load() ([]byte, error)
+}
+
+type fileSource struct {
+	path string
+}
+
+// NewFileSource returns a source reading maintenance windows from a file,
+// e.g. one mounted from a ConfigMap.
+func NewFileSource(path string) Source {
+	return &fileSource{path: path}
+}
+
+func (s *fileSource) Load() ([]byte, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read maintenance windows file %s: %v", s.path, err)
+	}
+	return data, nil
+}
+
+type configMapSource struct {
+	lister        v1lister.ConfigMapNamespaceLister
+	configMapName string
+}
+
+// NewConfigMapSource returns a source reading maintenance windows from the
+// WindowsConfigMapKey key of a ConfigMap.
+func NewConfigMapSource(lister v1lister.ConfigMapNamespaceLister, configMapName string) Source {
+	return &configMapSource{lister: lister, configMapName: configMapName}
+}
+
+func (s *configMapSource) Load() ([]byte, error) {
+	cm, err := s.lister.Get(s.configMapName)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get maintenance windows ConfigMap %s: %v", s.configMapName, err)
+	}
+	data, found := cm.Data[WindowsConfigMapKey]
+	if !found {
+		return nil, nil
+	}
+	return []byte(data), nil
+}
+
+// NewSource returns the source of maintenance windows configured by the
+// options: MaintenanceWindowsFile, or the MaintenanceWindowsConfigMapName
+// ConfigMap in ConfigNamespace. It returns nil if neither is set.
+func NewSource(opts config.AutoscalingOptions, kubeClient kube_client.Interface) Source {
+	if opts.MaintenanceWindowsFile != "" {
+		return NewFileSource(opts.MaintenanceWindowsFile)
+	}
+	if opts.MaintenanceWindowsConfigMapName != "" {
+		// The lister runs for the whole lifetime of the process, same as the
+		// one used by the priority expander.
+		stopChannel := make(chan struct{})
+		lister := kubernetes.NewConfigMapListerForNamespace(kubeClient, stopChannel, opts.ConfigNamespace)
+		return NewConfigMapSource(lister.ConfigMaps(opts.ConfigNamespace), opts.MaintenanceWindowsConfigMapName)
+	}
+	return nil
+}
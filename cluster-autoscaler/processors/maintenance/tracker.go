@@ -0,0 +1,193 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package maintenance suspends scale-down, or all actuation, during
+// maintenance windows, e.g. release freezes. Windows apply to the whole
+// cluster or to the node groups matching their patterns.
+package maintenance
+
+import (
+	"bytes"
+	"strings"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/clusterstate/api"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/metrics"
+	"k8s.io/klog/v2"
+)
+
+// Tracker keeps track of the maintenance windows read from a source and of
+// which of them are active. A nil Tracker has no active windows.
+type Tracker struct {
+	source   Source
+	loaded   bool
+	lastData []byte
+	windows  []config.MaintenanceWindow
+	// active maps the names of the active windows to the time they started.
+	active map[string]time.Time
+}
+
+// NewTracker returns a new Tracker of the maintenance windows read from the
+// source.
+func NewTracker(source Source) *Tracker {
+	return &Tracker{source: source, active: map[string]time.Time{}}
+}
+
+// Refresh reloads the windows if the source changed and updates the set of
+// active windows. Events are emitted when a window starts or ends. An invalid
+// source is reported once, the previous windows are kept until it changes.
+func (t *Tracker) Refresh(context *context.AutoscalingContext, now time.Time) {
+	t.reload()
+
+	active := map[string]time.Time{}
+	counts := map[config.Freeze]int{config.FreezeScaleDown: 0, config.FreezeAll: 0}
+	for i := range t.windows {
+		window := &t.windows[i]
+		if !window.Active(now) {
+			continue
+		}
+		since, found := t.active[window.Name]
+		if !found {
+			since = now
+			klog.Infof("Maintenance window %s started, %s frozen for %s", window.Name, window.Freeze, scope(window))
+			context.LogRecorder.Eventf(apiv1.EventTypeNormal, "MaintenanceWindowStarted", "Maintenance window %s started, %s frozen for %s", window.Name, window.Freeze, scope(window))
+		}
+		active[window.Name] = since
+		counts[window.Freeze]++
+	}
+	for name := range t.active {
+		if _, found := active[name]; !found {
+			klog.Infof("Maintenance window %s ended", name)
+			context.LogRecorder.Eventf(apiv1.EventTypeNormal, "MaintenanceWindowEnded", "Maintenance window %s ended", name)
+		}
+	}
+	t.active = active
+	for freeze, count := range counts {
+		metrics.UpdateMaintenanceWindowsActive(string(freeze), count)
+	}
+}
+
+func (t *Tracker) reload() {
+	data, err := t.source.Load()
+	if err != nil {
+		klog.Errorf("Failed to load maintenance windows: %v", err)
+		return
+	}
+	if t.loaded && bytes.Equal(data, t.lastData) {
+		return
+	}
+	t.loaded = true
+	t.lastData = data
+	if data == nil {
+		t.windows = nil
+		return
+	}
+	windows, err := config.ParseMaintenanceWindows(data)
+	if err != nil {
+		klog.Errorf("Keeping the previous maintenance windows: %v", err)
+		return
+	}
+	t.windows = windows.Windows
+}
+
+func scope(window *config.MaintenanceWindow) string {
+	if window.ClusterWide() {
+		return "the whole cluster"
+	}
+	return "node groups " + strings.Join(window.NodeGroups, ", ")
+}
+
+func (t *Tracker) activeWindows() []*config.MaintenanceWindow {
+	if t == nil {
+		return nil
+	}
+	var result []*config.MaintenanceWindow
+	for i := range t.windows {
+		if _, found := t.active[t.windows[i].Name]; found {
+			result = append(result, &t.windows[i])
+		}
+	}
+	return result
+}
+
+// ClusterFrozen returns true if an active window suspends all actuation in
+// the whole cluster.
+func (t *Tracker) ClusterFrozen() bool {
+	for _, window := range t.activeWindows() {
+		if window.ClusterWide() && window.Freeze == config.FreezeAll {
+			return true
+		}
+	}
+	return false
+}
+
+// ClusterScaleDownFrozen returns true if an active window suspends
+// scale-down in the whole cluster.
+func (t *Tracker) ClusterScaleDownFrozen() bool {
+	for _, window := range t.activeWindows() {
+		if window.ClusterWide() {
+			return true
+		}
+	}
+	return false
+}
+
+// ScaleDownFrozen returns true if an active window suspends scale-down of
+// the node group.
+func (t *Tracker) ScaleDownFrozen(nodeGroupId string) bool {
+	for _, window := range t.activeWindows() {
+		if window.AppliesTo(nodeGroupId) {
+			return true
+		}
+	}
+	return false
+}
+
+// ScaleUpFrozen returns true if an active window suspends scale-up of the
+// node group. Only windows freezing all actuation suspend scale-up.
+func (t *Tracker) ScaleUpFrozen(nodeGroupId string) bool {
+	return t.Frozen(nodeGroupId)
+}
+
+// Frozen returns true if an active window suspends all actuation of the node
+// group, including the removal of unregistered or failed nodes and target
+// size fixes.
+func (t *Tracker) Frozen(nodeGroupId string) bool {
+	for _, window := range t.activeWindows() {
+		if window.Freeze == config.FreezeAll && window.AppliesTo(nodeGroupId) {
+			return true
+		}
+	}
+	return false
+}
+
+// Status returns the active windows, to be reported in the status ConfigMap.
+func (t *Tracker) Status() []api.MaintenanceWindowStatus {
+	var result []api.MaintenanceWindowStatus
+	for _, window := range t.activeWindows() {
+		result = append(result, api.MaintenanceWindowStatus{
+			Name:       window.Name,
+			Freeze:     string(window.Freeze),
+			NodeGroups: window.NodeGroups,
+			Since:      metav1.NewTime(t.active[window.Name]),
+		})
+	}
+	return result
+}
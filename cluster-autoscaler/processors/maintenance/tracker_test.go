@@ -0,0 +1,165 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maintenance
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/clusterstate/utils"
+	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/callbacks"
+	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	kube_record "k8s.io/client-go/tools/record"
+)
+
+const testWindows = `
+windows:
+  - name: release-freeze
+    start: "2024-12-20T00:00:00Z"
+    end: "2025-01-06T00:00:00Z"
+    freeze: All
+  - name: nightly
+    schedule: "daily 01:00-03:00"
+  - name: batch
+    schedule: "daily 12:00-13:00"
+    nodeGroups: ["^batch-"]
+    freeze: All
+`
+
+type fakeSource struct {
+	data []byte
+}
+
+func (s *fakeSource) Load() ([]byte, error) {
+	return s.data, nil
+}
+
+func newTestContext(t *testing.T) (*context.AutoscalingContext, *kube_record.FakeRecorder) {
+	recorder := kube_record.NewFakeRecorder(10)
+	logRecorder, err := utils.NewStatusMapRecorder(fake.NewSimpleClientset(), "kube-system", recorder, true, "cluster-autoscaler-status")
+	assert.NoError(t, err)
+	return &context.AutoscalingContext{
+		AutoscalingKubeClients: context.AutoscalingKubeClients{
+			Recorder:    recorder,
+			LogRecorder: logRecorder,
+		},
+		ProcessorCallbacks: callbacks.NewTestProcessorCallbacks(),
+	}, recorder
+}
+
+func events(recorder *kube_record.FakeRecorder) []string {
+	var result []string
+	for {
+		select {
+		case event := <-recorder.Events:
+			result = append(result, strings.Fields(event)[1])
+		default:
+			return result
+		}
+	}
+}
+
+func TestTrackerRefresh(t *testing.T) {
+	context, recorder := newTestContext(t)
+	source := &fakeSource{data: []byte(testWindows)}
+	tracker := NewTracker(source)
+
+	nightStart := time.Date(2024, 6, 3, 1, 30, 0, 0, time.UTC)
+	tracker.Refresh(context, nightStart)
+	assert.Equal(t, []string{"MaintenanceWindowStarted"}, events(recorder))
+	assert.False(t, tracker.ClusterFrozen())
+	assert.True(t, tracker.ClusterScaleDownFrozen())
+	assert.True(t, tracker.ScaleDownFrozen("default"))
+	assert.False(t, tracker.ScaleUpFrozen("default"))
+	assert.Equal(t, 1, len(tracker.Status()))
+
+	// No events while the window stays active.
+	tracker.Refresh(context, nightStart.Add(time.Hour))
+	assert.Empty(t, events(recorder))
+	assert.Equal(t, metav1.NewTime(nightStart), tracker.Status()[0].Since)
+
+	tracker.Refresh(context, nightStart.Add(2*time.Hour))
+	assert.Equal(t, []string{"MaintenanceWindowEnded"}, events(recorder))
+	assert.False(t, tracker.ClusterScaleDownFrozen())
+	assert.Empty(t, tracker.Status())
+
+	noon := time.Date(2024, 6, 3, 12, 30, 0, 0, time.UTC)
+	tracker.Refresh(context, noon)
+	assert.Equal(t, []string{"MaintenanceWindowStarted"}, events(recorder))
+	assert.False(t, tracker.ClusterFrozen())
+	assert.False(t, tracker.ClusterScaleDownFrozen())
+	assert.True(t, tracker.ScaleDownFrozen("batch-pool"))
+	assert.True(t, tracker.ScaleUpFrozen("batch-pool"))
+	assert.False(t, tracker.ScaleDownFrozen("default"))
+
+	tracker.Refresh(context, time.Date(2024, 12, 24, 12, 30, 0, 0, time.UTC))
+	events(recorder)
+	assert.True(t, tracker.ClusterFrozen())
+	assert.True(t, tracker.ScaleUpFrozen("default"))
+	assert.Equal(t, []string{"release-freeze", "batch"}, statusNames(tracker))
+}
+
+func TestTrackerReload(t *testing.T) {
+	context, recorder := newTestContext(t)
+	source := &fakeSource{data: []byte(testWindows)}
+	tracker := NewTracker(source)
+	now := time.Date(2024, 6, 3, 1, 30, 0, 0, time.UTC)
+	tracker.Refresh(context, now)
+	assert.Equal(t, []string{"nightly"}, statusNames(tracker))
+
+	// Invalid windows are ignored, the previous ones are kept.
+	source.data = []byte("windows:\n  - name: broken")
+	tracker.Refresh(context, now)
+	assert.Equal(t, []string{"nightly"}, statusNames(tracker))
+
+	// Removing a window ends it.
+	events(recorder)
+	source.data = nil
+	tracker.Refresh(context, now)
+	assert.Empty(t, statusNames(tracker))
+	assert.Equal(t, []string{"MaintenanceWindowEnded"}, events(recorder))
+}
+
+func statusNames(tracker *Tracker) []string {
+	var names []string
+	for _, window := range tracker.Status() {
+		names = append(names, window.Name)
+	}
+	return names
+}
+
+func TestConfigMapSource(t *testing.T) {
+	lister, err := kube_util.NewTestConfigMapLister([]*apiv1.ConfigMap{{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "windows"},
+		Data:       map[string]string{WindowsConfigMapKey: testWindows},
+	}})
+	assert.NoError(t, err)
+
+	data, err := NewConfigMapSource(lister.ConfigMaps("kube-system"), "windows").Load()
+	assert.NoError(t, err)
+	assert.Equal(t, testWindows, string(data))
+
+	data, err = NewConfigMapSource(lister.ConfigMaps("kube-system"), "missing").Load()
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+}
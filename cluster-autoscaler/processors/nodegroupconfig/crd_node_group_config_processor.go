@@ -0,0 +1,381 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodegroupconfig
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/autoscaler/cluster-autoscaler/apis/nodegroupautoscalingconfig/autoscaling.x-k8s.io/v1alpha1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
+	klog "k8s.io/klog/v2"
+)
+
+// NodeGroupAutoscalingConfigClient gives access to NodeGroupAutoscalingConfig objects.
+type NodeGroupAutoscalingConfigClient interface {
+	// NodeGroupAutoscalingConfigs returns all NodeGroupAutoscalingConfig objects.
+	NodeGroupAutoscalingConfigs() ([]*v1alpha1.NodeGroupAutoscalingConfig, error)
+	// UpdateNodeGroupAutoscalingConfigStatus updates the status of the given object.
+	UpdateNodeGroupAutoscalingConfigStatus(config *v1alpha1.NodeGroupAutoscalingConfig) error
+}
+
+// CRDNodeGroupConfigProcessor provides config values set by NodeGroupAutoscalingConfig
+// objects selecting a NodeGroup. Values which are not set by any of them are taken
+// from the wrapped processor. Node selectors are matched against the labels of the
+// NodeGroup template node or, if the cloud provider can't build it, of the NodeGroup
+// nodes registered in the cluster. Objects are read once per loop, so Refresh has to be
+// called at the start of every loop; it also updates the status of every object
+// with the node groups it applied to in the previous loop, unless in dry-run mode.
+type CRDNodeGroupConfigProcessor struct {
+	fallback   NodeGroupConfigProcessor
+	client     NodeGroupAutoscalingConfigClient
+	nodeLister kube_util.NodeLister
+	dryRun     bool
+
+	mutex      sync.Mutex
+	loaded     bool
+	configs    []*nodeGroupConfig
+	resolved   map[string]*resolvedOptions
+	nodeLabels map[string]labels.Set
+}
+
+// nodeGroupConfig is a NodeGroupAutoscalingConfig parsed for matching node groups.
+type nodeGroupConfig struct {
+	object           *v1alpha1.NodeGroupAutoscalingConfig
+	nodeGroups       sets.Set[string]
+	selector         labels.Selector
+	minSizeSchedules []config.MinSizeSchedule
+	err              error
+	matched          sets.Set[string]
+}
+
+// resolvedOptions are the options of a node group merged from all matching objects.
+type resolvedOptions struct {
+	v1alpha1.NodeGroupAutoscalingOptions
+	minSizeSchedules []config.MinSizeSchedule
+}
+
+// NewCRDNodeGroupConfigProcessor returns a NodeGroupConfigProcessor consulting
+// NodeGroupAutoscalingConfig objects before the fallback processor. In dry-run
+// mode, the statuses are left to the Cluster Autoscaler which actually scales
+// the cluster.
+func NewCRDNodeGroupConfigProcessor(client NodeGroupAutoscalingConfigClient, nodeLister kube_util.NodeLister, fallback NodeGroupConfigProcessor, dryRun bool) *CRDNodeGroupConfigProcessor {
+	return &CRDNodeGroupConfigProcessor{
+		fallback:   fallback,
+		client:     client,
+		nodeLister: nodeLister,
+		dryRun:     dryRun,
+	}
+}
+
+// GetScaleDownUnneededTime returns ScaleDownUnneededTime value that should be used for a given NodeGroup.
+func (p *CRDNodeGroupConfigProcessor) GetScaleDownUnneededTime(nodeGroup cloudprovider.NodeGroup) (time.Duration, error) {
+	if options := p.options(nodeGroup); options.ScaleDownUnneededTime != nil {
+		return options.ScaleDownUnneededTime.Duration, nil
+	}
+	return p.fallback.GetScaleDownUnneededTime(nodeGroup)
+}
+
+// GetScaleDownUnreadyTime returns ScaleDownUnreadyTime value that should be used for a given NodeGroup.
+func (p *CRDNodeGroupConfigProcessor) GetScaleDownUnreadyTime(nodeGroup cloudprovider.NodeGroup) (time.Duration, error) {
+	if options := p.options(nodeGroup); options.ScaleDownUnreadyTime != nil {
+		return options.ScaleDownUnreadyTime.Duration, nil
+	}
+	return p.fallback.GetScaleDownUnreadyTime(nodeGroup)
+}
+
+// GetScaleDownUtilizationThreshold returns ScaleDownUtilizationThreshold value that should be used for a given NodeGroup.
+func (p *CRDNodeGroupConfigProcessor) GetScaleDownUtilizationThreshold(nodeGroup cloudprovider.NodeGroup) (float64, error) {
+	if options := p.options(nodeGroup); options.ScaleDownUtilizationThreshold != nil {
+		return *options.ScaleDownUtilizationThreshold, nil
+	}
+	return p.fallback.GetScaleDownUtilizationThreshold(nodeGroup)
+}
+
+// GetScaleDownGpuUtilizationThreshold returns ScaleDownGpuUtilizationThreshold value that should be used for a given NodeGroup.
+func (p *CRDNodeGroupConfigProcessor) GetScaleDownGpuUtilizationThreshold(nodeGroup cloudprovider.NodeGroup) (float64, error) {
+	if options := p.options(nodeGroup); options.ScaleDownGpuUtilizationThreshold != nil {
+		return *options.ScaleDownGpuUtilizationThreshold, nil
+	}
+	return p.fallback.GetScaleDownGpuUtilizationThreshold(nodeGroup)
+}
+
+// GetMaxNodeProvisionTime returns MaxNodeProvisionTime value that should be used for a given NodeGroup.
+func (p *CRDNodeGroupConfigProcessor) GetMaxNodeProvisionTime(nodeGroup cloudprovider.NodeGroup) (time.Duration, error) {
+	if options := p.options(nodeGroup); options.MaxNodeProvisionTime != nil {
+		return options.MaxNodeProvisionTime.Duration, nil
+	}
+	return p.fallback.GetMaxNodeProvisionTime(nodeGroup)
+}
+
+// GetIgnoreDaemonSetsUtilization returns IgnoreDaemonSetsUtilization value that should be used for a given NodeGroup.
+func (p *CRDNodeGroupConfigProcessor) GetIgnoreDaemonSetsUtilization(nodeGroup cloudprovider.NodeGroup) (bool, error) {
+	if options := p.options(nodeGroup); options.IgnoreDaemonSetsUtilization != nil {
+		return *options.IgnoreDaemonSetsUtilization, nil
+	}
+	return p.fallback.GetIgnoreDaemonSetsUtilization(nodeGroup)
+}
+
+// GetMinSize returns the min size that should be used for a given NodeGroup at a given time.
+func (p *CRDNodeGroupConfigProcessor) GetMinSize(nodeGroup cloudprovider.NodeGroup, now time.Time) (int, error) {
+	if options := p.options(nodeGroup); options.MinSizeSchedules != nil {
+		return scheduledMinSize(nodeGroup, options.minSizeSchedules, now), nil
+	}
+	return p.fallback.GetMinSize(nodeGroup, now)
+}
+
+// GetZeroOrMaxNodeScaling returns ZeroOrMaxNodeScaling value that should be used for a given NodeGroup.
+func (p *CRDNodeGroupConfigProcessor) GetZeroOrMaxNodeScaling(nodeGroup cloudprovider.NodeGroup) (bool, error) {
+	if options := p.options(nodeGroup); options.ZeroOrMaxNodeScaling != nil {
+		return *options.ZeroOrMaxNodeScaling, nil
+	}
+	return p.fallback.GetZeroOrMaxNodeScaling(nodeGroup)
+}
+
+// CleanUp cleans up processor's internal structures.
+func (p *CRDNodeGroupConfigProcessor) CleanUp() {
+	p.fallback.CleanUp()
+}
+
+// Refresh updates the statuses of the objects read in the previous loop and
+// makes the processor read the objects again.
+func (p *CRDNodeGroupConfigProcessor) Refresh() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.loaded && !p.dryRun {
+		for _, c := range p.configs {
+			p.updateStatus(c)
+		}
+	}
+	p.loaded = false
+	p.configs = nil
+	p.resolved = nil
+	p.nodeLabels = nil
+}
+
+// options returns the options set for the node group by the objects selecting it.
+func (p *CRDNodeGroupConfigProcessor) options(nodeGroup cloudprovider.NodeGroup) *resolvedOptions {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.load()
+	if options, found := p.resolved[nodeGroup.Id()]; found {
+		return options
+	}
+	options := &resolvedOptions{}
+	var nodeLabels labels.Set
+	labelsChecked := false
+	for _, c := range p.configs {
+		if c.err != nil {
+			continue
+		}
+		if !c.nodeGroups.Has(nodeGroup.Id()) {
+			if c.selector == nil {
+				continue
+			}
+			if !c.selector.Empty() && !labelsChecked {
+				nodeLabels = p.nodeGroupLabels(nodeGroup)
+				labelsChecked = true
+			}
+			if !c.selector.Matches(nodeLabels) {
+				continue
+			}
+		}
+		c.matched.Insert(nodeGroup.Id())
+		options.merge(c)
+	}
+	p.resolved[nodeGroup.Id()] = options
+	return options
+}
+
+// load reads the objects, unless they were already read in this loop.
+func (p *CRDNodeGroupConfigProcessor) load() {
+	if p.loaded {
+		return
+	}
+	p.loaded = true
+	p.resolved = map[string]*resolvedOptions{}
+	objects, err := p.client.NodeGroupAutoscalingConfigs()
+	if err != nil {
+		klog.Errorf("Failed to list NodeGroupAutoscalingConfigs, using default node group options: %v", err)
+		return
+	}
+	for _, object := range objects {
+		p.configs = append(p.configs, parseNodeGroupConfig(object))
+	}
+	sort.Slice(p.configs, func(i, j int) bool {
+		if p.configs[i].object.Spec.Priority != p.configs[j].object.Spec.Priority {
+			return p.configs[i].object.Spec.Priority > p.configs[j].object.Spec.Priority
+		}
+		return p.configs[i].object.Name < p.configs[j].object.Name
+	})
+}
+
+func parseNodeGroupConfig(object *v1alpha1.NodeGroupAutoscalingConfig) *nodeGroupConfig {
+	c := &nodeGroupConfig{
+		object:     object,
+		nodeGroups: sets.New(object.Spec.NodeGroups...),
+		matched:    sets.New[string](),
+	}
+	if object.Spec.NodeSelector != nil {
+		c.selector, c.err = metav1.LabelSelectorAsSelector(object.Spec.NodeSelector)
+		if c.err != nil {
+			c.err = fmt.Errorf("invalid node selector: %v", c.err)
+			return c
+		}
+	}
+	options := object.Spec.Options
+	for name, threshold := range map[string]*float64{
+		"scaleDownUtilizationThreshold":    options.ScaleDownUtilizationThreshold,
+		"scaleDownGpuUtilizationThreshold": options.ScaleDownGpuUtilizationThreshold,
+	} {
+		if threshold != nil && (*threshold < 0 || *threshold > 1) {
+			c.err = fmt.Errorf("%s must be between 0 and 1, got %v", name, *threshold)
+			return c
+		}
+	}
+	for name, duration := range map[string]*metav1.Duration{
+		"scaleDownUnneededTime": options.ScaleDownUnneededTime,
+		"scaleDownUnreadyTime":  options.ScaleDownUnreadyTime,
+		"maxNodeProvisionTime":  options.MaxNodeProvisionTime,
+	} {
+		if duration != nil && duration.Duration < 0 {
+			c.err = fmt.Errorf("%s can't be negative, got %v", name, duration.Duration)
+			return c
+		}
+	}
+	for _, value := range options.MinSizeSchedules {
+		schedule, err := config.ParseMinSizeSchedule(value)
+		if err != nil {
+			c.err = err
+			return c
+		}
+		c.minSizeSchedules = append(c.minSizeSchedules, schedule)
+	}
+	return c
+}
+
+// merge sets the options of the config which are not set yet.
+func (o *resolvedOptions) merge(c *nodeGroupConfig) {
+	options := c.object.Spec.Options
+	if o.ScaleDownUtilizationThreshold == nil {
+		o.ScaleDownUtilizationThreshold = options.ScaleDownUtilizationThreshold
+	}
+	if o.ScaleDownGpuUtilizationThreshold == nil {
+		o.ScaleDownGpuUtilizationThreshold = options.ScaleDownGpuUtilizationThreshold
+	}
+	if o.ScaleDownUnneededTime == nil {
+		o.ScaleDownUnneededTime = options.ScaleDownUnneededTime
+	}
+	if o.ScaleDownUnreadyTime == nil {
+		o.ScaleDownUnreadyTime = options.ScaleDownUnreadyTime
+	}
+	if o.MaxNodeProvisionTime == nil {
+		o.MaxNodeProvisionTime = options.MaxNodeProvisionTime
+	}
+	if o.IgnoreDaemonSetsUtilization == nil {
+		o.IgnoreDaemonSetsUtilization = options.IgnoreDaemonSetsUtilization
+	}
+	if o.ZeroOrMaxNodeScaling == nil {
+		o.ZeroOrMaxNodeScaling = options.ZeroOrMaxNodeScaling
+	}
+	if o.MinSizeSchedules == nil && options.MinSizeSchedules != nil {
+		o.MinSizeSchedules = options.MinSizeSchedules
+		o.minSizeSchedules = c.minSizeSchedules
+	}
+}
+
+// nodeGroupLabels returns labels of the node group template node or, if the
+// cloud provider can't build it, of the first node group node registered in
+// the cluster. Returns nil if neither is available.
+func (p *CRDNodeGroupConfigProcessor) nodeGroupLabels(nodeGroup cloudprovider.NodeGroup) labels.Set {
+	nodeInfo, err := nodeGroup.TemplateNodeInfo()
+	if err == nil {
+		return nodeInfo.Node().Labels
+	}
+	klog.V(4).Infof("Failed to get template of node group %s, matching NodeGroupAutoscalingConfig node selectors against its nodes: %v", nodeGroup.Id(), err)
+	instances, err := nodeGroup.Nodes()
+	if err != nil {
+		klog.V(4).Infof("Can't match node group %s against NodeGroupAutoscalingConfig node selectors, failed to get its nodes: %v", nodeGroup.Id(), err)
+		return nil
+	}
+	if p.nodeLabels == nil {
+		p.nodeLabels = p.listNodeLabels()
+	}
+	for _, instance := range instances {
+		if nodeLabels, found := p.nodeLabels[instance.Id]; found {
+			return nodeLabels
+		}
+	}
+	return nil
+}
+
+// listNodeLabels returns labels of the nodes registered in the cluster by their provider ids.
+func (p *CRDNodeGroupConfigProcessor) listNodeLabels() map[string]labels.Set {
+	result := map[string]labels.Set{}
+	if p.nodeLister == nil {
+		return result
+	}
+	nodes, err := p.nodeLister.List()
+	if err != nil {
+		klog.Errorf("Failed to list nodes, can't match node groups without templates against NodeGroupAutoscalingConfig node selectors: %v", err)
+		return result
+	}
+	for _, node := range nodes {
+		result[node.Spec.ProviderID] = node.Labels
+	}
+	return result
+}
+
+// updateStatus updates the object status if it doesn't reflect the last loop.
+func (p *CRDNodeGroupConfigProcessor) updateStatus(c *nodeGroupConfig) {
+	condition := metav1.Condition{
+		Type:               v1alpha1.Accepted,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: c.object.Generation,
+		Reason:             v1alpha1.AcceptedReason,
+		Message:            "Options are applied to the selected node groups",
+	}
+	if c.err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = v1alpha1.InvalidSpecReason
+		condition.Message = c.err.Error()
+	}
+	nodeGroups := sets.List(c.matched)
+	status := &c.object.Status
+	current := meta.FindStatusCondition(status.Conditions, v1alpha1.Accepted)
+	if status.ObservedGeneration == c.object.Generation && sets.New(status.NodeGroups...).Equal(c.matched) &&
+		current != nil && current.Status == condition.Status && current.Message == condition.Message && current.ObservedGeneration == condition.ObservedGeneration {
+		return
+	}
+	object := c.object.DeepCopy()
+	object.Status.ObservedGeneration = object.Generation
+	object.Status.NodeGroups = nodeGroups
+	meta.SetStatusCondition(&object.Status.Conditions, condition)
+	if err := p.client.UpdateNodeGroupAutoscalingConfigStatus(object); err != nil {
+		klog.Errorf("Failed to update status of NodeGroupAutoscalingConfig %s: %v", object.Name, err)
+	}
+}
+
+var _ NodeGroupConfigProcessor = &CRDNodeGroupConfigProcessor{}
@@ -0,0 +1,210 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodegroupconfig
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/apis/nodegroupautoscalingconfig/autoscaling.x-k8s.io/v1alpha1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/mocks"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+	schedulerframework "k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+type fakeConfigClient struct {
+	configs []*v1alpha1.NodeGroupAutoscalingConfig
+	err     error
+	updated map[string]*v1alpha1.NodeGroupAutoscalingConfig
+}
+
+func (c *fakeConfigClient) NodeGroupAutoscalingConfigs() ([]*v1alpha1.NodeGroupAutoscalingConfig, error) {
+	return c.configs, c.err
+}
+
+func (c *fakeConfigClient) UpdateNodeGroupAutoscalingConfigStatus(config *v1alpha1.NodeGroupAutoscalingConfig) error {
+	c.updated[config.Name] = config
+	return nil
+}
+
+func buildTestNodeGroupConfig(name string, priority int32, nodeGroups []string, selector *metav1.LabelSelector, options v1alpha1.NodeGroupAutoscalingOptions) *v1alpha1.NodeGroupAutoscalingConfig {
+	return &v1alpha1.NodeGroupAutoscalingConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Generation: 1},
+		Spec: v1alpha1.NodeGroupAutoscalingConfigSpec{
+			NodeGroups:   nodeGroups,
+			NodeSelector: selector,
+			Priority:     priority,
+			Options:      options,
+		},
+	}
+}
+
+func buildTestConfigNodeGroup(id string, nodeLabels map[string]string) *mocks.NodeGroup {
+	ng := &mocks.NodeGroup{}
+	ng.On("Id").Return(id)
+	ng.On("GetOptions", config.NodeGroupAutoscalingOptions{ScaleDownUnneededTime: 10 * time.Minute, ScaleDownUnreadyTime: 20 * time.Minute}).Return(nil, cloudprovider.ErrNotImplemented)
+	ng.On("MinSize").Return(1)
+	ng.On("MaxSize").Return(10)
+	if nodeLabels == nil {
+		ng.On("TemplateNodeInfo").Return(nil, cloudprovider.ErrNotImplemented)
+		ng.On("Nodes").Return([]cloudprovider.Instance{{Id: "provider://" + id}}, nil)
+		return ng
+	}
+	node := BuildTestNode(id, 1000, 1000)
+	node.Labels = nodeLabels
+	nodeInfo := schedulerframework.NewNodeInfo()
+	nodeInfo.SetNode(node)
+	ng.On("TemplateNodeInfo").Return(nodeInfo, nil)
+	return ng
+}
+
+func TestCRDNodeGroupConfigProcessor(t *testing.T) {
+	// Monday, 10:00 UTC.
+	now := time.Date(2024, time.January, 8, 10, 0, 0, 0, time.UTC)
+	threshold := 0.3
+	ignoreDaemonSets := true
+	zeroOrMax := true
+	client := &fakeConfigClient{
+		configs: []*v1alpha1.NodeGroupAutoscalingConfig{
+			buildTestNodeGroupConfig("by-id", 0, []string{"ng1", "ng3"}, nil, v1alpha1.NodeGroupAutoscalingOptions{
+				ScaleDownUnneededTime:         &metav1.Duration{Duration: time.Minute},
+				ScaleDownUtilizationThreshold: &threshold,
+			}),
+			buildTestNodeGroupConfig("by-label", 10, nil, &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "gpu"}}, v1alpha1.NodeGroupAutoscalingOptions{
+				ScaleDownUnneededTime:       &metav1.Duration{Duration: 2 * time.Minute},
+				IgnoreDaemonSetsUtilization: &ignoreDaemonSets,
+				MinSizeSchedules:            []string{"mon 08:00-18:00 min=4"},
+				ZeroOrMaxNodeScaling:        &zeroOrMax,
+			}),
+			buildTestNodeGroupConfig("invalid", 20, nil, &metav1.LabelSelector{}, v1alpha1.NodeGroupAutoscalingOptions{
+				MinSizeSchedules: []string{"sometimes"},
+			}),
+		},
+		updated: map[string]*v1alpha1.NodeGroupAutoscalingConfig{},
+	}
+	defaults := config.NodeGroupAutoscalingOptions{ScaleDownUnneededTime: 10 * time.Minute, ScaleDownUnreadyTime: 20 * time.Minute}
+	gpuNode := BuildTestNode("ng4-node", 1000, 1000)
+	gpuNode.Spec.ProviderID = "provider://ng4"
+	gpuNode.Labels = map[string]string{"pool": "gpu"}
+	nodeLister := kube_util.NewTestNodeLister([]*apiv1.Node{gpuNode})
+	p := NewCRDNodeGroupConfigProcessor(client, nodeLister, NewDefaultNodeGroupConfigProcessor(defaults), false)
+	p.Refresh()
+
+	gpuGroup := buildTestConfigNodeGroup("ng1", map[string]string{"pool": "gpu"})
+	cpuGroup := buildTestConfigNodeGroup("ng2", map[string]string{"pool": "cpu"})
+	noTemplateGroup := buildTestConfigNodeGroup("ng3", nil)
+	noTemplateGpuGroup := buildTestConfigNodeGroup("ng4", nil)
+
+	// The higher priority object wins, other options are merged from lower priority objects
+	// and the defaults.
+	unneededTime, err := p.GetScaleDownUnneededTime(gpuGroup)
+	assert.NoError(t, err)
+	assert.Equal(t, 2*time.Minute, unneededTime)
+	utilizationThreshold, err := p.GetScaleDownUtilizationThreshold(gpuGroup)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.3, utilizationThreshold)
+	ignore, err := p.GetIgnoreDaemonSetsUtilization(gpuGroup)
+	assert.NoError(t, err)
+	assert.True(t, ignore)
+	unreadyTime, err := p.GetScaleDownUnreadyTime(gpuGroup)
+	assert.NoError(t, err)
+	assert.Equal(t, 20*time.Minute, unreadyTime)
+	minSize, err := p.GetMinSize(gpuGroup, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, minSize)
+	zeroOrMaxNodeScaling, err := p.GetZeroOrMaxNodeScaling(gpuGroup)
+	assert.NoError(t, err)
+	assert.True(t, zeroOrMaxNodeScaling)
+
+	// Not selected, only defaults apply.
+	unneededTime, err = p.GetScaleDownUnneededTime(cpuGroup)
+	assert.NoError(t, err)
+	assert.Equal(t, 10*time.Minute, unneededTime)
+	minSize, err = p.GetMinSize(cpuGroup, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, minSize)
+	zeroOrMaxNodeScaling, err = p.GetZeroOrMaxNodeScaling(cpuGroup)
+	assert.NoError(t, err)
+	assert.False(t, zeroOrMaxNodeScaling)
+
+	// Selected by id even without a template.
+	unneededTime, err = p.GetScaleDownUnneededTime(noTemplateGroup)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, unneededTime)
+
+	// Selected by labels of the existing nodes without a template.
+	unneededTime, err = p.GetScaleDownUnneededTime(noTemplateGpuGroup)
+	assert.NoError(t, err)
+	assert.Equal(t, 2*time.Minute, unneededTime)
+
+	// Statuses list the node groups each object applied to.
+	p.Refresh()
+	assert.Equal(t, []string{"ng1", "ng3"}, client.updated["by-id"].Status.NodeGroups)
+	assert.Equal(t, []string{"ng1", "ng4"}, client.updated["by-label"].Status.NodeGroups)
+	assert.True(t, meta.IsStatusConditionTrue(client.updated["by-label"].Status.Conditions, v1alpha1.Accepted))
+	assert.Empty(t, client.updated["invalid"].Status.NodeGroups)
+	assert.True(t, meta.IsStatusConditionFalse(client.updated["invalid"].Status.Conditions, v1alpha1.Accepted))
+
+	// Changes are picked up in the next loop, unchanged statuses aren't updated.
+	client.configs = []*v1alpha1.NodeGroupAutoscalingConfig{client.updated["by-id"]}
+	client.updated = map[string]*v1alpha1.NodeGroupAutoscalingConfig{}
+	unneededTime, err = p.GetScaleDownUnneededTime(gpuGroup)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, unneededTime)
+	unneededTime, err = p.GetScaleDownUnneededTime(noTemplateGroup)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, unneededTime)
+	p.Refresh()
+	assert.Empty(t, client.updated)
+}
+
+func TestCRDNodeGroupConfigProcessorListError(t *testing.T) {
+	client := &fakeConfigClient{err: errors.New("no CRD"), updated: map[string]*v1alpha1.NodeGroupAutoscalingConfig{}}
+	defaults := config.NodeGroupAutoscalingOptions{ScaleDownUnneededTime: 10 * time.Minute, ScaleDownUnreadyTime: 20 * time.Minute}
+	p := NewCRDNodeGroupConfigProcessor(client, nil, NewDefaultNodeGroupConfigProcessor(defaults), false)
+	unneededTime, err := p.GetScaleDownUnneededTime(buildTestConfigNodeGroup("ng1", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 10*time.Minute, unneededTime)
+	p.Refresh()
+	assert.Empty(t, client.updated)
+}
+
+func TestCRDNodeGroupConfigProcessorDryRun(t *testing.T) {
+	client := &fakeConfigClient{
+		configs: []*v1alpha1.NodeGroupAutoscalingConfig{
+			buildTestNodeGroupConfig("by-id", 0, []string{"ng1"}, nil, v1alpha1.NodeGroupAutoscalingOptions{
+				ScaleDownUnneededTime: &metav1.Duration{Duration: time.Minute},
+			}),
+		},
+		updated: map[string]*v1alpha1.NodeGroupAutoscalingConfig{},
+	}
+	defaults := config.NodeGroupAutoscalingOptions{ScaleDownUnneededTime: 10 * time.Minute, ScaleDownUnreadyTime: 20 * time.Minute}
+	p := NewCRDNodeGroupConfigProcessor(client, nil, NewDefaultNodeGroupConfigProcessor(defaults), true)
+	unneededTime, err := p.GetScaleDownUnneededTime(buildTestConfigNodeGroup("ng1", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, unneededTime)
+	p.Refresh()
+	assert.Empty(t, client.updated)
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crdclient
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/autoscaler/cluster-autoscaler/apis/nodegroupautoscalingconfig/autoscaling.x-k8s.io/v1alpha1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	clientCallTimeout = 4 * time.Second
+)
+
+// NodeGroupAutoscalingConfigClient lists NodeGroupAutoscalingConfig objects
+// from an informer cache and updates their status.
+type NodeGroupAutoscalingConfigClient struct {
+	client dynamic.NamespaceableResourceInterface
+	lister cache.GenericLister
+}
+
+// NewNodeGroupAutoscalingConfigClient configures and returns a NodeGroupAutoscalingConfigClient.
+func NewNodeGroupAutoscalingConfigClient(kubeConfig *rest.Config) (*NodeGroupAutoscalingConfigClient, error) {
+	dynamicClient, err := dynamic.NewForConfig(kubeConfig)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create NodeGroupAutoscalingConfig client: %v", err)
+	}
+	return newClient(dynamicClient, make(chan struct{}))
+}
+
+func newClient(dynamicClient dynamic.Interface, stopChannel <-chan struct{}) (*NodeGroupAutoscalingConfigClient, error) {
+	factory := dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, 1*time.Hour)
+	lister := factory.ForResource(v1alpha1.SchemeGroupVersionResource).Lister()
+	factory.Start(stopChannel)
+	informersSynced := factory.WaitForCacheSync(stopChannel)
+	for _, synced := range informersSynced {
+		if !synced {
+			return nil, fmt.Errorf("can't create NodeGroupAutoscalingConfig lister")
+		}
+	}
+	klog.V(2).Info("Successful initial NodeGroupAutoscalingConfig sync")
+	return &NodeGroupAutoscalingConfigClient{
+		client: dynamicClient.Resource(v1alpha1.SchemeGroupVersionResource),
+		lister: lister,
+	}, nil
+}
+
+// NodeGroupAutoscalingConfigs returns all NodeGroupAutoscalingConfig objects.
+func (c *NodeGroupAutoscalingConfigClient) NodeGroupAutoscalingConfigs() ([]*v1alpha1.NodeGroupAutoscalingConfig, error) {
+	objects, err := c.lister.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("error fetching NodeGroupAutoscalingConfigs: %w", err)
+	}
+	configs := make([]*v1alpha1.NodeGroupAutoscalingConfig, 0, len(objects))
+	for _, object := range objects {
+		u, ok := object.(*unstructured.Unstructured)
+		if !ok {
+			return nil, fmt.Errorf("unexpected NodeGroupAutoscalingConfig object type %T", object)
+		}
+		config := &v1alpha1.NodeGroupAutoscalingConfig{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), config); err != nil {
+			klog.Errorf("Ignoring malformed NodeGroupAutoscalingConfig %s: %v", u.GetName(), err)
+			continue
+		}
+		configs = append(configs, config)
+	}
+	return configs, nil
+}
+
+// UpdateNodeGroupAutoscalingConfigStatus updates the status of the given NodeGroupAutoscalingConfig.
+func (c *NodeGroupAutoscalingConfigClient) UpdateNodeGroupAutoscalingConfigStatus(config *v1alpha1.NodeGroupAutoscalingConfig) error {
+	ctx, cancel := context.WithTimeout(context.Background(), clientCallTimeout)
+	defer cancel()
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(config)
+	if err != nil {
+		return err
+	}
+	u := &unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(v1alpha1.SchemeGroupVersion.WithKind("NodeGroupAutoscalingConfig"))
+	_, err = c.client.UpdateStatus(ctx, u, metav1.UpdateOptions{})
+	return err
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crdclient
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/autoscaler/cluster-autoscaler/apis/nodegroupautoscalingconfig/autoscaling.x-k8s.io/v1alpha1"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+func TestNodeGroupAutoscalingConfigClient(t *testing.T) {
+	object := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "autoscaling.x-k8s.io/v1alpha1",
+		"kind":       "NodeGroupAutoscalingConfig",
+		"metadata": map[string]interface{}{
+			"name": "gpu",
+		},
+		"spec": map[string]interface{}{
+			"nodeGroups": []interface{}{"ng1"},
+			"priority":   int64(5),
+			"options": map[string]interface{}{
+				"scaleDownUnneededTime":         "5m",
+				"scaleDownUtilizationThreshold": 0.25,
+			},
+		},
+	}}
+	dynamicClient := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		v1alpha1.SchemeGroupVersionResource: "NodeGroupAutoscalingConfigList",
+	}, object)
+	client, err := newClient(dynamicClient, make(chan struct{}))
+	assert.NoError(t, err)
+
+	configs, err := client.NodeGroupAutoscalingConfigs()
+	assert.NoError(t, err)
+	if assert.Len(t, configs, 1) {
+		config := configs[0]
+		assert.Equal(t, "gpu", config.Name)
+		assert.Equal(t, []string{"ng1"}, config.Spec.NodeGroups)
+		assert.Equal(t, int32(5), config.Spec.Priority)
+		assert.Equal(t, "5m0s", config.Spec.Options.ScaleDownUnneededTime.Duration.String())
+		assert.Equal(t, 0.25, *config.Spec.Options.ScaleDownUtilizationThreshold)
+
+		config.Status.NodeGroups = []string{"ng1"}
+		assert.NoError(t, client.UpdateNodeGroupAutoscalingConfigStatus(config))
+		updated, err := dynamicClient.Resource(v1alpha1.SchemeGroupVersionResource).Get(context.Background(), "gpu", metav1.GetOptions{})
+		assert.NoError(t, err)
+		nodeGroups, _, _ := unstructured.NestedStringSlice(updated.Object, "status", "nodeGroups")
+		assert.Equal(t, []string{"ng1"}, nodeGroups)
+	}
+}
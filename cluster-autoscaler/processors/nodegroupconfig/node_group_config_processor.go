@@ -37,6 +37,11 @@ type NodeGroupConfigProcessor interface {
 	GetMaxNodeProvisionTime(nodeGroup cloudprovider.NodeGroup) (time.Duration, error)
 	// GetIgnoreDaemonSetsUtilization returns IgnoreDaemonSetsUtilization value that should be used for a given NodeGroup.
 	GetIgnoreDaemonSetsUtilization(nodeGroup cloudprovider.NodeGroup) (bool, error)
+	// GetMinSize returns the min size that should be used for a given NodeGroup at a given time,
+	// taking into account the MinSizeSchedules active at that time.
+	GetMinSize(nodeGroup cloudprovider.NodeGroup, now time.Time) (int, error)
+	// GetZeroOrMaxNodeScaling returns ZeroOrMaxNodeScaling value that should be used for a given NodeGroup.
+	GetZeroOrMaxNodeScaling(nodeGroup cloudprovider.NodeGroup) (bool, error)
 	// CleanUp cleans up processor's internal structures.
 	CleanUp()
 }
@@ -120,6 +125,45 @@ func (p *DelegatingNodeGroupConfigProcessor) GetIgnoreDaemonSetsUtilization(node
 	return ngConfig.IgnoreDaemonSetsUtilization, nil
 }
 
+// GetZeroOrMaxNodeScaling returns ZeroOrMaxNodeScaling value that should be used for a given NodeGroup.
+func (p *DelegatingNodeGroupConfigProcessor) GetZeroOrMaxNodeScaling(nodeGroup cloudprovider.NodeGroup) (bool, error) {
+	ngConfig, err := nodeGroup.GetOptions(p.nodeGroupDefaults)
+	if err != nil && err != cloudprovider.ErrNotImplemented {
+		return false, err
+	}
+	if ngConfig == nil || err == cloudprovider.ErrNotImplemented {
+		return p.nodeGroupDefaults.ZeroOrMaxNodeScaling, nil
+	}
+	return ngConfig.ZeroOrMaxNodeScaling, nil
+}
+
+// GetMinSize returns the min size that should be used for a given NodeGroup at a given time.
+// Active MinSizeSchedules can only raise the min size, up to the max size of the NodeGroup.
+func (p *DelegatingNodeGroupConfigProcessor) GetMinSize(nodeGroup cloudprovider.NodeGroup, now time.Time) (int, error) {
+	ngConfig, err := nodeGroup.GetOptions(p.nodeGroupDefaults)
+	if err != nil && err != cloudprovider.ErrNotImplemented {
+		return 0, err
+	}
+	schedules := p.nodeGroupDefaults.MinSizeSchedules
+	if ngConfig != nil && err != cloudprovider.ErrNotImplemented {
+		schedules = ngConfig.MinSizeSchedules
+	}
+	return scheduledMinSize(nodeGroup, schedules, now), nil
+}
+
+// scheduledMinSize returns the min size of the node group raised by the
+// schedules active at the given time, up to the max size of the node group.
+func scheduledMinSize(nodeGroup cloudprovider.NodeGroup, schedules []config.MinSizeSchedule, now time.Time) int {
+	minSize := nodeGroup.MinSize()
+	if scheduledMinSize, active := config.ScheduledMinSize(schedules, now); active && scheduledMinSize > minSize {
+		minSize = scheduledMinSize
+	}
+	if maxSize := nodeGroup.MaxSize(); minSize > maxSize {
+		minSize = maxSize
+	}
+	return minSize
+}
+
 // CleanUp cleans up processor's internal structures.
 func (p *DelegatingNodeGroupConfigProcessor) CleanUp() {
 }
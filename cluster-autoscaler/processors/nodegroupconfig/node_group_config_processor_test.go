@@ -48,6 +48,7 @@ func TestDelegatingNodeGroupConfigProcessor(t *testing.T) {
 		ScaleDownUtilizationThreshold:    0.5,
 		MaxNodeProvisionTime:             15 * time.Minute,
 		IgnoreDaemonSetsUtilization:      true,
+		ZeroOrMaxNodeScaling:             true,
 	}
 	ngOpts := &config.NodeGroupAutoscalingOptions{
 		ScaleDownUnneededTime:            10 * time.Minute,
@@ -56,6 +57,7 @@ func TestDelegatingNodeGroupConfigProcessor(t *testing.T) {
 		ScaleDownUtilizationThreshold:    0.75,
 		MaxNodeProvisionTime:             60 * time.Minute,
 		IgnoreDaemonSetsUtilization:      false,
+		ZeroOrMaxNodeScaling:             false,
 	}
 
 	testUnneededTime := func(t *testing.T, p NodeGroupConfigProcessor, ng cloudprovider.NodeGroup, w Want, we error) {
@@ -121,6 +123,17 @@ func TestDelegatingNodeGroupConfigProcessor(t *testing.T) {
 		assert.Equal(t, res, results[w])
 	}
 
+	testZeroOrMaxNodeScaling := func(t *testing.T, p NodeGroupConfigProcessor, ng cloudprovider.NodeGroup, w Want, we error) {
+		res, err := p.GetZeroOrMaxNodeScaling(ng)
+		assert.Equal(t, err, we)
+		results := map[Want]bool{
+			NIL:    false,
+			GLOBAL: true,
+			NG:     false,
+		}
+		assert.Equal(t, res, results[w])
+	}
+
 	funcs := map[string]func(*testing.T, NodeGroupConfigProcessor, cloudprovider.NodeGroup, Want, error){
 		"ScaleDownUnneededTime":            testUnneededTime,
 		"ScaleDownUnreadyTime":             testUnreadyTime,
@@ -128,6 +141,7 @@ func TestDelegatingNodeGroupConfigProcessor(t *testing.T) {
 		"ScaleDownGpuUtilizationThreshold": testGpuThreshold,
 		"MaxNodeProvisionTime":             testMaxNodeProvisionTime,
 		"IgnoreDaemonSetsUtilization":      testIgnoreDSUtilization,
+		"ZeroOrMaxNodeScaling":             testZeroOrMaxNodeScaling,
 		"MultipleOptions": func(t *testing.T, p NodeGroupConfigProcessor, ng cloudprovider.NodeGroup, w Want, we error) {
 			testUnneededTime(t, p, ng, w, we)
 			testUnreadyTime(t, p, ng, w, we)
@@ -135,6 +149,7 @@ func TestDelegatingNodeGroupConfigProcessor(t *testing.T) {
 			testGpuThreshold(t, p, ng, w, we)
 			testMaxNodeProvisionTime(t, p, ng, w, we)
 			testIgnoreDSUtilization(t, p, ng, w, we)
+			testZeroOrMaxNodeScaling(t, p, ng, w, we)
 		},
 		"RepeatingTheSameCallGivesConsistentResults": func(t *testing.T, p NodeGroupConfigProcessor, ng cloudprovider.NodeGroup, w Want, we error) {
 			testUnneededTime(t, p, ng, w, we)
@@ -186,3 +201,58 @@ func TestDelegatingNodeGroupConfigProcessor(t *testing.T) {
 		}
 	}
 }
+
+func TestDelegatingNodeGroupConfigProcessorGetMinSize(t *testing.T) {
+	// Monday, 10:00 UTC.
+	now := time.Date(2024, time.January, 8, 10, 0, 0, 0, time.UTC)
+	active := config.MinSizeSchedule{Days: []time.Weekday{time.Monday}, Start: 8 * time.Hour, End: 18 * time.Hour, MinSize: 5}
+	inactive := config.MinSizeSchedule{Days: []time.Weekday{time.Tuesday}, Start: 8 * time.Hour, End: 18 * time.Hour, MinSize: 7}
+	testCases := map[string]struct {
+		globalOptions config.NodeGroupAutoscalingOptions
+		ngOptions     *config.NodeGroupAutoscalingOptions
+		ngError       error
+		maxSize       int
+		want          int
+		wantError     error
+	}{
+		"no schedules": {
+			ngError: cloudprovider.ErrNotImplemented,
+			maxSize: 10,
+			want:    1,
+		},
+		"global schedule active": {
+			globalOptions: config.NodeGroupAutoscalingOptions{MinSizeSchedules: []config.MinSizeSchedule{active, inactive}},
+			ngError:       cloudprovider.ErrNotImplemented,
+			maxSize:       10,
+			want:          5,
+		},
+		"node group schedule overrides global": {
+			globalOptions: config.NodeGroupAutoscalingOptions{MinSizeSchedules: []config.MinSizeSchedule{active}},
+			ngOptions:     &config.NodeGroupAutoscalingOptions{MinSizeSchedules: []config.MinSizeSchedule{inactive}},
+			maxSize:       10,
+			want:          1,
+		},
+		"capped at max size": {
+			ngOptions: &config.NodeGroupAutoscalingOptions{MinSizeSchedules: []config.MinSizeSchedule{active}},
+			maxSize:   3,
+			want:      3,
+		},
+		"error": {
+			ngError:   errors.New("This sentence is false."),
+			maxSize:   10,
+			wantError: errors.New("This sentence is false."),
+		},
+	}
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			ng := &mocks.NodeGroup{}
+			ng.On("GetOptions", tc.globalOptions).Return(tc.ngOptions, tc.ngError)
+			ng.On("MinSize").Return(1)
+			ng.On("MaxSize").Return(tc.maxSize)
+			p := NewDefaultNodeGroupConfigProcessor(tc.globalOptions)
+			minSize, err := p.GetMinSize(ng, now)
+			assert.Equal(t, tc.wantError, err)
+			assert.Equal(t, tc.want, minSize)
+		})
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/config"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/scheduler"
 	klog "k8s.io/klog/v2"
+	v1helper "k8s.io/kubernetes/pkg/apis/core/v1/helper"
 	schedulerframework "k8s.io/kubernetes/pkg/scheduler/framework"
 )
 
@@ -108,11 +109,18 @@ func IsCloudProviderNodeInfoSimilar(
 	free := make(map[apiv1.ResourceName][]resource.Quantity)
 	nodes := []*schedulerframework.NodeInfo{n1, n2}
 	for _, node := range nodes {
+		// Attachable volume limits aren't compared, templates built from
+		// existing nodes get them from CSINodes while other templates don't
+		// have them at all.
 		for res, quantity := range node.Node().Status.Capacity {
-			capacity[res] = append(capacity[res], quantity)
+			if !v1helper.IsAttachableVolumeResourceName(res) {
+				capacity[res] = append(capacity[res], quantity)
+			}
 		}
 		for res, quantity := range node.Node().Status.Allocatable {
-			allocatable[res] = append(allocatable[res], quantity)
+			if !v1helper.IsAttachableVolumeResourceName(res) {
+				allocatable[res] = append(allocatable[res], quantity)
+			}
 		}
 		for res, quantity := range scheduler.ResourceToResourceList(node.Requested) {
 			freeRes := node.Node().Status.Allocatable[res].DeepCopy()
@@ -159,6 +159,21 @@ func TestNodesSimilarVariousLargeMemoryRequirementsM516XLarge(t *testing.T) {
 	checkNodesSimilar(t, n1, n3, comparator, false)
 }
 
+func TestNodesSimilarVariousVolumeLimits(t *testing.T) {
+	comparator := CreateGenericNodeInfoComparator([]string{}, config.NewDefaultNodeGroupDifferenceRatios())
+	n1 := BuildTestNode("node1", 1000, 2000)
+	n1.Status.Allocatable["attachable-volumes-csi-ebs.csi.aws.com"] = *resource.NewQuantity(25, resource.DecimalSI)
+
+	// Volume limits missing on one of the nodes
+	n2 := BuildTestNode("node2", 1000, 2000)
+	checkNodesSimilar(t, n1, n2, comparator, true)
+
+	// Different volume limits
+	n2.Status.Allocatable["attachable-volumes-csi-ebs.csi.aws.com"] = *resource.NewQuantity(39, resource.DecimalSI)
+	n2.Status.Capacity["attachable-volumes-csi-ebs.csi.aws.com"] = *resource.NewQuantity(39, resource.DecimalSI)
+	checkNodesSimilar(t, n1, n2, comparator, true)
+}
+
 func TestNodesSimilarVariousLabels(t *testing.T) {
 	comparator := CreateGenericNodeInfoComparator([]string{"example.com/ready"}, config.NewDefaultNodeGroupDifferenceRatios())
 	n1 := BuildTestNode("node1", 1000, 2000)
@@ -52,6 +52,15 @@ type NodeGroupSetProcessor interface {
 	CleanUp()
 }
 
+// EstimatedScaleUpProcessor is an optional interface of a NodeGroupSetProcessor
+// adjusting scale-ups which the estimator already split between the node group
+// and its similar node groups. Such scale-ups are executed without calling
+// BalanceScaleUpBetweenGroups, so processors which don't implement it can't
+// change them.
+type EstimatedScaleUpProcessor interface {
+	ProcessEstimatedScaleUps(context *context.AutoscalingContext, groups []cloudprovider.NodeGroup, scaleUpInfos []ScaleUpInfo) ([]ScaleUpInfo, errors.AutoscalerError)
+}
+
 // NoOpNodeGroupSetProcessor returns no similar node groups and doesn't do any balancing.
 type NoOpNodeGroupSetProcessor struct {
 }
@@ -33,9 +33,9 @@ type AnnotationNodeInfoProvider struct {
 }
 
 // NewAnnotationNodeInfoProvider returns AnnotationNodeInfoProvider wrapping MixedTemplateNodeInfoProvider.
-func NewAnnotationNodeInfoProvider(t *time.Duration, forceDaemonSets bool) *AnnotationNodeInfoProvider {
+func NewAnnotationNodeInfoProvider(t *time.Duration, forceDaemonSets bool, opts ...MixedTemplateNodeInfoProviderOption) *AnnotationNodeInfoProvider {
 	return &AnnotationNodeInfoProvider{
-		templateNodeInfoProvider: NewMixedTemplateNodeInfoProvider(t, forceDaemonSets),
+		templateNodeInfoProvider: NewMixedTemplateNodeInfoProvider(t, forceDaemonSets, opts...),
 	}
 }
 
@@ -33,9 +33,9 @@ type AsgTagResourceNodeInfoProvider struct {
 }
 
 // NewAsgTagResourceNodeInfoProvider returns AsgTagResourceNodeInfoProvider.
-func NewAsgTagResourceNodeInfoProvider(t *time.Duration, forceDaemonSets bool) *AsgTagResourceNodeInfoProvider {
+func NewAsgTagResourceNodeInfoProvider(t *time.Duration, forceDaemonSets bool, opts ...MixedTemplateNodeInfoProviderOption) *AsgTagResourceNodeInfoProvider {
 	return &AsgTagResourceNodeInfoProvider{
-		mixedTemplateNodeInfoProvider: NewMixedTemplateNodeInfoProvider(t, forceDaemonSets),
+		mixedTemplateNodeInfoProvider: NewMixedTemplateNodeInfoProvider(t, forceDaemonSets, opts...),
 	}
 }
 
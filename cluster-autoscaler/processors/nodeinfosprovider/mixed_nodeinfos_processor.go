@@ -22,14 +22,20 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	apiv1 "k8s.io/api/core/v1"
+	resourceapi "k8s.io/api/resource/v1alpha2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
 	"k8s.io/autoscaler/cluster-autoscaler/context"
 	"k8s.io/autoscaler/cluster-autoscaler/core/utils"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/dynamicresources"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/errors"
 	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/taints"
+	storagelisters "k8s.io/client-go/listers/storage/v1"
 	schedulerframework "k8s.io/kubernetes/pkg/scheduler/framework"
+	volumeutil "k8s.io/kubernetes/pkg/volume/util"
 
 	klog "k8s.io/klog/v2"
 )
@@ -39,7 +45,8 @@ const maxCacheExpireTime = 87660 * time.Hour
 
 type cacheItem struct {
 	*schedulerframework.NodeInfo
-	added time.Time
+	resourceSlices []*resourceapi.ResourceSlice
+	added          time.Time
 }
 
 // MixedTemplateNodeInfoProvider build nodeInfos from the cluster's nodes and node groups.
@@ -47,20 +54,36 @@ type MixedTemplateNodeInfoProvider struct {
 	nodeInfoCache   map[string]cacheItem
 	ttl             time.Duration
 	forceDaemonSets bool
+	csiNodeLister   storagelisters.CSINodeLister
+}
+
+// MixedTemplateNodeInfoProviderOption configures optional behavior of MixedTemplateNodeInfoProvider.
+type MixedTemplateNodeInfoProviderOption func(*MixedTemplateNodeInfoProvider)
+
+// WithCSINodeLister makes MixedTemplateNodeInfoProvider set attachable volume limits
+// of the CSI drivers installed on real-world nodes on templates built from them.
+func WithCSINodeLister(csiNodeLister storagelisters.CSINodeLister) MixedTemplateNodeInfoProviderOption {
+	return func(p *MixedTemplateNodeInfoProvider) {
+		p.csiNodeLister = csiNodeLister
+	}
 }
 
 // NewMixedTemplateNodeInfoProvider returns a NodeInfoProvider processor building
 // NodeInfos from real-world nodes when available, otherwise from node groups templates.
-func NewMixedTemplateNodeInfoProvider(t *time.Duration, forceDaemonSets bool) *MixedTemplateNodeInfoProvider {
+func NewMixedTemplateNodeInfoProvider(t *time.Duration, forceDaemonSets bool, opts ...MixedTemplateNodeInfoProviderOption) *MixedTemplateNodeInfoProvider {
 	ttl := maxCacheExpireTime
 	if t != nil {
 		ttl = *t
 	}
-	return &MixedTemplateNodeInfoProvider{
+	p := &MixedTemplateNodeInfoProvider{
 		nodeInfoCache:   make(map[string]cacheItem),
 		ttl:             ttl,
 		forceDaemonSets: forceDaemonSets,
 	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
 }
 
 func (p *MixedTemplateNodeInfoProvider) isCacheItemExpired(added time.Time) bool {
@@ -82,6 +105,7 @@ func (p *MixedTemplateNodeInfoProvider) Process(ctx *context.AutoscalingContext,
 	if err != nil {
 		return map[string]*schedulerframework.NodeInfo{}, err
 	}
+	dynamicResources := dynamicResourcesSnapshot(ctx)
 
 	// processNode returns information whether the nodeTemplate was generated and if there was an error.
 	processNode := func(node *apiv1.Node) (bool, string, errors.AutoscalerError) {
@@ -99,6 +123,9 @@ func (p *MixedTemplateNodeInfoProvider) Process(ctx *context.AutoscalingContext,
 			if err != nil {
 				return false, "", err
 			}
+			if err := p.setCSIVolumeLimits(sanitizedNode, node.Name); err != nil {
+				return false, "", err
+			}
 			nodeInfo, err := simulator.BuildNodeInfoForNode(sanitizedNode, podsForNodes[node.Name], daemonsets, p.forceDaemonSets)
 			if err != nil {
 				return false, "", err
@@ -111,6 +138,7 @@ func (p *MixedTemplateNodeInfoProvider) Process(ctx *context.AutoscalingContext,
 
 			sanitizedNodeInfo := schedulerframework.NewNodeInfo(utils.SanitizePods(pods, sanitizedNode)...)
 			sanitizedNodeInfo.SetNode(sanitizedNode)
+			dynamicResources.CopyResourceSlices(node.Name, sanitizedNode.Name)
 			result[id] = sanitizedNodeInfo
 			return true, id, nil
 		}
@@ -128,7 +156,8 @@ func (p *MixedTemplateNodeInfoProvider) Process(ctx *context.AutoscalingContext,
 		}
 		if added && p.nodeInfoCache != nil {
 			nodeInfoCopy := utils.DeepCopyNodeInfo(result[id])
-			p.nodeInfoCache[id] = cacheItem{NodeInfo: nodeInfoCopy, added: time.Now()}
+			resourceSlices := dynamicResources.ResourceSlices(nodeInfoCopy.Node().Name)
+			p.nodeInfoCache[id] = cacheItem{NodeInfo: nodeInfoCopy, resourceSlices: resourceSlices, added: time.Now()}
 		}
 	}
 	for _, nodeGroup := range ctx.CloudProvider.NodeGroups() {
@@ -145,6 +174,7 @@ func (p *MixedTemplateNodeInfoProvider) Process(ctx *context.AutoscalingContext,
 					delete(p.nodeInfoCache, id)
 				} else {
 					result[id] = utils.DeepCopyNodeInfo(cacheItem.NodeInfo)
+					dynamicResources.SetResourceSlices(cacheItem.Node().Name, cacheItem.resourceSlices)
 					continue
 				}
 			}
@@ -194,6 +224,48 @@ func (p *MixedTemplateNodeInfoProvider) Process(ctx *context.AutoscalingContext,
 	return result, nil
 }
 
+// setCSIVolumeLimits sets attachable volume limits from the CSINode of a node
+// as allocatable resources of the template node built from it. Nodes created
+// from the template don't have CSINodes in simulations, the scheduler reads
+// their limits from the resources instead. Limits already set on the node are
+// kept.
+func (p *MixedTemplateNodeInfoProvider) setCSIVolumeLimits(templateNode *apiv1.Node, nodeName string) errors.AutoscalerError {
+	if p.csiNodeLister == nil {
+		return nil
+	}
+	csiNode, err := p.csiNodeLister.Get(nodeName)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.ToAutoscalerError(errors.ApiCallError, err)
+	}
+	for _, driver := range csiNode.Spec.Drivers {
+		if driver.Allocatable == nil || driver.Allocatable.Count == nil {
+			continue
+		}
+		limitKey := apiv1.ResourceName(volumeutil.GetCSIAttachLimitKey(driver.Name))
+		if _, found := templateNode.Status.Allocatable[limitKey]; found {
+			continue
+		}
+		if templateNode.Status.Allocatable == nil {
+			templateNode.Status.Allocatable = apiv1.ResourceList{}
+		}
+		templateNode.Status.Allocatable[limitKey] = *resource.NewQuantity(int64(*driver.Allocatable.Count), resource.DecimalSI)
+	}
+	return nil
+}
+
+// dynamicResourcesSnapshot returns the Dynamic Resource Allocation state of the
+// cluster snapshot, which template nodes get the ResourceSlices of the nodes
+// they're built from in. It's nil if DRA isn't simulated.
+func dynamicResourcesSnapshot(ctx *context.AutoscalingContext) *dynamicresources.Snapshot {
+	if ctx.ClusterSnapshot == nil {
+		return nil
+	}
+	return ctx.ClusterSnapshot.DynamicResources()
+}
+
 func getPodsForNodes(listers kube_util.ListerRegistry) (map[string][]*apiv1.Pod, errors.AutoscalerError) {
 	pods, err := listers.AllPodLister().List()
 	if err != nil {
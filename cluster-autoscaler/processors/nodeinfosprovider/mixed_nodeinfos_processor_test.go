@@ -30,6 +30,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	appsv1 "k8s.io/api/apps/v1"
 	apiv1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	storagelisters "k8s.io/client-go/listers/storage/v1"
+	"k8s.io/client-go/tools/cache"
 	schedulerframework "k8s.io/kubernetes/pkg/scheduler/framework"
 )
 
@@ -290,6 +295,67 @@ func TestGetNodeInfosCacheExpired(t *testing.T) {
 
 }
 
+func TestGetNodeInfosCSIVolumeLimits(t *testing.T) {
+	now := time.Now()
+	ready1 := BuildTestNode("n1", 1000, 1000)
+	SetNodeReadyState(ready1, true, now.Add(-2*time.Minute))
+	ready2 := BuildTestNode("n2", 1000, 1000)
+	SetNodeReadyState(ready2, true, now.Add(-2*time.Minute))
+	ready2.Status.Allocatable["attachable-volumes-csi-ebs.csi.aws.com"] = *resource.NewQuantity(39, resource.DecimalSI)
+	ready3 := BuildTestNode("n3", 1000, 1000)
+	SetNodeReadyState(ready3, true, now.Add(-2*time.Minute))
+
+	provider := testprovider.NewTestCloudProvider(nil, nil)
+	provider.AddNodeGroup("ng1", 1, 10, 1)
+	provider.AddNode("ng1", ready1)
+	provider.AddNodeGroup("ng2", 1, 10, 1)
+	provider.AddNode("ng2", ready2)
+	provider.AddNodeGroup("ng3", 1, 10, 1)
+	provider.AddNode("ng3", ready3)
+
+	limit := int32(25)
+	csiNodes := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, name := range []string{"n1", "n2"} {
+		assert.NoError(t, csiNodes.Add(&storagev1.CSINode{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: storagev1.CSINodeSpec{
+				Drivers: []storagev1.CSINodeDriver{
+					{Name: "ebs.csi.aws.com", Allocatable: &storagev1.VolumeNodeResources{Count: &limit}},
+					{Name: "efs.csi.aws.com"},
+				},
+			},
+		}))
+	}
+
+	podLister := kube_util.NewTestPodLister([]*apiv1.Pod{})
+	registry := kube_util.NewListerRegistry(nil, nil, podLister, nil, nil, nil, nil, nil, nil)
+	ctx := context.AutoscalingContext{
+		CloudProvider: provider,
+		AutoscalingKubeClients: context.AutoscalingKubeClients{
+			ListerRegistry: registry,
+		},
+	}
+	niProcessor := NewMixedTemplateNodeInfoProvider(&cacheTtl, false, WithCSINodeLister(storagelisters.NewCSINodeLister(csiNodes)))
+	res, err := niProcessor.Process(&ctx, []*apiv1.Node{ready1, ready2, ready3}, []*appsv1.DaemonSet{}, taints.TaintConfig{}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(res))
+
+	// Limits are set from the CSINode of the node.
+	allocatable := res["ng1"].Node().Status.Allocatable
+	limitQuantity := allocatable["attachable-volumes-csi-ebs.csi.aws.com"]
+	assert.Equal(t, int64(25), limitQuantity.Value())
+	_, found := allocatable["attachable-volumes-csi-efs.csi.aws.com"]
+	assert.False(t, found)
+	_, found = ready1.Status.Allocatable["attachable-volumes-csi-ebs.csi.aws.com"]
+	assert.False(t, found)
+	// Limits set on the node are kept.
+	limitQuantity = res["ng2"].Node().Status.Allocatable["attachable-volumes-csi-ebs.csi.aws.com"]
+	assert.Equal(t, int64(39), limitQuantity.Value())
+	// Nodes without CSINodes don't have limits.
+	_, found = res["ng3"].Node().Status.Allocatable["attachable-volumes-csi-ebs.csi.aws.com"]
+	assert.False(t, found)
+}
+
 func assertEqualNodeCapacities(t *testing.T, expected, actual *apiv1.Node) {
 	t.Helper()
 	assert.NotEqual(t, actual.Status, nil, "")
@@ -37,6 +37,6 @@ type TemplateNodeInfoProvider interface {
 }
 
 // NewDefaultTemplateNodeInfoProvider returns a default TemplateNodeInfoProvider.
-func NewDefaultTemplateNodeInfoProvider(time *time.Duration, forceDaemonSets bool) TemplateNodeInfoProvider {
-	return NewMixedTemplateNodeInfoProvider(time, forceDaemonSets)
+func NewDefaultTemplateNodeInfoProvider(time *time.Duration, forceDaemonSets bool, opts ...MixedTemplateNodeInfoProviderOption) TemplateNodeInfoProvider {
+	return NewMixedTemplateNodeInfoProvider(time, forceDaemonSets, opts...)
 }
@@ -80,6 +80,14 @@ func NewMaxNodesProcessor() *MaxNodesProcessor {
 // Otherwise, it's possible that another processor will break the property that this processor aims to restore:
 // no partial scale-downs for node groups that should be resized atomically.
 type AtomicResizeFilteringProcessor struct {
+	configGetter nodeGroupConfigGetter
+}
+
+// nodeGroupConfigGetter is an interface to limit the functions that can be used
+// from NodeGroupConfigProcessor interface
+type nodeGroupConfigGetter interface {
+	// GetZeroOrMaxNodeScaling returns ZeroOrMaxNodeScaling value that should be used for a given NodeGroup.
+	GetZeroOrMaxNodeScaling(nodeGroup cloudprovider.NodeGroup) (bool, error)
 }
 
 // GetNodesToRemove selects up to maxCount nodes for deletion, by selecting a first maxCount candidates
@@ -94,12 +102,12 @@ func (p *AtomicResizeFilteringProcessor) GetNodesToRemove(ctx *context.Autoscali
 			klog.Errorf("Node %v will not scale down, failed to get node info: %s", node.Node.Name, err)
 			continue
 		}
-		autoscalingOptions, err := nodeGroup.GetOptions(ctx.NodeGroupDefaults)
-		if err != nil && err != cloudprovider.ErrNotImplemented {
+		zeroOrMaxNodeScaling, err := p.configGetter.GetZeroOrMaxNodeScaling(nodeGroup)
+		if err != nil {
 			klog.Errorf("Failed to get autoscaling options for node group %s: %v", nodeGroup.Id(), err)
 			continue
 		}
-		if autoscalingOptions != nil && autoscalingOptions.ZeroOrMaxNodeScaling {
+		if zeroOrMaxNodeScaling {
 			klogx.V(2).UpTo(atomicQuota).Infof("Considering node %s for atomic scale down", node.Node.Name)
 			nodesByGroup[nodeGroup] = append(nodesByGroup[nodeGroup], node)
 		} else {
@@ -130,6 +138,6 @@ func (p *AtomicResizeFilteringProcessor) CleanUp() {
 }
 
 // NewAtomicResizeFilteringProcessor returns a new AtomicResizeFilteringProcessor
-func NewAtomicResizeFilteringProcessor() *AtomicResizeFilteringProcessor {
-	return &AtomicResizeFilteringProcessor{}
+func NewAtomicResizeFilteringProcessor(configGetter nodeGroupConfigGetter) *AtomicResizeFilteringProcessor {
+	return &AtomicResizeFilteringProcessor{configGetter: configGetter}
 }
@@ -22,6 +22,7 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/processors/actionablecluster"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/binpacking"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/customresources"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/maintenance"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroupconfig"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroups"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroupset"
@@ -71,10 +72,14 @@ type AutoscalingProcessors struct {
 	// * scale-up failures per nodegroup
 	// * scale-down failures per nodegroup
 	ScaleStateNotifier *nodegroupchange.NodeGroupChangeObserversList
+	// MaintenanceWindows tracks the maintenance windows suspending actuation, reported in the status ConfigMap.
+	// Nil if no maintenance windows are configured.
+	MaintenanceWindows *maintenance.Tracker
 }
 
 // DefaultProcessors returns default set of processors.
 func DefaultProcessors(options config.AutoscalingOptions) *AutoscalingProcessors {
+	nodeGroupConfigProcessor := nodegroupconfig.NewDefaultNodeGroupConfigProcessor(options.NodeGroupDefaults)
 	return &AutoscalingProcessors{
 		PodListProcessor:       pods.NewDefaultPodListProcessor(),
 		NodeGroupListProcessor: nodegroups.NewDefaultNodeGroupListProcessor(),
@@ -89,13 +94,13 @@ func DefaultProcessors(options config.AutoscalingOptions) *AutoscalingProcessors
 		ScaleDownSetProcessor: nodes.NewCompositeScaleDownSetProcessor(
 			[]nodes.ScaleDownSetProcessor{
 				nodes.NewMaxNodesProcessor(),
-				nodes.NewAtomicResizeFilteringProcessor(),
+				nodes.NewAtomicResizeFilteringProcessor(nodeGroupConfigProcessor),
 			},
 		),
 		ScaleDownStatusProcessor:    status.NewDefaultScaleDownStatusProcessor(),
 		AutoscalingStatusProcessor:  status.NewDefaultAutoscalingStatusProcessor(),
 		NodeGroupManager:            nodegroups.NewDefaultNodeGroupManager(),
-		NodeGroupConfigProcessor:    nodegroupconfig.NewDefaultNodeGroupConfigProcessor(options.NodeGroupDefaults),
+		NodeGroupConfigProcessor:    nodeGroupConfigProcessor,
 		CustomResourcesProcessor:    customresources.NewDefaultCustomResourcesProcessor(),
 		ActionableClusterProcessor:  actionablecluster.NewDefaultActionableClusterProcessor(),
 		TemplateNodeInfoProvider:    nodeinfosprovider.NewDefaultTemplateNodeInfoProvider(nil, false),
@@ -101,8 +101,9 @@ func (p *ProvisioningRequestPodsInjector) Process(
 func (p *ProvisioningRequestPodsInjector) CleanUp() {}
 
 // NewProvisioningRequestPodsInjector creates a ProvisioningRequest filter processor.
-func NewProvisioningRequestPodsInjector(kubeConfig *rest.Config) (pods.PodListProcessor, error) {
-	client, err := provreqclient.NewProvisioningRequestClient(kubeConfig)
+// In dry-run mode, it doesn't update ProvisioningRequests.
+func NewProvisioningRequestPodsInjector(kubeConfig *rest.Config, dryRun bool) (pods.PodListProcessor, error) {
+	client, err := provreqclient.NewProvisioningRequestClient(kubeConfig, dryRun)
 	if err != nil {
 		return nil, err
 	}
@@ -26,6 +26,7 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
 	cp_test "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
 	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/core/dryrun"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroupset"
 	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
 
@@ -57,6 +58,7 @@ func TestEventingScaleUpStatusProcessor(t *testing.T) {
 	testCases := []struct {
 		caseName            string
 		state               *ScaleUpStatus
+		dryRun              bool
 		expectedTriggered   int
 		expectedNoTriggered int
 	}{
@@ -101,6 +103,20 @@ func TestEventingScaleUpStatusProcessor(t *testing.T) {
 			expectedTriggered:   0,
 			expectedNoTriggered: 0,
 		},
+		{
+			caseName: "Dry run; some pods remain unschedulable",
+			state: &ScaleUpStatus{
+				Result:               ScaleUpSuccessful,
+				ScaleUpInfos:         []nodegroupset.ScaleUpInfo{{}},
+				PodsTriggeredScaleUp: []*apiv1.Pod{p3},
+				PodsRemainUnschedulable: []NoScaleUpInfo{
+					{p1, reasons, reasons},
+				},
+			},
+			dryRun:              true,
+			expectedTriggered:   0,
+			expectedNoTriggered: 0,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -110,6 +126,9 @@ func TestEventingScaleUpStatusProcessor(t *testing.T) {
 				Recorder: fakeRecorder,
 			},
 		}
+		if tc.dryRun {
+			context.DryRunRecorder = dryrun.NewRecorder(nil)
+		}
 		p.Process(context, tc.state)
 		triggered := 0
 		noTriggered := 0
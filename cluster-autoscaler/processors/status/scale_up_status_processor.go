@@ -22,6 +22,7 @@ import (
 
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
 	"k8s.io/autoscaler/cluster-autoscaler/context"
+	"k8s.io/autoscaler/cluster-autoscaler/expander"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroups"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroupset"
 )
@@ -40,6 +41,11 @@ type ScaleUpStatus struct {
 	ConsideredNodeGroups     []cloudprovider.NodeGroup
 	FailedCreationNodeGroups []cloudprovider.NodeGroup
 	FailedResizeNodeGroups   []cloudprovider.NodeGroup
+	// ExpansionOptions, ExpanderResults and BestOption describe how the
+	// expander chose the option to scale up, if it was run.
+	ExpansionOptions []expander.Option
+	ExpanderResults  []expander.FilterResult
+	BestOption       *expander.Option
 }
 
 // NoScaleUpInfo contains information about a pod that didn't trigger scale-up.
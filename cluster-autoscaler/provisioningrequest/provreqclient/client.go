@@ -48,10 +48,14 @@ type ProvisioningRequestClient struct {
 	client         versioned.Interface
 	provReqLister  listers.ProvisioningRequestLister
 	podTemplLister v1.PodTemplateLister
+	// dryRun makes the client skip status updates, they are made by the
+	// Cluster Autoscaler which actually scales the cluster.
+	dryRun bool
 }
 
 // NewProvisioningRequestClient configures and returns a provisioningRequestClient.
-func NewProvisioningRequestClient(kubeConfig *rest.Config) (*ProvisioningRequestClient, error) {
+// In dry-run mode, the returned client doesn't update ProvisioningRequests.
+func NewProvisioningRequestClient(kubeConfig *rest.Config, dryRun bool) (*ProvisioningRequestClient, error) {
 	prClient, err := newPRClient(kubeConfig)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to create Provisioning Request client: %v", err)
@@ -76,6 +80,7 @@ func NewProvisioningRequestClient(kubeConfig *rest.Config) (*ProvisioningRequest
 		client:         prClient,
 		provReqLister:  provReqLister,
 		podTemplLister: podTemplLister,
+		dryRun:         dryRun,
 	}, nil
 }
 
@@ -127,6 +132,10 @@ func (c *ProvisioningRequestClient) FetchPodTemplates(pr *v1beta1.ProvisioningRe
 
 // UpdateProvisioningRequest updates the given ProvisioningRequest CR by propagating the changes using the ProvisioningRequestInterface and returns the updated instance or the original one in case of an error.
 func (c *ProvisioningRequestClient) UpdateProvisioningRequest(pr *v1beta1.ProvisioningRequest) (*v1beta1.ProvisioningRequest, error) {
+	if c.dryRun {
+		klog.V(4).Infof("Dry run: not updating ProvisioningRequest %s/%s, status: %q", pr.Namespace, pr.Name, pr.Status)
+		return pr, nil
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), provisioningRequestClientCallTimeout)
 	defer cancel()
 
@@ -24,6 +24,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
 	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/autoscaler/cluster-autoscaler/apis/provisioningrequest/autoscaling.x-k8s.io/v1beta1"
 	"k8s.io/autoscaler/cluster-autoscaler/provisioningrequest/pods"
 	"k8s.io/autoscaler/cluster-autoscaler/provisioningrequest/provreqwrapper"
@@ -95,3 +96,20 @@ func TestProvisioningRequestsForPods(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateProvisioningRequestDryRun(t *testing.T) {
+	pr := ProvisioningRequestWrapperForTesting("namespace", "name")
+	ctx := context.Background()
+	c := NewFakeProvisioningRequestClient(ctx, t, pr)
+	c.dryRun = true
+
+	updated := pr.DeepCopy()
+	updated.Status.Conditions = []metav1.Condition{{Type: v1beta1.Provisioned, Status: metav1.ConditionTrue}}
+	got, err := c.UpdateProvisioningRequest(updated)
+	assert.NoError(t, err)
+	assert.Equal(t, updated, got)
+
+	stored, err := c.client.AutoscalingV1beta1().ProvisioningRequests("namespace").Get(ctx, "name", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, stored.Status.Conditions)
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	"k8s.io/autoscaler/cluster-autoscaler/debuggingsnapshot/replay"
+	"k8s.io/autoscaler/cluster-autoscaler/estimator"
+	"k8s.io/autoscaler/cluster-autoscaler/expander"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/units"
+)
+
+const (
+	// simulateCommand is the name of the subcommand replaying a debugging snapshot offline.
+	simulateCommand = "simulate"
+	// simulateScaleUpMode replays the scale-up path.
+	simulateScaleUpMode = "scale-up"
+	// simulateScaleDownMode replays the scale-down path.
+	simulateScaleDownMode = "scale-down"
+)
+
+// runSimulate replays a debugging snapshot captured from the /snapshotz endpoint
+// and prints the decisions Cluster Autoscaler would make for it.
+func runSimulate(args []string) error {
+	fs := pflag.NewFlagSet(simulateCommand, pflag.ExitOnError)
+	snapshotFile := fs.String("snapshot", "", "Path to the debugging snapshot to replay.")
+	mode := fs.String("mode", simulateScaleUpMode, "Which part of the loop to replay, one of: scale-up, scale-down.")
+	extraPodsFile := fs.String("extra-pods", "", "Path to a JSON list of pods that should be considered pending in addition to the ones captured in the snapshot.")
+	priorityConfigMapFile := fs.String("priority-config-map", "", "Path to a JSON ConfigMap used by the priority expander.")
+	expanderNames := fs.String("expander", expander.RandomExpanderName, "Type of node group expander to be used in scale up, in the same format as the main binary flag.")
+	pricingConfigFile := fs.String("pricing-config-file", "", "Path to a price table (YAML or JSON) used by the price expander.")
+	estimatorName := fs.String("estimator", estimator.BinpackingEstimatorName, "Type of resource estimator to be used in scale up.")
+	scaleDownUtilizationThreshold := fs.Float64("scale-down-utilization-threshold", config.DefaultScaleDownUtilizationThreshold, "The maximum value between the sum of cpu requests and sum of memory requests of all pods running on the node divided by node's corresponding allocatable resource, below which a node can be considered for scale down")
+	scaleDownUnneededTime := fs.Duration("scale-down-unneeded-time", config.DefaultScaleDownUnneededTime, "How long a node should be unneeded before it is eligible for scale down")
+	balanceSimilarNodeGroups := fs.Bool("balance-similar-node-groups", false, "Detect similar node groups and balance the number of nodes between them")
+	maxNodesTotal := fs.Int("max-nodes-total", 0, "Maximum number of nodes in all node groups.")
+	nodeGroupMaxSize := fs.Int("max-node-group-size", replay.DefaultNodeGroupMaxSize, "Maximum size assumed for every node group, the snapshot doesn't capture node group limits.")
+	coresTotal := fs.String("cores-total", minMaxFlagString(0, config.DefaultMaxClusterCores), "Minimum and maximum number of cores in cluster, in the format <min>:<max>.")
+	memoryTotal := fs.String("memory-total", minMaxFlagString(0, config.DefaultMaxClusterMemory), "Minimum and maximum number of gigabytes of memory in cluster, in the format <min>:<max>.")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *snapshotFile == "" {
+		return fmt.Errorf("--snapshot is required")
+	}
+	if *mode != simulateScaleUpMode && *mode != simulateScaleDownMode {
+		return fmt.Errorf("unknown --mode %q, expected %s or %s", *mode, simulateScaleUpMode, simulateScaleDownMode)
+	}
+
+	minCoresTotal, maxCoresTotal, err := parseMinMaxFlag(*coresTotal)
+	if err != nil {
+		return err
+	}
+	minMemoryTotal, maxMemoryTotal, err := parseMinMaxFlag(*memoryTotal)
+	if err != nil {
+		return err
+	}
+
+	opts := replay.Options{
+		AutoscalingOptions: config.AutoscalingOptions{
+			NodeGroupDefaults: config.NodeGroupAutoscalingOptions{
+				ScaleDownUtilizationThreshold:    *scaleDownUtilizationThreshold,
+				ScaleDownGpuUtilizationThreshold: config.DefaultScaleDownGpuUtilizationThreshold,
+				ScaleDownUnneededTime:            *scaleDownUnneededTime,
+				ScaleDownUnreadyTime:             config.DefaultScaleDownUnreadyTime,
+				MaxNodeProvisionTime:             15 * time.Minute,
+			},
+			ExpanderNames:             *expanderNames,
+			PricingConfigFile:         *pricingConfigFile,
+			EstimatorName:             *estimatorName,
+			BalanceSimilarNodeGroups:  *balanceSimilarNodeGroups,
+			MaxNodesTotal:             *maxNodesTotal,
+			MinCoresTotal:             minCoresTotal,
+			MaxCoresTotal:             maxCoresTotal,
+			MinMemoryTotal:            minMemoryTotal * units.GiB,
+			MaxMemoryTotal:            maxMemoryTotal * units.GiB,
+			MaxTotalUnreadyPercentage: 45,
+			OkTotalUnreadyCount:       3,
+			ScaleUpFromZero:           true,
+			MaxNodesPerScaleUp:        1000,
+			// Scale-down defaults, matching the main binary flags.
+			ScaleDownNonEmptyCandidatesCount: 30,
+			ScaleDownCandidatesPoolRatio:     0.1,
+			ScaleDownCandidatesPoolMinCount:  50,
+			UnremovableNodeRecheckTimeout:    5 * time.Minute,
+			MaxScaleDownParallelism:          10,
+			MaxDrainParallelism:              1,
+			ScaleDownSimulationTimeout:       30 * time.Second,
+			ConfigNamespace:                  "kube-system",
+			StatusConfigMapName:              "cluster-autoscaler-status",
+			NodeGroupSetRatios: config.NodeGroupDifferenceRatios{
+				MaxCapacityMemoryDifferenceRatio: config.DefaultMaxCapacityMemoryDifferenceRatio,
+				MaxAllocatableDifferenceRatio:    config.DefaultMaxAllocatableDifferenceRatio,
+				MaxFreeDifferenceRatio:           config.DefaultMaxFreeDifferenceRatio,
+			},
+		},
+		NodeGroupMaxSize: *nodeGroupMaxSize,
+	}
+	if *extraPodsFile != "" {
+		if err := readJSONFile(*extraPodsFile, &opts.ExtraPendingPods); err != nil {
+			return err
+		}
+	}
+	if *priorityConfigMapFile != "" {
+		opts.PriorityConfigMap = &apiv1.ConfigMap{}
+		if err := readJSONFile(*priorityConfigMapFile, opts.PriorityConfigMap); err != nil {
+			return err
+		}
+		if opts.PriorityConfigMap.Namespace == "" {
+			opts.PriorityConfigMap.Namespace = opts.ConfigNamespace
+		}
+	}
+
+	snapshot, err := replay.LoadSnapshot(*snapshotFile)
+	if err != nil {
+		return err
+	}
+	replayer, err := replay.NewReplayer(snapshot, opts)
+	if err != nil {
+		return err
+	}
+	if *mode == simulateScaleDownMode {
+		result, err := replayer.ScaleDown()
+		if err != nil {
+			return err
+		}
+		result.Print(os.Stdout)
+		return nil
+	}
+	result, err := replayer.ScaleUp()
+	if err != nil {
+		return err
+	}
+	result.Print(os.Stdout)
+	return nil
+}
+
+func readJSONFile(path string, into interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	if err := json.Unmarshal(data, into); err != nil {
+		return fmt.Errorf("failed to decode %s: %v", path, err)
+	}
+	return nil
+}
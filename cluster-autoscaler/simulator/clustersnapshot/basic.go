@@ -20,6 +20,7 @@ import (
 	"fmt"
 
 	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/dynamicresources"
 	"k8s.io/klog/v2"
 	schedulerframework "k8s.io/kubernetes/pkg/scheduler/framework"
 )
@@ -27,7 +28,8 @@ import (
 // BasicClusterSnapshot is simple, reference implementation of ClusterSnapshot.
 // It is inefficient. But hopefully bug-free and good for initial testing.
 type BasicClusterSnapshot struct {
-	data []*internalBasicSnapshotData
+	data             []*internalBasicSnapshotData
+	dynamicResources *dynamicresources.Snapshot
 }
 
 type internalBasicSnapshotData struct {
@@ -237,17 +239,29 @@ func (snapshot *BasicClusterSnapshot) AddNodeWithPods(node *apiv1.Node, pods []*
 
 // RemoveNode removes nodes (and pods scheduled to it) from the snapshot.
 func (snapshot *BasicClusterSnapshot) RemoveNode(nodeName string) error {
-	return snapshot.getInternalData().removeNode(nodeName)
+	if err := snapshot.getInternalData().removeNode(nodeName); err != nil {
+		return err
+	}
+	snapshot.dynamicResources.RemoveNode(nodeName)
+	return nil
 }
 
 // AddPod adds pod to the snapshot and schedules it to given node.
 func (snapshot *BasicClusterSnapshot) AddPod(pod *apiv1.Pod, nodeName string) error {
+	if err := allocateResourceClaims(snapshot, pod, nodeName); err != nil {
+		return err
+	}
 	return snapshot.getInternalData().addPod(pod, nodeName)
 }
 
 // RemovePod removes pod from the snapshot.
 func (snapshot *BasicClusterSnapshot) RemovePod(namespace, podName, nodeName string) error {
-	return snapshot.getInternalData().removePod(namespace, podName, nodeName)
+	pod := podWithResourceClaims(snapshot, namespace, podName, nodeName)
+	if err := snapshot.getInternalData().removePod(namespace, podName, nodeName); err != nil {
+		return err
+	}
+	snapshot.dynamicResources.DeallocatePod(pod)
+	return nil
 }
 
 // IsPVCUsedByPods returns if the pvc is used by any pod
@@ -259,6 +273,7 @@ func (snapshot *BasicClusterSnapshot) IsPVCUsedByPods(key string) bool {
 func (snapshot *BasicClusterSnapshot) Fork() {
 	forkData := snapshot.getInternalData().clone()
 	snapshot.data = append(snapshot.data, forkData)
+	snapshot.dynamicResources.Fork()
 }
 
 // Revert reverts snapshot state to moment of forking.
@@ -267,6 +282,7 @@ func (snapshot *BasicClusterSnapshot) Revert() {
 		return
 	}
 	snapshot.data = snapshot.data[:len(snapshot.data)-1]
+	snapshot.dynamicResources.Revert()
 }
 
 // Commit commits changes done after forking.
@@ -276,6 +292,7 @@ func (snapshot *BasicClusterSnapshot) Commit() error {
 		return nil
 	}
 	snapshot.data = append(snapshot.data[:len(snapshot.data)-2], snapshot.data[len(snapshot.data)-1])
+	snapshot.dynamicResources.Commit()
 	return nil
 }
 
@@ -283,6 +300,17 @@ func (snapshot *BasicClusterSnapshot) Commit() error {
 func (snapshot *BasicClusterSnapshot) Clear() {
 	baseData := newInternalBasicSnapshotData()
 	snapshot.data = []*internalBasicSnapshotData{baseData}
+	snapshot.dynamicResources = nil
+}
+
+// DynamicResources returns the Dynamic Resource Allocation state of the snapshot.
+func (snapshot *BasicClusterSnapshot) DynamicResources() *dynamicresources.Snapshot {
+	return snapshot.dynamicResources
+}
+
+// SetDynamicResources replaces the Dynamic Resource Allocation state of the snapshot.
+func (snapshot *BasicClusterSnapshot) SetDynamicResources(dynamicResources *dynamicresources.Snapshot) {
+	snapshot.dynamicResources = dynamicResources
 }
 
 // implementation of SharedLister interface
@@ -20,6 +20,7 @@ import (
 	"errors"
 
 	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/dynamicresources"
 	"k8s.io/klog/v2"
 	schedulerframework "k8s.io/kubernetes/pkg/scheduler/framework"
 )
@@ -42,6 +43,13 @@ type ClusterSnapshot interface {
 	AddNodeWithPods(node *apiv1.Node, pods []*apiv1.Pod) error
 	// IsPVCUsedByPods returns if the pvc is used by any pod, key = <namespace>/<pvc_name>
 	IsPVCUsedByPods(key string) bool
+	// DynamicResources returns the Dynamic Resource Allocation state of the snapshot. ResourceClaims
+	// of pods are allocated and deallocated in it as pods are added and removed. It's nil unless set,
+	// ResourceClaims are ignored then.
+	DynamicResources() *dynamicresources.Snapshot
+	// SetDynamicResources replaces the Dynamic Resource Allocation state of the snapshot. It should
+	// only be called on an unforked snapshot, before pods with ResourceClaims are added.
+	SetDynamicResources(dynamicResources *dynamicresources.Snapshot)
 
 	// Fork creates a fork of snapshot state. All modifications can later be reverted to moment of forking via Revert().
 	// Use WithForkedSnapshot() helper function instead if possible.
@@ -57,6 +65,36 @@ type ClusterSnapshot interface {
 // ErrNodeNotFound means that a node wasn't found in the snapshot.
 var ErrNodeNotFound = errors.New("node not found")
 
+// allocateResourceClaims allocates the ResourceClaims of a pod about to be added to the node.
+func allocateResourceClaims(snapshot ClusterSnapshot, pod *apiv1.Pod, nodeName string) error {
+	if snapshot.DynamicResources() == nil || len(pod.Spec.ResourceClaims) == 0 {
+		return nil
+	}
+	nodeInfo, err := snapshot.NodeInfos().Get(nodeName)
+	if err != nil {
+		return err
+	}
+	return snapshot.DynamicResources().AllocatePod(pod, nodeInfo.Node())
+}
+
+// podWithResourceClaims returns the pod scheduled to the node if it has
+// ResourceClaims to deallocate when it's removed, nil otherwise.
+func podWithResourceClaims(snapshot ClusterSnapshot, namespace, podName, nodeName string) *apiv1.Pod {
+	if snapshot.DynamicResources() == nil {
+		return nil
+	}
+	nodeInfo, err := snapshot.NodeInfos().Get(nodeName)
+	if err != nil {
+		return nil
+	}
+	for _, podInfo := range nodeInfo.Pods {
+		if podInfo.Pod.Namespace == namespace && podInfo.Pod.Name == podName && len(podInfo.Pod.Spec.ResourceClaims) > 0 {
+			return podInfo.Pod
+		}
+	}
+	return nil
+}
+
 // WithForkedSnapshot is a helper function for snapshot that makes sure all Fork() calls are closed with Commit() or Revert() calls.
 // The function return (error, error) pair. The first error comes from the passed function, the second error indicate the success of the function itself.
 func WithForkedSnapshot(snapshot ClusterSnapshot, f func() (bool, error)) (error, error) {
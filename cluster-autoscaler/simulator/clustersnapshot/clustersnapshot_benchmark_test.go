@@ -18,6 +18,7 @@ package clustersnapshot
 
 import (
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -267,3 +268,44 @@ func BenchmarkBuildNodeInfoList(b *testing.B) {
 		})
 	}
 }
+
+func BenchmarkParallelCloneForkAddRevert(b *testing.B) {
+	nodeTestCases := []int{100, 1000, 5000, 15000}
+	workers := 8
+
+	for _, ntc := range nodeTestCases {
+		nodes := createTestNodes(ntc)
+		pods := createTestPods(ntc * 30)
+		assignPodsToNodes(pods, nodes)
+		clusterSnapshot := NewPersistentClusterSnapshot()
+		err := clusterSnapshot.AddNodes(nodes)
+		assert.NoError(b, err)
+		for _, pod := range pods {
+			err = clusterSnapshot.AddPod(pod, pod.Spec.NodeName)
+			assert.NoError(b, err)
+		}
+		b.ResetTimer()
+		b.Run(fmt.Sprintf("persistent: %d workers CloneForkAddRevert (%d nodes, 30 pods)", workers, ntc), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				var wg sync.WaitGroup
+				for w := 0; w < workers; w++ {
+					clone := clusterSnapshot.Clone()
+					tmpNode := BuildTestNode(fmt.Sprintf("tmp-%d", w), 2000, 2000000)
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						clone.Fork()
+						if err := clone.AddNode(tmpNode); err != nil {
+							assert.NoError(b, err)
+						}
+						if _, err := clone.NodeInfos().List(); err != nil {
+							assert.NoError(b, err)
+						}
+						clone.Revert()
+					}()
+				}
+				wg.Wait()
+			}
+		})
+	}
+}
@@ -23,6 +23,10 @@ import (
 	"time"
 
 	apiv1 "k8s.io/api/core/v1"
+	resourceapi "k8s.io/api/resource/v1alpha2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/dynamicresources"
 	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
 	schedulerframework "k8s.io/kubernetes/pkg/scheduler/framework"
 
@@ -30,8 +34,9 @@ import (
 )
 
 var snapshots = map[string]func() ClusterSnapshot{
-	"basic": func() ClusterSnapshot { return NewBasicClusterSnapshot() },
-	"delta": func() ClusterSnapshot { return NewDeltaClusterSnapshot() },
+	"basic":      func() ClusterSnapshot { return NewBasicClusterSnapshot() },
+	"delta":      func() ClusterSnapshot { return NewDeltaClusterSnapshot() },
+	"persistent": func() ClusterSnapshot { return NewPersistentClusterSnapshot() },
 }
 
 func nodeNames(nodes []*apiv1.Node) []string {
@@ -764,3 +769,91 @@ func TestWithForkedSnapshot(t *testing.T) {
 		}
 	}
 }
+
+func TestDynamicResources(t *testing.T) {
+	structured := true
+	newDynamicResources := func() *dynamicresources.Snapshot {
+		parameters := &resourceapi.ResourceClaimParameters{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "one-gpu"},
+			DriverRequests: []resourceapi.DriverRequests{{
+				DriverName: "gpu.example.com",
+				Requests: []resourceapi.ResourceRequest{{
+					ResourceRequestModel: resourceapi.ResourceRequestModel{
+						NamedResources: &resourceapi.NamedResourcesRequest{Selector: "true"},
+					},
+				}},
+			}},
+		}
+		var claims []*resourceapi.ResourceClaim
+		for _, name := range []string{"gpu-a", "gpu-b"} {
+			claims = append(claims, &resourceapi.ResourceClaim{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, UID: types.UID(name)},
+				Spec: resourceapi.ResourceClaimSpec{
+					ResourceClassName: "gpu",
+					ParametersRef: &resourceapi.ResourceClaimParametersReference{
+						APIGroup: resourceapi.GroupName,
+						Kind:     "ResourceClaimParameters",
+						Name:     parameters.Name,
+					},
+				},
+			})
+		}
+		slice := &resourceapi.ResourceSlice{
+			ObjectMeta: metav1.ObjectMeta{Name: "n1-gpus"},
+			NodeName:   "n1",
+			DriverName: "gpu.example.com",
+			ResourceModel: resourceapi.ResourceModel{
+				NamedResources: &resourceapi.NamedResourcesResources{
+					Instances: []resourceapi.NamedResourcesInstance{{Name: "gpu-0"}},
+				},
+			},
+		}
+		class := &resourceapi.ResourceClass{
+			ObjectMeta:           metav1.ObjectMeta{Name: "gpu"},
+			DriverName:           "gpu.example.com",
+			StructuredParameters: &structured,
+		}
+		return dynamicresources.NewSnapshotFromObjects(
+			[]*resourceapi.ResourceSlice{slice}, claims, []*resourceapi.ResourceClass{class}, nil,
+			[]*resourceapi.ResourceClaimParameters{parameters})
+	}
+	withClaim := func(claimName string) func(*apiv1.Pod) {
+		return func(pod *apiv1.Pod) {
+			pod.Spec.ResourceClaims = []apiv1.PodResourceClaim{{Name: "gpu", ResourceClaimName: &claimName}}
+		}
+	}
+	podA := BuildTestPod("a", 100, 100, withClaim("gpu-a"))
+	podB := BuildTestPod("b", 100, 100, withClaim("gpu-b"))
+
+	for name, snapshotFactory := range snapshots {
+		t.Run(name, func(t *testing.T) {
+			snapshot := snapshotFactory()
+			assert.NoError(t, snapshot.AddNode(BuildTestNode("n1", 1000, 1000)))
+			// Claims are ignored unless Dynamic Resource Allocation is simulated.
+			assert.NoError(t, snapshot.AddPod(podA, "n1"))
+			assert.NoError(t, snapshot.AddPod(podB, "n1"))
+
+			snapshot.Clear()
+			snapshot.SetDynamicResources(newDynamicResources())
+			assert.NoError(t, snapshot.AddNode(BuildTestNode("n1", 1000, 1000)))
+
+			snapshot.Fork()
+			assert.NoError(t, snapshot.AddPod(podA, "n1"))
+			// The only GPU of the node is allocated.
+			assert.Error(t, snapshot.AddPod(podB, "n1"))
+			snapshot.Revert()
+			assert.NoError(t, snapshot.AddPod(podB, "n1"))
+			assert.Error(t, snapshot.AddPod(podA, "n1"))
+
+			snapshot.Fork()
+			assert.NoError(t, snapshot.RemovePod("default", "b", "n1"))
+			assert.NoError(t, snapshot.AddPod(podA, "n1"))
+			assert.NoError(t, snapshot.Commit())
+			assert.Nil(t, snapshot.DynamicResources().Claim("default", "gpu-b").Status.Allocation)
+			assert.NotNil(t, snapshot.DynamicResources().Claim("default", "gpu-a").Status.Allocation)
+
+			assert.NoError(t, snapshot.RemoveNode("n1"))
+			assert.Empty(t, snapshot.DynamicResources().ResourceSlices("n1"))
+		})
+	}
+}
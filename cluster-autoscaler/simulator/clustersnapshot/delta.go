@@ -20,6 +20,7 @@ import (
 	"fmt"
 
 	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/dynamicresources"
 	"k8s.io/klog/v2"
 	schedulerframework "k8s.io/kubernetes/pkg/scheduler/framework"
 )
@@ -42,7 +43,8 @@ import (
 //	pod affinity - causes scheduler framework to list pods with non-empty selector,
 //		so basic caching doesn't help.
 type DeltaClusterSnapshot struct {
-	data *internalDeltaSnapshotData
+	data             *internalDeltaSnapshotData
+	dynamicResources *dynamicresources.Snapshot
 }
 
 type deltaSnapshotNodeLister DeltaClusterSnapshot
@@ -427,17 +429,29 @@ func (snapshot *DeltaClusterSnapshot) AddNodeWithPods(node *apiv1.Node, pods []*
 
 // RemoveNode removes nodes (and pods scheduled to it) from the snapshot.
 func (snapshot *DeltaClusterSnapshot) RemoveNode(nodeName string) error {
-	return snapshot.data.removeNode(nodeName)
+	if err := snapshot.data.removeNode(nodeName); err != nil {
+		return err
+	}
+	snapshot.dynamicResources.RemoveNode(nodeName)
+	return nil
 }
 
 // AddPod adds pod to the snapshot and schedules it to given node.
 func (snapshot *DeltaClusterSnapshot) AddPod(pod *apiv1.Pod, nodeName string) error {
+	if err := allocateResourceClaims(snapshot, pod, nodeName); err != nil {
+		return err
+	}
 	return snapshot.data.addPod(pod, nodeName)
 }
 
 // RemovePod removes pod from the snapshot.
 func (snapshot *DeltaClusterSnapshot) RemovePod(namespace, podName, nodeName string) error {
-	return snapshot.data.removePod(namespace, podName, nodeName)
+	pod := podWithResourceClaims(snapshot, namespace, podName, nodeName)
+	if err := snapshot.data.removePod(namespace, podName, nodeName); err != nil {
+		return err
+	}
+	snapshot.dynamicResources.DeallocatePod(pod)
+	return nil
 }
 
 // IsPVCUsedByPods returns if the pvc is used by any pod
@@ -449,6 +463,7 @@ func (snapshot *DeltaClusterSnapshot) IsPVCUsedByPods(key string) bool {
 // Time: O(1)
 func (snapshot *DeltaClusterSnapshot) Fork() {
 	snapshot.data = snapshot.data.fork()
+	snapshot.dynamicResources.Fork()
 }
 
 // Revert reverts snapshot state to moment of forking.
@@ -456,6 +471,7 @@ func (snapshot *DeltaClusterSnapshot) Fork() {
 func (snapshot *DeltaClusterSnapshot) Revert() {
 	if snapshot.data.baseData != nil {
 		snapshot.data = snapshot.data.baseData
+		snapshot.dynamicResources.Revert()
 	}
 }
 
@@ -467,6 +483,7 @@ func (snapshot *DeltaClusterSnapshot) Commit() error {
 		return err
 	}
 	snapshot.data = newData
+	snapshot.dynamicResources.Commit()
 	return nil
 }
 
@@ -474,4 +491,15 @@ func (snapshot *DeltaClusterSnapshot) Commit() error {
 // Time: O(1)
 func (snapshot *DeltaClusterSnapshot) Clear() {
 	snapshot.data = newInternalDeltaSnapshotData()
+	snapshot.dynamicResources = nil
+}
+
+// DynamicResources returns the Dynamic Resource Allocation state of the snapshot.
+func (snapshot *DeltaClusterSnapshot) DynamicResources() *dynamicresources.Snapshot {
+	return snapshot.dynamicResources
+}
+
+// SetDynamicResources replaces the Dynamic Resource Allocation state of the snapshot.
+func (snapshot *DeltaClusterSnapshot) SetDynamicResources(dynamicResources *dynamicresources.Snapshot) {
+	snapshot.dynamicResources = dynamicResources
 }
@@ -0,0 +1,429 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clustersnapshot
+
+import (
+	"fmt"
+	"sync"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/dynamicresources"
+	"k8s.io/klog/v2"
+	schedulerframework "k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// PersistentClusterSnapshot is an implementation of ClusterSnapshot built of
+// layers of changes which are shared, copy-on-write, between snapshots. On top
+// of the Fork/Revert/Commit stack, Clone creates independent snapshots which
+// can be modified and used concurrently with the snapshot they were cloned from.
+//
+// Complexity of some notable operations:
+//
+//	fork - O(1)
+//	revert - O(1)
+//	commit - O(n), where n = number of nodes added, modified or deleted since forking
+//	clone - O(1), amortized
+//	get node info - O(l), where l = number of layers
+//	list node infos - O(n), cached
+//
+// A snapshot can be read concurrently, e.g. by parallel predicate checks, as
+// long as it isn't modified at the same time. Snapshots sharing layers can be
+// both read and modified concurrently.
+type PersistentClusterSnapshot struct {
+	layer            *persistentLayer
+	forks            []*persistentLayer
+	dynamicResources *dynamicresources.Snapshot
+}
+
+type persistentSnapshotNodeLister PersistentClusterSnapshot
+type persistentSnapshotStorageLister PersistentClusterSnapshot
+
+// persistentLayer holds the node infos added or modified on top of its parent
+// layer, and the nodes of the parent layers it deletes. A layer is only
+// modified by the snapshot it's the top layer of, and only until it's shared
+// with a clone. Node infos of a layer are never modified once another layer is
+// put on top of it, they are copied to the top layer first.
+type persistentLayer struct {
+	parent *persistentLayer
+
+	nodeInfos map[string]*schedulerframework.NodeInfo
+	deleted   map[string]bool
+	shared    bool
+
+	cacheLock                        sync.Mutex
+	nodeInfoList                     []*schedulerframework.NodeInfo
+	havePodsWithAffinity             []*schedulerframework.NodeInfo
+	havePodsWithRequiredAntiAffinity []*schedulerframework.NodeInfo
+	pvcRefCounts                     map[string]int
+}
+
+func newPersistentLayer(parent *persistentLayer) *persistentLayer {
+	return &persistentLayer{
+		parent:    parent,
+		nodeInfos: make(map[string]*schedulerframework.NodeInfo),
+		deleted:   make(map[string]bool),
+	}
+}
+
+func (layer *persistentLayer) getNodeInfo(name string) (*schedulerframework.NodeInfo, bool) {
+	for ; layer != nil; layer = layer.parent {
+		if nodeInfo, found := layer.nodeInfos[name]; found {
+			return nodeInfo, true
+		}
+		if layer.deleted[name] {
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+func (layer *persistentLayer) getNodeInfoList() []*schedulerframework.NodeInfo {
+	if layer == nil {
+		return nil
+	}
+	layer.cacheLock.Lock()
+	defer layer.cacheLock.Unlock()
+	if layer.nodeInfoList == nil {
+		layer.nodeInfoList = layer.buildNodeInfoList()
+	}
+	return layer.nodeInfoList
+}
+
+func (layer *persistentLayer) buildNodeInfoList() []*schedulerframework.NodeInfo {
+	parentList := layer.parent.getNodeInfoList()
+	nodeInfoList := make([]*schedulerframework.NodeInfo, 0, len(parentList)+len(layer.nodeInfos))
+	replaced := make(map[string]bool, len(layer.nodeInfos))
+	for _, nodeInfo := range parentList {
+		name := nodeInfo.Node().Name
+		if modified, found := layer.nodeInfos[name]; found {
+			nodeInfoList = append(nodeInfoList, modified)
+			replaced[name] = true
+		} else if !layer.deleted[name] {
+			nodeInfoList = append(nodeInfoList, nodeInfo)
+		}
+	}
+	for name, nodeInfo := range layer.nodeInfos {
+		if !replaced[name] {
+			nodeInfoList = append(nodeInfoList, nodeInfo)
+		}
+	}
+	return nodeInfoList
+}
+
+func (layer *persistentLayer) getHavePodsWithAffinityList() []*schedulerframework.NodeInfo {
+	nodeInfoList := layer.getNodeInfoList()
+	layer.cacheLock.Lock()
+	defer layer.cacheLock.Unlock()
+	if layer.havePodsWithAffinity == nil {
+		layer.havePodsWithAffinity = make([]*schedulerframework.NodeInfo, 0, len(nodeInfoList))
+		for _, nodeInfo := range nodeInfoList {
+			if len(nodeInfo.PodsWithAffinity) > 0 {
+				layer.havePodsWithAffinity = append(layer.havePodsWithAffinity, nodeInfo)
+			}
+		}
+	}
+	return layer.havePodsWithAffinity
+}
+
+func (layer *persistentLayer) getHavePodsWithRequiredAntiAffinityList() []*schedulerframework.NodeInfo {
+	nodeInfoList := layer.getNodeInfoList()
+	layer.cacheLock.Lock()
+	defer layer.cacheLock.Unlock()
+	if layer.havePodsWithRequiredAntiAffinity == nil {
+		layer.havePodsWithRequiredAntiAffinity = make([]*schedulerframework.NodeInfo, 0, len(nodeInfoList))
+		for _, nodeInfo := range nodeInfoList {
+			if len(nodeInfo.PodsWithRequiredAntiAffinity) > 0 {
+				layer.havePodsWithRequiredAntiAffinity = append(layer.havePodsWithRequiredAntiAffinity, nodeInfo)
+			}
+		}
+	}
+	return layer.havePodsWithRequiredAntiAffinity
+}
+
+func (layer *persistentLayer) isPVCUsedByPods(key string) bool {
+	nodeInfoList := layer.getNodeInfoList()
+	layer.cacheLock.Lock()
+	defer layer.cacheLock.Unlock()
+	if layer.pvcRefCounts == nil {
+		layer.pvcRefCounts = make(map[string]int)
+		for _, nodeInfo := range nodeInfoList {
+			for pvc, count := range nodeInfo.PVCRefCounts {
+				layer.pvcRefCounts[pvc] += count
+			}
+		}
+	}
+	return layer.pvcRefCounts[key] > 0
+}
+
+func (layer *persistentLayer) clearCaches() {
+	layer.cacheLock.Lock()
+	defer layer.cacheLock.Unlock()
+	layer.nodeInfoList = nil
+	layer.havePodsWithAffinity = nil
+	layer.havePodsWithRequiredAntiAffinity = nil
+	layer.pvcRefCounts = nil
+}
+
+// share marks the layer and all the layers below it as shared.
+func (layer *persistentLayer) share() {
+	for ; layer != nil && !layer.shared; layer = layer.parent {
+		layer.shared = true
+	}
+}
+
+// mergeInto applies the changes of the layer to its parent.
+func (layer *persistentLayer) mergeInto(parent *persistentLayer) {
+	for name := range layer.deleted {
+		delete(parent.nodeInfos, name)
+		if _, found := parent.parent.getNodeInfo(name); found {
+			parent.deleted[name] = true
+		}
+	}
+	for name, nodeInfo := range layer.nodeInfos {
+		parent.nodeInfos[name] = nodeInfo
+	}
+	parent.clearCaches()
+}
+
+// NewPersistentClusterSnapshot creates instances of PersistentClusterSnapshot.
+func NewPersistentClusterSnapshot() *PersistentClusterSnapshot {
+	snapshot := &PersistentClusterSnapshot{}
+	snapshot.Clear()
+	return snapshot
+}
+
+// writableLayer returns the top layer, putting a new one on top of it first if
+// it's shared.
+func (snapshot *PersistentClusterSnapshot) writableLayer() *persistentLayer {
+	if snapshot.layer.shared {
+		snapshot.layer = newPersistentLayer(snapshot.layer)
+	}
+	return snapshot.layer
+}
+
+// nodeInfoToModify returns the node info of the node in the top layer, copying
+// it there first if needed.
+func (snapshot *PersistentClusterSnapshot) nodeInfoToModify(nodeName string) (*schedulerframework.NodeInfo, bool) {
+	layer := snapshot.writableLayer()
+	if nodeInfo, found := layer.nodeInfos[nodeName]; found {
+		return nodeInfo, true
+	}
+	nodeInfo, found := layer.getNodeInfo(nodeName)
+	if !found {
+		return nil, false
+	}
+	nodeInfo = nodeInfo.Snapshot()
+	layer.nodeInfos[nodeName] = nodeInfo
+	return nodeInfo, true
+}
+
+// NodeInfos returns node lister.
+func (snapshot *PersistentClusterSnapshot) NodeInfos() schedulerframework.NodeInfoLister {
+	return (*persistentSnapshotNodeLister)(snapshot)
+}
+
+// StorageInfos returns storage lister
+func (snapshot *PersistentClusterSnapshot) StorageInfos() schedulerframework.StorageInfoLister {
+	return (*persistentSnapshotStorageLister)(snapshot)
+}
+
+// List returns list of all node infos.
+func (snapshot *persistentSnapshotNodeLister) List() ([]*schedulerframework.NodeInfo, error) {
+	return snapshot.layer.getNodeInfoList(), nil
+}
+
+// HavePodsWithAffinityList returns list of all node infos with pods that have affinity constraints.
+func (snapshot *persistentSnapshotNodeLister) HavePodsWithAffinityList() ([]*schedulerframework.NodeInfo, error) {
+	return snapshot.layer.getHavePodsWithAffinityList(), nil
+}
+
+// HavePodsWithRequiredAntiAffinityList returns the list of NodeInfos of nodes with pods with required anti-affinity terms.
+func (snapshot *persistentSnapshotNodeLister) HavePodsWithRequiredAntiAffinityList() ([]*schedulerframework.NodeInfo, error) {
+	return snapshot.layer.getHavePodsWithRequiredAntiAffinityList(), nil
+}
+
+// Get returns node info by node name.
+func (snapshot *persistentSnapshotNodeLister) Get(nodeName string) (*schedulerframework.NodeInfo, error) {
+	nodeInfo, found := snapshot.layer.getNodeInfo(nodeName)
+	if !found {
+		return nil, ErrNodeNotFound
+	}
+	return nodeInfo, nil
+}
+
+// IsPVCUsedByPods returns if PVC is used by pods
+func (snapshot *persistentSnapshotStorageLister) IsPVCUsedByPods(key string) bool {
+	return (*PersistentClusterSnapshot)(snapshot).IsPVCUsedByPods(key)
+}
+
+// AddNode adds node to the snapshot.
+func (snapshot *PersistentClusterSnapshot) AddNode(node *apiv1.Node) error {
+	nodeInfo := schedulerframework.NewNodeInfo()
+	nodeInfo.SetNode(node)
+	return snapshot.addNodeInfo(nodeInfo)
+}
+
+func (snapshot *PersistentClusterSnapshot) addNodeInfo(nodeInfo *schedulerframework.NodeInfo) error {
+	if _, found := snapshot.layer.getNodeInfo(nodeInfo.Node().Name); found {
+		return fmt.Errorf("node %s already in snapshot", nodeInfo.Node().Name)
+	}
+	layer := snapshot.writableLayer()
+	layer.nodeInfos[nodeInfo.Node().Name] = nodeInfo
+	layer.clearCaches()
+	return nil
+}
+
+// AddNodes adds nodes in batch to the snapshot.
+func (snapshot *PersistentClusterSnapshot) AddNodes(nodes []*apiv1.Node) error {
+	for _, node := range nodes {
+		if err := snapshot.AddNode(node); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddNodeWithPods adds a node and set of pods to be scheduled to this node to the snapshot.
+func (snapshot *PersistentClusterSnapshot) AddNodeWithPods(node *apiv1.Node, pods []*apiv1.Pod) error {
+	if err := snapshot.AddNode(node); err != nil {
+		return err
+	}
+	for _, pod := range pods {
+		if err := snapshot.AddPod(pod, node.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveNode removes nodes (and pods scheduled to it) from the snapshot.
+func (snapshot *PersistentClusterSnapshot) RemoveNode(nodeName string) error {
+	if _, found := snapshot.layer.getNodeInfo(nodeName); !found {
+		return ErrNodeNotFound
+	}
+	layer := snapshot.writableLayer()
+	delete(layer.nodeInfos, nodeName)
+	if _, found := layer.parent.getNodeInfo(nodeName); found {
+		layer.deleted[nodeName] = true
+	}
+	layer.clearCaches()
+	snapshot.dynamicResources.RemoveNode(nodeName)
+	return nil
+}
+
+// AddPod adds pod to the snapshot and schedules it to given node.
+func (snapshot *PersistentClusterSnapshot) AddPod(pod *apiv1.Pod, nodeName string) error {
+	if err := allocateResourceClaims(snapshot, pod, nodeName); err != nil {
+		return err
+	}
+	nodeInfo, found := snapshot.nodeInfoToModify(nodeName)
+	if !found {
+		return ErrNodeNotFound
+	}
+	nodeInfo.AddPod(pod)
+	snapshot.layer.clearCaches()
+	return nil
+}
+
+// RemovePod removes pod from the snapshot.
+func (snapshot *PersistentClusterSnapshot) RemovePod(namespace, podName, nodeName string) error {
+	nodeInfo, found := snapshot.nodeInfoToModify(nodeName)
+	if !found {
+		return ErrNodeNotFound
+	}
+	for _, podInfo := range nodeInfo.Pods {
+		if podInfo.Pod.Namespace == namespace && podInfo.Pod.Name == podName {
+			if err := nodeInfo.RemovePod(klog.Background(), podInfo.Pod); err != nil {
+				return fmt.Errorf("cannot remove pod; %v", err)
+			}
+			snapshot.layer.clearCaches()
+			snapshot.dynamicResources.DeallocatePod(podInfo.Pod)
+			return nil
+		}
+	}
+	return fmt.Errorf("pod %s/%s not in snapshot", namespace, podName)
+}
+
+// IsPVCUsedByPods returns if the pvc is used by any pod
+func (snapshot *PersistentClusterSnapshot) IsPVCUsedByPods(key string) bool {
+	return snapshot.layer.isPVCUsedByPods(key)
+}
+
+// Fork creates a fork of snapshot state. All modifications can later be reverted to moment of forking via Revert().
+// Time: O(1)
+func (snapshot *PersistentClusterSnapshot) Fork() {
+	snapshot.forks = append(snapshot.forks, snapshot.layer)
+	snapshot.layer = newPersistentLayer(snapshot.layer)
+	snapshot.dynamicResources.Fork()
+}
+
+// Revert reverts snapshot state to moment of forking.
+// Time: O(1)
+func (snapshot *PersistentClusterSnapshot) Revert() {
+	if len(snapshot.forks) == 0 {
+		return
+	}
+	snapshot.layer = snapshot.forks[len(snapshot.forks)-1]
+	snapshot.forks = snapshot.forks[:len(snapshot.forks)-1]
+	snapshot.dynamicResources.Revert()
+}
+
+// Commit commits changes done after forking.
+// Time: O(n), where n = size of delta (number of nodes added, modified or deleted since forking)
+func (snapshot *PersistentClusterSnapshot) Commit() error {
+	if len(snapshot.forks) == 0 {
+		// do nothing... as in basic snapshot.
+		return nil
+	}
+	base := snapshot.forks[len(snapshot.forks)-1]
+	snapshot.forks = snapshot.forks[:len(snapshot.forks)-1]
+	snapshot.dynamicResources.Commit()
+	if base.shared || snapshot.layer.parent != base {
+		// The layers are shared with a clone, keep the changes on top of them.
+		return nil
+	}
+	snapshot.layer.mergeInto(base)
+	snapshot.layer = base
+	return nil
+}
+
+// Clear reset cluster snapshot to empty, unforked state
+// Time: O(1)
+func (snapshot *PersistentClusterSnapshot) Clear() {
+	snapshot.layer = newPersistentLayer(nil)
+	snapshot.forks = nil
+	snapshot.dynamicResources = nil
+}
+
+// Clone returns an independent snapshot with the current state of this one,
+// sharing its layers. The clone isn't forked, it can't be reverted to the
+// states this snapshot was forked at.
+// Time: O(1), amortized
+func (snapshot *PersistentClusterSnapshot) Clone() *PersistentClusterSnapshot {
+	snapshot.layer.share()
+	return &PersistentClusterSnapshot{layer: snapshot.layer, dynamicResources: snapshot.dynamicResources.Clone()}
+}
+
+// DynamicResources returns the Dynamic Resource Allocation state of the snapshot.
+func (snapshot *PersistentClusterSnapshot) DynamicResources() *dynamicresources.Snapshot {
+	return snapshot.dynamicResources
+}
+
+// SetDynamicResources replaces the Dynamic Resource Allocation state of the snapshot.
+func (snapshot *PersistentClusterSnapshot) SetDynamicResources(dynamicResources *dynamicresources.Snapshot) {
+	snapshot.dynamicResources = dynamicResources
+}
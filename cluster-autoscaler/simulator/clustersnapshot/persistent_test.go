@@ -0,0 +1,128 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clustersnapshot
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+)
+
+func TestPersistentClone(t *testing.T) {
+	n1 := BuildTestNode("n1", 1000, 1000)
+	n2 := BuildTestNode("n2", 1000, 1000)
+	n3 := BuildTestNode("n3", 1000, 1000)
+	n4 := BuildTestNode("n4", 1000, 1000)
+	p1 := BuildTestPod("p1", 100, 0, WithNodeName(n1.Name))
+	p2 := BuildTestPod("p2", 100, 0, WithNodeName(n1.Name))
+
+	snapshot := NewPersistentClusterSnapshot()
+	assert.NoError(t, snapshot.AddNodeWithPods(n1, []*apiv1.Pod{p1}))
+	assert.NoError(t, snapshot.AddNode(n2))
+	base := getSnapshotState(t, snapshot)
+
+	clone := snapshot.Clone()
+	compareStates(t, base, getSnapshotState(t, clone))
+
+	assert.NoError(t, snapshot.AddNode(n3))
+	assert.NoError(t, snapshot.RemoveNode(n2.Name))
+	assert.NoError(t, snapshot.AddPod(p2, n1.Name))
+	assert.NoError(t, clone.AddNode(n4))
+	assert.NoError(t, clone.RemovePod(p1.Namespace, p1.Name, n1.Name))
+
+	compareStates(t, snapshotState{nodes: []*apiv1.Node{n1, n3}, pods: []*apiv1.Pod{p1, p2}}, getSnapshotState(t, snapshot))
+	compareStates(t, snapshotState{nodes: []*apiv1.Node{n1, n2, n4}}, getSnapshotState(t, clone))
+}
+
+func TestPersistentCloneForked(t *testing.T) {
+	n1 := BuildTestNode("n1", 1000, 1000)
+	n2 := BuildTestNode("n2", 1000, 1000)
+	n3 := BuildTestNode("n3", 1000, 1000)
+
+	snapshot := NewPersistentClusterSnapshot()
+	assert.NoError(t, snapshot.AddNode(n1))
+	snapshot.Fork()
+	assert.NoError(t, snapshot.AddNode(n2))
+	clone := snapshot.Clone()
+
+	// Reverting the snapshot doesn't affect the clone, which can't be reverted.
+	snapshot.Revert()
+	clone.Revert()
+	compareStates(t, snapshotState{nodes: []*apiv1.Node{n1}}, getSnapshotState(t, snapshot))
+	compareStates(t, snapshotState{nodes: []*apiv1.Node{n1, n2}}, getSnapshotState(t, clone))
+
+	// Changes committed on top of layers shared with a clone are kept.
+	snapshot.Fork()
+	assert.NoError(t, snapshot.AddNode(n3))
+	snapshot.Clone()
+	assert.NoError(t, snapshot.RemoveNode(n1.Name))
+	assert.NoError(t, snapshot.Commit())
+	snapshot.Revert()
+	compareStates(t, snapshotState{nodes: []*apiv1.Node{n3}}, getSnapshotState(t, snapshot))
+	compareStates(t, snapshotState{nodes: []*apiv1.Node{n1, n2}}, getSnapshotState(t, clone))
+}
+
+func TestPersistentConcurrentClones(t *testing.T) {
+	nodes := createTestNodes(100)
+	pods := createTestPods(300)
+	assignPodsToNodes(pods, nodes)
+	snapshot := NewPersistentClusterSnapshot()
+	assert.NoError(t, snapshot.AddNodes(nodes))
+	for _, pod := range pods {
+		assert.NoError(t, snapshot.AddPod(pod, pod.Spec.NodeName))
+	}
+	snapshot.Fork()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		clone := snapshot.Clone()
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				clone.Fork()
+				node := BuildTestNode(fmt.Sprintf("tmp-%d-%d", i, j), 1000, 1000)
+				assert.NoError(t, clone.AddNode(node))
+				assert.NoError(t, clone.AddPod(BuildTestPod(fmt.Sprintf("tmp-%d-%d", i, j), 100, 0), nodes[j].Name))
+				list, err := clone.NodeInfos().List()
+				assert.NoError(t, err)
+				assert.Len(t, list, len(nodes)+1)
+				clone.Revert()
+			}
+		}(i)
+		// The snapshot itself is read in parallel, e.g. by predicate checks.
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			list, err := snapshot.NodeInfos().List()
+			assert.NoError(t, err)
+			assert.Len(t, list, len(nodes))
+			_, err = snapshot.NodeInfos().HavePodsWithAffinityList()
+			assert.NoError(t, err)
+			_, err = snapshot.NodeInfos().Get(nodes[0].Name)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	compareStates(t, snapshotState{nodes: nodes, pods: pods}, getSnapshotState(t, snapshot))
+}
@@ -0,0 +1,435 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dynamicresources
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	apiv1 "k8s.io/api/core/v1"
+	resourceapi "k8s.io/api/resource/v1alpha2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/component-helpers/scheduling/corev1/nodeaffinity"
+	namedresourcesmodel "k8s.io/kubernetes/pkg/scheduler/framework/plugins/dynamicresources/structured/namedresources"
+)
+
+const (
+	resourceClassParametersKind = "ResourceClassParameters"
+	resourceClaimParametersKind = "ResourceClaimParameters"
+)
+
+// claimAllocator allocates a ResourceClaim using structured parameters.
+type claimAllocator struct {
+	driverName string
+	shareable  bool
+	drivers    []*driverAllocator
+}
+
+// driverAllocator allocates the requests of a ResourceClaim for one driver.
+type driverAllocator struct {
+	driverName        string
+	controller        *namedresourcesmodel.Controller
+	classParameters   runtime.RawExtension
+	claimParameters   runtime.RawExtension
+	requestParameters []runtime.RawExtension
+	requests          []*resourceapi.NamedResourcesRequest
+}
+
+// CheckPod returns an error if the ResourceClaims of the pod can't be
+// allocated on the node, or are allocated on another node.
+func (s *Snapshot) CheckPod(pod *apiv1.Pod, node *apiv1.Node) error {
+	if s == nil || len(pod.Spec.ResourceClaims) == 0 {
+		return nil
+	}
+	_, err := s.allocate(pod, node)
+	return err
+}
+
+// AllocatePod allocates the ResourceClaims of the pod on the node, unless
+// they're allocated already, and reserves them for the pod. ResourceClaims
+// allocated on another node for the first pod consuming them are allocated
+// again if they're only reserved for the pod, as the pod is moved.
+func (s *Snapshot) AllocatePod(pod *apiv1.Pod, node *apiv1.Node) error {
+	if s == nil || len(pod.Spec.ResourceClaims) == 0 {
+		return nil
+	}
+	claims, err := s.allocate(pod, node)
+	if err != nil {
+		return err
+	}
+	state := s.writableState()
+	for _, claim := range claims {
+		state.setClaim(claim)
+	}
+	return nil
+}
+
+// DeallocatePod removes reservations of ResourceClaims for the pod. Claims
+// which aren't reserved for any pod anymore are deallocated, unless they were
+// allocated immediately.
+func (s *Snapshot) DeallocatePod(pod *apiv1.Pod) {
+	if s == nil || pod == nil {
+		return
+	}
+	claimKeys := s.state.podClaims[podKey(pod.Namespace, pod.UID)]
+	if len(claimKeys) == 0 {
+		return
+	}
+	state := s.writableState()
+	for _, key := range claimKeys {
+		claim := state.claims[key]
+		index := -1
+		for i, consumer := range claim.Status.ReservedFor {
+			if consumer.Resource == "pods" && consumer.UID == pod.UID {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			continue
+		}
+		claim = claim.DeepCopy()
+		claim.Status.ReservedFor = append(claim.Status.ReservedFor[:index], claim.Status.ReservedFor[index+1:]...)
+		if len(claim.Status.ReservedFor) == 0 && claim.Spec.AllocationMode != resourceapi.AllocationModeImmediate {
+			claim.Status.Allocation = nil
+			claim.Status.DriverName = ""
+		}
+		state.setClaim(claim)
+	}
+}
+
+// allocate returns the ResourceClaims of the pod allocated on the node and
+// reserved for the pod, without modifying the snapshot.
+func (s *Snapshot) allocate(pod *apiv1.Pod, node *apiv1.Node) ([]*resourceapi.ResourceClaim, error) {
+	names, err := podClaimNames(pod)
+	if err != nil {
+		return nil, err
+	}
+	var result, toAllocate []*resourceapi.ResourceClaim
+	reallocated := map[string]bool{}
+	for _, name := range names {
+		claim := s.Claim(pod.Namespace, name)
+		if claim == nil {
+			return nil, fmt.Errorf("ResourceClaim %s/%s of pod %s not found", pod.Namespace, name, pod.Name)
+		}
+		if claim.Status.DeallocationRequested {
+			return nil, fmt.Errorf("ResourceClaim %s/%s is being deallocated", claim.Namespace, claim.Name)
+		}
+		if claim.Status.Allocation != nil {
+			if availableOn(claim.Status.Allocation, node) {
+				if !claim.Status.Allocation.Shareable && !reservedOnlyFor(claim, pod) {
+					return nil, fmt.Errorf("ResourceClaim %s/%s is in use by another pod", claim.Namespace, claim.Name)
+				}
+				result = append(result, reserve(claim, pod))
+				continue
+			}
+			if claim.Spec.AllocationMode == resourceapi.AllocationModeImmediate || !reservedOnlyFor(claim, pod) {
+				return nil, fmt.Errorf("ResourceClaim %s/%s is allocated on another node", claim.Namespace, claim.Name)
+			}
+		}
+		toAllocate = append(toAllocate, claim)
+		reallocated[claimKey(claim.Namespace, claim.Name)] = true
+	}
+	if len(toAllocate) == 0 {
+		return result, nil
+	}
+
+	models := s.nodeModels(node.Name, reallocated)
+	for _, claim := range toAllocate {
+		allocator, err := s.claimAllocator(claim)
+		if err != nil {
+			return nil, err
+		}
+		allocation, err := allocator.allocate(models, node.Name)
+		if err != nil {
+			return nil, fmt.Errorf("can't allocate ResourceClaim %s/%s on node %s: %w", claim.Namespace, claim.Name, node.Name, err)
+		}
+		claim = claim.DeepCopy()
+		claim.Status.Allocation = allocation
+		claim.Status.DriverName = allocator.driverName
+		result = append(result, reserve(claim, pod))
+	}
+	return result, nil
+}
+
+// podClaimNames returns the names of the ResourceClaims the pod needs.
+func podClaimNames(pod *apiv1.Pod) ([]string, error) {
+	var names []string
+	for _, podClaim := range pod.Spec.ResourceClaims {
+		switch {
+		case podClaim.ResourceClaimName != nil:
+			names = append(names, *podClaim.ResourceClaimName)
+		case podClaim.ResourceClaimTemplateName != nil:
+			found := false
+			for _, status := range pod.Status.ResourceClaimStatuses {
+				if status.Name != podClaim.Name {
+					continue
+				}
+				found = true
+				// A nil name means that the pod doesn't need the claim.
+				if status.ResourceClaimName != nil {
+					names = append(names, *status.ResourceClaimName)
+				}
+				break
+			}
+			if !found {
+				return nil, fmt.Errorf("ResourceClaim %s of pod %s/%s wasn't created yet", podClaim.Name, pod.Namespace, pod.Name)
+			}
+		default:
+			return nil, fmt.Errorf("ResourceClaim %s of pod %s/%s has no source", podClaim.Name, pod.Namespace, pod.Name)
+		}
+	}
+	return names, nil
+}
+
+func reservedFor(claim *resourceapi.ResourceClaim, pod *apiv1.Pod) bool {
+	for _, consumer := range claim.Status.ReservedFor {
+		if consumer.Resource == "pods" && consumer.UID == pod.UID {
+			return true
+		}
+	}
+	return false
+}
+
+func reservedOnlyFor(claim *resourceapi.ResourceClaim, pod *apiv1.Pod) bool {
+	return len(claim.Status.ReservedFor) == 0 || (len(claim.Status.ReservedFor) == 1 && reservedFor(claim, pod))
+}
+
+// reserve returns the claim reserved for the pod, a copy if it wasn't yet.
+func reserve(claim *resourceapi.ResourceClaim, pod *apiv1.Pod) *resourceapi.ResourceClaim {
+	if reservedFor(claim, pod) {
+		return claim
+	}
+	claim = claim.DeepCopy()
+	claim.Status.ReservedFor = append(claim.Status.ReservedFor, resourceapi.ResourceClaimConsumerReference{
+		Resource: "pods",
+		Name:     pod.Name,
+		UID:      pod.UID,
+	})
+	return claim
+}
+
+func availableOn(allocation *resourceapi.AllocationResult, node *apiv1.Node) bool {
+	if allocation.AvailableOnNodes == nil {
+		return true
+	}
+	selector, err := nodeaffinity.NewNodeSelector(allocation.AvailableOnNodes)
+	if err != nil {
+		return false
+	}
+	return selector.Match(node)
+}
+
+// nodeModels returns the named resources of the node per driver, with the
+// ones allocated to ResourceClaims marked, except for the excluded claims.
+func (s *Snapshot) nodeModels(nodeName string, excludedClaims map[string]bool) map[string]*namedresourcesmodel.Model {
+	models := map[string]*namedresourcesmodel.Model{}
+	for _, slice := range s.state.resourceSlices[nodeName] {
+		model, found := models[slice.DriverName]
+		if !found {
+			model = &namedresourcesmodel.Model{}
+			models[slice.DriverName] = model
+		}
+		namedresourcesmodel.AddResources(model, slice.NamedResources)
+	}
+	for key, claim := range s.state.claims {
+		if excludedClaims[key] || claim.Status.Allocation == nil {
+			continue
+		}
+		for _, handle := range claim.Status.Allocation.ResourceHandles {
+			model, found := models[handle.DriverName]
+			if !found || handle.StructuredData == nil || handle.StructuredData.NodeName != nodeName {
+				continue
+			}
+			for _, result := range handle.StructuredData.Results {
+				namedresourcesmodel.AddAllocation(model, result.NamedResources)
+			}
+		}
+	}
+	return models
+}
+
+// claimAllocator returns the allocator of the claim, cached by claim UID.
+func (s *Snapshot) claimAllocator(claim *resourceapi.ResourceClaim) (*claimAllocator, error) {
+	if cached, found := s.objects.allocators.Load(claim.UID); found && claim.UID != "" {
+		return cached.(*claimAllocator), nil
+	}
+	allocator, err := s.newClaimAllocator(claim)
+	if err != nil {
+		return nil, err
+	}
+	if claim.UID != "" {
+		s.objects.allocators.Store(claim.UID, allocator)
+	}
+	return allocator, nil
+}
+
+func (s *Snapshot) newClaimAllocator(claim *resourceapi.ResourceClaim) (*claimAllocator, error) {
+	class := s.objects.classes[claim.Spec.ResourceClassName]
+	if class == nil {
+		return nil, fmt.Errorf("ResourceClass %s of ResourceClaim %s/%s not found", claim.Spec.ResourceClassName, claim.Namespace, claim.Name)
+	}
+	if class.StructuredParameters == nil || !*class.StructuredParameters {
+		return nil, fmt.Errorf("ResourceClass %s doesn't use structured parameters, allocation of ResourceClaim %s/%s can't be simulated", class.Name, claim.Namespace, claim.Name)
+	}
+	classParameters, err := s.classParameters(class)
+	if err != nil {
+		return nil, err
+	}
+	claimParameters, err := s.claimParameters(claim)
+	if err != nil {
+		return nil, err
+	}
+
+	allocator := &claimAllocator{driverName: class.DriverName, shareable: claimParameters.Shareable}
+	drivers := map[string]*driverAllocator{}
+	for i, driverRequests := range claimParameters.DriverRequests {
+		for j, request := range driverRequests.Requests {
+			if request.NamedResources == nil {
+				return nil, fmt.Errorf("ResourceClaimParameters %s/%s: driverRequests[%d].requests[%d]: no supported structured parameters found", claimParameters.Namespace, claimParameters.Name, i, j)
+			}
+			driver, found := drivers[driverRequests.DriverName]
+			if !found {
+				driver = &driverAllocator{driverName: driverRequests.DriverName, claimParameters: driverRequests.VendorParameters}
+				drivers[driverRequests.DriverName] = driver
+				allocator.drivers = append(allocator.drivers, driver)
+			}
+			driver.requests = append(driver.requests, request.NamedResources)
+			driver.requestParameters = append(driver.requestParameters, request.VendorParameters)
+		}
+	}
+	sort.Slice(allocator.drivers, func(i, j int) bool {
+		return allocator.drivers[i].driverName < allocator.drivers[j].driverName
+	})
+	for _, driver := range allocator.drivers {
+		var filter *resourceapi.NamedResourcesFilter
+		if classParameters != nil {
+			for _, f := range classParameters.Filters {
+				if f.DriverName == driver.driverName && f.NamedResources != nil {
+					filter = f.NamedResources
+					break
+				}
+			}
+			for _, p := range classParameters.VendorParameters {
+				if p.DriverName == driver.driverName {
+					driver.classParameters = p.Parameters
+					break
+				}
+			}
+		}
+		driver.controller, err = namedresourcesmodel.NewClaimController(filter, driver.requests)
+		if err != nil {
+			return nil, fmt.Errorf("ResourceClaim %s/%s: %w", claim.Namespace, claim.Name, err)
+		}
+	}
+	return allocator, nil
+}
+
+// classParameters returns the parameters the class refers to, nil if none.
+func (s *Snapshot) classParameters(class *resourceapi.ResourceClass) (*resourceapi.ResourceClassParameters, error) {
+	ref := class.ParametersRef
+	if ref == nil {
+		return nil, nil
+	}
+	for _, parameters := range s.objects.classParameters {
+		if parameters.Namespace != ref.Namespace {
+			continue
+		}
+		if ref.APIGroup == resourceapi.GroupName && ref.Kind == resourceClassParametersKind {
+			if parameters.Name == ref.Name {
+				return parameters, nil
+			}
+		} else if generated := parameters.GeneratedFrom; generated != nil &&
+			generated.APIGroup == ref.APIGroup && generated.Kind == ref.Kind && generated.Name == ref.Name {
+			return parameters, nil
+		}
+	}
+	return nil, fmt.Errorf("parameters of ResourceClass %s not found", class.Name)
+}
+
+// claimParameters returns the parameters the claim refers to, empty ones if
+// none.
+func (s *Snapshot) claimParameters(claim *resourceapi.ResourceClaim) (*resourceapi.ResourceClaimParameters, error) {
+	ref := claim.Spec.ParametersRef
+	if ref == nil {
+		return &resourceapi.ResourceClaimParameters{}, nil
+	}
+	for _, parameters := range s.objects.claimParameters {
+		if parameters.Namespace != claim.Namespace {
+			continue
+		}
+		if ref.APIGroup == resourceapi.GroupName && ref.Kind == resourceClaimParametersKind {
+			if parameters.Name == ref.Name {
+				return parameters, nil
+			}
+		} else if generated := parameters.GeneratedFrom; generated != nil &&
+			generated.APIGroup == ref.APIGroup && generated.Kind == ref.Kind && generated.Name == ref.Name {
+			return parameters, nil
+		}
+	}
+	return nil, fmt.Errorf("parameters of ResourceClaim %s/%s not found", claim.Namespace, claim.Name)
+}
+
+// allocate allocates the claim on the node, marking the allocated named
+// resources in the models.
+func (a *claimAllocator) allocate(models map[string]*namedresourcesmodel.Model, nodeName string) (*resourceapi.AllocationResult, error) {
+	allocation := &resourceapi.AllocationResult{
+		Shareable: a.shareable,
+		AvailableOnNodes: &apiv1.NodeSelector{
+			NodeSelectorTerms: []apiv1.NodeSelectorTerm{{
+				// Nodes created in simulations don't necessarily have
+				// hostname labels matching their names.
+				MatchFields: []apiv1.NodeSelectorRequirement{
+					{Key: metav1.ObjectNameField, Operator: apiv1.NodeSelectorOpIn, Values: []string{nodeName}},
+				},
+			}},
+		},
+	}
+	for _, driver := range a.drivers {
+		model, found := models[driver.driverName]
+		if !found {
+			model = &namedresourcesmodel.Model{}
+			models[driver.driverName] = model
+		}
+		results, err := driver.controller.Allocate(context.Background(), *model)
+		if err != nil {
+			return nil, fmt.Errorf("resources of driver %s: %w", driver.driverName, err)
+		}
+		handle := resourceapi.ResourceHandle{
+			DriverName: driver.driverName,
+			StructuredData: &resourceapi.StructuredResourceHandle{
+				VendorClassParameters: driver.classParameters,
+				VendorClaimParameters: driver.claimParameters,
+				NodeName:              nodeName,
+			},
+		}
+		for i, result := range results {
+			if result == nil {
+				continue
+			}
+			namedresourcesmodel.AddAllocation(model, result)
+			handle.StructuredData.Results = append(handle.StructuredData.Results, resourceapi.DriverAllocationResult{
+				VendorRequestParameters: driver.requestParameters[i],
+				AllocationResultModel:   resourceapi.AllocationResultModel{NamedResources: result},
+			})
+		}
+		allocation.ResourceHandles = append(allocation.ResourceHandles, handle)
+	}
+	return allocation, nil
+}
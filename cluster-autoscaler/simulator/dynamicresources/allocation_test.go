@@ -0,0 +1,315 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dynamicresources
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	resourceapi "k8s.io/api/resource/v1alpha2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+)
+
+const (
+	testDriver = "gpu.example.com"
+	testClass  = "gpu"
+)
+
+func buildTestSlice(nodeName string, gpus int) *resourceapi.ResourceSlice {
+	slice := &resourceapi.ResourceSlice{
+		ObjectMeta: metav1.ObjectMeta{Name: nodeName + "-gpus"},
+		NodeName:   nodeName,
+		DriverName: testDriver,
+		ResourceModel: resourceapi.ResourceModel{
+			NamedResources: &resourceapi.NamedResourcesResources{},
+		},
+	}
+	for i := 0; i < gpus; i++ {
+		slice.NamedResources.Instances = append(slice.NamedResources.Instances, resourceapi.NamedResourcesInstance{Name: fmt.Sprintf("gpu-%d", i)})
+	}
+	return slice
+}
+
+func buildTestClass(structured bool) *resourceapi.ResourceClass {
+	return &resourceapi.ResourceClass{
+		ObjectMeta:           metav1.ObjectMeta{Name: testClass},
+		DriverName:           testDriver,
+		StructuredParameters: &structured,
+	}
+}
+
+// buildTestClaimParameters returns parameters requesting the given number of GPUs.
+func buildTestClaimParameters(name string, gpus int) *resourceapi.ResourceClaimParameters {
+	parameters := &resourceapi.ResourceClaimParameters{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		DriverRequests: []resourceapi.DriverRequests{{
+			DriverName: testDriver,
+		}},
+	}
+	for i := 0; i < gpus; i++ {
+		parameters.DriverRequests[0].Requests = append(parameters.DriverRequests[0].Requests, resourceapi.ResourceRequest{
+			ResourceRequestModel: resourceapi.ResourceRequestModel{
+				NamedResources: &resourceapi.NamedResourcesRequest{Selector: "true"},
+			},
+		})
+	}
+	return parameters
+}
+
+func buildTestClaim(name, parametersName string) *resourceapi.ResourceClaim {
+	return &resourceapi.ResourceClaim{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, UID: types.UID(name)},
+		Spec: resourceapi.ResourceClaimSpec{
+			ResourceClassName: testClass,
+			ParametersRef: &resourceapi.ResourceClaimParametersReference{
+				APIGroup: resourceapi.GroupName,
+				Kind:     resourceClaimParametersKind,
+				Name:     parametersName,
+			},
+		},
+	}
+}
+
+func withClaims(claimNames ...string) func(*apiv1.Pod) {
+	return func(pod *apiv1.Pod) {
+		for _, claimName := range claimNames {
+			name := claimName
+			pod.Spec.ResourceClaims = append(pod.Spec.ResourceClaims, apiv1.PodResourceClaim{
+				Name:              name,
+				ResourceClaimName: &name,
+			})
+		}
+	}
+}
+
+func buildTestSnapshot(class *resourceapi.ResourceClass) *Snapshot {
+	return NewSnapshotFromObjects(
+		[]*resourceapi.ResourceSlice{buildTestSlice("n1", 2), buildTestSlice("n2", 1)},
+		[]*resourceapi.ResourceClaim{
+			buildTestClaim("one-gpu-a", "one-gpu"),
+			buildTestClaim("one-gpu-b", "one-gpu"),
+			buildTestClaim("two-gpus", "two-gpus"),
+		},
+		[]*resourceapi.ResourceClass{class},
+		nil,
+		[]*resourceapi.ResourceClaimParameters{
+			buildTestClaimParameters("one-gpu", 1),
+			buildTestClaimParameters("two-gpus", 2),
+		},
+	)
+}
+
+func TestAllocatePod(t *testing.T) {
+	n1 := BuildTestNode("n1", 1000, 1000)
+	n2 := BuildTestNode("n2", 1000, 1000)
+	n3 := BuildTestNode("n3", 1000, 1000)
+
+	testCases := []struct {
+		name         string
+		class        *resourceapi.ResourceClass
+		allocated    []*apiv1.Pod
+		allocatedOn  *apiv1.Node
+		pod          *apiv1.Pod
+		node         *apiv1.Node
+		wantErr      bool
+		wantReserved []string
+	}{
+		{
+			name: "pod without claims",
+			pod:  BuildTestPod("p", 100, 100),
+			node: n3,
+		},
+		{
+			name:         "claim allocated on node with devices",
+			pod:          BuildTestPod("p", 100, 100, withClaims("two-gpus")),
+			node:         n1,
+			wantReserved: []string{"two-gpus"},
+		},
+		{
+			name:    "node without enough devices",
+			pod:     BuildTestPod("p", 100, 100, withClaims("two-gpus")),
+			node:    n2,
+			wantErr: true,
+		},
+		{
+			name:    "node without devices",
+			pod:     BuildTestPod("p", 100, 100, withClaims("one-gpu-a")),
+			node:    n3,
+			wantErr: true,
+		},
+		{
+			name:        "devices allocated to other claims",
+			allocated:   []*apiv1.Pod{BuildTestPod("other", 100, 100, withClaims("one-gpu-a"))},
+			allocatedOn: n2,
+			pod:         BuildTestPod("p", 100, 100, withClaims("one-gpu-b")),
+			node:        n2,
+			wantErr:     true,
+		},
+		{
+			name:         "devices left after allocating other claims",
+			allocated:    []*apiv1.Pod{BuildTestPod("other", 100, 100, withClaims("one-gpu-a"))},
+			allocatedOn:  n1,
+			pod:          BuildTestPod("p", 100, 100, withClaims("one-gpu-b")),
+			node:         n1,
+			wantReserved: []string{"one-gpu-b"},
+		},
+		{
+			name:        "claim in use by another pod",
+			allocated:   []*apiv1.Pod{BuildTestPod("other", 100, 100, withClaims("one-gpu-a"))},
+			allocatedOn: n1,
+			pod:         BuildTestPod("p", 100, 100, withClaims("one-gpu-a")),
+			node:        n1,
+			wantErr:     true,
+		},
+		{
+			name:    "claim not found",
+			pod:     BuildTestPod("p", 100, 100, withClaims("missing")),
+			node:    n1,
+			wantErr: true,
+		},
+		{
+			name:    "class without structured parameters",
+			class:   buildTestClass(false),
+			pod:     BuildTestPod("p", 100, 100, withClaims("one-gpu-a")),
+			node:    n1,
+			wantErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			class := tc.class
+			if class == nil {
+				class = buildTestClass(true)
+			}
+			snapshot := buildTestSnapshot(class)
+			for _, pod := range tc.allocated {
+				assert.NoError(t, snapshot.AllocatePod(pod, tc.allocatedOn))
+			}
+
+			err := snapshot.CheckPod(tc.pod, tc.node)
+			assert.Equal(t, tc.wantErr, err != nil, "CheckPod: %v", err)
+			err = snapshot.AllocatePod(tc.pod, tc.node)
+			assert.Equal(t, tc.wantErr, err != nil, "AllocatePod: %v", err)
+			for _, name := range tc.wantReserved {
+				claim := snapshot.Claim("default", name)
+				assert.NotNil(t, claim.Status.Allocation)
+				assert.True(t, availableOn(claim.Status.Allocation, tc.node))
+				assert.True(t, reservedFor(claim, tc.pod))
+			}
+		})
+	}
+}
+
+func TestDeallocatePod(t *testing.T) {
+	n1 := BuildTestNode("n1", 1000, 1000)
+	n2 := BuildTestNode("n2", 1000, 1000)
+	snapshot := buildTestSnapshot(buildTestClass(true))
+	pod := BuildTestPod("p", 100, 100, withClaims("one-gpu-a"))
+
+	assert.NoError(t, snapshot.AllocatePod(pod, n2))
+	// The only device of n2 is taken.
+	assert.Error(t, snapshot.CheckPod(BuildTestPod("other", 100, 100, withClaims("one-gpu-b")), n2))
+	// The claim reserved only for the pod is allocated again when the pod is moved.
+	assert.NoError(t, snapshot.CheckPod(pod, n1))
+
+	// Reservations are matched by UID, not by the name of a recreated pod.
+	recreated := pod.DeepCopy()
+	recreated.UID = "p-recreated"
+	snapshot.DeallocatePod(recreated)
+	assert.NotNil(t, snapshot.Claim("default", "one-gpu-a").Status.Allocation)
+
+	snapshot.DeallocatePod(pod)
+	claim := snapshot.Claim("default", "one-gpu-a")
+	assert.Nil(t, claim.Status.Allocation)
+	assert.Empty(t, claim.Status.ReservedFor)
+	assert.NoError(t, snapshot.CheckPod(BuildTestPod("other", 100, 100, withClaims("one-gpu-b")), n2))
+}
+
+func TestDeallocatePodReservedInCluster(t *testing.T) {
+	pod := BuildTestPod("p", 100, 100, withClaims("shared"))
+	other := BuildTestPod("q", 100, 100, withClaims("shared"))
+	claim := buildTestClaim("shared", "one-gpu")
+	claim.Status.Allocation = &resourceapi.AllocationResult{Shareable: true}
+	claim.Status.DriverName = testDriver
+	claim.Status.ReservedFor = []resourceapi.ResourceClaimConsumerReference{
+		{Resource: "pods", Name: pod.Name, UID: pod.UID},
+		{Resource: "pods", Name: other.Name, UID: other.UID},
+	}
+	snapshot := NewSnapshotFromObjects(nil, []*resourceapi.ResourceClaim{claim}, nil, nil, nil)
+
+	snapshot.DeallocatePod(pod)
+	assert.NotNil(t, snapshot.Claim("default", "shared").Status.Allocation)
+	assert.Equal(t, []resourceapi.ResourceClaimConsumerReference{{Resource: "pods", Name: other.Name, UID: other.UID}}, snapshot.Claim("default", "shared").Status.ReservedFor)
+	snapshot.DeallocatePod(other)
+	assert.Nil(t, snapshot.Claim("default", "shared").Status.Allocation)
+	assert.Empty(t, snapshot.Claim("default", "shared").Status.ReservedFor)
+}
+
+func TestSnapshotForkRevertCommit(t *testing.T) {
+	n1 := BuildTestNode("n1", 1000, 1000)
+	snapshot := buildTestSnapshot(buildTestClass(true))
+	pod := BuildTestPod("p", 100, 100, withClaims("one-gpu-a"))
+
+	snapshot.Fork()
+	assert.NoError(t, snapshot.AllocatePod(pod, n1))
+	snapshot.CopyResourceSlices("n1", "new")
+	assert.Len(t, snapshot.ResourceSlices("new"), 1)
+	snapshot.Revert()
+	assert.Nil(t, snapshot.Claim("default", "one-gpu-a").Status.Allocation)
+	assert.Empty(t, snapshot.ResourceSlices("new"))
+
+	snapshot.Fork()
+	assert.NoError(t, snapshot.AllocatePod(pod, n1))
+	snapshot.Commit()
+	snapshot.Revert()
+	assert.NotNil(t, snapshot.Claim("default", "one-gpu-a").Status.Allocation)
+}
+
+func TestSnapshotClone(t *testing.T) {
+	n1 := BuildTestNode("n1", 1000, 1000)
+	snapshot := buildTestSnapshot(buildTestClass(true))
+	clone := snapshot.Clone()
+
+	assert.NoError(t, clone.AllocatePod(BuildTestPod("p", 100, 100, withClaims("two-gpus")), n1))
+	clone.RemoveNode("n2")
+	assert.NotNil(t, clone.Claim("default", "two-gpus").Status.Allocation)
+	assert.Nil(t, snapshot.Claim("default", "two-gpus").Status.Allocation)
+	assert.Empty(t, clone.ResourceSlices("n2"))
+	assert.Len(t, snapshot.ResourceSlices("n2"), 1)
+
+	// Modifying the snapshot doesn't affect the clone either.
+	assert.NoError(t, snapshot.AllocatePod(BuildTestPod("q", 100, 100, withClaims("one-gpu-a")), n1))
+	assert.Nil(t, clone.Claim("default", "one-gpu-a").Status.Allocation)
+}
+
+func TestNilSnapshot(t *testing.T) {
+	var snapshot *Snapshot
+	pod := BuildTestPod("p", 100, 100, withClaims("one-gpu-a"))
+	node := BuildTestNode("n1", 1000, 1000)
+
+	snapshot.Fork()
+	assert.NoError(t, snapshot.CheckPod(pod, node))
+	assert.NoError(t, snapshot.AllocatePod(pod, node))
+	snapshot.DeallocatePod(pod)
+	snapshot.CopyResourceSlices("n1", "n2")
+	snapshot.Revert()
+	assert.Nil(t, snapshot.Clone())
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dynamicresources
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	resourcelisters "k8s.io/client-go/listers/resource/v1alpha2"
+)
+
+// Provider builds snapshots of the Dynamic Resource Allocation objects in
+// the cluster.
+type Provider struct {
+	resourceSlices  resourcelisters.ResourceSliceLister
+	claims          resourcelisters.ResourceClaimLister
+	classes         resourcelisters.ResourceClassLister
+	classParameters resourcelisters.ResourceClassParametersLister
+	claimParameters resourcelisters.ResourceClaimParametersLister
+}
+
+// NewProviderFromInformers returns a provider listing the objects with the
+// informers of the factory. The resource.k8s.io/v1alpha2 API has to be
+// enabled in the cluster.
+func NewProviderFromInformers(informerFactory informers.SharedInformerFactory) *Provider {
+	resourceInformers := informerFactory.Resource().V1alpha2()
+	return &Provider{
+		resourceSlices:  resourceInformers.ResourceSlices().Lister(),
+		claims:          resourceInformers.ResourceClaims().Lister(),
+		classes:         resourceInformers.ResourceClasses().Lister(),
+		classParameters: resourceInformers.ResourceClassParameters().Lister(),
+		claimParameters: resourceInformers.ResourceClaimParameters().Lister(),
+	}
+}
+
+// Snapshot returns a snapshot of the objects in the cluster.
+func (p *Provider) Snapshot() (*Snapshot, error) {
+	resourceSlices, err := p.resourceSlices.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ResourceSlices: %w", err)
+	}
+	claims, err := p.claims.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ResourceClaims: %w", err)
+	}
+	classes, err := p.classes.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ResourceClasses: %w", err)
+	}
+	classParameters, err := p.classParameters.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ResourceClassParameters: %w", err)
+	}
+	claimParameters, err := p.claimParameters.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ResourceClaimParameters: %w", err)
+	}
+	return NewSnapshotFromObjects(resourceSlices, claims, classes, classParameters, claimParameters), nil
+}
@@ -0,0 +1,285 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dynamicresources
+
+import (
+	"sync"
+
+	resourceapi "k8s.io/api/resource/v1alpha2"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Snapshot is the state of Dynamic Resource Allocation used in simulations:
+// the resources nodes publish in ResourceSlices, and the ResourceClaims of
+// pods with their allocations. Template nodes have ResourceSlices of their
+// own, copied to the nodes created from them during simulations.
+//
+// Like ClusterSnapshot, a Snapshot can be forked, and the changes done after
+// forking reverted or committed. Forking is O(1), the state is copied on the
+// first modification after forking. Clones of a Snapshot can be modified
+// independently, concurrently with each other.
+//
+// A nil Snapshot means that Dynamic Resource Allocation isn't simulated: all
+// methods can be called on it, ResourceClaims of pods are ignored.
+type Snapshot struct {
+	state *snapshotState
+	forks []*snapshotState
+	// objects are the objects which don't change during simulations. They
+	// are shared by all forks and clones of the snapshot.
+	objects *staticObjects
+}
+
+// snapshotState is the part of the snapshot modified by simulations. A state
+// is never modified after it's frozen, it's copied first.
+type snapshotState struct {
+	// resourceSlices are keyed by node name.
+	resourceSlices map[string][]*resourceapi.ResourceSlice
+	// claims are keyed by namespace/name.
+	claims map[string]*resourceapi.ResourceClaim
+	// podClaims are the keys of the claims reserved for a pod, keyed by the
+	// pod namespace/UID. The slices are never modified, only replaced.
+	podClaims map[string][]string
+	frozen    bool
+}
+
+type staticObjects struct {
+	// classes are keyed by name.
+	classes         map[string]*resourceapi.ResourceClass
+	classParameters []*resourceapi.ResourceClassParameters
+	claimParameters []*resourceapi.ResourceClaimParameters
+	// allocators caches claim allocators by claim UID, as compiling their
+	// CEL expressions is expensive.
+	allocators sync.Map
+}
+
+// NewSnapshot returns an empty snapshot.
+func NewSnapshot() *Snapshot {
+	return NewSnapshotFromObjects(nil, nil, nil, nil, nil)
+}
+
+// NewSnapshotFromObjects returns a snapshot with the given objects.
+func NewSnapshotFromObjects(
+	resourceSlices []*resourceapi.ResourceSlice,
+	claims []*resourceapi.ResourceClaim,
+	classes []*resourceapi.ResourceClass,
+	classParameters []*resourceapi.ResourceClassParameters,
+	claimParameters []*resourceapi.ResourceClaimParameters,
+) *Snapshot {
+	state := &snapshotState{
+		resourceSlices: map[string][]*resourceapi.ResourceSlice{},
+		claims:         map[string]*resourceapi.ResourceClaim{},
+		podClaims:      map[string][]string{},
+	}
+	for _, slice := range resourceSlices {
+		if slice.NodeName != "" {
+			state.resourceSlices[slice.NodeName] = append(state.resourceSlices[slice.NodeName], slice)
+		}
+	}
+	for _, claim := range claims {
+		state.setClaim(claim)
+	}
+	objects := &staticObjects{
+		classes:         map[string]*resourceapi.ResourceClass{},
+		classParameters: classParameters,
+		claimParameters: claimParameters,
+	}
+	for _, class := range classes {
+		objects.classes[class.Name] = class
+	}
+	return &Snapshot{state: state, objects: objects}
+}
+
+func claimKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+func podKey(namespace string, uid types.UID) string {
+	return namespace + "/" + string(uid)
+}
+
+func (state *snapshotState) clone() *snapshotState {
+	cloned := &snapshotState{
+		resourceSlices: make(map[string][]*resourceapi.ResourceSlice, len(state.resourceSlices)),
+		claims:         make(map[string]*resourceapi.ResourceClaim, len(state.claims)),
+		podClaims:      make(map[string][]string, len(state.podClaims)),
+	}
+	for nodeName, slices := range state.resourceSlices {
+		cloned.resourceSlices[nodeName] = slices
+	}
+	for key, claim := range state.claims {
+		cloned.claims[key] = claim
+	}
+	for key, claimKeys := range state.podClaims {
+		cloned.podClaims[key] = claimKeys
+	}
+	return cloned
+}
+
+// setClaim adds or replaces the claim, keeping the claims reserved for pods indexed.
+func (state *snapshotState) setClaim(claim *resourceapi.ResourceClaim) {
+	key := claimKey(claim.Namespace, claim.Name)
+	if old, found := state.claims[key]; found {
+		for _, consumer := range old.Status.ReservedFor {
+			if consumer.Resource == "pods" {
+				state.unindexPodClaim(podKey(old.Namespace, consumer.UID), key)
+			}
+		}
+	}
+	state.claims[key] = claim
+	for _, consumer := range claim.Status.ReservedFor {
+		if consumer.Resource == "pods" {
+			state.indexPodClaim(podKey(claim.Namespace, consumer.UID), key)
+		}
+	}
+}
+
+func (state *snapshotState) indexPodClaim(podKey, claimKey string) {
+	claimKeys := make([]string, 0, len(state.podClaims[podKey])+1)
+	for _, key := range state.podClaims[podKey] {
+		if key == claimKey {
+			return
+		}
+		claimKeys = append(claimKeys, key)
+	}
+	state.podClaims[podKey] = append(claimKeys, claimKey)
+}
+
+func (state *snapshotState) unindexPodClaim(podKey, claimKey string) {
+	var claimKeys []string
+	for _, key := range state.podClaims[podKey] {
+		if key != claimKey {
+			claimKeys = append(claimKeys, key)
+		}
+	}
+	if len(claimKeys) == 0 {
+		delete(state.podClaims, podKey)
+		return
+	}
+	state.podClaims[podKey] = claimKeys
+}
+
+// freeze freezes the state. Frozen states can be shared, they're only read.
+func (state *snapshotState) freeze() {
+	if !state.frozen {
+		state.frozen = true
+	}
+}
+
+// writableState returns the state, copying it first if it's frozen.
+func (s *Snapshot) writableState() *snapshotState {
+	if s.state.frozen {
+		s.state = s.state.clone()
+	}
+	return s.state
+}
+
+// Fork creates a fork of the snapshot state.
+// Time: O(1)
+func (s *Snapshot) Fork() {
+	if s == nil {
+		return
+	}
+	s.state.freeze()
+	s.forks = append(s.forks, s.state)
+}
+
+// Revert reverts the snapshot state to the moment of forking.
+// Time: O(1)
+func (s *Snapshot) Revert() {
+	if s == nil || len(s.forks) == 0 {
+		return
+	}
+	s.state = s.forks[len(s.forks)-1]
+	s.forks = s.forks[:len(s.forks)-1]
+}
+
+// Commit commits changes done after forking.
+// Time: O(1)
+func (s *Snapshot) Commit() {
+	if s == nil || len(s.forks) == 0 {
+		return
+	}
+	s.forks = s.forks[:len(s.forks)-1]
+}
+
+// Clone returns an unforked snapshot with the current state of this one,
+// which can be modified independently.
+// Time: O(1)
+func (s *Snapshot) Clone() *Snapshot {
+	if s == nil {
+		return nil
+	}
+	s.state.freeze()
+	return &Snapshot{state: s.state, objects: s.objects}
+}
+
+// ResourceSlices returns the ResourceSlices of the node.
+func (s *Snapshot) ResourceSlices(nodeName string) []*resourceapi.ResourceSlice {
+	if s == nil {
+		return nil
+	}
+	return s.state.resourceSlices[nodeName]
+}
+
+// SetResourceSlices replaces the ResourceSlices of the node, e.g. of a
+// template node.
+func (s *Snapshot) SetResourceSlices(nodeName string, slices []*resourceapi.ResourceSlice) {
+	if s == nil {
+		return
+	}
+	state := s.writableState()
+	if len(slices) == 0 {
+		delete(state.resourceSlices, nodeName)
+		return
+	}
+	state.resourceSlices[nodeName] = slices
+}
+
+// CopyResourceSlices sets ResourceSlices of a node to copies of ResourceSlices
+// of another node, e.g. of the template node the node was created from.
+func (s *Snapshot) CopyResourceSlices(fromNodeName, toNodeName string) {
+	slices := s.ResourceSlices(fromNodeName)
+	if len(slices) == 0 {
+		return
+	}
+	copies := make([]*resourceapi.ResourceSlice, 0, len(slices))
+	for _, slice := range slices {
+		sliceCopy := slice.DeepCopy()
+		sliceCopy.Name = slice.Name + "-" + toNodeName
+		sliceCopy.NodeName = toNodeName
+		copies = append(copies, sliceCopy)
+	}
+	s.SetResourceSlices(toNodeName, copies)
+}
+
+// RemoveNode removes the ResourceSlices of the node.
+func (s *Snapshot) RemoveNode(nodeName string) {
+	if s == nil {
+		return
+	}
+	if _, found := s.state.resourceSlices[nodeName]; found {
+		delete(s.writableState().resourceSlices, nodeName)
+	}
+}
+
+// Claim returns the ResourceClaim, nil if it's not in the snapshot.
+func (s *Snapshot) Claim(namespace, name string) *resourceapi.ResourceClaim {
+	if s == nil {
+		return nil
+	}
+	return s.state.claims[claimKey(namespace, name)]
+}
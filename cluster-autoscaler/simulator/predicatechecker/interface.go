@@ -29,3 +29,11 @@ type PredicateChecker interface {
 	FitsAnyNodeMatching(clusterSnapshot clustersnapshot.ClusterSnapshot, pod *apiv1.Pod, nodeMatches func(*schedulerframework.NodeInfo) bool) (string, error)
 	CheckPredicates(clusterSnapshot clustersnapshot.ClusterSnapshot, pod *apiv1.Pod, nodeName string) *PredicateError
 }
+
+// CopyablePredicateChecker is a PredicateChecker which can create copies of
+// itself. Predicate checkers aren't safe for concurrent use, but a checker and
+// its copies can be used concurrently.
+type CopyablePredicateChecker interface {
+	PredicateChecker
+	Copy() (PredicateChecker, error)
+}
@@ -33,6 +33,10 @@ import (
 	schedulerframeworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
 )
 
+// dynamicResourcesPredicateName is the name of the predicate checking that
+// ResourceClaims of pods can be allocated on nodes.
+const dynamicResourcesPredicateName = "DynamicResources"
+
 // SchedulerBasedPredicateChecker checks whether all required predicates pass for given Pod and Node.
 // The verification is done by calling out to scheduler code.
 type SchedulerBasedPredicateChecker struct {
@@ -41,6 +45,8 @@ type SchedulerBasedPredicateChecker struct {
 	nodeLister             v1listers.NodeLister
 	podLister              v1listers.PodLister
 	lastIndex              int
+	informerFactory        informers.SharedInformerFactory
+	schedConfig            *config.KubeSchedulerConfiguration
 }
 
 // NewSchedulerBasedPredicateChecker builds scheduler based PredicateChecker.
@@ -73,11 +79,19 @@ func NewSchedulerBasedPredicateChecker(informerFactory informers.SharedInformerF
 	checker := &SchedulerBasedPredicateChecker{
 		framework:              framework,
 		delegatingSharedLister: sharedLister,
+		informerFactory:        informerFactory,
+		schedConfig:            schedConfig,
 	}
 
 	return checker, nil
 }
 
+// Copy returns a new SchedulerBasedPredicateChecker with the same configuration,
+// backed by its own scheduler framework.
+func (p *SchedulerBasedPredicateChecker) Copy() (PredicateChecker, error) {
+	return NewSchedulerBasedPredicateChecker(p.informerFactory, p.schedConfig)
+}
+
 // FitsAnyNode checks if the given pod can be placed on any of the given nodes.
 func (p *SchedulerBasedPredicateChecker) FitsAnyNode(clusterSnapshot clustersnapshot.ClusterSnapshot, pod *apiv1.Pod) (string, error) {
 	return p.FitsAnyNodeMatching(clusterSnapshot, pod, func(*schedulerframework.NodeInfo) bool {
@@ -127,6 +141,9 @@ func (p *SchedulerBasedPredicateChecker) FitsAnyNodeMatching(clusterSnapshot clu
 
 		filterStatus := p.framework.RunFilterPlugins(context.TODO(), state, pod, nodeInfo)
 		if filterStatus.IsSuccess() {
+			if err := clusterSnapshot.DynamicResources().CheckPod(pod, nodeInfo.Node()); err != nil {
+				continue
+			}
 			p.lastIndex = (p.lastIndex + i + 1) % len(nodeInfosList)
 			return nodeInfo.Node().Name, nil
 		}
@@ -181,6 +198,10 @@ func (p *SchedulerBasedPredicateChecker) CheckPredicates(clusterSnapshot cluster
 			p.buildDebugInfo(filterName, nodeInfo))
 	}
 
+	if err := clusterSnapshot.DynamicResources().CheckPod(pod, nodeInfo.Node()); err != nil {
+		return NewPredicateError(NotSchedulablePredicateError, dynamicResourcesPredicateName, err.Error(), nil, emptyString)
+	}
+
 	return nil
 }
 
@@ -39,4 +39,43 @@ type Backoff interface {
 	RemoveBackoff(nodeGroup cloudprovider.NodeGroup, nodeInfo *schedulerframework.NodeInfo)
 	// RemoveStaleBackoffData removes stale backoff data.
 	RemoveStaleBackoffData(currentTime time.Time)
+	// RegisterInterruption records an interruption of the node group, e.g. a reclaimed spot
+	// instance, and backs the node group off if it was interrupted too often. Returns the
+	// resulting backoff status.
+	RegisterInterruption(nodeGroup cloudprovider.NodeGroup, nodeInfo *schedulerframework.NodeInfo, errorInfo cloudprovider.InstanceErrorInfo, currentTime time.Time) Status
+	// InterruptionCount returns the number of recent interruptions of the given node group.
+	InterruptionCount(nodeGroup cloudprovider.NodeGroup, nodeInfo *schedulerframework.NodeInfo, currentTime time.Time) int
+}
+
+// InterruptionPolicy configures how interruptions of a node group affect its backoff.
+type InterruptionPolicy struct {
+	// Window is how long interruptions are remembered.
+	Window time.Duration
+	// Threshold is the number of interruptions within Window after which the node group
+	// is backed off. Zero disables backing off because of interruptions.
+	Threshold int
+}
+
+// Persistable is implemented by backoffs whose state can be saved and restored, so
+// that it survives restarts of Cluster Autoscaler.
+type Persistable interface {
+	// State returns the current state of the backoff.
+	State() State
+	// RestoreState restores a previously saved state. Entries already present in the
+	// backoff take precedence over the restored ones.
+	RestoreState(state State)
+}
+
+// State is the serializable state of a backoff, keyed by node group key.
+type State struct {
+	Entries       map[string]StateEntry  `json:"entries,omitempty"`
+	Interruptions map[string][]time.Time `json:"interruptions,omitempty"`
+}
+
+// StateEntry is the serializable backoff state of a single node group.
+type StateEntry struct {
+	Duration            time.Duration                   `json:"duration"`
+	BackoffUntil        time.Time                       `json:"backoffUntil"`
+	LastFailedExecution time.Time                       `json:"lastFailedExecution"`
+	ErrorInfo           cloudprovider.InstanceErrorInfo `json:"errorInfo"`
 }
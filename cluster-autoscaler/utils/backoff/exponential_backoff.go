@@ -31,6 +31,8 @@ type exponentialBackoff struct {
 	backoffResetTimeout    time.Duration
 	backoffInfo            map[string]exponentialBackoffInfo
 	nodeGroupKey           func(nodeGroup cloudprovider.NodeGroup) string
+	interruptionPolicy     InterruptionPolicy
+	interruptions          map[string][]time.Time
 }
 
 type exponentialBackoffInfo struct {
@@ -40,18 +42,37 @@ type exponentialBackoffInfo struct {
 	errorInfo           cloudprovider.InstanceErrorInfo
 }
 
-// NewExponentialBackoff creates an instance of exponential backoff.
+// NewExponentialBackoff creates an instance of exponential backoff. Interruptions are
+// remembered for backoffResetTimeout and don't cause node groups to be backed off.
 func NewExponentialBackoff(
 	initialBackoffDuration time.Duration,
 	maxBackoffDuration time.Duration,
 	backoffResetTimeout time.Duration,
 	nodeGroupKey func(nodeGroup cloudprovider.NodeGroup) string) Backoff {
+	return NewExponentialBackoffWithInterruptions(
+		initialBackoffDuration,
+		maxBackoffDuration,
+		backoffResetTimeout,
+		InterruptionPolicy{Window: backoffResetTimeout},
+		nodeGroupKey)
+}
+
+// NewExponentialBackoffWithInterruptions creates an instance of exponential backoff
+// which also backs off node groups interrupted too often.
+func NewExponentialBackoffWithInterruptions(
+	initialBackoffDuration time.Duration,
+	maxBackoffDuration time.Duration,
+	backoffResetTimeout time.Duration,
+	interruptionPolicy InterruptionPolicy,
+	nodeGroupKey func(nodeGroup cloudprovider.NodeGroup) string) Backoff {
 	return &exponentialBackoff{
 		maxBackoffDuration:     maxBackoffDuration,
 		initialBackoffDuration: initialBackoffDuration,
 		backoffResetTimeout:    backoffResetTimeout,
 		backoffInfo:            make(map[string]exponentialBackoffInfo),
 		nodeGroupKey:           nodeGroupKey,
+		interruptionPolicy:     interruptionPolicy,
+		interruptions:          make(map[string][]time.Time),
 	}
 }
 
@@ -66,6 +87,19 @@ func NewIdBasedExponentialBackoff(initialBackoffDuration time.Duration, maxBacko
 		})
 }
 
+// NewIdBasedExponentialBackoffWithInterruptions creates an instance of exponential backoff
+// with node group Id used as a key, which also backs off node groups interrupted too often.
+func NewIdBasedExponentialBackoffWithInterruptions(initialBackoffDuration time.Duration, maxBackoffDuration time.Duration, backoffResetTimeout time.Duration, interruptionPolicy InterruptionPolicy) Backoff {
+	return NewExponentialBackoffWithInterruptions(
+		initialBackoffDuration,
+		maxBackoffDuration,
+		backoffResetTimeout,
+		interruptionPolicy,
+		func(nodeGroup cloudprovider.NodeGroup) string {
+			return nodeGroup.Id()
+		})
+}
+
 // Backoff execution for the given node group. Returns time till execution is backed off.
 func (b *exponentialBackoff) Backoff(nodeGroup cloudprovider.NodeGroup, nodeInfo *schedulerframework.NodeInfo, errorInfo cloudprovider.InstanceErrorInfo, currentTime time.Time) time.Time {
 	duration := b.initialBackoffDuration
@@ -117,4 +151,85 @@ func (b *exponentialBackoff) RemoveStaleBackoffData(currentTime time.Time) {
 			delete(b.backoffInfo, key)
 		}
 	}
+	for key := range b.interruptions {
+		b.removeStaleInterruptions(key, currentTime)
+	}
+}
+
+// RegisterInterruption records an interruption of the node group and backs it off once
+// it was interrupted at least Threshold times within the interruption window.
+func (b *exponentialBackoff) RegisterInterruption(nodeGroup cloudprovider.NodeGroup, nodeInfo *schedulerframework.NodeInfo, errorInfo cloudprovider.InstanceErrorInfo, currentTime time.Time) Status {
+	key := b.nodeGroupKey(nodeGroup)
+	b.interruptions[key] = append(b.interruptions[key], currentTime)
+	b.removeStaleInterruptions(key, currentTime)
+	if b.interruptionPolicy.Threshold > 0 && len(b.interruptions[key]) >= b.interruptionPolicy.Threshold {
+		b.Backoff(nodeGroup, nodeInfo, errorInfo, currentTime)
+	}
+	return b.BackoffStatus(nodeGroup, nodeInfo, currentTime)
+}
+
+// InterruptionCount returns the number of interruptions of the node group within the interruption window.
+func (b *exponentialBackoff) InterruptionCount(nodeGroup cloudprovider.NodeGroup, nodeInfo *schedulerframework.NodeInfo, currentTime time.Time) int {
+	count := 0
+	for _, interruptionTime := range b.interruptions[b.nodeGroupKey(nodeGroup)] {
+		if !interruptionTime.Add(b.interruptionPolicy.Window).Before(currentTime) {
+			count++
+		}
+	}
+	return count
+}
+
+func (b *exponentialBackoff) removeStaleInterruptions(key string, currentTime time.Time) {
+	var recent []time.Time
+	for _, interruptionTime := range b.interruptions[key] {
+		if !interruptionTime.Add(b.interruptionPolicy.Window).Before(currentTime) {
+			recent = append(recent, interruptionTime)
+		}
+	}
+	if len(recent) == 0 {
+		delete(b.interruptions, key)
+		return
+	}
+	b.interruptions[key] = recent
+}
+
+// State returns the current state of the backoff.
+func (b *exponentialBackoff) State() State {
+	state := State{
+		Entries:       make(map[string]StateEntry, len(b.backoffInfo)),
+		Interruptions: make(map[string][]time.Time, len(b.interruptions)),
+	}
+	for key, backoffInfo := range b.backoffInfo {
+		state.Entries[key] = StateEntry{
+			Duration:            backoffInfo.duration,
+			BackoffUntil:        backoffInfo.backoffUntil,
+			LastFailedExecution: backoffInfo.lastFailedExecution,
+			ErrorInfo:           backoffInfo.errorInfo,
+		}
+	}
+	for key, interruptions := range b.interruptions {
+		state.Interruptions[key] = append([]time.Time{}, interruptions...)
+	}
+	return state
+}
+
+// RestoreState restores a previously saved state, keeping the entries already present.
+func (b *exponentialBackoff) RestoreState(state State) {
+	for key, entry := range state.Entries {
+		if _, found := b.backoffInfo[key]; found {
+			continue
+		}
+		b.backoffInfo[key] = exponentialBackoffInfo{
+			duration:            entry.Duration,
+			backoffUntil:        entry.BackoffUntil,
+			lastFailedExecution: entry.LastFailedExecution,
+			errorInfo:           entry.ErrorInfo,
+		}
+	}
+	for key, interruptions := range state.Interruptions {
+		if _, found := b.interruptions[key]; found {
+			continue
+		}
+		b.interruptions[key] = append([]time.Time{}, interruptions...)
+	}
 }
@@ -116,3 +116,66 @@ func TestIncreaseExistingBackoff(t *testing.T) {
 	assert.Equal(t, noBackOff, backoff.BackoffStatus(nodeGroup1, nil, currentTime))
 	// Result: existing backoff duration was scaled up beyond initial duration
 }
+
+func TestInterruptionCount(t *testing.T) {
+	backoff := NewIdBasedExponentialBackoff(1*time.Minute, 3*time.Minute, 3*time.Hour)
+	startTime := time.Now()
+	assert.Equal(t, 0, backoff.InterruptionCount(nodeGroup1, nil, startTime))
+	// Interruptions are only tracked, not backed off by default.
+	assert.Equal(t, noBackOff, backoff.RegisterInterruption(nodeGroup1, nil, quotaError, startTime))
+	assert.Equal(t, noBackOff, backoff.RegisterInterruption(nodeGroup1, nil, quotaError, startTime.Add(time.Hour)))
+	assert.Equal(t, 2, backoff.InterruptionCount(nodeGroup1, nil, startTime.Add(time.Hour)))
+	assert.Equal(t, 0, backoff.InterruptionCount(nodeGroup2, nil, startTime.Add(time.Hour)))
+	assert.Equal(t, 1, backoff.InterruptionCount(nodeGroup1, nil, startTime.Add(3*time.Hour+time.Minute)))
+
+	backoff.RemoveStaleBackoffData(startTime.Add(4*time.Hour + time.Minute))
+	assert.Equal(t, 0, backoff.InterruptionCount(nodeGroup1, nil, startTime))
+}
+
+func TestBackoffOnInterruptions(t *testing.T) {
+	policy := InterruptionPolicy{Window: 30 * time.Minute, Threshold: 2}
+	backoff := NewIdBasedExponentialBackoffWithInterruptions(1*time.Minute, 3*time.Minute, 3*time.Hour, policy)
+	startTime := time.Now()
+	assert.Equal(t, noBackOff, backoff.RegisterInterruption(nodeGroup1, nil, quotaError, startTime))
+	// The first interruption fell out of the window.
+	assert.Equal(t, noBackOff, backoff.RegisterInterruption(nodeGroup1, nil, quotaError, startTime.Add(31*time.Minute)))
+	assert.Equal(t, backoffWithQuotaError, backoff.RegisterInterruption(nodeGroup1, nil, quotaError, startTime.Add(32*time.Minute)))
+	assert.Equal(t, backoffWithQuotaError, backoff.BackoffStatus(nodeGroup1, nil, startTime.Add(33*time.Minute)))
+	assert.Equal(t, noBackOff, backoff.BackoffStatus(nodeGroup2, nil, startTime.Add(33*time.Minute)))
+	assert.Equal(t, noBackOff, backoff.BackoffStatus(nodeGroup1, nil, startTime.Add(33*time.Minute+time.Millisecond)))
+
+	// Further interruptions back off exponentially.
+	backoff.RegisterInterruption(nodeGroup1, nil, quotaError, startTime.Add(34*time.Minute))
+	assert.Equal(t, backoffWithQuotaError, backoff.BackoffStatus(nodeGroup1, nil, startTime.Add(35*time.Minute+time.Millisecond)))
+	assert.Equal(t, noBackOff, backoff.BackoffStatus(nodeGroup1, nil, startTime.Add(36*time.Minute+time.Millisecond)))
+}
+
+func TestRestoreState(t *testing.T) {
+	startTime := time.Now()
+	backoff := NewIdBasedExponentialBackoff(1*time.Minute, 3*time.Minute, 3*time.Hour)
+	backoff.Backoff(nodeGroup1, nil, quotaError, startTime)
+	backoff.Backoff(nodeGroup1, nil, quotaError, startTime.Add(2*time.Minute))
+	backoff.RegisterInterruption(nodeGroup2, nil, ipSpaceExhaustedError, startTime)
+	state := backoff.(Persistable).State()
+	assert.Equal(t, StateEntry{
+		Duration:            2 * time.Minute,
+		BackoffUntil:        startTime.Add(4 * time.Minute),
+		LastFailedExecution: startTime.Add(2 * time.Minute),
+		ErrorInfo:           quotaError,
+	}, state.Entries[nodeGroup1.Id()])
+
+	restored := NewIdBasedExponentialBackoff(1*time.Minute, 3*time.Minute, 3*time.Hour)
+	restored.(Persistable).RestoreState(state)
+	assert.Equal(t, state, restored.(Persistable).State())
+	assert.Equal(t, backoffWithQuotaError, restored.BackoffStatus(nodeGroup1, nil, startTime.Add(3*time.Minute)))
+	assert.Equal(t, 1, restored.InterruptionCount(nodeGroup2, nil, startTime))
+	// The restored backoff duration keeps growing exponentially.
+	restored.Backoff(nodeGroup1, nil, quotaError, startTime.Add(5*time.Minute))
+	assert.Equal(t, backoffWithQuotaError, restored.BackoffStatus(nodeGroup1, nil, startTime.Add(7*time.Minute)))
+
+	// Entries present before restoring are kept.
+	fresh := NewIdBasedExponentialBackoff(1*time.Minute, 3*time.Minute, 3*time.Hour)
+	fresh.Backoff(nodeGroup1, nil, ipSpaceExhaustedError, startTime.Add(3*time.Minute))
+	fresh.(Persistable).RestoreState(state)
+	assert.Equal(t, backoffWithIpSpaceExhaustedError, fresh.BackoffStatus(nodeGroup1, nil, startTime.Add(3*time.Minute)))
+}